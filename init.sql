@@ -2,15 +2,39 @@ CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
 
 CREATE TABLE cities (
   uuid           UUID      PRIMARY KEY DEFAULT uuid_generate_v4(),
-  name           TEXT      NOT NULL UNIQUE
+  name           TEXT      NOT NULL UNIQUE,
+  aliases        TEXT[]    NOT NULL DEFAULT '{}', -- альтернативные названия: Питер, СПб
+  region         TEXT,                            -- субъект РФ
+  latitude       DOUBLE PRECISION CHECK(latitude BETWEEN -90 AND 90),
+  longitude      DOUBLE PRECISION CHECK(longitude BETWEEN -180 AND 180)
 );
 
+CREATE TABLE cargo_types (
+  uuid           UUID      PRIMARY KEY DEFAULT uuid_generate_v4(),
+  code           TEXT      NOT NULL UNIQUE CHECK(code ~ '^[a-z0-9_]+$'), -- код для API и фильтров
+  name           TEXT      NOT NULL UNIQUE,
+  is_active      BOOLEAN   NOT NULL DEFAULT true  -- неактивные типы нельзя выбрать для новых заказов
+);
+
+INSERT INTO cargo_types (code, name) VALUES
+  ('general',      'Генеральный груз'),
+  ('food',         'Продукты питания'),
+  ('building',     'Стройматериалы'),
+  ('equipment',    'Оборудование'),
+  ('furniture',    'Мебель'),
+  ('vehicles',     'Автомобили и техника'),
+  ('chemicals',    'Химия'),
+  ('bulk',         'Сыпучие грузы'),
+  ('liquid',       'Наливные грузы'),
+  ('timber',       'Лес и пиломатериалы');
+
 CREATE TABLE customers (
   uuid           UUID      PRIMARY KEY DEFAULT uuid_generate_v4(),
   name           TEXT      NOT NULL,    -- ФИО или название
   phone          TEXT      NOT NULL,
   telegram_id    BIGINT,                 -- числовой ID в Telegram
   telegram_tag   TEXT,                   -- @username
+  is_active      BOOLEAN   NOT NULL DEFAULT true,
   created_at     TIMESTAMP NOT NULL DEFAULT now()
 );
 
@@ -19,11 +43,37 @@ CREATE TABLE drivers (
   name                    TEXT      NOT NULL,
   telegram_id             BIGINT   NOT NULL UNIQUE,
   telegram_tag            TEXT,                   -- @username
+  phone                   TEXT,                   -- телефон из контакта Telegram (+79001234567)
   notification_enabled    BOOLEAN  NOT NULL DEFAULT true,
   city_uuid               UUID      REFERENCES cities(uuid) ON DELETE RESTRICT,
+  pickup_radius_km        INTEGER   CHECK(pickup_radius_km >= 0),   -- радиус поиска погрузки от своего города
+  delivery_radius_km      INTEGER   CHECK(delivery_radius_km >= 0), -- радиус поиска выгрузки от своего города
+  timezone                TEXT,                   -- часовой пояс IANA, NULL — Europe/Moscow
+  digest_hour             SMALLINT  CHECK(digest_hour BETWEEN 0 AND 23), -- час ежедневной сводки, NULL — сводка выключена
+  last_digest_at          TIMESTAMP,              -- когда отправлена последняя сводка
+  notification_mode       TEXT      NOT NULL DEFAULT 'instant' CHECK(notification_mode IN ('instant', 'digest')), -- новые заказы сразу или только в сводке
+  quiet_hours_start       SMALLINT  CHECK(quiet_hours_start BETWEEN 0 AND 23), -- начало тихих часов по местному времени, NULL — без тихих часов
+  quiet_hours_end         SMALLINT  CHECK(quiet_hours_end BETWEEN 0 AND 23),   -- конец тихих часов (не включая)
+  source                  TEXT,                   -- источник привлечения: ref_<код кампании> или order_link, NULL — без метки
+  onboarding_step         TEXT      CHECK(onboarding_step IN ('phone', 'city', 'vehicle', 'terms')), -- шаг регистрации в боте, NULL — пройдена
+  terms_accepted_at       TIMESTAMP,              -- когда водитель принял условия сервиса
   created_at              TIMESTAMP NOT NULL DEFAULT now()
 );
 
+CREATE TABLE vehicles (
+  uuid           UUID      PRIMARY KEY DEFAULT uuid_generate_v4(),
+  driver_uuid    UUID      NOT NULL UNIQUE REFERENCES drivers(uuid) ON DELETE CASCADE,
+  body_type      TEXT      NOT NULL CHECK(body_type IN ('tent', 'refrigerator', 'isotherm', 'van', 'flatbed',
+                                                       'container', 'tipper', 'lowboy', 'car_carrier', 'tanker')),
+  payload_kg     NUMERIC   NOT NULL CHECK(payload_kg > 0),  -- грузоподъемность
+  volume_m3      NUMERIC             CHECK(volume_m3 > 0),
+  length_cm      NUMERIC             CHECK(length_cm > 0),  -- внутренние размеры кузова
+  width_cm       NUMERIC             CHECK(width_cm > 0),
+  height_cm      NUMERIC             CHECK(height_cm > 0),
+  adr            BOOLEAN   NOT NULL DEFAULT false,          -- допуск ДОПОГ (опасные грузы)
+  updated_at     TIMESTAMP NOT NULL DEFAULT now()
+);
+
 CREATE TABLE orders (
   uuid           UUID      PRIMARY KEY DEFAULT uuid_generate_v4(),
   customer_uuid  UUID      NOT NULL REFERENCES customers(uuid) ON DELETE CASCADE,
@@ -40,13 +90,321 @@ CREATE TABLE orders (
   tags           TEXT[]    NOT NULL DEFAULT '{}',
   price          NUMERIC   NOT NULL CHECK(price >= 0),
   available_from DATE,
-  status         TEXT      NOT NULL DEFAULT 'active' CHECK(status IN ('active', 'archived')),
+  distance_km    NUMERIC             CHECK(distance_km >= 0), -- расчетное расстояние маршрута по дорогам
+  cargo_type_uuid UUID     REFERENCES cargo_types(uuid) ON DELETE RESTRICT,
+  temperature_min_c NUMERIC,                      -- температурный режим, °C
+  temperature_max_c NUMERIC CHECK(temperature_max_c >= temperature_min_c),
+  adr_class      TEXT,                            -- класс опасности ДОПОГ
+  is_oversize    BOOLEAN   NOT NULL DEFAULT false,
+  is_fragile     BOOLEAN   NOT NULL DEFAULT false,
+  is_urgent      BOOLEAN   NOT NULL DEFAULT false, -- срочный: уведомления приходят и в тихие часы водителей
+  status         TEXT      NOT NULL DEFAULT 'active' CHECK(status IN ('active', 'archived', 'expired', 'assigned', 'delivered')),
+  assigned_driver_uuid UUID REFERENCES drivers(uuid) ON DELETE SET NULL, -- водитель, выполняющий перевозку
+  assigned_at    TIMESTAMP,
+  delivered_at   TIMESTAMP,
+  delivery_latitude  DOUBLE PRECISION,            -- где водитель подтвердил доставку
+  delivery_longitude DOUBLE PRECISION,
+  expiry_warned_at TIMESTAMP,                     -- когда предупредили о скором истечении
+  activated_at   TIMESTAMP,                       -- когда заказ вернули в активные; NULL — активен с создания
   created_at     TIMESTAMP NOT NULL DEFAULT now()
 );
 
 CREATE INDEX idx_orders_tags   ON orders USING GIN(tags);
 CREATE INDEX idx_orders_price  ON orders(price);
+CREATE INDEX idx_orders_price_per_km ON orders((price / NULLIF(distance_km, 0)));
 CREATE INDEX idx_orders_weight ON orders(weight_kg);
 CREATE INDEX idx_orders_status ON orders(status);
+CREATE INDEX idx_orders_cargo_type ON orders(cargo_type_uuid);
+CREATE INDEX idx_orders_assigned_driver ON orders(assigned_driver_uuid) WHERE assigned_driver_uuid IS NOT NULL;
+CREATE INDEX idx_orders_delivered_at ON orders(delivered_at) WHERE status = 'delivered';
+CREATE INDEX idx_orders_available_from ON orders(available_from) WHERE status = 'active';
 CREATE INDEX idx_drivers_city  ON drivers(city_uuid);
-CREATE INDEX idx_drivers_notification ON drivers(notification_enabled) WHERE notification_enabled = true; 
\ No newline at end of file
+CREATE INDEX idx_customers_phone ON customers(phone);
+CREATE INDEX idx_orders_customer ON orders(customer_uuid);
+CREATE INDEX idx_drivers_notification ON drivers(notification_enabled) WHERE notification_enabled = true;
+
+CREATE TABLE order_attachments (
+  uuid           UUID      PRIMARY KEY DEFAULT uuid_generate_v4(),
+  order_uuid     UUID      NOT NULL REFERENCES orders(uuid) ON DELETE CASCADE,
+  kind           TEXT      NOT NULL CHECK(kind IN ('photo', 'document')),
+  purpose        TEXT      NOT NULL DEFAULT 'cargo' CHECK(purpose IN ('cargo', 'pod')), -- pod: подтверждение доставки
+  source_bot     TEXT      NOT NULL DEFAULT 'admin' CHECK(source_bot IN ('admin', 'driver')),
+  telegram_file_id        TEXT NOT NULL,            -- file_id в боте-источнике
+  telegram_file_unique_id TEXT NOT NULL,            -- общий для всех ботов идентификатор файла
+  admin_file_id  TEXT,                              -- file_id в админском боте после первой отправки
+  driver_file_id TEXT,                              -- file_id в боте водителей после первой отправки
+  file_name      TEXT,
+  mime_type      TEXT,
+  size_bytes     BIGINT    CHECK(size_bytes >= 0),
+  caption        TEXT,
+  storage_key    TEXT,                              -- ключ копии в файловом хранилище
+  uploaded_by    TEXT      NOT NULL CHECK(uploaded_by IN ('admin', 'customer', 'driver')),
+  uploader_telegram_id BIGINT NOT NULL,
+  created_at     TIMESTAMP NOT NULL DEFAULT now(),
+  UNIQUE (order_uuid, telegram_file_unique_id)
+);
+
+CREATE INDEX idx_order_attachments_order ON order_attachments(order_uuid);
+
+CREATE TABLE driver_ratings (
+  uuid           UUID      PRIMARY KEY DEFAULT uuid_generate_v4(),
+  order_uuid     UUID      NOT NULL UNIQUE REFERENCES orders(uuid) ON DELETE CASCADE, -- одна оценка на заказ
+  driver_uuid    UUID      NOT NULL REFERENCES drivers(uuid) ON DELETE CASCADE,
+  customer_uuid  UUID      NOT NULL REFERENCES customers(uuid) ON DELETE RESTRICT, -- оценки переносятся при слиянии заказчиков
+  score          SMALLINT  NOT NULL CHECK(score BETWEEN 1 AND 5),
+  comment        TEXT,
+  source         TEXT      NOT NULL CHECK(source IN ('bot', 'web')),
+  created_at     TIMESTAMP NOT NULL DEFAULT now(),
+  updated_at     TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_driver_ratings_driver ON driver_ratings(driver_uuid);
+
+-- Анонимная переписка водителя с заказчиком через ботов
+CREATE TABLE order_conversations (
+  uuid            UUID      PRIMARY KEY DEFAULT uuid_generate_v4(),
+  order_uuid      UUID      NOT NULL REFERENCES orders(uuid) ON DELETE CASCADE,
+  driver_uuid     UUID      NOT NULL REFERENCES drivers(uuid) ON DELETE CASCADE,
+  created_at      TIMESTAMP NOT NULL DEFAULT now(),
+  last_message_at TIMESTAMP NOT NULL DEFAULT now(),
+  UNIQUE (order_uuid, driver_uuid)
+);
+
+CREATE TABLE conversation_messages (
+  uuid              UUID      PRIMARY KEY DEFAULT uuid_generate_v4(),
+  conversation_uuid UUID      NOT NULL REFERENCES order_conversations(uuid) ON DELETE CASCADE,
+  sender            TEXT      NOT NULL CHECK(sender IN ('driver', 'customer', 'admin')),
+  text              TEXT      NOT NULL,
+  delivered         BOOLEAN   NOT NULL DEFAULT true,
+  created_at        TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_conversation_messages_conversation ON conversation_messages(conversation_uuid, created_at);
+CREATE INDEX idx_order_conversations_last_message ON order_conversations(last_message_at DESC);
+
+-- Сохраненные поиски водителей для ежедневной сводки
+CREATE TABLE driver_saved_searches (
+  uuid           UUID      PRIMARY KEY DEFAULT uuid_generate_v4(),
+  driver_uuid    UUID      NOT NULL REFERENCES drivers(uuid) ON DELETE CASCADE,
+  city_uuid      UUID      NOT NULL REFERENCES cities(uuid) ON DELETE RESTRICT,
+  radius_km      INTEGER   NOT NULL CHECK(radius_km >= 0),
+  direction      TEXT      NOT NULL CHECK(direction IN ('pickup', 'delivery', 'any')),
+  min_price      NUMERIC   CHECK(min_price >= 0), -- нижняя граница цены, NULL — без ограничения
+  created_at     TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_driver_saved_searches_driver ON driver_saved_searches(driver_uuid);
+CREATE INDEX idx_drivers_digest_hour ON drivers(digest_hour) WHERE digest_hour IS NOT NULL;
+CREATE INDEX idx_orders_created_at ON orders(created_at) WHERE status = 'active';
+
+-- Предложения водителей по цене и дате погрузки
+CREATE TABLE bids (
+  uuid           UUID      PRIMARY KEY DEFAULT uuid_generate_v4(),
+  order_uuid     UUID      NOT NULL REFERENCES orders(uuid) ON DELETE CASCADE,
+  driver_uuid    UUID      NOT NULL REFERENCES drivers(uuid) ON DELETE CASCADE,
+  price          NUMERIC   NOT NULL CHECK(price > 0),
+  pickup_date    DATE,
+  comment        TEXT,
+  status         TEXT      NOT NULL DEFAULT 'pending' CHECK(status IN ('pending', 'accepted', 'rejected')),
+  created_at     TIMESTAMP NOT NULL DEFAULT now(),
+  updated_at     TIMESTAMP NOT NULL DEFAULT now(),
+  UNIQUE (order_uuid, driver_uuid) -- одно предложение водителя на заказ
+);
+
+CREATE INDEX idx_bids_order ON bids(order_uuid, status);
+
+-- Настройки, изменяемые администраторами во время работы
+CREATE TABLE app_settings (
+  key            TEXT      PRIMARY KEY,
+  value          TEXT      NOT NULL,
+  updated_at     TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE TABLE order_notifications (
+  order_uuid     UUID      NOT NULL REFERENCES orders(uuid) ON DELETE CASCADE,
+  driver_uuid    UUID      NOT NULL REFERENCES drivers(uuid) ON DELETE CASCADE,
+  notified_at    TIMESTAMP NOT NULL DEFAULT now(),
+  PRIMARY KEY (order_uuid, driver_uuid)
+);
+
+CREATE INDEX idx_order_notifications_driver ON order_notifications(driver_uuid);
+
+-- Уведомления, отложенные до конца тихих часов водителя
+CREATE TABLE deferred_notifications (
+  order_uuid     UUID      NOT NULL REFERENCES orders(uuid) ON DELETE CASCADE,
+  driver_uuid    UUID      NOT NULL REFERENCES drivers(uuid) ON DELETE CASCADE,
+  kind           TEXT      NOT NULL CHECK(kind IN ('new', 'updated')),
+  changes        TEXT[]    NOT NULL DEFAULT '{}', -- изменения заказа для kind = 'updated'
+  deliver_at     TIMESTAMP NOT NULL,             -- когда заканчиваются тихие часы водителя
+  created_at     TIMESTAMP NOT NULL DEFAULT now(),
+  PRIMARY KEY (order_uuid, driver_uuid)
+);
+
+CREATE INDEX idx_deferred_notifications_deliver_at ON deferred_notifications(deliver_at);
+
+-- Индексы для аналитики: заказы по дням создания и назначения за произвольный период
+CREATE INDEX idx_orders_created_at_all ON orders(created_at);
+CREATE INDEX idx_orders_assigned_at ON orders(assigned_at) WHERE assigned_at IS NOT NULL;
+CREATE INDEX idx_bids_created_at ON bids(created_at);
+
+-- Полнотекстовый поиск заказов: название, описание, города с альтернативными названиями, адреса
+-- и имя заказчика с русской морфологией. Блок можно выполнить и на существующей базе как миграцию.
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS search_vector tsvector;
+
+-- order_search_document собирает поисковый документ заказа; ё заменяется на е, как в запросах
+CREATE OR REPLACE FUNCTION order_search_document(o orders) RETURNS tsvector AS $$
+  SELECT
+    setweight(to_tsvector('russian', translate(coalesce(o.title, ''), 'Ёё', 'Ее')), 'A') ||
+    setweight(to_tsvector('russian', translate(coalesce(o.description, ''), 'Ёё', 'Ее')), 'B') ||
+    setweight(to_tsvector('russian', translate(concat_ws(' ',
+      fc.name, array_to_string(fc.aliases, ' '), o.from_address,
+      tc.name, array_to_string(tc.aliases, ' '), o.to_address), 'Ёё', 'Ее')), 'C') ||
+    setweight(to_tsvector('russian', translate(coalesce(c.name, ''), 'Ёё', 'Ее')), 'D')
+  FROM (SELECT 1) AS one
+  LEFT JOIN cities fc ON fc.uuid = o.from_city_uuid
+  LEFT JOIN cities tc ON tc.uuid = o.to_city_uuid
+  LEFT JOIN customers c ON c.uuid = o.customer_uuid
+$$ LANGUAGE sql STABLE;
+
+CREATE OR REPLACE FUNCTION orders_search_vector_update() RETURNS trigger AS $$
+BEGIN
+  NEW.search_vector := order_search_document(NEW);
+  RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+
+-- Обновление search_vector вручную (SET search_vector = NULL) тоже пересчитывает документ
+DROP TRIGGER IF EXISTS orders_search_vector ON orders;
+CREATE TRIGGER orders_search_vector
+  BEFORE INSERT OR UPDATE OF title, description, from_city_uuid, from_address, to_city_uuid, to_address,
+    customer_uuid, search_vector ON orders
+  FOR EACH ROW EXECUTE FUNCTION orders_search_vector_update();
+
+-- Переименование города или заказчика пересчитывает документы их заказов
+CREATE OR REPLACE FUNCTION orders_search_refresh_city() RETURNS trigger AS $$
+BEGIN
+  UPDATE orders SET search_vector = NULL WHERE from_city_uuid = NEW.uuid OR to_city_uuid = NEW.uuid;
+  RETURN NULL;
+END
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS cities_search_refresh ON cities;
+CREATE TRIGGER cities_search_refresh
+  AFTER UPDATE OF name, aliases ON cities
+  FOR EACH ROW WHEN (OLD.name IS DISTINCT FROM NEW.name OR OLD.aliases IS DISTINCT FROM NEW.aliases)
+  EXECUTE FUNCTION orders_search_refresh_city();
+
+CREATE OR REPLACE FUNCTION orders_search_refresh_customer() RETURNS trigger AS $$
+BEGIN
+  UPDATE orders SET search_vector = NULL WHERE customer_uuid = NEW.uuid;
+  RETURN NULL;
+END
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS customers_search_refresh ON customers;
+CREATE TRIGGER customers_search_refresh
+  AFTER UPDATE OF name ON customers
+  FOR EACH ROW WHEN (OLD.name IS DISTINCT FROM NEW.name)
+  EXECUTE FUNCTION orders_search_refresh_customer();
+
+-- Заполнение документов уже существующих заказов
+UPDATE orders SET search_vector = NULL;
+
+CREATE INDEX IF NOT EXISTS idx_orders_search ON orders USING GIN(search_vector);
+
+-- События ленты активных заказов для сайта (Server-Sent Events): приложение слушает канал order_events.
+-- Уведомления отправляются при фиксации транзакции. Блок можно выполнить на существующей базе как миграцию.
+CREATE OR REPLACE FUNCTION orders_notify_event() RETURNS trigger AS $$
+DECLARE
+  event_type TEXT;
+BEGIN
+  IF TG_OP = 'DELETE' THEN
+    IF OLD.status <> 'active' THEN
+      RETURN NULL;
+    END IF;
+    PERFORM pg_notify('order_events', json_build_object('type', 'removed', 'uuid', OLD.uuid, 'status', OLD.status)::text);
+    RETURN NULL;
+  END IF;
+
+  IF TG_OP = 'INSERT' THEN
+    IF NEW.status <> 'active' THEN
+      RETURN NULL;
+    END IF;
+    event_type := 'created';
+  ELSIF NEW.status = 'active' AND OLD.status <> 'active' THEN
+    event_type := 'created';
+  ELSIF NEW.status <> 'active' AND OLD.status = 'active' THEN
+    event_type := 'removed';
+  ELSIF NEW.status = 'active' AND (NEW.title, NEW.description, NEW.weight_kg, NEW.length_cm, NEW.width_cm, NEW.height_cm,
+      NEW.from_city_uuid, NEW.from_address, NEW.to_city_uuid, NEW.to_address, NEW.tags, NEW.price, NEW.available_from,
+      NEW.distance_km, NEW.cargo_type_uuid, NEW.temperature_min_c, NEW.temperature_max_c, NEW.adr_class,
+      NEW.is_oversize, NEW.is_fragile, NEW.is_urgent)
+    IS DISTINCT FROM (OLD.title, OLD.description, OLD.weight_kg, OLD.length_cm, OLD.width_cm, OLD.height_cm,
+      OLD.from_city_uuid, OLD.from_address, OLD.to_city_uuid, OLD.to_address, OLD.tags, OLD.price, OLD.available_from,
+      OLD.distance_km, OLD.cargo_type_uuid, OLD.temperature_min_c, OLD.temperature_max_c, OLD.adr_class,
+      OLD.is_oversize, OLD.is_fragile, OLD.is_urgent) THEN
+    event_type := 'updated';
+  ELSE
+    RETURN NULL; -- Служебные изменения (поисковый документ, предупреждения) ленту не меняют
+  END IF;
+
+  PERFORM pg_notify('order_events', json_build_object('type', event_type, 'uuid', NEW.uuid, 'status', NEW.status)::text);
+  RETURN NULL;
+END
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS orders_notify_event ON orders;
+CREATE TRIGGER orders_notify_event
+  AFTER INSERT OR UPDATE OR DELETE ON orders
+  FOR EACH ROW EXECUTE FUNCTION orders_notify_event();
+
+-- Приведение телефонов заказчиков к формату E.164 (+79001234567), как в NormalizePhone приложения.
+-- Блок можно выполнить на существующей базе как миграцию: нераспознанные номера остаются как есть
+-- и выводятся предупреждениями, как и заказчики, у которых после приведения совпали телефоны.
+CREATE OR REPLACE FUNCTION normalize_phone(phone TEXT) RETURNS TEXT AS $$
+DECLARE
+  trimmed  TEXT := btrim(phone);
+  has_plus BOOLEAN := left(btrim(phone), 1) = '+';
+  digits   TEXT;
+BEGIN
+  IF trimmed = '' OR trimmed ~ '[^0-9+ ().-]' THEN
+    RETURN NULL;
+  END IF;
+  digits := regexp_replace(trimmed, '[^0-9]', '', 'g');
+
+  IF length(digits) = 11 AND left(digits, 1) = '8' AND NOT has_plus THEN
+    RETURN '+7' || substr(digits, 2);
+  ELSIF length(digits) = 11 AND left(digits, 1) = '7' THEN
+    RETURN '+' || digits;
+  ELSIF length(digits) = 10 AND left(digits, 1) = '9' AND NOT has_plus THEN
+    RETURN '+7' || digits;
+  ELSIF has_plus AND length(digits) BETWEEN 8 AND 15 THEN
+    RETURN '+' || digits;
+  END IF;
+  RETURN NULL;
+END
+$$ LANGUAGE plpgsql IMMUTABLE;
+
+DO $$
+DECLARE
+  customer   RECORD;
+  normalized TEXT;
+BEGIN
+  FOR customer IN SELECT uuid, name, phone FROM customers LOOP
+    normalized := normalize_phone(customer.phone);
+    IF normalized IS NULL THEN
+      RAISE WARNING 'Телефон заказчика % (%) не распознан: %', customer.name, customer.uuid, customer.phone;
+    ELSIF normalized <> customer.phone THEN
+      UPDATE customers SET phone = normalized WHERE uuid = customer.uuid;
+    END IF;
+  END LOOP;
+
+  -- Совпавшие телефоны — кандидаты на MERGE_CUSTOMERS
+  FOR customer IN
+    SELECT phone, string_agg(name || ' (' || uuid || ')', ', ') AS names
+    FROM customers GROUP BY phone HAVING COUNT(*) > 1
+  LOOP
+    RAISE WARNING 'Заказчики с одинаковым телефоном %: %', customer.phone, customer.names;
+  END LOOP;
+END
+$$;
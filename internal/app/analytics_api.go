@@ -0,0 +1,61 @@
+package app
+
+import (
+	"net/http"
+	"time"
+
+	"dalnoboy/internal/service"
+)
+
+// getAnalyticsHandler возвращает показатели аналитики: GET /v1/analytics/{report}?from=2025-01-01&to=2025-01-31.
+// Отчеты: summary, volume, assignment, routes, prices, drivers, sources. Даты включительные,
+// по умолчанию — последние 30 дней; routes и drivers принимают limit.
+func (a *App) getAnalyticsHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	period, err := service.ParseAnalyticsPeriod(query.Get("from"), query.Get("to"), time.Now())
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	var payload interface{}
+	switch r.PathValue("report") {
+	case "summary":
+		payload, err = a.AnalyticsService.GetSummary(period)
+	case "volume":
+		payload, err = a.AnalyticsService.GetDailyVolume(period)
+	case "assignment":
+		payload, err = a.AnalyticsService.GetAssignmentTime(period)
+	case "prices":
+		payload, err = a.AnalyticsService.GetPriceStats(period)
+	case "routes":
+		limit, limitErr := service.ParseAnalyticsLimit(query.Get("limit"))
+		if limitErr != nil {
+			writeServiceError(w, limitErr)
+			return
+		}
+		payload, err = a.AnalyticsService.GetTopRoutes(period, limit)
+	case "drivers":
+		limit, limitErr := service.ParseAnalyticsLimit(query.Get("limit"))
+		if limitErr != nil {
+			writeServiceError(w, limitErr)
+			return
+		}
+		drivers, total, driversErr := a.AnalyticsService.GetDriverActivity(period, limit)
+		payload, err = map[string]interface{}{"drivers": drivers, "active_drivers": total}, driversErr
+	case "sources":
+		payload, err = a.AnalyticsService.GetDriverSources(period)
+	default:
+		writeJSONError(w, http.StatusNotFound, "Неизвестный отчет: доступны summary, volume, assignment, routes, prices, drivers, sources")
+		return
+	}
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"period": period,
+		"data":   payload,
+	})
+}
@@ -0,0 +1,121 @@
+package app
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"dalnoboy/internal/domain"
+	"dalnoboy/internal/service"
+)
+
+// requireAdminKey пропускает запрос только с действующим ключом администратора API
+// в заголовке "Authorization: Bearer <ключ>" или "X-API-Key"
+func (a *App) requireAdminKey(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !a.isAdminRequest(r) {
+			writeJSONError(w, http.StatusUnauthorized, "Требуется ключ администратора API")
+			return
+		}
+		next(w, r)
+	}
+}
+
+// isAdminRequest проверяет, что запрос содержит один из настроенных ключей администратора
+func (a *App) isAdminRequest(r *http.Request) bool {
+	key := r.Header.Get("X-API-Key")
+	if auth := r.Header.Get("Authorization"); key == "" && strings.HasPrefix(auth, "Bearer ") {
+		key = strings.TrimSpace(strings.TrimPrefix(auth, "Bearer "))
+	}
+	if key == "" || a.Config == nil {
+		return false
+	}
+
+	for _, adminKey := range a.Config.API.AdminKeys {
+		if subtle.ConstantTimeCompare([]byte(key), []byte(adminKey)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// writeJSON отправляет ответ в формате JSON
+func writeJSON(w http.ResponseWriter, status int, payload interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(payload)
+}
+
+// writeJSONError отправляет ошибку в формате {"error": "..."}
+func writeJSONError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
+
+// writeServiceError преобразует ошибку сервиса в HTTP ответ
+func writeServiceError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrOrderNotFound),
+		errors.Is(err, service.ErrCustomerNotFound),
+		errors.Is(err, service.ErrCityNotFound),
+		errors.Is(err, service.ErrCargoTypeNotFound),
+		errors.Is(err, service.ErrAttachmentNotFound):
+		writeJSONError(w, http.StatusNotFound, err.Error())
+	case service.IsValidationError(err):
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+	default:
+		log.Printf("Ошибка обработки API запроса: %v", err)
+		writeJSONError(w, http.StatusInternalServerError, "Внутренняя ошибка сервера")
+	}
+}
+
+// patchOrderHandler частично изменяет заказ: PATCH /v1/orders/{uuid}
+func (a *App) patchOrderHandler(w http.ResponseWriter, r *http.Request) {
+	var request domain.UpdateOrderRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&request); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "Некорректное тело запроса: "+err.Error())
+		return
+	}
+
+	order, changes, err := a.OrderService.UpdateOrder(r.PathValue("uuid"), &request)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"order":   order,
+		"changes": changes,
+	})
+}
+
+// putOrderUrgentHandler отмечает заказ срочным или снимает отметку: PUT /v1/orders/{uuid}/urgent
+// с телом {"urgent": true}. Срочный заказ приходит водителям и в тихие часы.
+func (a *App) putOrderUrgentHandler(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Urgent *bool `json:"urgent"`
+	}
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&request); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "Некорректное тело запроса: "+err.Error())
+		return
+	}
+	if request.Urgent == nil {
+		writeJSONError(w, http.StatusBadRequest, "Поле urgent обязательно")
+		return
+	}
+
+	order, err := a.OrderService.SetOrderUrgent(r.PathValue("uuid"), *request.Urgent)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, order)
+}
@@ -13,20 +13,40 @@ import (
 	"dalnoboy/internal/bot"
 	"dalnoboy/internal/cache"
 	"dalnoboy/internal/database"
+	"dalnoboy/internal/domain"
 	"dalnoboy/internal/service"
+	"dalnoboy/internal/storage"
+	"dalnoboy/web"
 )
 
 // App представляет основное приложение
 type App struct {
-	Name            string
-	AdminBot        *bot.AdminBot
-	DriverBot       *bot.DriverBot
-	Database        *database.Database
-	Cache           cache.Cache
-	OrderService    *service.OrderService
-	CustomerService *service.CustomerService
-	DriverService   *service.DriverService
-	HTTPServer      *http.Server
+	Name                string
+	Config              *internal.Config
+	AdminBot            *bot.AdminBot
+	DriverBot           *bot.DriverBot
+	Database            *database.Database
+	Cache               cache.Cache
+	OrderService        *service.OrderService
+	CustomerService     *service.CustomerService
+	DriverService       *service.DriverService
+	CityService         *service.CityService
+	CargoService        *service.CargoService
+	AttachmentService   *service.AttachmentService
+	DeliveryService     *service.DeliveryService
+	RatingService       *service.RatingService
+	ChatService         *service.ChatService
+	BidService          *service.BidService
+	DigestService       *service.DigestService
+	ExportService       *service.ExportService
+	AnalyticsService    *service.AnalyticsService
+	NotificationService *service.NotificationService
+	ExpiryService       *service.ExpiryService
+	OrderEvents         *service.OrderEventHub
+	HTTPServer          *http.Server
+
+	stopBackground context.CancelFunc // Останавливает фоновые задачи
+	siteAssets     *siteAssets        // Файлы сайта, встроенные в бинарный файл
 }
 
 // HealthResponse представляет ответ health check
@@ -63,6 +83,26 @@ func (a *App) startHTTPServer() error {
 	// API маршруты
 	mux.HandleFunc("/health", a.healthCheckHandler)
 	mux.HandleFunc("/v1/orders", a.getOrdersHandler)
+	mux.HandleFunc("GET /v1/orders/events", a.orderEventsHandler)
+	mux.HandleFunc("GET /v1/orders/{uuid}", a.getOrderHandler)
+	mux.HandleFunc("PATCH /v1/orders/{uuid}", a.requireAdminKey(a.patchOrderHandler))
+	mux.HandleFunc("PUT /v1/orders/{uuid}/urgent", a.requireAdminKey(a.putOrderUrgentHandler))
+	mux.HandleFunc("GET /v1/orders/{uuid}/attachments", a.getOrderAttachmentsHandler)
+	mux.HandleFunc("GET /v1/orders/{uuid}/attachments/{attachment_uuid}", a.getOrderAttachmentContentHandler)
+	mux.HandleFunc("GET /v1/deliveries", a.requireAdminKey(a.getDeliveriesHandler))
+	mux.HandleFunc("GET /v1/exports/{kind}", a.requireAdminKey(a.getExportHandler))
+	mux.HandleFunc("GET /v1/analytics/{report}", a.requireAdminKey(a.getAnalyticsHandler))
+	mux.HandleFunc("GET /v1/cargo-types", a.getCargoTypesHandler)
+	mux.HandleFunc("GET /orders/{uuid}", a.orderPageHandler)
+	mux.HandleFunc("GET /rate", a.ratingPageHandler)
+	mux.HandleFunc("POST /rate", a.submitRatingHandler)
+	mux.HandleFunc("POST /v1/cargo-types", a.requireAdminKey(a.createCargoTypeHandler))
+	mux.HandleFunc("GET /v1/cities", a.getCitiesHandler)
+	mux.HandleFunc("GET /v1/cities/resolve", a.resolveCityHandler)
+	mux.HandleFunc("POST /v1/cities", a.requireAdminKey(a.createCityHandler))
+	mux.HandleFunc("POST /v1/cities/import", a.requireAdminKey(a.importCitiesHandler))
+	mux.HandleFunc("PATCH /v1/cities/{uuid}", a.requireAdminKey(a.updateCityHandler))
+	mux.HandleFunc("DELETE /v1/cities/{uuid}", a.requireAdminKey(a.deleteCityHandler))
 
 	// Статические файлы сайта
 	mux.HandleFunc("/", a.staticHandler)
@@ -78,6 +118,23 @@ func (a *App) startHTTPServer() error {
 	return a.HTTPServer.ListenAndServe()
 }
 
+// createBlobStore создает хранилище копий вложений по настройкам
+func (a *App) createBlobStore(config internal.StorageConfig) (storage.BlobStore, error) {
+	factory := storage.NewFactory()
+	switch storage.StoreType(config.Type) {
+	case storage.S3StoreType:
+		return factory.Create(storage.S3StoreType, storage.S3Config{
+			Endpoint:  config.S3.Endpoint,
+			Region:    config.S3.Region,
+			Bucket:    config.S3.Bucket,
+			AccessKey: config.S3.AccessKey,
+			SecretKey: config.S3.SecretKey,
+		})
+	default:
+		return factory.Create(storage.StoreType(config.Type), storage.LocalConfig{Dir: config.LocalDir})
+	}
+}
+
 // Run запускает приложение
 func (a *App) Run() error {
 
@@ -92,6 +149,16 @@ func (a *App) Run() error {
 	if err := config.Validate(); err != nil {
 		return fmt.Errorf("ошибка валидации конфига: %v", err)
 	}
+	a.Config = config
+
+	// Файлы сайта встроены в бинарный файл; ошибка в шаблонах страниц обнаруживается при запуске
+	a.siteAssets, err = loadSiteAssets(web.Static(), true)
+	if err != nil {
+		return err
+	}
+	if config.Website.Dir != "" {
+		log.Printf("Файлы сайта читаются с диска: %s", config.Website.Dir)
+	}
 
 	// Подключение к базе данных
 	db, err := database.New(config)
@@ -115,28 +182,78 @@ func (a *App) Run() error {
 	a.Cache = redisCache
 	defer a.Cache.Close()
 
+	// Инициализация хранилища копий вложений (необязательно)
+	var blobStore storage.BlobStore
+	if config.Storage.Type != "" {
+		blobStore, err = a.createBlobStore(config.Storage)
+		if err != nil {
+			return fmt.Errorf("ошибка инициализации хранилища вложений: %v", err)
+		}
+	}
+
 	// Инициализация сервисов
-	a.OrderService = service.NewOrderService(db, db)
+	a.CityService = service.NewCityService(db)
+	a.CargoService = service.NewCargoService(db)
+	a.NotificationService = service.NewNotificationService(db, a.CityService)
+	a.OrderService = service.NewOrderService(db, a.CityService, a.CargoService, a.NotificationService)
 	a.CustomerService = service.NewCustomerService(db)
-	a.DriverService = service.NewDriverService(db)
+	a.DriverService = service.NewDriverService(db, a.CityService)
+	a.AttachmentService = service.NewAttachmentService(db, blobStore)
+	a.RatingService = service.NewRatingService(db, config.Ratings.LinkSecret, config.Ratings.BaseURL)
+	a.DeliveryService = service.NewDeliveryService(db, a.AttachmentService, a.RatingService, config.Bot.AdminChatIDs)
+	a.ChatService = service.NewChatService(db, config.Bot.AdminChatIDs)
+	a.BidService = service.NewBidService(db, config.Bot.AdminChatIDs)
+	a.DigestService = service.NewDigestService(db, a.CityService)
+	a.ExportService = service.NewExportService(db, a.OrderService)
+	a.AnalyticsService = service.NewAnalyticsService(db, config.Bot.AdminChatIDs)
+	a.ExpiryService = service.NewExpiryService(db, service.ExpirySettings{
+		GracePeriod: config.Expiry.GracePeriod,
+		MaxAge:      config.Expiry.MaxAge,
+		WarnBefore:  config.Expiry.WarnBefore,
+	}, config.Bot.AdminChatIDs)
+	a.OrderEvents = service.NewOrderEventHub(db)
 
 	// Инициализация админского бота
-	adminBot, err := bot.NewAdminBot(config, db, a.OrderService, a.CustomerService, a.DriverService)
+	adminBot, err := bot.NewAdminBot(config, db, a.OrderService, a.CustomerService, a.DriverService, a.CityService, a.CargoService, a.AttachmentService, a.DeliveryService, a.RatingService, a.NotificationService, a.ChatService, a.BidService, a.ExportService, a.AnalyticsService)
 	if err != nil {
 		return fmt.Errorf("ошибка инициализации админского бота: %v", err)
 	}
 	a.AdminBot = adminBot
+	a.ExpiryService.SetNotifier(adminBot)
+	a.AttachmentService.SetDownloader(domain.AttachmentBotAdmin, adminBot)
+	a.DeliveryService.SetNotifier(adminBot)
+	a.RatingService.SetNotifier(adminBot)
+	a.ChatService.SetCustomerRelay(adminBot)
+	a.BidService.SetNotifier(adminBot)
+	a.AnalyticsService.SetNotifier(adminBot)
 
 	// Инициализация бота для водителей
-	driverBot, err := bot.NewDriverBot(config, db, a.OrderService, a.DriverService)
+	driverBot, err := bot.NewDriverBot(config, db, a.OrderService, a.DriverService, a.CargoService, a.AttachmentService, a.DeliveryService, a.ChatService, a.BidService, a.DigestService)
 	if err != nil {
 		return fmt.Errorf("ошибка инициализации бота для водителей: %v", err)
 	}
 	a.DriverBot = driverBot
+	a.NotificationService.SetNotifier(driverBot)
+	a.AttachmentService.SetDownloader(domain.AttachmentBotDriver, driverBot)
+	a.DeliveryService.SetAssignmentNotifier(driverBot)
+	a.ChatService.SetDriverRelay(driverBot)
+	a.BidService.SetBidderNotifier(driverBot)
+	a.DigestService.SetNotifier(driverBot)
 
 	// Запуск ботов и HTTP сервера в отдельных горутинах
 	var wg sync.WaitGroup
 
+	backgroundCtx, stopBackground := context.WithCancel(context.Background())
+	a.stopBackground = stopBackground
+	defer stopBackground()
+
+	// Лента событий заказов для сайта
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		a.OrderEvents.Run(backgroundCtx)
+	}()
+
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
@@ -161,6 +278,37 @@ func (a *App) Run() error {
 		}
 	}()
 
+	if config.Expiry.Enabled {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			a.runExpiryScheduler(backgroundCtx)
+		}()
+	}
+
+	if config.Digest.Enabled {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			a.runDigestScheduler(backgroundCtx)
+		}()
+	}
+
+	// Отложенные уведомления отправляются всегда: иначе водители с тихими часами их не получат
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		a.runDeferredScheduler(backgroundCtx)
+	}()
+
+	if config.Analytics.WeeklySummary {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			a.runAnalyticsScheduler(backgroundCtx)
+		}()
+	}
+
 	fmt.Println("Оба бота и HTTP сервер запущены и работают...")
 	wg.Wait()
 
@@ -169,6 +317,9 @@ func (a *App) Run() error {
 
 // Shutdown gracefully завершает работу приложения
 func (a *App) Shutdown(ctx context.Context) error {
+	if a.stopBackground != nil {
+		a.stopBackground()
+	}
 	if a.HTTPServer != nil {
 		if err := a.HTTPServer.Shutdown(ctx); err != nil {
 			log.Printf("Ошибка завершения HTTP сервера: %v", err)
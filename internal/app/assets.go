@@ -0,0 +1,232 @@
+package app
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+)
+
+// siteAssetTypes — расширения файлов, которые отдает сайт. Остальные файлы директории не отдаются.
+var siteAssetTypes = map[string]string{
+	".html": "text/html; charset=utf-8",
+	".css":  "text/css; charset=utf-8",
+	".js":   "text/javascript; charset=utf-8",
+	".svg":  "image/svg+xml",
+	".png":  "image/png",
+	".ico":  "image/x-icon",
+}
+
+// siteCompressibleTypes — текстовые файлы, которые имеет смысл сжимать
+var siteCompressibleTypes = map[string]bool{".html": true, ".css": true, ".js": true, ".svg": true}
+
+const (
+	// siteImmutableCache — кеш для ресурсов с хешем содержимого в имени
+	siteImmutableCache = "public, max-age=31536000, immutable"
+	// siteRevalidateCache — страницы и ресурсы без хеша браузер проверяет по ETag при каждом запросе
+	siteRevalidateCache = "no-cache"
+)
+
+// siteAsset — файл сайта, подготовленный к отдаче
+type siteAsset struct {
+	content     []byte
+	gzip        []byte // Сжатая копия, если она меньше исходного файла
+	brotli      []byte // Копия, сжатая brotli при сборке (файл <имя>.br)
+	contentType string
+	hash        string
+	immutable   bool
+}
+
+// siteAssets — файлы сайта по путям запросов. Ресурсы доступны по обычному имени (/style.css)
+// и по имени с хешем содержимого (/style.1a2b3c4d5e.css), которое подставляют страницы.
+type siteAssets struct {
+	files     map[string]*siteAsset
+	urls      map[string]string // Имя ресурса -> путь с хешем
+	orderPage *template.Template
+}
+
+// loadSiteAssets подготавливает файлы сайта: считает хеши, сжимает текстовые файлы
+// и подставляет в страницы имена ресурсов с хешем. Страницы — шаблоны с функцией asset.
+// Копии *.br используются, только если precompressed: при чтении с диска они могут устареть.
+func loadSiteAssets(files fs.FS, precompressed bool) (*siteAssets, error) {
+	entries, err := fs.ReadDir(files, ".")
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения файлов сайта: %v", err)
+	}
+
+	assets := &siteAssets{
+		files: make(map[string]*siteAsset),
+		urls:  make(map[string]string),
+	}
+	var pages []string
+	for _, entry := range entries {
+		name := entry.Name()
+		ext := path.Ext(name)
+		if entry.IsDir() || strings.HasPrefix(name, ".") || siteAssetTypes[ext] == "" {
+			continue
+		}
+		if ext == ".html" {
+			pages = append(pages, name)
+			continue
+		}
+
+		content, err := fs.ReadFile(files, name)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка чтения файла сайта %s: %v", name, err)
+		}
+		asset, err := newSiteAsset(content, ext)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка сжатия файла сайта %s: %v", name, err)
+		}
+		if precompressed {
+			if brotli, err := fs.ReadFile(files, name+".br"); err == nil {
+				asset.brotli = brotli
+			}
+		}
+
+		hashedURL := "/" + strings.TrimSuffix(name, ext) + "." + asset.hash[:10] + ext
+		immutable := *asset
+		immutable.immutable = true
+		assets.files["/"+name] = asset
+		assets.files[hashedURL] = &immutable
+		assets.urls[name] = hashedURL
+	}
+
+	funcs := template.FuncMap{"asset": assets.url}
+	for _, name := range pages {
+		page, err := template.New(name).Funcs(funcs).ParseFS(files, name)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка разбора страницы %s: %v", name, err)
+		}
+		// Страница заказа отображается с данными заказа в orderPageHandler
+		if name == orderPageFile {
+			assets.orderPage = page
+			continue
+		}
+
+		var content bytes.Buffer
+		if err := page.Execute(&content, nil); err != nil {
+			return nil, fmt.Errorf("ошибка отображения страницы %s: %v", name, err)
+		}
+		asset, err := newSiteAsset(content.Bytes(), ".html")
+		if err != nil {
+			return nil, fmt.Errorf("ошибка сжатия страницы %s: %v", name, err)
+		}
+		assets.files["/"+name] = asset
+	}
+
+	if assets.files["/index.html"] == nil {
+		return nil, fmt.Errorf("не найдена главная страница сайта index.html")
+	}
+	if assets.orderPage == nil {
+		return nil, fmt.Errorf("не найдена страница заказа %s", orderPageFile)
+	}
+	return assets, nil
+}
+
+// newSiteAsset считает хеш содержимого и сжимает текстовые файлы
+func newSiteAsset(content []byte, ext string) (*siteAsset, error) {
+	sum := sha256.Sum256(content)
+	asset := &siteAsset{
+		content:     content,
+		contentType: siteAssetTypes[ext],
+		hash:        hex.EncodeToString(sum[:]),
+	}
+	if !siteCompressibleTypes[ext] {
+		return asset, nil
+	}
+
+	var compressed bytes.Buffer
+	writer, err := gzip.NewWriterLevel(&compressed, gzip.BestCompression)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := writer.Write(content); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	if compressed.Len() < len(content) {
+		asset.gzip = compressed.Bytes()
+	}
+	return asset, nil
+}
+
+// url возвращает путь ресурса с хешем содержимого для подстановки в страницы
+func (s *siteAssets) url(name string) (string, error) {
+	url, ok := s.urls[name]
+	if !ok {
+		return "", fmt.Errorf("неизвестный ресурс сайта %s", name)
+	}
+	return url, nil
+}
+
+// serve отдает файл сайта по пути запроса
+func (s *siteAssets) serve(w http.ResponseWriter, r *http.Request) {
+	urlPath := r.URL.Path
+	// Корневой путь -> index.html
+	if urlPath == "/" {
+		urlPath = "/index.html"
+	}
+
+	asset, ok := s.files[urlPath]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	asset.serve(w, r)
+}
+
+// serve отдает файл в лучшем сжатии, которое поддерживает браузер. У каждого сжатия свой ETag,
+// условные запросы (If-None-Match) и HEAD обрабатывает http.ServeContent.
+func (a *siteAsset) serve(w http.ResponseWriter, r *http.Request) {
+	body, encoding := a.content, ""
+	accept := r.Header.Get("Accept-Encoding")
+	switch {
+	case a.brotli != nil && acceptsEncoding(accept, "br"):
+		body, encoding = a.brotli, "br"
+	case a.gzip != nil && acceptsEncoding(accept, "gzip"):
+		body, encoding = a.gzip, "gzip"
+	}
+
+	header := w.Header()
+	header.Set("Content-Type", a.contentType)
+	if a.immutable {
+		header.Set("Cache-Control", siteImmutableCache)
+	} else {
+		header.Set("Cache-Control", siteRevalidateCache)
+	}
+	etag := a.hash[:20]
+	if a.gzip != nil || a.brotli != nil {
+		header.Add("Vary", "Accept-Encoding")
+	}
+	if encoding != "" {
+		header.Set("Content-Encoding", encoding)
+		etag += "-" + encoding
+	}
+	header.Set("ETag", `"`+etag+`"`)
+
+	http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(body))
+}
+
+// acceptsEncoding проверяет, что заголовок Accept-Encoding разрешает сжатие encoding
+func acceptsEncoding(header, encoding string) bool {
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if !strings.EqualFold(strings.TrimSpace(name), encoding) {
+			continue
+		}
+		// q=0 означает, что сжатие запрещено
+		q := strings.ReplaceAll(strings.TrimSpace(params), " ", "")
+		return q != "q=0" && q != "q=0.0" && q != "q=0.00" && q != "q=0.000"
+	}
+	return false
+}
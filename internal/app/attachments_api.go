@@ -0,0 +1,83 @@
+package app
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path"
+
+	"dalnoboy/internal/domain"
+	"dalnoboy/internal/service"
+)
+
+// attachmentResponse представляет вложение в ответе API со ссылкой на содержимое
+type attachmentResponse struct {
+	domain.Attachment
+	URL string `json:"url"`
+}
+
+// getOrderAttachmentsHandler возвращает вложения заказа: GET /v1/orders/{uuid}/attachments
+func (a *App) getOrderAttachmentsHandler(w http.ResponseWriter, r *http.Request) {
+	orderUUID := r.PathValue("uuid")
+	if _, err := a.OrderService.GetOrderByUUID(orderUUID); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	attachments, err := a.AttachmentService.GetAttachments(orderUUID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	// Подтверждение доставки (подписанные документы) доступно только администраторам
+	if !a.isAdminRequest(r) {
+		attachments = service.FilterAttachmentsByPurpose(attachments, domain.AttachmentPurposeCargo)
+	}
+
+	response := make([]attachmentResponse, len(attachments))
+	for i, attachment := range attachments {
+		response[i] = attachmentResponse{
+			Attachment: attachment,
+			URL:        fmt.Sprintf("/v1/orders/%s/attachments/%s", orderUUID, attachment.UUID),
+		}
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// getOrderAttachmentContentHandler отдает файл вложения: GET /v1/orders/{uuid}/attachments/{attachment_uuid}
+func (a *App) getOrderAttachmentContentHandler(w http.ResponseWriter, r *http.Request) {
+	attachment, err := a.AttachmentService.GetAttachment(r.PathValue("attachment_uuid"))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	if attachment.OrderUUID != r.PathValue("uuid") || (attachment.Purpose == domain.AttachmentPurposePOD && !a.isAdminRequest(r)) {
+		writeServiceError(w, service.ErrAttachmentNotFound)
+		return
+	}
+
+	content, err := a.AttachmentService.OpenAttachment(attachment)
+	if err != nil {
+		log.Printf("Ошибка открытия вложения %s: %v", attachment.UUID, err)
+		writeJSONError(w, http.StatusBadGateway, "Файл вложения временно недоступен")
+		return
+	}
+	defer content.Close()
+
+	contentType := "application/octet-stream"
+	if attachment.MimeType != nil {
+		contentType = *attachment.MimeType
+	}
+	name := attachment.UUID + ".jpg"
+	if attachment.FileName != nil {
+		name = path.Base(*attachment.FileName)
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=%q", name))
+	if _, err := io.Copy(w, content); err != nil {
+		log.Printf("Ошибка отправки вложения %s: %v", attachment.UUID, err)
+	}
+}
@@ -0,0 +1,46 @@
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"dalnoboy/internal/domain"
+)
+
+// CargoTypeRequest представляет тело запроса на создание типа груза
+type CargoTypeRequest struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+// getCargoTypesHandler возвращает справочник типов грузов: GET /v1/cargo-types.
+// Параметр all=true включает отключенные типы.
+func (a *App) getCargoTypesHandler(w http.ResponseWriter, r *http.Request) {
+	cargoTypes, err := a.CargoService.GetCargoTypes(r.URL.Query().Get("all") != "true")
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	if cargoTypes == nil {
+		cargoTypes = []domain.CargoType{}
+	}
+
+	writeJSON(w, http.StatusOK, cargoTypes)
+}
+
+// createCargoTypeHandler добавляет тип груза: POST /v1/cargo-types
+func (a *App) createCargoTypeHandler(w http.ResponseWriter, r *http.Request) {
+	var request CargoTypeRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "Некорректное тело запроса: "+err.Error())
+		return
+	}
+
+	cargoType, err := a.CargoService.CreateCargoType(request.Code, request.Name)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, cargoType)
+}
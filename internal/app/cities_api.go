@@ -0,0 +1,120 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"dalnoboy/internal/domain"
+	"dalnoboy/internal/service"
+)
+
+// getCitiesHandler возвращает справочник городов: GET /v1/cities
+func (a *App) getCitiesHandler(w http.ResponseWriter, r *http.Request) {
+	cities, err := a.CityService.GetAllCities()
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	if cities == nil {
+		cities = []domain.City{}
+	}
+
+	writeJSON(w, http.StatusOK, cities)
+}
+
+// resolveCityHandler распознает название города: GET /v1/cities/resolve?q=питер.
+// При неоднозначном совпадении возвращает 404 со списком подсказок.
+func (a *App) resolveCityHandler(w http.ResponseWriter, r *http.Request) {
+	city, err := a.CityService.ResolveCity(r.URL.Query().Get("q"))
+	if err != nil {
+		var cityErr *service.CityNotFoundError
+		if errors.As(err, &cityErr) {
+			suggestions := cityErr.Suggestions
+			if suggestions == nil {
+				suggestions = []domain.City{}
+			}
+			writeJSON(w, http.StatusNotFound, map[string]interface{}{
+				"error":       cityErr.Error(),
+				"suggestions": suggestions,
+			})
+			return
+		}
+		writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, city)
+}
+
+// createCityHandler добавляет город: POST /v1/cities
+func (a *App) createCityHandler(w http.ResponseWriter, r *http.Request) {
+	var request domain.CityRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "Некорректное тело запроса: "+err.Error())
+		return
+	}
+	if request.Name == nil {
+		writeJSONError(w, http.StatusBadRequest, "Поле name обязательно")
+		return
+	}
+
+	city, err := a.CityService.CreateCity(&request)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, city)
+}
+
+// updateCityHandler изменяет название, псевдонимы, регион или координаты города: PATCH /v1/cities/{uuid}
+func (a *App) updateCityHandler(w http.ResponseWriter, r *http.Request) {
+	var request domain.CityRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "Некорректное тело запроса: "+err.Error())
+		return
+	}
+
+	city, err := a.CityService.UpdateCity(r.PathValue("uuid"), &request)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, city)
+}
+
+// deleteCityHandler удаляет неиспользуемый город: DELETE /v1/cities/{uuid}
+func (a *App) deleteCityHandler(w http.ResponseWriter, r *http.Request) {
+	if _, err := a.CityService.DeleteCity(r.PathValue("uuid")); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// importCitiesHandler загружает встроенный справочник городов России и пересчитывает расстояния заказов:
+// POST /v1/cities/import[?overwrite=true]
+func (a *App) importCitiesHandler(w http.ResponseWriter, r *http.Request) {
+	overwrite := r.URL.Query().Get("overwrite") == "true"
+
+	result, err := a.CityService.ImportBundledCities(overwrite)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	// Координаты могли измениться — пересчитываем расстояния маршрутов
+	recalculated, err := a.OrderService.RecalculateDistances()
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"cities":                  result,
+		"orders_distance_updated": recalculated,
+	})
+}
@@ -0,0 +1,60 @@
+package app
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"dalnoboy/internal/domain"
+	"dalnoboy/internal/service"
+)
+
+// deliveriesDefaultPeriod — период выгрузки доставок без параметра from
+const deliveriesDefaultPeriod = 30 * 24 * time.Hour
+
+// getDeliveriesHandler возвращает доставленные заказы: GET /v1/deliveries?from=2025-01-01&to=2025-01-31.
+// Даты включительные; format=csv отдает сводку в CSV.
+func (a *App) getDeliveriesHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	to := time.Now()
+	from := to.Add(-deliveriesDefaultPeriod)
+	if value := query.Get("from"); value != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", value, time.Local)
+		if err != nil {
+			writeJSONError(w, http.StatusBadRequest, "Некорректный параметр from, ожидается ГГГГ-ММ-ДД")
+			return
+		}
+		from = parsed
+	}
+	if value := query.Get("to"); value != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", value, time.Local)
+		if err != nil {
+			writeJSONError(w, http.StatusBadRequest, "Некорректный параметр to, ожидается ГГГГ-ММ-ДД")
+			return
+		}
+		to = parsed.AddDate(0, 0, 1)
+	}
+
+	deliveries, err := a.DeliveryService.GetDeliveries(from, to)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	if query.Get("format") == "csv" {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q",
+			fmt.Sprintf("pod_%s_%s.csv", from.Format("20060102"), to.Add(-time.Second).Format("20060102"))))
+		if err := service.WriteDeliveriesCSV(w, deliveries, domain.AdminViewer()); err != nil {
+			log.Printf("Ошибка выгрузки доставок в CSV: %v", err)
+		}
+		return
+	}
+
+	if deliveries == nil {
+		deliveries = []domain.Delivery{}
+	}
+	writeJSON(w, http.StatusOK, deliveries)
+}
@@ -0,0 +1,70 @@
+package app
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"dalnoboy/internal/service"
+)
+
+// downloadWriter задает заголовки файла при первой записи: пока строки не начали
+// отправляться, ошибку можно вернуть обычным JSON ответом
+type downloadWriter struct {
+	w        http.ResponseWriter
+	format   string
+	fileName string
+	started  bool
+}
+
+func (d *downloadWriter) Write(p []byte) (int, error) {
+	if !d.started {
+		d.started = true
+		d.w.Header().Set("Content-Type", service.ExportContentType(d.format))
+		d.w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", d.fileName))
+	}
+	return d.w.Write(p)
+}
+
+// getExportHandler выгружает заказы, заказчиков или водителей: GET /v1/exports/{kind}?format=csv|xlsx.
+// Выгрузка заказов принимает те же фильтры, что и GET /v1/orders, а также status, from и to.
+func (a *App) getExportHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	kind := r.PathValue("kind")
+
+	format, err := service.ParseExportFormat(query.Get("format"))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	download := &downloadWriter{w: w, format: format, fileName: service.ExportFileName(kind, format, time.Now())}
+	var export func(io.Writer) (int, error)
+	switch kind {
+	case service.ExportOrders:
+		filter, err := service.ParseOrderFilter(query)
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+		export = func(w io.Writer) (int, error) { return a.ExportService.ExportOrders(w, format, filter) }
+	case service.ExportCustomers:
+		export = func(w io.Writer) (int, error) { return a.ExportService.ExportCustomers(w, format) }
+	case service.ExportDrivers:
+		export = func(w io.Writer) (int, error) { return a.ExportService.ExportDrivers(w, format) }
+	default:
+		writeJSONError(w, http.StatusNotFound, "Неизвестная выгрузка: доступны orders, customers, drivers")
+		return
+	}
+
+	if _, err := export(download); err != nil {
+		if !download.started {
+			writeServiceError(w, err)
+			return
+		}
+		// Файл уже частично отправлен: клиент получит оборванную выгрузку
+		log.Printf("Ошибка выгрузки %s: %v", kind, err)
+	}
+}
@@ -0,0 +1,77 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"dalnoboy/internal/service"
+)
+
+// orderEventsHeartbeat — период комментариев-пингов, чтобы прокси не закрывали молчащее соединение
+const orderEventsHeartbeat = 25 * time.Second
+
+// orderEventsHandler отдает ленту изменений заказов в формате Server-Sent Events: GET /v1/orders/events.
+// События содержат только тип, UUID и статус заказа — сами заказы клиент запрашивает через /v1/orders.
+func (a *App) orderEventsHandler(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeJSONError(w, http.StatusInternalServerError, "Потоковая передача не поддерживается")
+		return
+	}
+
+	events, unsubscribe, err := a.OrderEvents.Subscribe()
+	if err != nil {
+		if errors.Is(err, service.ErrTooManySubscribers) || errors.Is(err, service.ErrOrderEventsStopped) {
+			w.Header().Set("Retry-After", "30")
+			writeJSONError(w, http.StatusServiceUnavailable, err.Error())
+			return
+		}
+		writeServiceError(w, err)
+		return
+	}
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.WriteHeader(http.StatusOK)
+
+	// Браузер переподключится через 5 секунд, если соединение оборвется
+	fmt.Fprint(w, "retry: 5000\n\n")
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(orderEventsHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		case event, ok := <-events:
+			if !ok {
+				// Приложение останавливается
+				return
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				log.Printf("Ошибка кодирования события заказа: %v", err)
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
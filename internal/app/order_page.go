@@ -0,0 +1,69 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"dalnoboy/internal/domain"
+	"dalnoboy/internal/service"
+)
+
+// orderPageFile — шаблон страницы заказа среди файлов сайта. Данные заказа страница загружает
+// через /v1/orders/{uuid}, а сервер подставляет только заголовок и описание для превью ссылки.
+const orderPageFile = "order.html"
+
+// orderPageData — данные шаблона страницы заказа
+type orderPageData struct {
+	UUID        string
+	Title       string
+	Description string
+	BotLink     string
+	NotFound    bool
+}
+
+// orderPageHandler показывает страницу заказа по постоянной ссылке: GET /orders/{uuid}
+func (a *App) orderPageHandler(w http.ResponseWriter, r *http.Request) {
+	status := http.StatusOK
+	data := &orderPageData{UUID: r.PathValue("uuid")}
+
+	order, err := a.OrderService.GetOrderByUUID(data.UUID)
+	switch {
+	case err == nil:
+		data.Title = order.Title
+		data.Description = orderPageDescription(order)
+		data.BotLink = a.orderBotLink(order.UUID)
+	case errors.Is(err, service.ErrOrderNotFound), service.IsValidationError(err):
+		status = http.StatusNotFound
+		data.NotFound = true
+		data.Title = "Заказ не найден"
+	default:
+		log.Printf("Ошибка получения заказа %s для страницы: %v", data.UUID, err)
+		http.Error(w, "Внутренняя ошибка сервера", http.StatusInternalServerError)
+		return
+	}
+
+	assets, err := a.currentSiteAssets()
+	if err != nil {
+		log.Printf("Ошибка загрузки файлов сайта: %v", err)
+		http.Error(w, "Внутренняя ошибка сервера", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", siteRevalidateCache)
+	w.WriteHeader(status)
+	if err := assets.orderPage.Execute(w, data); err != nil {
+		log.Printf("Ошибка отображения страницы заказа: %v", err)
+	}
+}
+
+// orderPageDescription кратко описывает заказ для превью ссылки: маршрут, вес и цена
+func orderPageDescription(order *domain.Order) string {
+	description := fmt.Sprintf("%.0f кг, %.0f ₽", order.WeightKg, order.Price)
+	if order.FromCityName != nil && order.ToCityName != nil {
+		description = fmt.Sprintf("%s → %s, %s", *order.FromCityName, *order.ToCityName, description)
+	}
+	return description
+}
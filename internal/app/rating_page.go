@@ -0,0 +1,150 @@
+package app
+
+import (
+	"errors"
+	"html/template"
+	"log"
+	"net/http"
+	"strconv"
+
+	"dalnoboy/internal/domain"
+	"dalnoboy/internal/service"
+)
+
+// ratingPageTemplate — страница оценки водителя по подписанной ссылке из бота
+var ratingPageTemplate = template.Must(template.New("rate").Parse(`<!DOCTYPE html>
+<html lang="ru">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>Оценка водителя</title>
+    <link rel="stylesheet" href="/style.css">
+</head>
+<body>
+    <header>
+        <h1>⭐ Оценка водителя</h1>
+    </header>
+    <main>
+        {{if .Error}}<div class="error">⚠️ {{.Error}}</div>{{end}}
+        {{if .Done}}
+        <div class="order-card">Спасибо! Ваша оценка {{.Score}} из 5 сохранена.</div>
+        {{else if .Order}}
+        <form class="order-card" method="post" action="/rate">
+            <div class="order-title">Заказ #{{.ShortID}} «{{.Order.Title}}»</div>
+            <div class="order-details">
+                <div class="order-detail"><strong>Водитель:</strong> {{.DriverName}}</div>
+            </div>
+            <input type="hidden" name="order" value="{{.Order.UUID}}">
+            <input type="hidden" name="sig" value="{{.Signature}}">
+            <p>
+                {{range .Scores}}<label><input type="radio" name="score" value="{{.}}" {{if eq . $.Score}}checked{{end}} required> {{.}}</label>
+                {{end}}
+            </p>
+            <p><textarea name="comment" rows="4" maxlength="1000" placeholder="Комментарий (необязательно)" style="width: 100%"></textarea></p>
+            <p><button type="submit">Отправить</button></p>
+        </form>
+        {{end}}
+    </main>
+</body>
+</html>
+`))
+
+// ratingPageData — данные страницы оценки
+type ratingPageData struct {
+	Order      *domain.Order
+	ShortID    string
+	DriverName string
+	Signature  string
+	Scores     []int
+	Score      int
+	Done       bool
+	Error      string
+}
+
+// ratingPageHandler показывает форму оценки водителя: GET /rate?order=<UUID>&sig=<подпись>
+func (a *App) ratingPageHandler(w http.ResponseWriter, r *http.Request) {
+	orderUUID := r.URL.Query().Get("order")
+	signature := r.URL.Query().Get("sig")
+	score, _ := strconv.Atoi(r.URL.Query().Get("score"))
+
+	data, status := a.ratingPageData(orderUUID, signature)
+	data.Score = score
+	renderRatingPage(w, status, data)
+}
+
+// submitRatingHandler сохраняет оценку водителя из формы: POST /rate
+func (a *App) submitRatingHandler(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		renderRatingPage(w, http.StatusBadRequest, &ratingPageData{Error: "Некорректные данные формы"})
+		return
+	}
+
+	orderUUID := r.PostForm.Get("order")
+	signature := r.PostForm.Get("sig")
+	data, status := a.ratingPageData(orderUUID, signature)
+	if data.Order == nil {
+		renderRatingPage(w, status, data)
+		return
+	}
+
+	score, _ := strconv.Atoi(r.PostForm.Get("score"))
+	rating, err := a.RatingService.RateDriver(&domain.RateDriverRequest{
+		OrderUUID: orderUUID,
+		Score:     score,
+		Comment:   r.PostForm.Get("comment"),
+		Source:    domain.RatingSourceWeb,
+	})
+	if err != nil {
+		status = http.StatusInternalServerError
+		data.Error = "Не удалось сохранить оценку, попробуйте позже"
+		if service.IsValidationError(err) {
+			status = http.StatusBadRequest
+			data.Error = err.Error()
+		} else {
+			log.Printf("Ошибка сохранения оценки по заказу %s: %v", orderUUID, err)
+		}
+		renderRatingPage(w, status, data)
+		return
+	}
+
+	renderRatingPage(w, http.StatusOK, &ratingPageData{Done: true, Score: rating.Score})
+}
+
+// ratingPageData проверяет подпись ссылки и загружает заказ для страницы оценки
+func (a *App) ratingPageData(orderUUID, signature string) (*ratingPageData, int) {
+	if !a.RatingService.VerifyLink(orderUUID, signature) {
+		return &ratingPageData{Error: "Ссылка недействительна"}, http.StatusForbidden
+	}
+
+	order, err := a.RatingService.GetRatableOrder(orderUUID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrOrderNotFound):
+			return &ratingPageData{Error: "Заказ не найден"}, http.StatusNotFound
+		case service.IsValidationError(err):
+			return &ratingPageData{Error: err.Error()}, http.StatusBadRequest
+		}
+		log.Printf("Ошибка получения заказа %s для оценки: %v", orderUUID, err)
+		return &ratingPageData{Error: "Внутренняя ошибка сервера"}, http.StatusInternalServerError
+	}
+
+	data := &ratingPageData{
+		Order:     order,
+		ShortID:   order.UUID[:8],
+		Signature: signature,
+		Scores:    []int{1, 2, 3, 4, 5},
+	}
+	if order.AssignedDriverName != nil {
+		data.DriverName = *order.AssignedDriverName
+	}
+	return data, http.StatusOK
+}
+
+// renderRatingPage отображает страницу оценки
+func renderRatingPage(w http.ResponseWriter, status int, data *ratingPageData) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	if err := ratingPageTemplate.Execute(w, data); err != nil {
+		log.Printf("Ошибка отображения страницы оценки: %v", err)
+	}
+}
@@ -0,0 +1,230 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"dalnoboy/internal/service"
+)
+
+// expiryLeaseKey — ключ аренды в кеше: проверку истечения заказов за один интервал
+// выполняет только тот экземпляр приложения, который первым занял ключ
+const expiryLeaseKey = "dalnoboy:lease:order-expiry"
+
+// digestLeaseKey — ключ аренды рассылки ежедневных сводок водителям
+const digestLeaseKey = "dalnoboy:lease:driver-digest"
+
+// deferredLeaseKey — ключ аренды отправки отложенных уведомлений водителям
+const deferredLeaseKey = "dalnoboy:lease:deferred-notifications"
+
+// weeklySummaryKeyPrefix — префикс ключа в кеше, которым отмечается отправленная еженедельная сводка
+const weeklySummaryKeyPrefix = "dalnoboy:analytics:weekly-summary:"
+
+// weeklySummaryLeaseSuffix — суффикс ключа аренды отправки сводки: сводку отправляет только тот
+// экземпляр приложения, который первым занял аренду
+const weeklySummaryLeaseSuffix = ":lease"
+
+// instanceID возвращает идентификатор экземпляра приложения для значения аренды
+func instanceID() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	return fmt.Sprintf("%s:%d", hostname, os.Getpid())
+}
+
+// runExpiryScheduler периодически снимает устаревшие заказы до отмены контекста
+func (a *App) runExpiryScheduler(ctx context.Context) {
+	interval := a.Config.Expiry.CheckInterval
+	log.Printf("⏰ Планировщик истечения заказов запущен (интервал %s)", interval)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	owner := instanceID()
+	for {
+		a.runExpiryCheck(ctx, owner, interval)
+
+		select {
+		case <-ctx.Done():
+			log.Printf("⏰ Планировщик истечения заказов остановлен")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runExpiryCheck выполняет одну проверку, если удалось занять аренду.
+// Аренда живет чуть меньше интервала, чтобы на следующем тике ее мог занять любой экземпляр.
+func (a *App) runExpiryCheck(ctx context.Context, owner string, interval time.Duration) {
+	acquired, err := a.Cache.SetNX(ctx, expiryLeaseKey, owner, interval*9/10)
+	if err != nil {
+		log.Printf("Ошибка получения аренды планировщика истечения заказов: %v", err)
+		return
+	}
+	if !acquired {
+		return // Проверку в этом интервале выполняет другой экземпляр
+	}
+
+	result, err := a.ExpiryService.Run(time.Now())
+	if err != nil {
+		log.Printf("Ошибка проверки истечения заказов: %v", err)
+	}
+	if result != nil && (result.Expired > 0 || result.Warned > 0) {
+		log.Printf("⏰ Истечение заказов: снято %d, предупреждений %d", result.Expired, result.Warned)
+	}
+}
+
+// runDigestScheduler периодически отправляет водителям ежедневные сводки до отмены контекста
+func (a *App) runDigestScheduler(ctx context.Context) {
+	interval := a.Config.Digest.CheckInterval
+	log.Printf("🗞 Планировщик сводок для водителей запущен (интервал %s)", interval)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	owner := instanceID()
+	for {
+		a.runDigestCheck(ctx, owner, interval)
+
+		select {
+		case <-ctx.Done():
+			log.Printf("🗞 Планировщик сводок для водителей остановлен")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runDigestCheck отправляет сводки, если удалось занять аренду интервала
+func (a *App) runDigestCheck(ctx context.Context, owner string, interval time.Duration) {
+	acquired, err := a.Cache.SetNX(ctx, digestLeaseKey, owner, interval*9/10)
+	if err != nil {
+		log.Printf("Ошибка получения аренды планировщика сводок: %v", err)
+		return
+	}
+	if !acquired {
+		return // Сводки в этом интервале отправляет другой экземпляр
+	}
+
+	sent, err := a.DigestService.Run(time.Now())
+	if err != nil {
+		log.Printf("Ошибка отправки сводок водителям: %v", err)
+	}
+	if sent > 0 {
+		log.Printf("🗞 Отправлено сводок водителям: %d", sent)
+	}
+}
+
+// runDeferredScheduler периодически отправляет отложенные уведомления водителям до отмены контекста
+func (a *App) runDeferredScheduler(ctx context.Context) {
+	interval := a.Config.QuietHours.CheckInterval
+	log.Printf("🌙 Планировщик отложенных уведомлений запущен (интервал %s)", interval)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	owner := instanceID()
+	for {
+		a.runDeferredCheck(ctx, owner, interval)
+
+		select {
+		case <-ctx.Done():
+			log.Printf("🌙 Планировщик отложенных уведомлений остановлен")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runDeferredCheck отправляет отложенные уведомления, если удалось занять аренду интервала
+func (a *App) runDeferredCheck(ctx context.Context, owner string, interval time.Duration) {
+	acquired, err := a.Cache.SetNX(ctx, deferredLeaseKey, owner, interval*9/10)
+	if err != nil {
+		log.Printf("Ошибка получения аренды планировщика отложенных уведомлений: %v", err)
+		return
+	}
+	if !acquired {
+		return // Уведомления в этом интервале отправляет другой экземпляр
+	}
+
+	sent, err := a.NotificationService.SendDeferred(time.Now())
+	if err != nil {
+		log.Printf("Ошибка отправки отложенных уведомлений: %v", err)
+	}
+	if sent > 0 {
+		log.Printf("🌙 Отправлено отложенных уведомлений: %d", sent)
+	}
+}
+
+// runAnalyticsScheduler отправляет администраторам еженедельную сводку аналитики до отмены контекста
+func (a *App) runAnalyticsScheduler(ctx context.Context) {
+	config := a.Config.Analytics
+	log.Printf("📊 Планировщик еженедельной сводки запущен (%s, %02d:00)", config.SummaryWeekday, config.SummaryHour)
+
+	ticker := time.NewTicker(config.CheckInterval)
+	defer ticker.Stop()
+
+	owner := instanceID()
+	for {
+		a.runWeeklySummaryCheck(ctx, owner)
+
+		select {
+		case <-ctx.Done():
+			log.Printf("📊 Планировщик еженедельной сводки остановлен")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runWeeklySummaryCheck отправляет сводку, если наступили день и час сводки и за эту неделю
+// ее еще никто не отправил. Если приложение было остановлено в час сводки, она уйдет после запуска в тот же день.
+// Отметка об отправке ставится только после успешной отправки, иначе сводка повторится на следующем тике.
+func (a *App) runWeeklySummaryCheck(ctx context.Context, owner string) {
+	config := a.Config.Analytics
+	now := time.Now()
+	if now.Weekday() != config.Weekday() || now.Hour() < config.SummaryHour {
+		return
+	}
+
+	period := service.WeeklyPeriod(now)
+	sentKey := weeklySummaryKeyPrefix + period.From.Format("2006-01-02")
+	alreadySent, err := a.Cache.Exists(ctx, sentKey)
+	if err != nil {
+		log.Printf("Ошибка проверки отметки еженедельной сводки: %v", err)
+		return
+	}
+	if alreadySent {
+		return // Сводку за эту неделю уже отправили
+	}
+
+	leaseKey := sentKey + weeklySummaryLeaseSuffix
+	acquired, err := a.Cache.SetNX(ctx, leaseKey, owner, config.CheckInterval*9/10)
+	if err != nil {
+		log.Printf("Ошибка получения аренды еженедельной сводки: %v", err)
+		return
+	}
+	if !acquired {
+		return // Сводку в этом интервале отправляет другой экземпляр
+	}
+
+	sent, err := a.AnalyticsService.SendWeeklySummary(now)
+	if err != nil || sent == 0 {
+		if err != nil {
+			log.Printf("Ошибка отправки еженедельной сводки: %v", err)
+		}
+		if err := a.Cache.Delete(ctx, leaseKey); err != nil {
+			log.Printf("Ошибка снятия аренды еженедельной сводки: %v", err)
+		}
+		return
+	}
+
+	log.Printf("📊 Еженедельная сводка отправлена в чатов: %d", sent)
+	if err := a.Cache.Set(ctx, sentKey, owner, 8*24*time.Hour); err != nil {
+		log.Printf("Ошибка отметки еженедельной сводки: %v", err)
+	}
+}
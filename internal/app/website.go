@@ -2,47 +2,39 @@ package app
 
 import (
 	"dalnoboy/internal/domain"
+	"dalnoboy/internal/service"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
-	"path/filepath"
-	"strconv"
 	"strings"
 )
 
-// WebsiteHandler обрабатывает статические файлы сайта
+// staticHandler отдает файлы сайта: встроенные в бинарный файл или, если задан website.dir, с диска
 func (a *App) staticHandler(w http.ResponseWriter, r *http.Request) {
-	path := r.URL.Path
-
-	// Корневой путь -> index.html
-	if path == "/" {
-		path = "/index.html"
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "Метод не поддерживается", http.StatusMethodNotAllowed)
+		return
 	}
 
-	// Убираем начальный слеш
-	filePath := strings.TrimPrefix(path, "/")
-
-	// Проверяем что файл существует
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
-		http.NotFound(w, r)
+	assets, err := a.currentSiteAssets()
+	if err != nil {
+		log.Printf("Ошибка загрузки файлов сайта: %v", err)
+		http.Error(w, "Внутренняя ошибка сервера", http.StatusInternalServerError)
 		return
 	}
+	assets.serve(w, r)
+}
 
-	// Определяем Content-Type по расширению
-	switch filepath.Ext(filePath) {
-	case ".html":
-		w.Header().Set("Content-Type", "text/html; charset=utf-8")
-	case ".css":
-		w.Header().Set("Content-Type", "text/css")
-	case ".js":
-		w.Header().Set("Content-Type", "application/javascript")
-	default:
-		w.Header().Set("Content-Type", "text/plain")
+// currentSiteAssets возвращает файлы сайта. При разработке (website.dir) файлы перечитываются
+// с диска при каждом запросе, чтобы правки были видны без пересборки.
+func (a *App) currentSiteAssets() (*siteAssets, error) {
+	if a.Config != nil && a.Config.Website.Dir != "" {
+		return loadSiteAssets(os.DirFS(a.Config.Website.Dir), false)
 	}
-
-	http.ServeFile(w, r, filePath)
+	return a.siteAssets, nil
 }
 
 // getOrdersHandler обрабатывает запросы на получение всех заказов
@@ -52,124 +44,140 @@ func (a *App) getOrdersHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Получаем query параметры для фильтрации по весу
-	queryParams := r.URL.Query()
-	minWeightStr := queryParams.Get("min_weight")
-	maxWeightStr := queryParams.Get("max_weight")
-
-	var minWeight, maxWeight *float64
-	var err error
+	// Фильтры: вес (min_weight, max_weight), расстояние (near_city, radius_km, direction),
+	// груз (cargo_type, requirements), ставка за километр, статус, дата создания, поиск (q) и сортировка
+	filter, err := service.ParseOrderFilter(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
-	// Парсим минимальный вес
-	if minWeightStr != "" {
-		var weight float64
-		weight, err = strconv.ParseFloat(minWeightStr, 64)
-		if err != nil {
-			http.Error(w, "Некорректный параметр min_weight", http.StatusBadRequest)
+	orders, err := a.OrderService.ListOrders(filter)
+	if err != nil {
+		if service.IsValidationError(err) || errors.Is(err, service.ErrCityNotFound) || errors.Is(err, service.ErrCargoTypeNotFound) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
-		minWeight = &weight
+		log.Printf("Ошибка получения заказов: %v", err)
+		http.Error(w, "Внутренняя ошибка сервера", http.StatusInternalServerError)
+		return
 	}
 
-	// Парсим максимальный вес
-	if maxWeightStr != "" {
-		var weight float64
-		weight, err = strconv.ParseFloat(maxWeightStr, 64)
-		if err != nil {
-			http.Error(w, "Некорректный параметр max_weight", http.StatusBadRequest)
-			return
-		}
-		maxWeight = &weight
+	// Телефоны заказчиков видны только с ключом администратора API
+	viewer := domain.ContactViewer{Admin: a.isAdminRequest(r)}
+
+	// Преобразуем domain.Order в формат для фронтенда (как у бота)
+	response := make([]map[string]interface{}, len(orders))
+	for i := range orders {
+		response[i] = a.webOrder(&orders[i], viewer)
 	}
 
-	var orders []domain.Order
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
 
-	// Если указаны параметры веса, используем фильтрацию
-	if minWeight != nil || maxWeight != nil {
-		orders, err = a.OrderService.GetOrdersByWeightRange(minWeight, maxWeight)
-	} else {
-		// Иначе получаем все заказы
-		orders, err = a.OrderService.GetAllOrders()
+// webOrder преобразует заказ в формат для сайта
+func (a *App) webOrder(order *domain.Order, viewer domain.ContactViewer) map[string]interface{} {
+	// Форматируем дату
+	dateStr := "Не указана"
+	if order.AvailableFrom != nil {
+		dateStr = order.AvailableFrom.Format("02.01.2006")
 	}
 
-	if err != nil {
-		log.Printf("Ошибка получения заказов: %v", err)
-		http.Error(w, "Внутренняя ошибка сервера", http.StatusInternalServerError)
-		return
+	// Форматируем размеры
+	dimensions := "Не указаны"
+	if order.LengthCm != nil && order.WidthCm != nil && order.HeightCm != nil {
+		dimensions = fmt.Sprintf("%.0f×%.0f×%.0f см", *order.LengthCm, *order.WidthCm, *order.HeightCm)
 	}
 
-	// Преобразуем domain.Order в формат для фронтенда (как у бота)
-	response := make([]map[string]interface{}, len(orders))
-	for i, order := range orders {
-		// Форматируем дату
-		dateStr := "Не указана"
-		if order.AvailableFrom != nil {
-			dateStr = order.AvailableFrom.Format("02.01.2006")
+	// Форматируем локации для межгородских перевозок
+	fromLoc := "Не указано"
+	toLoc := "Не указано"
+
+	if order.FromCityName != nil && order.ToCityName != nil {
+		// Основной маршрут между городами
+		fromLoc = fmt.Sprintf("%s → %s", *order.FromCityName, *order.ToCityName)
+
+		// Адреса в одной строке
+		if order.FromAddress != nil && order.ToAddress != nil {
+			toLoc = fmt.Sprintf("%s: %s | %s: %s",
+				*order.FromCityName, *order.FromAddress,
+				*order.ToCityName, *order.ToAddress)
+		} else if order.FromAddress != nil {
+			toLoc = fmt.Sprintf("%s: %s", *order.FromCityName, *order.FromAddress)
+		} else if order.ToAddress != nil {
+			toLoc = fmt.Sprintf("%s: %s", *order.ToCityName, *order.ToAddress)
+		} else {
+			toLoc = "Адреса не указаны"
 		}
-
-		// Форматируем размеры
-		dimensions := "Не указаны"
-		if order.LengthCm != nil && order.WidthCm != nil && order.HeightCm != nil {
-			dimensions = fmt.Sprintf("%.0f×%.0f×%.0f см", *order.LengthCm, *order.WidthCm, *order.HeightCm)
+	} else if order.FromCityName != nil {
+		fromLoc = *order.FromCityName
+		if order.FromAddress != nil {
+			toLoc = fmt.Sprintf("Адрес: %s", *order.FromAddress)
 		}
-
-		// Форматируем локации для межгородских перевозок
-		fromLoc := "Не указано"
-		toLoc := "Не указано"
-
-		if order.FromCityName != nil && order.ToCityName != nil {
-			// Основной маршрут между городами
-			fromLoc = fmt.Sprintf("%s → %s", *order.FromCityName, *order.ToCityName)
-
-			// Адреса в одной строке
-			if order.FromAddress != nil && order.ToAddress != nil {
-				toLoc = fmt.Sprintf("%s: %s | %s: %s",
-					*order.FromCityName, *order.FromAddress,
-					*order.ToCityName, *order.ToAddress)
-			} else if order.FromAddress != nil {
-				toLoc = fmt.Sprintf("%s: %s", *order.FromCityName, *order.FromAddress)
-			} else if order.ToAddress != nil {
-				toLoc = fmt.Sprintf("%s: %s", *order.ToCityName, *order.ToAddress)
-			} else {
-				toLoc = "Адреса не указаны"
-			}
-		} else if order.FromCityName != nil {
-			fromLoc = *order.FromCityName
-			if order.FromAddress != nil {
-				toLoc = fmt.Sprintf("Адрес: %s", *order.FromAddress)
-			}
-		} else if order.ToCityName != nil {
-			toLoc = *order.ToCityName
-			if order.ToAddress != nil {
-				fromLoc = fmt.Sprintf("Адрес: %s", *order.ToAddress)
-			}
+	} else if order.ToCityName != nil {
+		toLoc = *order.ToCityName
+		if order.ToAddress != nil {
+			fromLoc = fmt.Sprintf("Адрес: %s", *order.ToAddress)
 		}
+	}
 
-		// Форматируем теги
-		tagsStr := "Нет тегов"
-		if len(order.Tags) > 0 {
-			tagsStr = strings.Join(order.Tags, ", ")
-		}
+	// Форматируем теги
+	tagsStr := "Нет тегов"
+	if len(order.Tags) > 0 {
+		tagsStr = strings.Join(order.Tags, ", ")
+	}
 
-		response[i] = map[string]interface{}{
-			"id":          order.UUID[:8],
-			"title":       order.Title,
-			"description": order.Description,
-			"customer":    order.CustomerName,
-			"phone":       order.CustomerPhone,
-			"from":        fromLoc,
-			"to":          toLoc,
-			"weight":      order.WeightKg,
-			"dimensions":  dimensions,
-			"tags":        tagsStr,
-			"price":       order.Price,
-			"date":        dateStr,
-			"uuid":        order.UUID,
-		}
+	result := map[string]interface{}{
+		"id":           order.UUID[:8],
+		"title":        order.Title,
+		"description":  order.Description,
+		"customer":     order.CustomerName,
+		"phone":        service.CustomerPhoneFor(order, viewer),
+		"phone_hidden": !service.CanViewCustomerContacts(order, viewer),
+		"from":         fromLoc,
+		"to":           toLoc,
+		"from_city":    order.FromCityName,
+		"to_city":      order.ToCityName,
+		"weight":       order.WeightKg,
+		"dimensions":   dimensions,
+		"tags":         tagsStr,
+		"price":        order.Price,
+		"date":         dateStr,
+		"uuid":         order.UUID,
+		"status":       order.Status,
+		"status_name":  domain.OrderStatusNames[order.Status],
+		"created_at":   order.CreatedAt,
+		"distance_km":  order.DistanceKm,
+		"price_per_km": order.PricePerKm(),
+		"cargo_type":   order.CargoTypeName,
+		"requirements": order.Requirements,
+		"temperature":  service.FormatTemperatureRange(order.Requirements.TemperatureMinC, order.Requirements.TemperatureMaxC),
+		"attachments":  order.AttachmentsCount,
+		"url":          "/orders/" + order.UUID,
 	}
+	if link := a.orderBotLink(order.UUID); link != "" {
+		result["bot_link"] = link
+	}
+	return result
+}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.Header().Set("Access-Control-Allow-Origin", "*")
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(response)
+// orderBotLink возвращает ссылку, открывающую заказ в боте для водителей
+func (a *App) orderBotLink(orderUUID string) string {
+	if a.DriverBot == nil || a.DriverBot.Username() == "" {
+		return ""
+	}
+	return fmt.Sprintf("https://t.me/%s?start=order_%s", a.DriverBot.Username(), orderUUID)
+}
+
+// getOrderHandler возвращает один заказ в любом статусе: GET /v1/orders/{uuid}
+func (a *App) getOrderHandler(w http.ResponseWriter, r *http.Request) {
+	order, err := a.OrderService.GetOrderByUUID(r.PathValue("uuid"))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, a.webOrder(order, domain.ContactViewer{Admin: a.isAdminRequest(r)}))
 }
@@ -0,0 +1,153 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"dalnoboy/internal/domain"
+	"dalnoboy/internal/service"
+)
+
+// analyticsHelp описывает команду сводки аналитики
+const analyticsHelp = `📊 Аналитика
+
+Сводка за период (даты включительно, по умолчанию — последние 30 дней):
+ANALYTICS [ГГГГ-ММ-ДД] [ГГГГ-ММ-ДД]
+
+Графики по дням — на странице /admin.html сайта (нужен ключ администратора API).
+Раз в неделю администраторы получают сводку за прошедшие семь дней (день и час — в настройках analytics).`
+
+// handleAnalyticsCommand обрабатывает команду ANALYTICS. Возвращает false, если текст не является этой командой.
+func (ab *AdminBot) handleAnalyticsCommand(chatID int64, text string) (string, bool) {
+	fields := strings.Fields(text)
+	if len(fields) == 0 || fields[0] != "ANALYTICS" {
+		return "", false
+	}
+	// Сводка содержит выручку и активность водителей, поэтому доступна только чатам администраторов
+	if !ab.isAdminChat(chatID) {
+		return "❌ Аналитика доступна только администраторам", true
+	}
+	if len(fields) > 3 {
+		return analyticsHelp, true
+	}
+
+	var from, to string
+	if len(fields) > 1 {
+		from = fields[1]
+	}
+	if len(fields) > 2 {
+		to = fields[2]
+	}
+	period, err := service.ParseAnalyticsPeriod(from, to, time.Now())
+	if err != nil {
+		return fmt.Sprintf("❌ %v\n\n%s", err, analyticsHelp), true
+	}
+
+	summary, err := ab.analyticsService.GetSummary(period)
+	if err != nil {
+		log.Printf("Ошибка получения аналитики: %v", err)
+		return "❌ Ошибка получения аналитики", true
+	}
+	return formatAnalyticsSummary("📊 Аналитика", summary), true
+}
+
+// SendAnalyticsSummary отправляет администратору еженедельную сводку аналитики
+func (ab *AdminBot) SendAnalyticsSummary(chatID int64, summary *domain.AnalyticsSummary) error {
+	return ab.sendText(chatID, formatAnalyticsSummary("📊 Сводка за неделю", summary))
+}
+
+// formatAnalyticsSummary форматирует сводку аналитики для сообщения в Telegram
+func formatAnalyticsSummary(title string, summary *domain.AnalyticsSummary) string {
+	var text strings.Builder
+	lastDay := summary.Period.To.AddDate(0, 0, -1)
+	text.WriteString(fmt.Sprintf("%s: %s — %s\n\n", title,
+		summary.Period.From.Format("02.01.2006"), lastDay.Format("02.01.2006")))
+
+	text.WriteString(fmt.Sprintf("📋 Создано заказов: %d\n🚚 Назначено водителям: %d\n✅ Доставлено: %d\n",
+		summary.Created, summary.Assigned, summary.Delivered))
+
+	assignment := summary.Assignment
+	if assignment.MedianHours != nil {
+		text.WriteString(fmt.Sprintf("⏱ Медиана до назначения: %s (по %d заказам)\n",
+			formatHours(*assignment.MedianHours), assignment.Orders))
+	}
+	if assignment.Conversion != nil {
+		text.WriteString(fmt.Sprintf("🎯 Нашли водителя: %.0f%% созданных заказов\n", *assignment.Conversion*100))
+	}
+
+	prices := summary.Prices
+	if prices.AvgPrice != nil {
+		text.WriteString(fmt.Sprintf("\n💰 Средняя цена заказа: %.0f ₽\n", *prices.AvgPrice))
+	}
+	if prices.AvgPricePerKg != nil {
+		text.WriteString(fmt.Sprintf("⚖️ Средняя цена за кг: %.2f ₽\n", *prices.AvgPricePerKg))
+	}
+	if prices.AvgPricePerKm != nil {
+		text.WriteString(fmt.Sprintf("🛣 Средняя цена за км: %.2f ₽\n", *prices.AvgPricePerKm))
+	}
+
+	if len(summary.TopRoutes) > 0 {
+		text.WriteString("\n🗺 Популярные направления:\n")
+		for i, route := range summary.TopRoutes {
+			text.WriteString(fmt.Sprintf("%d. %s → %s — %d, в среднем %.0f ₽", i+1, route.FromCity, route.ToCity, route.Orders, route.AvgPrice))
+			if route.AvgPricePerKm != nil {
+				text.WriteString(fmt.Sprintf(" (%.2f ₽/км)", *route.AvgPricePerKm))
+			}
+			text.WriteString("\n")
+		}
+	}
+
+	if len(summary.TopDrivers) > 0 {
+		text.WriteString(fmt.Sprintf("\n👷 Активных водителей: %d. Самые активные:\n", summary.ActiveDrivers))
+		for i, driver := range summary.TopDrivers {
+			text.WriteString(fmt.Sprintf("%d. %s — доставлено %d, назначено %d, предложений %d",
+				i+1, driver.Name, driver.Delivered, driver.Assigned, driver.Bids))
+			if driver.Revenue > 0 {
+				text.WriteString(fmt.Sprintf(", %.0f ₽", driver.Revenue))
+			}
+			text.WriteString("\n")
+		}
+	}
+
+	if len(summary.DriverSources) > 0 {
+		text.WriteString("\n🧲 Новые водители по источникам:\n")
+		for _, source := range summary.DriverSources {
+			text.WriteString(fmt.Sprintf("• %s — %d, из них активны %d\n",
+				formatDriverSource(source.Source), source.Drivers, source.Active))
+		}
+	}
+
+	if summary.Created == 0 && summary.Assigned == 0 && summary.Delivered == 0 {
+		text.WriteString("\nЗа период заказов не было.")
+	}
+	return strings.TrimRight(text.String(), "\n")
+}
+
+// formatDriverSource возвращает понятное название источника привлечения водителя
+func formatDriverSource(source string) string {
+	switch {
+	case source == "":
+		return "без метки"
+	case source == domain.DriverSourceOrderLink:
+		return "ссылка на заказ с сайта"
+	case strings.HasPrefix(source, domain.DriverSourceReferralPrefix):
+		return "кампания " + strings.TrimPrefix(source, domain.DriverSourceReferralPrefix)
+	default:
+		return source
+	}
+}
+
+// formatHours форматирует длительность в часах: 0.5 → "30 мин", 26.5 → "1 д 2 ч"
+func formatHours(hours float64) string {
+	duration := time.Duration(hours * float64(time.Hour)).Round(time.Minute)
+	switch {
+	case duration < time.Hour:
+		return fmt.Sprintf("%d мин", int(duration.Minutes()))
+	case duration < 24*time.Hour:
+		return fmt.Sprintf("%d ч %d мин", int(duration.Hours()), int(duration.Minutes())%60)
+	default:
+		return fmt.Sprintf("%d д %d ч", int(duration.Hours())/24, int(duration.Hours())%24)
+	}
+}
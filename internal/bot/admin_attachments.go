@@ -0,0 +1,172 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	"dalnoboy/internal/domain"
+	"dalnoboy/internal/service"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// attachTargetTTL — сколько после команды ATTACH файлы без подписи прикрепляются к заказу
+const attachTargetTTL = 15 * time.Minute
+
+// attachmentsHelp описывает работу с вложениями заказов
+const attachmentsHelp = `📎 Фото и документы заказа
+
+Отправьте фото или файл с подписью:
+ATTACH <UUID заказа>
+Необязательная подпись к файлу
+
+Или сначала отправьте команду ATTACH <UUID заказа> — следующие файлы в течение 15 минут будут прикреплены к этому заказу (альбомы тоже).
+
+Посмотреть вложения заказа:
+ATTACHMENTS <UUID заказа>
+
+Удалить вложение:
+DELETE_ATTACHMENT <UUID вложения>
+
+Заказчик, написавший боту со своего Telegram, может прикреплять файлы только к своим заказам.`
+
+// attachTarget — заказ, к которому прикрепляются следующие файлы чата
+type attachTarget struct {
+	orderUUID string
+	expiresAt time.Time
+}
+
+// DownloadFile скачивает файл, полученный админским ботом
+func (ab *AdminBot) DownloadFile(fileID string) (io.ReadCloser, error) {
+	return downloadTelegramFile(ab.bot, fileID)
+}
+
+// setAttachTarget запоминает заказ, к которому прикрепляются файлы чата
+func (ab *AdminBot) setAttachTarget(chatID int64, orderUUID string) {
+	ab.attachMu.Lock()
+	defer ab.attachMu.Unlock()
+	ab.attachTargets[chatID] = attachTarget{orderUUID: orderUUID, expiresAt: time.Now().Add(attachTargetTTL)}
+}
+
+// currentAttachTarget возвращает заказ, выбранный командой ATTACH, если он еще актуален
+func (ab *AdminBot) currentAttachTarget(chatID int64) string {
+	ab.attachMu.Lock()
+	defer ab.attachMu.Unlock()
+	target, ok := ab.attachTargets[chatID]
+	if !ok || time.Now().After(target.expiresAt) {
+		delete(ab.attachTargets, chatID)
+		return ""
+	}
+	return target.orderUUID
+}
+
+// handleAttachmentMessage прикрепляет фото или документ из сообщения к заказу
+func (ab *AdminBot) handleAttachmentMessage(message *tgbotapi.Message) string {
+	request := attachmentFromMessage(message)
+	if request == nil {
+		return ""
+	}
+
+	// Подпись "ATTACH <UUID>" выбирает заказ, остальные строки подписи сохраняются как описание
+	caption := strings.TrimSpace(message.Caption)
+	firstLine, rest, _ := strings.Cut(caption, "\n")
+	if command, orderUUID, _ := strings.Cut(strings.TrimSpace(firstLine), " "); command == "ATTACH" {
+		request.OrderUUID = strings.TrimSpace(orderUUID)
+		caption = strings.TrimSpace(rest)
+		ab.setAttachTarget(message.Chat.ID, request.OrderUUID)
+	} else {
+		request.OrderUUID = ab.currentAttachTarget(message.Chat.ID)
+	}
+	if request.OrderUUID == "" {
+		return "❌ Не указан заказ для вложения\n\n" + attachmentsHelp
+	}
+	request.Caption = caption
+
+	if message.From != nil {
+		request.UploaderTelegramID = message.From.ID
+		if !ab.isAdminChat(message.Chat.ID) {
+			customer, err := ab.customerService.GetCustomerByTelegramID(message.From.ID)
+			if err != nil {
+				log.Printf("Ошибка поиска заказчика по Telegram ID %d: %v", message.From.ID, err)
+				return "❌ Ошибка проверки прав на заказ"
+			}
+			if customer != nil {
+				customerUUID := customer.UUID.String()
+				request.CustomerUUID = &customerUUID
+			}
+		}
+	}
+
+	attachment, err := ab.attachmentService.AddAttachment(request)
+	if err != nil {
+		if errors.Is(err, service.ErrOrderNotFound) || service.IsValidationError(err) {
+			return fmt.Sprintf("❌ %v", err)
+		}
+		log.Printf("Ошибка добавления вложения к заказу %s: %v", request.OrderUUID, err)
+		return "❌ Ошибка сохранения вложения"
+	}
+
+	stored := ""
+	if attachment.StorageKey != nil {
+		stored = " (копия сохранена в хранилище)"
+	}
+	return fmt.Sprintf("✅ Файл прикреплен к заказу %s%s\n🆔 %s", attachment.OrderUUID[:8], stored, attachment.UUID)
+}
+
+// isAdminChat проверяет, что чат указан в списке администраторов. Остальные отправители,
+// найденные среди заказчиков по Telegram ID, могут прикреплять файлы только к своим заказам.
+func (ab *AdminBot) isAdminChat(chatID int64) bool {
+	for _, adminChatID := range ab.adminChatIDs {
+		if adminChatID == chatID {
+			return true
+		}
+	}
+	return false
+}
+
+// handleAttachmentCommand обрабатывает команды вложений заказов.
+// Возвращает false, если текст не является такой командой.
+func (ab *AdminBot) handleAttachmentCommand(chatID int64, text string) (string, bool) {
+	command, argument, _ := strings.Cut(strings.TrimSpace(text), " ")
+	argument = strings.TrimSpace(argument)
+
+	switch command {
+	case "ATTACH":
+		if argument == "" {
+			return attachmentsHelp, true
+		}
+		order, err := ab.orderService.GetOrderByUUID(argument)
+		if err != nil {
+			return fmt.Sprintf("❌ %v", err), true
+		}
+		ab.setAttachTarget(chatID, order.UUID)
+		return fmt.Sprintf("📎 Отправьте фото или документы — они будут прикреплены к заказу %s (%s)",
+			order.UUID[:8], order.Title), true
+	case "ATTACHMENTS":
+		if argument == "" {
+			return attachmentsHelp, true
+		}
+		attachments, err := ab.attachmentService.GetAttachments(argument)
+		if err != nil {
+			return fmt.Sprintf("❌ %v", err), true
+		}
+		if err := sendOrderAttachments(ab.bot, ab.attachmentService, domain.AttachmentBotAdmin, chatID, attachments); err != nil {
+			log.Printf("Ошибка отправки вложений заказа %s: %v", argument, err)
+		}
+		return formatAttachments(attachments), true
+	case "DELETE_ATTACHMENT":
+		if argument == "" {
+			return attachmentsHelp, true
+		}
+		attachment, err := ab.attachmentService.DeleteAttachment(argument)
+		if err != nil {
+			return fmt.Sprintf("❌ %v", err), true
+		}
+		return fmt.Sprintf("✅ Вложение удалено из заказа %s", attachment.OrderUUID[:8]), true
+	}
+	return "", false
+}
@@ -0,0 +1,158 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"dalnoboy/internal/domain"
+	"dalnoboy/internal/service"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Данные кнопок предложений: bidaccept:<UUID предложения>, bids:<UUID заказа>
+const (
+	bidAcceptCallbackPrefix = "bidaccept:"
+	bidsCallbackPrefix      = "bids:"
+)
+
+// bidsHelp описывает работу с предложениями водителей
+const bidsHelp = `💰 Предложения водителей
+
+Водитель может предложить свою цену и дату погрузки по активному заказу. Заказчик (если пишет этому боту) и администраторы получают каждое предложение с кнопкой «✅ Принять».
+
+Принятое предложение назначает заказ водителю по его цене, остальные предложения отклоняются, все водители получают уведомления.
+
+Предложения по заказу:
+BIDS <UUID заказа>
+
+Принять предложение:
+ACCEPT_BID <номер предложения>`
+
+// NotifyNewBid сообщает заказчику или администратору о новом предложении водителя
+func (ab *AdminBot) NotifyNewBid(chatID int64, order *domain.Order, bid *domain.Bid) error {
+	text := fmt.Sprintf("💰 Новое предложение по заказу #%s «%s»\n\n%s", order.UUID[:8], order.Title, formatBid(bid, order))
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("✅ Принять", bidAcceptCallbackPrefix+bid.UUID),
+		tgbotapi.NewInlineKeyboardButtonData("📋 Все предложения", bidsCallbackPrefix+order.UUID),
+	))
+	_, err := ab.bot.Send(msg)
+	return err
+}
+
+// handleBidCallback обрабатывает кнопки предложений: принятие и список предложений по заказу
+func (ab *AdminBot) handleBidCallback(query *tgbotapi.CallbackQuery) string {
+	if query.Message == nil {
+		return ""
+	}
+	chatID := query.Message.Chat.ID
+
+	var response, answer string
+	switch {
+	case strings.HasPrefix(query.Data, bidAcceptCallbackPrefix):
+		response = ab.acceptBid(chatID, query.From.ID, strings.TrimPrefix(query.Data, bidAcceptCallbackPrefix))
+		answer = "Готово"
+	case strings.HasPrefix(query.Data, bidsCallbackPrefix):
+		response = ab.orderBids(chatID, query.From.ID, strings.TrimPrefix(query.Data, bidsCallbackPrefix))
+	}
+
+	if err := ab.sendText(chatID, response); err != nil {
+		log.Printf("Ошибка отправки сообщения: %v", err)
+	}
+	return answer
+}
+
+// handleBidCommand обрабатывает команды предложений. Возвращает false, если текст не является такой командой.
+func (ab *AdminBot) handleBidCommand(chatID int64, message *tgbotapi.Message) (string, bool) {
+	fields := strings.Fields(message.Text)
+	if len(fields) == 0 {
+		return "", false
+	}
+
+	switch fields[0] {
+	case "BIDS":
+		if len(fields) != 2 {
+			return bidsHelp, true
+		}
+		return ab.orderBids(chatID, message.From.ID, fields[1]), true
+	case "ACCEPT_BID":
+		if len(fields) != 2 {
+			return bidsHelp, true
+		}
+		return ab.acceptBid(chatID, message.From.ID, fields[1]), true
+	}
+	return "", false
+}
+
+// orderBids возвращает список предложений по заказу
+func (ab *AdminBot) orderBids(chatID, fromID int64, orderUUID string) string {
+	order, bids, err := ab.bidService.GetOrderBids(orderUUID, fromID, ab.isAdminChat(chatID))
+	if err != nil {
+		return formatBidError(err)
+	}
+	return formatBids(order, bids)
+}
+
+// acceptBid принимает предложение от имени заказчика или администратора
+func (ab *AdminBot) acceptBid(chatID, fromID int64, bidRef string) string {
+	order, bid, err := ab.bidService.AcceptBid(bidRef, fromID, ab.isAdminChat(chatID))
+	if err != nil {
+		return formatBidError(err)
+	}
+
+	driverName := "водитель"
+	if order.AssignedDriverName != nil {
+		driverName = *order.AssignedDriverName
+	}
+	return fmt.Sprintf("✅ Предложение %s принято: заказ #%s «%s» назначен водителю %s за %.0f ₽. Остальные водители получили отказ.",
+		bid.ShortID(), order.UUID[:8], order.Title, driverName, bid.Price)
+}
+
+// formatBidError преобразует ошибку сервиса предложений в ответ бота
+func formatBidError(err error) string {
+	if service.IsValidationError(err) || errors.Is(err, service.ErrOrderNotFound) {
+		return fmt.Sprintf("❌ %v", err)
+	}
+	log.Printf("Ошибка обработки предложения: %v", err)
+	return "❌ Ошибка обработки предложения"
+}
+
+// formatBids форматирует предложения по заказу
+func formatBids(order *domain.Order, bids []domain.Bid) string {
+	if len(bids) == 0 {
+		return fmt.Sprintf("💰 По заказу #%s «%s» предложений пока нет", order.UUID[:8], order.Title)
+	}
+
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("💰 Предложения по заказу #%s «%s» (%d), цена заказа %.0f ₽:\n\n",
+		order.UUID[:8], order.Title, len(bids), order.Price))
+	for i := range bids {
+		text.WriteString(fmt.Sprintf("%d. %s — %s\n", i+1, bids[i].ShortID(), domain.BidStatusNames[bids[i].Status]))
+		text.WriteString(formatBid(&bids[i], order))
+		if bids[i].Status == domain.BidStatusPending {
+			text.WriteString(fmt.Sprintf("   ✅ ACCEPT_BID %s\n", bids[i].ShortID()))
+		}
+		text.WriteString("\n")
+	}
+	return text.String()
+}
+
+// formatBid форматирует предложение: водитель с рейтингом и транспортом, цена, дата и комментарий
+func formatBid(bid *domain.Bid, order *domain.Order) string {
+	var text strings.Builder
+	if bid.Driver != nil {
+		text.WriteString(fmt.Sprintf("   🚚 %s, %s\n", bid.Driver.Name, formatDriverRating(bid.Driver)))
+		text.WriteString(fmt.Sprintf("   🚛 %s\n", formatVehicleShort(bid.Driver.Vehicle)))
+	}
+	text.WriteString(fmt.Sprintf("   💰 %.0f ₽ (в заказе %.0f ₽)\n", bid.Price, order.Price))
+	if bid.PickupDate != nil {
+		text.WriteString(fmt.Sprintf("   📅 Погрузка: %s\n", bid.PickupDate.Format("02.01.2006")))
+	}
+	if bid.Comment != nil {
+		text.WriteString(fmt.Sprintf("   💬 %s\n", *bid.Comment))
+	}
+	return text.String()
+}
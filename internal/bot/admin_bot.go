@@ -5,6 +5,7 @@ import (
 	"log"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"dalnoboy/internal"
@@ -23,10 +24,35 @@ type AdminBot struct {
 	orderService    *service.OrderService
 	customerService *service.CustomerService
 	driverService   *service.DriverService
+	cityService     *service.CityService
+	cargoService    *service.CargoService
+
+	attachmentService *service.AttachmentService
+	deliveryService   *service.DeliveryService
+	adminChatIDs      []int64
+	attachMu          sync.Mutex
+	attachTargets     map[int64]attachTarget // Заказ для следующих файлов чата после ATTACH
+
+	ratingService       *service.RatingService
+	notificationService *service.NotificationService
+	ratingMu            sync.Mutex
+	ratingComments      map[int64]ratingCommentTarget // Оценка, ожидающая комментария заказчика
+
+	chatService *service.ChatService
+	bidService  *service.BidService
+	chatMu      sync.Mutex
+	chatReplies map[int64]chatReplyTarget // Переписка для следующего сообщения после «Ответить»
+
+	exportService  *service.ExportService
+	importMu       sync.Mutex
+	importTargets  map[int64]importTarget  // Заказчик для следующего файла после IMPORT_ORDERS
+	pendingImports map[int64]pendingImport // Проверенный импорт, ожидающий подтверждения
+
+	analyticsService *service.AnalyticsService
 }
 
 // NewAdminBot создает новый экземпляр админского бота
-func NewAdminBot(config *internal.Config, db *database.Database, orderService *service.OrderService, customerService *service.CustomerService, driverService *service.DriverService) (*AdminBot, error) {
+func NewAdminBot(config *internal.Config, db *database.Database, orderService *service.OrderService, customerService *service.CustomerService, driverService *service.DriverService, cityService *service.CityService, cargoService *service.CargoService, attachmentService *service.AttachmentService, deliveryService *service.DeliveryService, ratingService *service.RatingService, notificationService *service.NotificationService, chatService *service.ChatService, bidService *service.BidService, exportService *service.ExportService, analyticsService *service.AnalyticsService) (*AdminBot, error) {
 	log.Printf("Инициализация админского бота с токеном: %s...", config.Bot.AdminToken[:10]+"...")
 
 	bot, err := tgbotapi.NewBotAPI(config.Bot.AdminToken)
@@ -43,6 +69,27 @@ func NewAdminBot(config *internal.Config, db *database.Database, orderService *s
 		orderService:    orderService,
 		customerService: customerService,
 		driverService:   driverService,
+		cityService:     cityService,
+		cargoService:    cargoService,
+
+		attachmentService: attachmentService,
+		deliveryService:   deliveryService,
+		adminChatIDs:      config.Bot.AdminChatIDs,
+		attachTargets:     make(map[int64]attachTarget),
+
+		ratingService:       ratingService,
+		notificationService: notificationService,
+		ratingComments:      make(map[int64]ratingCommentTarget),
+
+		chatService: chatService,
+		bidService:  bidService,
+		chatReplies: make(map[int64]chatReplyTarget),
+
+		exportService:  exportService,
+		importTargets:  make(map[int64]importTarget),
+		pendingImports: make(map[int64]pendingImport),
+
+		analyticsService: analyticsService,
 	}, nil
 }
 
@@ -57,6 +104,9 @@ func (ab *AdminBot) Start() error {
 		if update.Message != nil {
 			// Обработка сообщений
 			ab.handleMessage(update.Message)
+		} else if update.CallbackQuery != nil {
+			// Нажатия inline-кнопок (оценка водителя)
+			ab.handleCallback(update.CallbackQuery)
 		}
 	}
 
@@ -126,13 +176,29 @@ func (ab *AdminBot) formatOrders(orders []domain.Order) string {
 		// Форматируем статус
 		statusEmoji := "🟢"
 		statusText := "Активный"
-		if order.Status == "archived" {
+		switch order.Status {
+		case domain.OrderStatusArchived:
 			statusEmoji = "🔴"
 			statusText = "Архивный"
+		case domain.OrderStatusExpired:
+			statusEmoji = "⌛"
+			statusText = "Истек срок"
+		case domain.OrderStatusAssigned:
+			statusEmoji = "🚚"
+			statusText = "В пути"
+		case domain.OrderStatusDelivered:
+			statusEmoji = "✅"
+			statusText = "Доставлен"
 		}
 
 		result.WriteString(fmt.Sprintf("%d. 🚚 Заказ #%s\n", i+1, order.UUID[:8]))
 		result.WriteString(fmt.Sprintf("   %s %s\n", statusEmoji, statusText))
+		if order.Urgent {
+			result.WriteString("   🔥 Срочно\n")
+		}
+		if assignment := formatAssignment(&order); assignment != "" {
+			result.WriteString(fmt.Sprintf("   %s\n", assignment))
+		}
 		result.WriteString(fmt.Sprintf("   📝 %s\n", order.Title))
 		if order.Description != "" {
 			result.WriteString(fmt.Sprintf("   📄 %s\n", order.Description))
@@ -150,8 +216,14 @@ func (ab *AdminBot) formatOrders(orders []domain.Order) string {
 		result.WriteString(fmt.Sprintf("   %s\n", toLoc))
 		result.WriteString(fmt.Sprintf("   ⚖️ %.1f кг\n", order.WeightKg))
 		result.WriteString(fmt.Sprintf("   📏 %s\n", dimensions))
+		if cargo := formatCargo(&order); cargo != "" {
+			result.WriteString(fmt.Sprintf("   %s\n", cargo))
+		}
 		result.WriteString(fmt.Sprintf("   🏷️ %s\n", tagsStr))
 		result.WriteString(fmt.Sprintf("   💰 %.0f ₽\n", order.Price))
+		if routeRate := formatRouteRate(&order); routeRate != "" {
+			result.WriteString(fmt.Sprintf("   %s\n", routeRate))
+		}
 		if dateStr != "" {
 			result.WriteString(fmt.Sprintf("   📅 %s\n", dateStr))
 		}
@@ -185,6 +257,9 @@ func (ab *AdminBot) formatCustomers(customers []domain.Customer) string {
 		}
 
 		result.WriteString(fmt.Sprintf("%d. 👤 %s\n", i+1, customer.Name))
+		if !customer.IsActive {
+			result.WriteString("   ⏸ Деактивирован\n")
+		}
 		result.WriteString(fmt.Sprintf("   📱 %s\n", customer.Phone))
 		result.WriteString(fmt.Sprintf("   🆔 Telegram ID: %s\n", telegramIDStr))
 		result.WriteString(fmt.Sprintf("   🏷️ Telegram Tag: %s\n", telegramTagStr))
@@ -227,9 +302,21 @@ func (ab *AdminBot) formatDrivers(drivers []domain.Driver) string {
 		result.WriteString(fmt.Sprintf("%d. 🚚 %s\n", i+1, driver.Name))
 		result.WriteString(fmt.Sprintf("   📱 Telegram ID: %d\n", driver.TelegramID))
 		result.WriteString(fmt.Sprintf("   🏷️ Telegram Tag: %s\n", telegramTagStr))
+		if driver.Phone != nil {
+			result.WriteString(fmt.Sprintf("   ☎️ Телефон: %s\n", *driver.Phone))
+		}
 		result.WriteString(fmt.Sprintf("   🏙️ Город: %s\n", cityNameStr))
+		result.WriteString(fmt.Sprintf("   📍 Радиус: %s\n", formatDriverRadius(&driver)))
+		result.WriteString(fmt.Sprintf("   🚛 Транспорт: %s\n", formatVehicleShort(driver.Vehicle)))
+		result.WriteString(fmt.Sprintf("   %s\n", formatDriverRating(&driver)))
 		result.WriteString(fmt.Sprintf("   %s\n", notificationStatus))
 		result.WriteString(fmt.Sprintf("   📅 Зарегистрирован: %s\n", driver.CreatedAt.Format("02.01.2006 15:04")))
+		if driver.IsOnboarding() {
+			result.WriteString(fmt.Sprintf("   📝 Регистрация не завершена: %s\n", formatOnboardingStep(*driver.OnboardingStep)))
+		}
+		if driver.Source != nil {
+			result.WriteString(fmt.Sprintf("   🧲 Источник: %s\n", formatDriverSource(*driver.Source)))
+		}
 		result.WriteString(fmt.Sprintf("   🆔 UUID: %s\n", driver.UUID))
 		result.WriteString("\n")
 	}
@@ -429,6 +516,35 @@ func (ab *AdminBot) parseSetCityAndNotificationMessage(text string) (*domain.Set
 	return request, nil
 }
 
+// parseSetDriverRadiusMessage парсит сообщение с командой настройки радиусов поиска водителя
+// Формат: SET_DRIVER_RADIUS\n<driver_uuid>, <погрузка_км_or_->, <выгрузка_км_or_->
+func (ab *AdminBot) parseSetDriverRadiusMessage(text string) (*domain.SetDriverRadiusRequest, error) {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	if len(lines) < 2 || strings.TrimSpace(lines[0]) != "SET_DRIVER_RADIUS" {
+		return nil, fmt.Errorf("ожидается формат: SET_DRIVER_RADIUS + строка \"UUID, погрузка км, выгрузка км\"")
+	}
+
+	params := strings.Split(lines[1], ",")
+	driverUUID, err := uuid.Parse(strings.TrimSpace(params[0]))
+	if err != nil {
+		return nil, fmt.Errorf("неверный формат UUID водителя: %v", err)
+	}
+
+	request := &domain.SetDriverRadiusRequest{DriverUUID: driverUUID}
+	if len(params) > 1 {
+		if request.PickupRadiusKm, err = parseRadius(params[1]); err != nil {
+			return nil, err
+		}
+	}
+	if len(params) > 2 {
+		if request.DeliveryRadiusKm, err = parseRadius(params[2]); err != nil {
+			return nil, err
+		}
+	}
+
+	return request, nil
+}
+
 // parseOrderTgMessage парсит упрощенное сообщение с данными заказа для Telegram
 // Формат: ADD_ORDER\nНазвание\nОписание\nВес\nОткуда город\nОткуда адрес\nКуда город\nКуда адрес\nЦена\nUUID клиента
 func (ab *AdminBot) parseOrderTgMessage(text string) (*domain.CreateOrderTgRequest, error) {
@@ -489,9 +605,127 @@ func (ab *AdminBot) parseOrderTgMessage(text string) (*domain.CreateOrderTgReque
 		CustomerUUID: customerUUID,
 	}
 
+	// Необязательные строки "Поле: значение" с характеристиками груза
+	cargo := &domain.UpdateOrderRequest{}
+	for _, line := range lines[10:] {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			return nil, fmt.Errorf("строка '%s' должна иметь вид 'Поле: значение'", line)
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		if key == "срочно" {
+			urgent, err := parseYesNo(value)
+			if err != nil {
+				return nil, fmt.Errorf("поле 'срочно': %v", err)
+			}
+			request.Urgent = urgent
+			continue
+		}
+		if ok, err := applyCargoField(cargo, key, strings.TrimSpace(value)); err != nil {
+			return nil, err
+		} else if !ok {
+			return nil, fmt.Errorf("неизвестное поле груза '%s'", key)
+		}
+	}
+	request.CargoType = stringValue(cargo.CargoType)
+	request.TemperatureRange = stringValue(cargo.TemperatureRange)
+	request.ADRClass = stringValue(cargo.ADRClass)
+	request.Oversize = cargo.Oversize != nil && *cargo.Oversize
+	request.Fragile = cargo.Fragile != nil && *cargo.Fragile
+
 	return request, nil
 }
 
+// parseEditOrderMessage парсит сообщение с изменениями заказа
+// Формат: EDIT_ORDER <UUID>\nПоле: значение\n... Значение "-" очищает необязательное поле
+func (ab *AdminBot) parseEditOrderMessage(text string) (string, *domain.UpdateOrderRequest, error) {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	header := strings.Fields(lines[0])
+	if len(header) != 2 || header[0] != "EDIT_ORDER" {
+		return "", nil, fmt.Errorf("неверный заголовок. Ожидается: EDIT_ORDER <UUID>")
+	}
+	orderUUID := header[1]
+
+	if len(lines) < 2 {
+		return "", nil, fmt.Errorf("не указано ни одного поля для изменения")
+	}
+
+	request := &domain.UpdateOrderRequest{}
+	for _, line := range lines[1:] {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			return "", nil, fmt.Errorf("строка '%s' должна иметь вид 'Поле: значение'", line)
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		// Прочерк очищает необязательные строковые поля и дату
+		clearable := value
+		if clearable == "-" {
+			clearable = ""
+		}
+
+		switch key {
+		case "название":
+			request.Title = &value
+		case "описание":
+			request.Description = &value
+		case "вес":
+			weight, err := parseFloat(value)
+			if err != nil {
+				return "", nil, fmt.Errorf("ошибка парсинга веса: %v", err)
+			}
+			request.WeightKg = &weight
+		case "длина", "ширина", "высота":
+			size, err := parseFloat(value)
+			if err != nil {
+				return "", nil, fmt.Errorf("ошибка парсинга поля '%s': %v", key, err)
+			}
+			switch key {
+			case "длина":
+				request.LengthCm = &size
+			case "ширина":
+				request.WidthCm = &size
+			default:
+				request.HeightCm = &size
+			}
+		case "цена":
+			price, err := parseFloat(value)
+			if err != nil {
+				return "", nil, fmt.Errorf("ошибка парсинга цены: %v", err)
+			}
+			request.Price = &price
+		case "откуда город":
+			request.FromCityName = &value
+		case "откуда адрес":
+			request.FromAddress = &clearable
+		case "куда город":
+			request.ToCityName = &value
+		case "куда адрес":
+			request.ToAddress = &clearable
+		case "дата":
+			request.AvailableFrom = &clearable
+		default:
+			if ok, err := applyCargoField(request, key, value); err != nil {
+				return "", nil, err
+			} else if !ok {
+				return "", nil, fmt.Errorf("неизвестное поле '%s'", key)
+			}
+		}
+	}
+
+	return orderUUID, request, nil
+}
+
 // parseTelegramID парсит Telegram ID из строки
 func parseTelegramID(text string) (int64, error) {
 	return strconv.ParseInt(strings.TrimSpace(text), 10, 64)
@@ -557,12 +791,41 @@ func (ab *AdminBot) handleMessage(message *tgbotapi.Message) {
 	var response string
 	var keyboard tgbotapi.ReplyKeyboardMarkup
 
+	// Таблица заказов для импорта (подпись IMPORT_ORDERS или файл после этой команды)
+	if ab.handleImportDocument(message) {
+		return
+	}
+
+	// Фото и документы прикрепляются к заказу
+	if attachmentResponse := ab.handleAttachmentMessage(message); attachmentResponse != "" {
+		if err := ab.sendText(chatID, attachmentResponse); err != nil {
+			log.Printf("Ошибка отправки сообщения: %v", err)
+		}
+		return
+	}
+
+	// Ответ водителю в анонимной переписке
+	if replyResponse := ab.handleChatReply(message); replyResponse != "" {
+		if err := ab.sendText(chatID, replyResponse); err != nil {
+			log.Printf("Ошибка отправки сообщения: %v", err)
+		}
+		return
+	}
+
+	// Комментарий заказчика к только что поставленной оценке водителя
+	if commentResponse := ab.handleRatingComment(message); commentResponse != "" {
+		if err := ab.sendText(chatID, commentResponse); err != nil {
+			log.Printf("Ошибка отправки сообщения: %v", err)
+		}
+		return
+	}
+
 	switch text {
 	case "/start":
 		response = "Добро пожаловать в админскую панель! Выберите действие."
 		keyboard = adminMainMenuKeyboard()
 	case "/help", "❓ Помощь":
-		response = "Доступные команды:\n/start - Начать работу\n/help - Показать помощь\n/status - Статус системы\n/orders - Посмотреть заказы\n/👥 Заказчики - Посмотреть заказчиков\n/🚚 Водители - Посмотреть водителей\n// Закомментировано - убираем фильтры\n// /filter - Настроить фильтры\n\nДля добавления пользователя используйте формат:\nADD_USER\nИмя\nТелефон\nTelegramID\nTelegramTag\n\nДля создания заказа используйте формат:\nADD_ORDER\nНазвание\nОписание\nВес\nОткуда город\nОткуда адрес\nКуда город\nКуда адрес\nЦена\nUUID клиента\n\nДля изменения статуса заказа используйте формат:\nARCHIVE_ORDER <UUID>\nACTIVATE_ORDER <UUID>\n\nДля настройки города и уведомлений водителя используйте формат:\nSET_CITY_AND_NOTIFICATION\nUUID, город, уведомления\n\nПримеры:\nSET_CITY_AND_NOTIFICATION\n12345678-1234-1234-1234-123456789abc, Москва, вкл\nSET_CITY_AND_NOTIFICATION\n12345678-1234-1234-1234-123456789abc, Москва, выкл\nSET_CITY_AND_NOTIFICATION\n12345678-1234-1234-1234-123456789abc, Москва\nSET_CITY_AND_NOTIFICATION\n12345678-1234-1234-1234-123456789abc, -, \nSET_CITY_AND_NOTIFICATION\n12345678-1234-1234-1234-123456789abc,, вкл\nSET_CITY_AND_NOTIFICATION\n12345678-1234-1234-1234-123456789abc,, выкл"
+		response = "Доступные команды:\n/start - Начать работу\n/help - Показать помощь\n/status - Статус системы\n/orders - Посмотреть заказы\n/find <слова> - Поиск заказов по названию, описанию, адресам, городам и заказчику\n/👥 Заказчики - Посмотреть заказчиков\n/🚚 Водители - Посмотреть водителей\n// Закомментировано - убираем фильтры\n// /filter - Настроить фильтры\n\nДля добавления пользователя используйте формат:\nADD_USER\nИмя\nТелефон\nTelegramID\nTelegramTag\n\nДля создания заказа используйте формат:\nADD_ORDER\nНазвание\nОписание\nВес\nОткуда город\nОткуда адрес\nКуда город\nКуда адрес\nЦена\nUUID клиента\n\nДля управления справочником городов используйте:\nADD_CITY, RENAME_CITY, ADD_CITY_ALIAS, REMOVE_CITY_ALIAS, DELETE_CITY, FIND_CITY (подробнее: 🏙️ Города → 🛠 Управление городами)\n\nДля управления заказчиками используйте:\nEDIT_CUSTOMER, DEACTIVATE_CUSTOMER, ACTIVATE_CUSTOMER, MERGE_CUSTOMERS, CUSTOMER_ORDERS, FIND_CUSTOMER (подробнее: 🛠 Управление заказчиками)\n\nДля справочника типов грузов используйте:\nCARGO_TYPES, ADD_CARGO_TYPE, RENAME_CARGO_TYPE, DEACTIVATE_CARGO_TYPE, ACTIVATE_CARGO_TYPE, ORDERS_BY_CARGO (подробнее: 📋 Заказы → 📦 Типы грузов)\n\nДля импорта заказов из таблицы CSV/XLSX используйте:\nIMPORT_ORDERS <UUID заказчика> (подписью к файлу или перед отправкой файла), CONFIRM_IMPORT, CANCEL_IMPORT\n\nДля аналитики (объемы, время до назначения, направления, цены, активность водителей) используйте:\nANALYTICS [с] [по] (графики — на странице /admin.html сайта)\n\nДля выгрузок в CSV/XLSX (отчеты для бухгалтерии) используйте:\nEXPORT_ORDERS [xlsx] [from=ГГГГ-ММ-ДД to=ГГГГ-ММ-ДД status=...], EXPORT_CUSTOMERS [xlsx], EXPORT_DRIVERS [xlsx] (подробнее: EXPORTS)\n\nДля фото и документов заказа используйте:\nATTACH <UUID> (подписью к файлу или перед отправкой файлов), ATTACHMENTS <UUID>, DELETE_ATTACHMENT <UUID вложения>\n\nДля перевозок и подтверждения доставки используйте:\nASSIGN_ORDER <UUID заказа> <UUID водителя>, UNASSIGN_ORDER <UUID>, POD <UUID>, POD_REPORT [с] [по] (подробнее: 📋 Заказы → 🚚 В пути)\n\nДля оценок водителей и приоритета уведомлений используйте:\nDRIVER_REVIEWS <UUID водителя>, NOTIFY_PRIORITY <мин. рейтинг> <задержка> | OFF\n\nДля переписки водителей с заказчиками используйте:\nCHATS, CHAT_LOG <UUID заказа>, CHAT_REPLY <номер чата> (ответ водителю — reply на пересланное сообщение)\n\nДля предложений водителей по цене используйте:\nBIDS <UUID заказа>, ACCEPT_BID <номер предложения>\n\nДля редактирования заказа используйте формат:\nEDIT_ORDER <UUID>\nПоле: значение\n\nДля пересчета расстояний маршрутов (после изменения координат городов):\nRECALC_DISTANCES\n\nДля изменения статуса заказа используйте формат:\nARCHIVE_ORDER <UUID>\nACTIVATE_ORDER <UUID>\n\nДля срочных заказов (уведомления приходят водителям и в тихие часы):\nURGENT_ORDER <UUID> [OFF]\n\nДля настройки радиусов поиска водителя (км от его города, \"-\" — отключить):\nSET_DRIVER_RADIUS\nUUID, погрузка, выгрузка\n\nДля настройки города и уведомлений водителя используйте формат:\nSET_CITY_AND_NOTIFICATION\nUUID, город, уведомления\n\nПримеры:\nSET_CITY_AND_NOTIFICATION\n12345678-1234-1234-1234-123456789abc, Москва, вкл\nSET_CITY_AND_NOTIFICATION\n12345678-1234-1234-1234-123456789abc, Москва, выкл\nSET_CITY_AND_NOTIFICATION\n12345678-1234-1234-1234-123456789abc, Москва\nSET_CITY_AND_NOTIFICATION\n12345678-1234-1234-1234-123456789abc, -, \nSET_CITY_AND_NOTIFICATION\n12345678-1234-1234-1234-123456789abc,, вкл\nSET_CITY_AND_NOTIFICATION\n12345678-1234-1234-1234-123456789abc,, выкл"
 	case "/status":
 		// Получаем статистику из базы данных
 		ordersCount, err := ab.database.GetOrdersCount()
@@ -577,6 +840,7 @@ func (ab *AdminBot) handleMessage(message *tgbotapi.Message) {
 			activeOrdersCount = -1
 		}
 
+		// Неактивными считаются заказы в пути, доставленные, архивные и истекшие
 		archivedOrdersCount := 0
 		if ordersCount >= 0 && activeOrdersCount >= 0 {
 			archivedOrdersCount = ordersCount - activeOrdersCount
@@ -595,7 +859,7 @@ func (ab *AdminBot) handleMessage(message *tgbotapi.Message) {
 		}
 
 		if ordersCount >= 0 && customersCount >= 0 && activeOrdersCount >= 0 && driversCount >= 0 {
-			response = fmt.Sprintf("✅ Система работает нормально.\n📊 Статистика:\n📋 Всего заказов: %d\n🟢 Активных: %d\n🔴 Архивных: %d\n👥 Заказчиков: %d\n🚚 Водителей: %d",
+			response = fmt.Sprintf("✅ Система работает нормально.\n📊 Статистика:\n📋 Всего заказов: %d\n🟢 Активных: %d\n🔴 Неактивных (в пути, доставлены, архив, истекли): %d\n👥 Заказчиков: %d\n🚚 Водителей: %d\n\nДинамика по дням, направления, цены и активность водителей: ANALYTICS",
 				ordersCount, activeOrdersCount, archivedOrdersCount, customersCount, driversCount)
 		} else {
 			response = "⚠️ Система работает, но есть проблемы с базой данных"
@@ -625,6 +889,13 @@ ADD_ORDER
 Куда адрес
 Цена (руб)
 UUID клиента
+Необязательные строки о грузе:
+Тип груза: ...
+Температура: +2..+6
+ADR: 3
+Негабарит: да
+Хрупкий: да
+Срочно: да — водители получат заказ и в тихие часы
 
 Пример:
 ADD_ORDER
@@ -637,9 +908,17 @@ ADD_ORDER
 Невский проспект, д. 10
 15000
 12345678-1234-1234-1234-123456789abc
+Тип груза: Мебель
+Хрупкий: да
 
 Отправьте сообщение с данными заказа в указанном формате.`
 		keyboard = ordersMenuKeyboard()
+	case "/cargo_types", "📦 Типы грузов":
+		response, _ = ab.handleCargoCommand("CARGO_TYPES")
+		keyboard = ordersMenuKeyboard()
+	case "✏️ Редактировать заказ":
+		response = editOrderHelp
+		keyboard = ordersMenuKeyboard()
 	case "/active_orders", "🟢 Активные заказы":
 		// Получаем только активные заказы
 		orders, err := ab.orderService.GetActiveOrders()
@@ -660,6 +939,29 @@ ADD_ORDER
 			response = ab.formatOrders(orders)
 		}
 		keyboard = ordersMenuKeyboard()
+	case "/expired_orders", "⌛ Истекшие заказы":
+		// Получаем заказы, снятые автоматически по сроку
+		orders, err := ab.orderService.GetOrdersByStatus(domain.OrderStatusExpired)
+		if err != nil {
+			log.Printf("Ошибка получения истекших заказов: %v", err)
+			response = "❌ Ошибка получения истекших заказов из базы данных"
+		} else {
+			response = ab.formatOrders(orders)
+		}
+		keyboard = ordersMenuKeyboard()
+	case "/assigned_orders", "🚚 В пути":
+		// Получаем заказы, назначенные водителям
+		orders, err := ab.orderService.GetOrdersByStatus(domain.OrderStatusAssigned)
+		if err != nil {
+			log.Printf("Ошибка получения заказов в пути: %v", err)
+			response = "❌ Ошибка получения заказов в пути из базы данных"
+		} else {
+			response = ab.formatOrders(orders) + "\n" + deliveryHelp
+		}
+		keyboard = ordersMenuKeyboard()
+	case "/deliveries", "✅ Доставленные":
+		response = ab.podReport(chatID, nil)
+		keyboard = ordersMenuKeyboard()
 	case "/users", "👥 Заказчики":
 		// Получаем заказчиков через сервис
 		customers, err := ab.customerService.GetAllCustomers()
@@ -669,7 +971,22 @@ ADD_ORDER
 		} else {
 			response = ab.formatCustomers(customers)
 		}
-		keyboard = adminMainMenuKeyboard()
+		keyboard = usersMenuKeyboard()
+	case "🛠 Управление заказчиками":
+		response = customersHelp
+		keyboard = usersMenuKeyboard()
+	case "/cities", "🏙️ Города":
+		cities, err := ab.cityService.GetAllCities()
+		if err != nil {
+			log.Printf("Ошибка получения городов: %v", err)
+			response = "❌ Ошибка получения справочника городов"
+		} else {
+			response = ab.formatCities(cities)
+		}
+		keyboard = citiesMenuKeyboard()
+	case "🛠 Управление городами":
+		response = citiesHelp
+		keyboard = citiesMenuKeyboard()
 	case "/drivers", "🚚 Водители":
 		// Получаем водителей через сервис
 		drivers, err := ab.driverService.GetAllDrivers()
@@ -735,7 +1052,7 @@ ADD_ORDER
 				// Создаем заказ через сервис
 				createdOrder, err := ab.orderService.CreateOrderFromTgRequest(request)
 				if err != nil {
-					response = fmt.Sprintf("❌ Ошибка создания заказа: %v", err)
+					response = fmt.Sprintf("❌ Ошибка создания заказа: %v%s", err, cityLookupHint(err))
 				} else {
 					response = fmt.Sprintf("✅ Заказ успешно создан!\n\n📝 %s\n📄 %s\n⚖️ %.1f кг\n🏙️ %s → %s\n💰 %.0f ₽\n🆔 ID: %s",
 						createdOrder.Title,
@@ -745,10 +1062,67 @@ ADD_ORDER
 						*createdOrder.ToCityName,
 						createdOrder.Price,
 						createdOrder.UUID)
+					if cargo := formatCargo(createdOrder); cargo != "" {
+						response += "\n" + cargo
+					}
 				}
 			}
 			// Сбрасываем состояние создания заказа
 			keyboard = ordersMenuKeyboard()
+		} else if findResponse, ok := ab.handleFindCommand(chatID, text); ok {
+			response = findResponse
+			keyboard = ordersMenuKeyboard()
+		} else if analyticsResponse, ok := ab.handleAnalyticsCommand(chatID, text); ok {
+			response = analyticsResponse
+			keyboard = adminMainMenuKeyboard()
+		} else if exportResponse, ok := ab.handleExportCommand(chatID, text); ok {
+			response = exportResponse
+			keyboard = adminMainMenuKeyboard()
+		} else if importResponse, ok := ab.handleImportCommand(chatID, text); ok {
+			response = importResponse
+			keyboard = ordersMenuKeyboard()
+		} else if attachmentResponse, ok := ab.handleAttachmentCommand(chatID, text); ok {
+			response = attachmentResponse
+			keyboard = ordersMenuKeyboard()
+		} else if bidResponse, ok := ab.handleBidCommand(chatID, message); ok {
+			response = bidResponse
+			keyboard = adminMainMenuKeyboard()
+		} else if chatResponse, ok := ab.handleChatCommand(chatID, message); ok {
+			response = chatResponse
+			keyboard = adminMainMenuKeyboard()
+		} else if ratingResponse, ok := ab.handleRatingCommand(text); ok {
+			response = ratingResponse
+			keyboard = adminMainMenuKeyboard()
+		} else if deliveryResponse, ok := ab.handleDeliveryCommand(chatID, text); ok {
+			response = deliveryResponse
+			keyboard = ordersMenuKeyboard()
+		} else if cargoResponse, ok := ab.handleCargoCommand(text); ok {
+			response = cargoResponse
+			keyboard = ordersMenuKeyboard()
+		} else if cityResponse, ok := ab.handleCityCommand(text); ok {
+			response = cityResponse
+			keyboard = citiesMenuKeyboard()
+		} else if customerResponse, ok := ab.handleCustomerCommand(text); ok {
+			response = customerResponse
+			keyboard = usersMenuKeyboard()
+		} else if strings.TrimSpace(text) == "RECALC_DISTANCES" {
+			response = ab.recalculateDistances()
+			keyboard = ordersMenuKeyboard()
+		} else if strings.HasPrefix(text, "EDIT_ORDER") {
+			response = ab.handleEditOrder(text)
+			keyboard = ordersMenuKeyboard()
+		} else if strings.HasPrefix(text, "SET_DRIVER_RADIUS") {
+			request, err := ab.parseSetDriverRadiusMessage(text)
+			if err != nil {
+				response = fmt.Sprintf("❌ Ошибка парсинга команды: %v", err)
+			} else if err := ab.driverService.SetDriverRadius(request); err != nil {
+				response = fmt.Sprintf("❌ Ошибка обновления радиусов водителя: %v", err)
+			} else {
+				response = fmt.Sprintf("✅ Радиусы водителя %s обновлены!\n\n📍 %s",
+					request.DriverUUID.String()[:8],
+					formatRadius(request.PickupRadiusKm, request.DeliveryRadiusKm))
+			}
+			keyboard = adminMainMenuKeyboard()
 		} else if strings.HasPrefix(text, "SET_CITY_AND_NOTIFICATION") {
 			// Парсим и выполняем команду настройки города и уведомлений водителя
 			request, err := ab.parseSetCityAndNotificationMessage(text)
@@ -805,6 +1179,8 @@ ADD_ORDER
 				response = fmt.Sprintf("✅ Заказ %s успешно архивирован!", orderUUID[:8])
 			}
 		}
+	} else if strings.HasPrefix(text, "URGENT_ORDER ") {
+		response = ab.handleUrgentOrder(strings.Fields(strings.TrimPrefix(text, "URGENT_ORDER ")))
 	} else if strings.HasPrefix(text, "ACTIVATE_ORDER ") {
 		orderUUID := strings.TrimSpace(strings.TrimPrefix(text, "ACTIVATE_ORDER "))
 		if orderUUID == "" {
@@ -835,6 +1211,80 @@ ADD_ORDER
 	}
 }
 
+// handleUrgentOrder отмечает заказ срочным или снимает отметку: URGENT_ORDER <UUID> [OFF]
+func (ab *AdminBot) handleUrgentOrder(args []string) string {
+	if len(args) == 0 || len(args) > 2 || (len(args) == 2 && !strings.EqualFold(args[1], "OFF")) {
+		return "❌ Укажите UUID заказа\n\nПример: URGENT_ORDER 12345678-1234-1234-1234-123456789abc\n" +
+			"Снять отметку: URGENT_ORDER 12345678-1234-1234-1234-123456789abc OFF"
+	}
+
+	urgent := len(args) == 1
+	order, err := ab.orderService.SetOrderUrgent(args[0], urgent)
+	if err != nil {
+		return fmt.Sprintf("❌ Ошибка изменения срочности заказа: %v", err)
+	}
+	if !urgent {
+		return fmt.Sprintf("✅ Заказ %s больше не срочный: водители в тихие часы получат его позже", order.UUID[:8])
+	}
+	return fmt.Sprintf("🔥 Заказ %s отмечен срочным: водители получат уведомления о нем и в тихие часы", order.UUID[:8])
+}
+
+// editOrderHelp описывает формат команды редактирования заказа
+const editOrderHelp = `✏️ Редактирование заказа
+
+Укажите UUID заказа и только те поля, которые нужно изменить:
+
+EDIT_ORDER <UUID>
+Название: ...
+Описание: ...
+Вес: ...
+Длина / Ширина / Высота: ... (см)
+Откуда город: ...
+Откуда адрес: ...
+Куда город: ...
+Куда адрес: ...
+Цена: ...
+Дата: ГГГГ-ММ-ДД
+Тип груза: ...
+Температура: +2..+6
+ADR: 3
+Негабарит: да / нет
+Хрупкий: да / нет
+
+Прочерк "-" очищает адрес, дату, тип груза, температуру или класс ADR.
+
+Пример:
+EDIT_ORDER 12345678-1234-1234-1234-123456789abc
+Цена: 18000
+Куда адрес: Невский проспект, д. 12
+
+Водители, получившие заказ, будут уведомлены об изменении цены, веса, маршрута, даты или характеристик груза.`
+
+// handleEditOrder применяет команду EDIT_ORDER и возвращает текст ответа
+func (ab *AdminBot) handleEditOrder(text string) string {
+	orderUUID, request, err := ab.parseEditOrderMessage(text)
+	if err != nil {
+		return fmt.Sprintf("❌ Ошибка парсинга изменений заказа: %v\n\n%s", err, editOrderHelp)
+	}
+
+	order, changes, err := ab.orderService.UpdateOrder(orderUUID, request)
+	if err != nil {
+		return fmt.Sprintf("❌ Ошибка изменения заказа: %v", err)
+	}
+	if len(changes) == 0 {
+		return "ℹ️ Значения совпадают с текущими, заказ не изменен."
+	}
+
+	var result strings.Builder
+	result.WriteString("✅ Заказ обновлен!\n\n")
+	for _, change := range changes {
+		result.WriteString(fmt.Sprintf("%s\n", change))
+	}
+	result.WriteString("\n")
+	result.WriteString(ab.formatOrders([]domain.Order{*order}))
+	return result.String()
+}
+
 // splitMessage разбивает длинное сообщение на части для Telegram
 func (ab *AdminBot) splitMessage(text string, maxLength int) []string {
 	if len(text) <= maxLength {
@@ -894,3 +1344,12 @@ func (ab *AdminBot) splitMessage(text string, maxLength int) []string {
 
 	return parts
 }
+
+// recalculateDistances пересчитывает расстояния маршрутов заказов и формирует отчет
+func (ab *AdminBot) recalculateDistances() string {
+	updated, err := ab.orderService.RecalculateDistances()
+	if err != nil {
+		return fmt.Sprintf("❌ Ошибка пересчета расстояний: %v", err)
+	}
+	return fmt.Sprintf("🛣 Расстояния маршрутов пересчитаны, изменено заказов: %d", updated)
+}
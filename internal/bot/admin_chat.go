@@ -0,0 +1,215 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"dalnoboy/internal/domain"
+	"dalnoboy/internal/service"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// chatReplyCallbackPrefix — данные кнопки ответа водителю: chatreply:<UUID переписки>
+const chatReplyCallbackPrefix = "chatreply:"
+
+// chatReplyTTL — сколько после нажатия «Ответить» следующее сообщение пересылается водителю
+const chatReplyTTL = 30 * time.Minute
+
+// recentChatsLimit — сколько переписок показывает команда CHATS
+const recentChatsLimit = 20
+
+// chatCodePattern находит номер переписки в пересланном ботом сообщении
+var chatCodePattern = regexp.MustCompile(`Чат #([0-9a-f]{8})`)
+
+// chatHelp описывает переписку водителей с заказчиками
+const chatHelp = `💬 Переписка водителей с заказчиками
+
+Водитель пишет заказчику из карточки заказа в своем боте. Сообщения пересылаются ботами: стороны не видят телефонов и Telegram друг друга. Если у заказчика нет Telegram, сообщения приходят администраторам.
+
+Ответить водителю: кнопка «↩️ Ответить» под сообщением или ответ (reply) на него.
+
+Последние переписки:
+CHATS
+
+Журнал переписки по заказу:
+CHAT_LOG <UUID заказа>
+
+Написать водителю от имени диспетчера:
+CHAT_REPLY <номер чата>
+Текст сообщения`
+
+// chatReplyTarget — переписка, в которую уйдет следующее сообщение чата
+type chatReplyTarget struct {
+	conversationUUID string
+	expiresAt        time.Time
+}
+
+// RelayToCustomer пересылает сообщение водителя заказчику или администратору
+func (ab *AdminBot) RelayToCustomer(chatID int64, conversation *domain.Conversation, toAdmin bool, text string) error {
+	header := fmt.Sprintf("💬 Водитель по заказу #%s «%s»", conversation.OrderUUID[:8], conversation.OrderTitle)
+	if toAdmin {
+		// Администраторам видно имя водителя; у заказчика нет Telegram, отвечает диспетчер
+		header = fmt.Sprintf("💬 Водитель %s по заказу #%s «%s» (у заказчика нет Telegram)",
+			conversation.DriverName, conversation.OrderUUID[:8], conversation.OrderTitle)
+	}
+
+	msg := tgbotapi.NewMessage(chatID, fmt.Sprintf("%s\n🔒 Чат #%s\n\n%s", header, conversation.ShortID(), text))
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("↩️ Ответить", chatReplyCallbackPrefix+conversation.UUID),
+	))
+	_, err := ab.bot.Send(msg)
+	return err
+}
+
+// handleChatReplyCallback запоминает переписку, в которую уйдет следующее сообщение
+func (ab *AdminBot) handleChatReplyCallback(query *tgbotapi.CallbackQuery) string {
+	if query.Message == nil {
+		return ""
+	}
+	chatID := query.Message.Chat.ID
+
+	ab.chatMu.Lock()
+	ab.chatReplies[chatID] = chatReplyTarget{
+		conversationUUID: strings.TrimPrefix(query.Data, chatReplyCallbackPrefix),
+		expiresAt:        time.Now().Add(chatReplyTTL),
+	}
+	ab.chatMu.Unlock()
+
+	if err := ab.sendText(chatID, "✏️ Напишите ответ водителю одним сообщением"); err != nil {
+		log.Printf("Ошибка отправки сообщения: %v", err)
+	}
+	return "Напишите ответ"
+}
+
+// handleChatReply пересылает водителю ответ на его сообщение: reply на пересланное сообщение
+// или следующее сообщение после кнопки «Ответить». Возвращает пустую строку, если сообщение
+// не является ответом в переписке.
+func (ab *AdminBot) handleChatReply(message *tgbotapi.Message) string {
+	text := strings.TrimSpace(message.Text)
+	chatID := message.Chat.ID
+	if text == "" || strings.HasPrefix(text, "/") || message.From == nil {
+		return ""
+	}
+
+	conversationRef := ""
+	if reply := message.ReplyToMessage; reply != nil && reply.From != nil && reply.From.ID == ab.bot.Self.ID {
+		if match := chatCodePattern.FindStringSubmatch(reply.Text); match != nil {
+			conversationRef = match[1]
+		}
+	}
+
+	ab.chatMu.Lock()
+	if target, ok := ab.chatReplies[chatID]; ok {
+		delete(ab.chatReplies, chatID)
+		if conversationRef == "" && time.Now().Before(target.expiresAt) {
+			conversationRef = target.conversationUUID
+		}
+	}
+	ab.chatMu.Unlock()
+
+	if conversationRef == "" {
+		return ""
+	}
+	return ab.sendToDriver(message.From.ID, chatID, conversationRef, text)
+}
+
+// sendToDriver пересылает сообщение заказчика или диспетчера водителю
+func (ab *AdminBot) sendToDriver(senderTelegramID, chatID int64, conversationRef, text string) string {
+	conversation, err := ab.chatService.SendToDriver(senderTelegramID, ab.isAdminChat(chatID), conversationRef, text)
+	if err != nil {
+		if service.IsValidationError(err) {
+			return fmt.Sprintf("❌ %v", err)
+		}
+		log.Printf("Ошибка пересылки сообщения водителю по переписке %s: %v", conversationRef, err)
+		return "❌ Не удалось отправить сообщение водителю"
+	}
+	return fmt.Sprintf("✅ Отправлено водителю (чат #%s)", conversation.ShortID())
+}
+
+// handleChatCommand обрабатывает команды переписки. Возвращает false, если текст не является такой командой.
+func (ab *AdminBot) handleChatCommand(chatID int64, message *tgbotapi.Message) (string, bool) {
+	firstLine, body, _ := strings.Cut(strings.TrimSpace(message.Text), "\n")
+	fields := strings.Fields(firstLine)
+	if len(fields) == 0 {
+		return "", false
+	}
+
+	switch fields[0] {
+	case "CHATS":
+		conversations, err := ab.chatService.GetRecentConversations(recentChatsLimit)
+		if err != nil {
+			log.Printf("Ошибка получения переписок: %v", err)
+			return "❌ Ошибка получения переписок из базы данных", true
+		}
+		return formatConversations(conversations), true
+	case "CHAT_LOG":
+		if len(fields) != 2 {
+			return chatHelp, true
+		}
+		conversations, messages, err := ab.chatService.GetOrderConversations(fields[1])
+		if err != nil {
+			return fmt.Sprintf("❌ %v", err), true
+		}
+		return formatChatLog(conversations, messages), true
+	case "CHAT_REPLY":
+		if len(fields) < 2 {
+			return chatHelp, true
+		}
+		text := strings.TrimSpace(strings.Join(fields[2:], " ") + "\n" + body)
+		if !ab.isAdminChat(chatID) {
+			return "❌ Команда доступна только администраторам", true
+		}
+		return ab.sendToDriver(message.From.ID, chatID, fields[1], text), true
+	}
+	return "", false
+}
+
+// formatConversations форматирует список переписок
+func formatConversations(conversations []domain.Conversation) string {
+	if len(conversations) == 0 {
+		return "💬 Переписок пока нет"
+	}
+
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("💬 Последние переписки (%d):\n\n", len(conversations)))
+	for i, conversation := range conversations {
+		text.WriteString(fmt.Sprintf("%d. Чат #%s — заказ #%s «%s»\n", i+1, conversation.ShortID(),
+			conversation.OrderUUID[:8], conversation.OrderTitle))
+		text.WriteString(fmt.Sprintf("   🚚 %s, сообщений: %d, последнее %s\n", conversation.DriverName,
+			conversation.MessagesCount, conversation.LastMessageAt.Format("02.01.2006 15:04")))
+		text.WriteString(fmt.Sprintf("   CHAT_LOG %s\n", conversation.OrderUUID))
+	}
+	return text.String()
+}
+
+// formatChatLog форматирует журнал переписок по заказу
+func formatChatLog(conversations []domain.Conversation, messages map[string][]domain.ChatMessage) string {
+	if len(conversations) == 0 {
+		return "💬 По заказу не было переписки"
+	}
+
+	senders := map[string]string{
+		domain.ChatSenderDriver:   "🚚 Водитель",
+		domain.ChatSenderCustomer: "👤 Заказчик",
+		domain.ChatSenderAdmin:    "🛠 Диспетчер",
+	}
+
+	var text strings.Builder
+	for _, conversation := range conversations {
+		text.WriteString(fmt.Sprintf("💬 Чат #%s: водитель %s, заказ #%s «%s»\n\n", conversation.ShortID(),
+			conversation.DriverName, conversation.OrderUUID[:8], conversation.OrderTitle))
+		for _, message := range messages[conversation.UUID] {
+			status := ""
+			if !message.Delivered {
+				status = " (не доставлено)"
+			}
+			text.WriteString(fmt.Sprintf("[%s] %s%s:\n%s\n\n", message.CreatedAt.Format("02.01 15:04"),
+				senders[message.Sender], status, message.Text))
+		}
+	}
+	return text.String()
+}
@@ -0,0 +1,226 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"dalnoboy/internal/domain"
+	"dalnoboy/internal/service"
+)
+
+// citiesHelp описывает команды управления справочником городов
+const citiesHelp = `🏙️ Справочник городов
+
+Добавить город (псевдонимы через запятую, необязательно):
+ADD_CITY
+Санкт-Петербург
+Питер, СПб
+
+Переименовать город:
+RENAME_CITY
+Старое название или UUID
+Новое название
+
+Добавить / удалить псевдонимы:
+ADD_CITY_ALIAS
+Город
+Питер, Ленинград
+
+REMOVE_CITY_ALIAS
+Город
+Ленинград
+
+Удалить город (только если он нигде не используется):
+DELETE_CITY <название или UUID>
+
+Проверить, как распознается название:
+FIND_CITY <текст>
+
+Задать координаты и регион (регион необязателен):
+SET_CITY_COORDS
+Подольск
+55.4242, 37.5547
+Московская область
+
+Загрузить встроенный справочник городов России (координаты и регионы):
+IMPORT_CITIES
+IMPORT_CITIES OVERWRITE — перезаписать уже заданные координаты
+
+Город ищется без учета регистра, "ё" и дефисов, с допуском небольших опечаток.`
+
+// handleCityCommand обрабатывает команды управления справочником городов.
+// Возвращает false, если текст не является такой командой.
+func (ab *AdminBot) handleCityCommand(text string) (string, bool) {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	command, argument, _ := strings.Cut(strings.TrimSpace(lines[0]), " ")
+	argument = strings.TrimSpace(argument)
+
+	// line возвращает строку сообщения с указанным номером или пустую строку
+	line := func(i int) string {
+		if i < len(lines) {
+			return strings.TrimSpace(lines[i])
+		}
+		return ""
+	}
+
+	switch command {
+	case "ADD_CITY":
+		if line(1) == "" {
+			return "❌ Укажите название города\n\n" + citiesHelp, true
+		}
+		name, aliases := line(1), splitList(line(2))
+		city, err := ab.cityService.CreateCity(&domain.CityRequest{Name: &name, Aliases: &aliases})
+		if err != nil {
+			return fmt.Sprintf("❌ Ошибка добавления города: %v", err), true
+		}
+		return "✅ Город добавлен!\n\n" + formatCity(city), true
+	case "RENAME_CITY":
+		if line(1) == "" || line(2) == "" {
+			return "❌ Укажите текущее и новое название города\n\n" + citiesHelp, true
+		}
+		newName := line(2)
+		city, err := ab.cityService.UpdateCity(line(1), &domain.CityRequest{Name: &newName})
+		if err != nil {
+			return fmt.Sprintf("❌ Ошибка переименования города: %v", err), true
+		}
+		return "✅ Город переименован!\n\n" + formatCity(city), true
+	case "ADD_CITY_ALIAS", "REMOVE_CITY_ALIAS":
+		aliases := splitList(line(2))
+		if line(1) == "" || len(aliases) == 0 {
+			return "❌ Укажите город и псевдонимы\n\n" + citiesHelp, true
+		}
+		var city *domain.City
+		var err error
+		if command == "ADD_CITY_ALIAS" {
+			city, err = ab.cityService.AddAliases(line(1), aliases)
+		} else {
+			city, err = ab.cityService.RemoveAliases(line(1), aliases)
+		}
+		if err != nil {
+			return fmt.Sprintf("❌ Ошибка изменения псевдонимов: %v", err), true
+		}
+		return "✅ Псевдонимы обновлены!\n\n" + formatCity(city), true
+	case "DELETE_CITY":
+		if argument == "" {
+			return "❌ Укажите название или UUID города\n\nПример: DELETE_CITY Тольятти", true
+		}
+		city, err := ab.cityService.DeleteCity(argument)
+		if err != nil {
+			return fmt.Sprintf("❌ Ошибка удаления города: %v", err), true
+		}
+		return fmt.Sprintf("🗑 Город %s удален из справочника", city.Name), true
+	case "SET_CITY_COORDS":
+		lat, lon, err := parseCoordinates(line(2))
+		if line(1) == "" || err != nil {
+			return fmt.Sprintf("❌ Укажите город и координаты (широта, долгота)\n\n%s", citiesHelp), true
+		}
+		request := &domain.CityRequest{Latitude: &lat, Longitude: &lon}
+		if region := line(3); region != "" {
+			request.Region = &region
+		}
+		city, err := ab.cityService.UpdateCity(line(1), request)
+		if err != nil {
+			return fmt.Sprintf("❌ Ошибка изменения координат: %v", err), true
+		}
+		return "✅ Координаты обновлены!\n\n" + formatCity(city) + "\n\n" + ab.recalculateDistances(), true
+	case "IMPORT_CITIES":
+		overwrite := strings.EqualFold(argument, "OVERWRITE")
+		result, err := ab.cityService.ImportBundledCities(overwrite)
+		if err != nil {
+			return fmt.Sprintf("❌ Ошибка импорта справочника: %v", err), true
+		}
+		return fmt.Sprintf("✅ Справочник импортирован\n\n➕ Добавлено: %d\n✏️ Обновлено: %d\n⏭ Пропущено (координаты уже заданы): %d\n\n%s",
+			result.Created, result.Updated, result.Skipped, ab.recalculateDistances()), true
+	case "FIND_CITY":
+		if argument == "" {
+			return "❌ Укажите название для поиска\n\nПример: FIND_CITY питер", true
+		}
+		city, err := ab.cityService.ResolveCity(argument)
+		if err != nil {
+			return fmt.Sprintf("🔍 %v", err), true
+		}
+		return "🔍 Найден город:\n\n" + formatCity(city), true
+	}
+
+	return "", false
+}
+
+// formatCities форматирует справочник городов
+func (ab *AdminBot) formatCities(cities []domain.City) string {
+	if len(cities) == 0 {
+		return "🏙️ Справочник городов пуст"
+	}
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("🏙️ Справочник городов (%d):\n\n", len(cities)))
+	for i, city := range cities {
+		result.WriteString(fmt.Sprintf("%d. %s", i+1, city.Name))
+		if len(city.Aliases) > 0 {
+			result.WriteString(fmt.Sprintf(" (%s)", strings.Join(city.Aliases, ", ")))
+		}
+		if !city.HasCoordinates() {
+			result.WriteString(" 📍❔")
+		}
+		result.WriteString("\n")
+	}
+	result.WriteString("\n📍❔ — координаты не заданы\nУправление: кнопка \"🛠 Управление городами\"")
+
+	return result.String()
+}
+
+// formatCity форматирует карточку города
+func formatCity(city *domain.City) string {
+	aliases := "нет"
+	if len(city.Aliases) > 0 {
+		aliases = strings.Join(city.Aliases, ", ")
+	}
+	region := "не указан"
+	if city.Region != nil {
+		region = *city.Region
+	}
+	coordinates := "не заданы"
+	if city.HasCoordinates() {
+		coordinates = fmt.Sprintf("%.4f, %.4f", *city.Latitude, *city.Longitude)
+	}
+	return fmt.Sprintf("🏙️ %s\n🗺 Регион: %s\n📍 Координаты: %s\n🔤 Псевдонимы: %s\n🆔 UUID: %s",
+		city.Name, region, coordinates, aliases, city.UUID)
+}
+
+// parseCoordinates разбирает строку вида "55.7558, 37.6173"
+func parseCoordinates(text string) (float64, float64, error) {
+	parts := strings.Split(text, ",")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("ожидается формат: широта, долгота")
+	}
+	lat, err := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("некорректная широта: %v", err)
+	}
+	lon, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("некорректная долгота: %v", err)
+	}
+	return lat, lon, nil
+}
+
+// splitList разбивает строку со списком через запятую, пропуская пустые элементы
+func splitList(text string) []string {
+	var result []string
+	for _, item := range strings.Split(text, ",") {
+		if item = strings.TrimSpace(item); item != "" && item != "-" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// cityLookupHint дополняет ошибку поиска города подсказкой о справочнике
+func cityLookupHint(err error) string {
+	var cityErr *service.CityNotFoundError
+	if errors.As(err, &cityErr) {
+		return "\n\nСписок городов: кнопка \"🏙️ Города\""
+	}
+	return ""
+}
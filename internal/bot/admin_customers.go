@@ -0,0 +1,197 @@
+package bot
+
+import (
+	"fmt"
+	"strings"
+
+	"dalnoboy/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// customersHelp описывает команды управления заказчиками
+const customersHelp = `🛠 Управление заказчиками
+
+Изменить данные (указывайте только нужные поля, "-" очищает Telegram данные):
+EDIT_CUSTOMER <UUID>
+Имя: ...
+Телефон: ...
+Telegram ID: ...
+Telegram Tag: ...
+
+Деактивировать / активировать:
+DEACTIVATE_CUSTOMER <UUID>
+ACTIVATE_CUSTOMER <UUID>
+
+Объединить дубликаты (заказы дубликата переходят основному заказчику, дубликат удаляется):
+MERGE_CUSTOMERS <UUID дубликата> <UUID основного>
+
+История заказов:
+CUSTOMER_ORDERS <UUID>
+
+Поиск по телефону в любом формате (8 900…, +7 900…):
+FIND_CUSTOMER <телефон>`
+
+// handleCustomerCommand обрабатывает команды управления заказчиками.
+// Возвращает false, если текст не является такой командой.
+func (ab *AdminBot) handleCustomerCommand(text string) (string, bool) {
+	command := strings.Fields(strings.SplitN(text, "\n", 2)[0])
+	if len(command) == 0 {
+		return "", false
+	}
+
+	switch command[0] {
+	case "EDIT_CUSTOMER":
+		return ab.handleEditCustomer(text), true
+	case "DEACTIVATE_CUSTOMER", "ACTIVATE_CUSTOMER":
+		customerUUID, err := parseUUIDArgs(command, 1)
+		if err != nil {
+			return fmt.Sprintf("❌ %v\n\nПример: %s 12345678-1234-1234-1234-123456789abc", err, command[0]), true
+		}
+		active := command[0] == "ACTIVATE_CUSTOMER"
+		customer, err := ab.customerService.SetCustomerActive(customerUUID[0], active)
+		if err != nil {
+			return fmt.Sprintf("❌ Ошибка изменения статуса заказчика: %v", err), true
+		}
+		if active {
+			return fmt.Sprintf("✅ Заказчик %s активирован", customer.Name), true
+		}
+		return fmt.Sprintf("⏸ Заказчик %s деактивирован. Новые заказы для него создать нельзя.", customer.Name), true
+	case "MERGE_CUSTOMERS":
+		uuids, err := parseUUIDArgs(command, 2)
+		if err != nil {
+			return fmt.Sprintf("❌ %v\n\nПример: MERGE_CUSTOMERS <UUID дубликата> <UUID основного>", err), true
+		}
+		target, moved, err := ab.customerService.MergeCustomers(uuids[0], uuids[1])
+		if err != nil {
+			return fmt.Sprintf("❌ Ошибка объединения заказчиков: %v", err), true
+		}
+		return fmt.Sprintf("✅ Заказчики объединены. Перенесено заказов: %d\n\n%s", moved, ab.formatCustomers([]domain.Customer{*target})), true
+	case "CUSTOMER_ORDERS":
+		customerUUID, err := parseUUIDArgs(command, 1)
+		if err != nil {
+			return fmt.Sprintf("❌ %v\n\nПример: CUSTOMER_ORDERS 12345678-1234-1234-1234-123456789abc", err), true
+		}
+		customer, orders, err := ab.customerService.GetCustomerOrders(customerUUID[0])
+		if err != nil {
+			return fmt.Sprintf("❌ Ошибка получения истории заказов: %v", err), true
+		}
+		return ab.formatCustomerOrders(customer, orders), true
+	case "FIND_CUSTOMER":
+		phone := strings.TrimSpace(strings.TrimPrefix(text, "FIND_CUSTOMER"))
+		customer, err := ab.customerService.GetCustomerByPhone(phone)
+		if err != nil {
+			return fmt.Sprintf("❌ Ошибка поиска заказчика: %v", err), true
+		}
+		if customer == nil {
+			return fmt.Sprintf("🔍 Заказчик с телефоном %s не найден", phone), true
+		}
+		return ab.formatCustomers([]domain.Customer{*customer}), true
+	}
+
+	return "", false
+}
+
+// handleEditCustomer применяет команду EDIT_CUSTOMER и возвращает текст ответа
+func (ab *AdminBot) handleEditCustomer(text string) string {
+	customerUUID, request, err := ab.parseEditCustomerMessage(text)
+	if err != nil {
+		return fmt.Sprintf("❌ Ошибка парсинга данных заказчика: %v\n\n%s", err, customersHelp)
+	}
+
+	customer, err := ab.customerService.UpdateCustomer(customerUUID, request)
+	if err != nil {
+		return fmt.Sprintf("❌ Ошибка изменения заказчика: %v", err)
+	}
+
+	return "✅ Данные заказчика обновлены!\n\n" + ab.formatCustomers([]domain.Customer{*customer})
+}
+
+// parseEditCustomerMessage парсит сообщение с изменениями заказчика
+// Формат: EDIT_CUSTOMER <UUID>\nПоле: значение\n...
+func (ab *AdminBot) parseEditCustomerMessage(text string) (uuid.UUID, *domain.UpdateCustomerRequest, error) {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	customerUUID, err := parseUUIDArgs(strings.Fields(lines[0]), 1)
+	if err != nil {
+		return uuid.Nil, nil, err
+	}
+	if len(lines) < 2 {
+		return uuid.Nil, nil, fmt.Errorf("не указано ни одного поля для изменения")
+	}
+
+	request := &domain.UpdateCustomerRequest{}
+	for _, line := range lines[1:] {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			return uuid.Nil, nil, fmt.Errorf("строка '%s' должна иметь вид 'Поле: значение'", line)
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "имя":
+			request.Name = &value
+		case "телефон":
+			request.Phone = &value
+		case "telegram id":
+			var telegramID int64
+			if value != "-" && value != "" {
+				telegramID, err = parseTelegramID(value)
+				if err != nil {
+					return uuid.Nil, nil, fmt.Errorf("некорректный Telegram ID: %v", err)
+				}
+			}
+			request.TelegramID = &telegramID
+		case "telegram tag":
+			if value == "-" {
+				value = ""
+			}
+			request.TelegramTag = &value
+		default:
+			return uuid.Nil, nil, fmt.Errorf("неизвестное поле '%s'", key)
+		}
+	}
+
+	return customerUUID[0], request, nil
+}
+
+// formatCustomerOrders форматирует историю заказов заказчика
+func (ab *AdminBot) formatCustomerOrders(customer *domain.Customer, orders []domain.Order) string {
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("📜 История заказов: %s (%s)\n", customer.Name, customer.Phone))
+
+	var total float64
+	active := 0
+	for _, order := range orders {
+		total += order.Price
+		if order.Status == domain.OrderStatusActive {
+			active++
+		}
+	}
+	result.WriteString(fmt.Sprintf("Всего: %d | 🟢 Активных: %d | 💰 Сумма: %.0f ₽\n\n", len(orders), active, total))
+	result.WriteString(ab.formatOrders(orders))
+
+	return result.String()
+}
+
+// parseUUIDArgs разбирает count UUID, следующих за именем команды
+func parseUUIDArgs(command []string, count int) ([]uuid.UUID, error) {
+	if len(command) != count+1 {
+		return nil, fmt.Errorf("команда %s ожидает UUID: %d", command[0], count)
+	}
+
+	result := make([]uuid.UUID, 0, count)
+	for _, arg := range command[1:] {
+		parsed, err := uuid.Parse(arg)
+		if err != nil {
+			return nil, fmt.Errorf("неверный формат UUID '%s'", arg)
+		}
+		result = append(result, parsed)
+	}
+	return result, nil
+}
@@ -0,0 +1,206 @@
+package bot
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"dalnoboy/internal/domain"
+	"dalnoboy/internal/service"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// podReportDefaultPeriod — период сводки POD_REPORT без указанных дат
+const podReportDefaultPeriod = 7 * 24 * time.Hour
+
+// deliveryHelp описывает назначение заказов и подтверждение доставки
+const deliveryHelp = `🚚 Перевозки и подтверждение доставки
+
+Назначить активный заказ водителю (водитель получит уведомление):
+ASSIGN_ORDER <UUID заказа> <UUID водителя>
+
+Снять заказ с водителя и вернуть в активные:
+UNASSIGN_ORDER <UUID заказа>
+
+Водитель подтверждает доставку в своем боте командой /deliver_<номер>: присылает фото подписанных документов и, по желанию, местоположение. Заказ переходит в статус «Доставлен», заказчик и администраторы получают фото.
+
+Фото подтверждения доставки заказа:
+POD <UUID заказа>
+
+Сводка доставок с выгрузкой в CSV (по умолчанию — последние 7 дней):
+POD_REPORT [ГГГГ-ММ-ДД] [ГГГГ-ММ-ДД]`
+
+// handleDeliveryCommand обрабатывает команды назначения заказов и подтверждения доставки.
+// Возвращает false, если текст не является такой командой.
+func (ab *AdminBot) handleDeliveryCommand(chatID int64, text string) (string, bool) {
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return "", false
+	}
+	args := fields[1:]
+
+	switch fields[0] {
+	case "ASSIGN_ORDER":
+		if len(args) != 2 {
+			return deliveryHelp, true
+		}
+		order, err := ab.deliveryService.AssignOrder(args[0], args[1])
+		if err != nil {
+			return fmt.Sprintf("❌ Ошибка назначения заказа: %v", err), true
+		}
+		return fmt.Sprintf("✅ Заказ %s «%s» назначен водителю %s", order.UUID[:8], order.Title, stringValue(order.AssignedDriverName)), true
+	case "UNASSIGN_ORDER":
+		if len(args) != 1 {
+			return deliveryHelp, true
+		}
+		order, err := ab.deliveryService.UnassignOrder(args[0])
+		if err != nil {
+			return fmt.Sprintf("❌ Ошибка снятия заказа: %v", err), true
+		}
+		return fmt.Sprintf("✅ Заказ %s снят с водителя и снова активен", order.UUID[:8]), true
+	case "POD":
+		if len(args) != 1 {
+			return deliveryHelp, true
+		}
+		attachments, err := ab.deliveryService.GetPODAttachments(args[0])
+		if err != nil {
+			return fmt.Sprintf("❌ %v", err), true
+		}
+		if len(attachments) == 0 {
+			return "📎 У заказа нет подтверждения доставки", true
+		}
+		if err := sendOrderAttachments(ab.bot, ab.attachmentService, domain.AttachmentBotAdmin, chatID, attachments); err != nil {
+			log.Printf("Ошибка отправки подтверждения доставки заказа %s: %v", args[0], err)
+		}
+		return formatAttachments(attachments), true
+	case "POD_REPORT":
+		return ab.podReport(chatID, args), true
+	}
+	return "", false
+}
+
+// podReport отправляет CSV сводки доставок за период и возвращает текст сводки.
+// Даты включительные: POD_REPORT 2025-01-01 2025-01-31.
+func (ab *AdminBot) podReport(chatID int64, args []string) string {
+	if len(args) > 2 {
+		return deliveryHelp
+	}
+
+	to := time.Now()
+	from := to.Add(-podReportDefaultPeriod)
+	if len(args) > 0 {
+		parsed, err := time.ParseInLocation("2006-01-02", args[0], time.Local)
+		if err != nil {
+			return fmt.Sprintf("❌ Некорректная дата: %s (ожидается ГГГГ-ММ-ДД)", args[0])
+		}
+		from = parsed
+	}
+	if len(args) > 1 {
+		parsed, err := time.ParseInLocation("2006-01-02", args[1], time.Local)
+		if err != nil {
+			return fmt.Sprintf("❌ Некорректная дата: %s (ожидается ГГГГ-ММ-ДД)", args[1])
+		}
+		to = parsed.AddDate(0, 0, 1)
+	}
+
+	deliveries, err := ab.deliveryService.GetDeliveries(from, to)
+	if err != nil {
+		return fmt.Sprintf("❌ Ошибка получения доставок: %v", err)
+	}
+
+	period := fmt.Sprintf("%s — %s", from.Format("02.01.2006"), to.Add(-time.Second).Format("02.01.2006"))
+	if len(deliveries) == 0 {
+		return fmt.Sprintf("✅ За период %s доставок нет", period)
+	}
+
+	var csvData bytes.Buffer
+	if err := service.WriteDeliveriesCSV(&csvData, deliveries, domain.AdminViewer()); err != nil {
+		log.Printf("Ошибка формирования CSV доставок: %v", err)
+	} else {
+		document := tgbotapi.NewDocument(chatID, tgbotapi.FileBytes{
+			Name:  fmt.Sprintf("pod_%s_%s.csv", from.Format("20060102"), to.Add(-time.Second).Format("20060102")),
+			Bytes: csvData.Bytes(),
+		})
+		if _, err := ab.bot.Send(document); err != nil {
+			log.Printf("Ошибка отправки CSV доставок: %v", err)
+		}
+	}
+
+	return formatDeliveries(period, deliveries)
+}
+
+// formatDeliveries форматирует сводку доставок за период
+func formatDeliveries(period string, deliveries []domain.Delivery) string {
+	var text strings.Builder
+	var total float64
+	withoutLocation := 0
+	for _, delivery := range deliveries {
+		total += delivery.Order.Price
+		if delivery.Order.DeliveryLatitude == nil {
+			withoutLocation++
+		}
+	}
+
+	text.WriteString(fmt.Sprintf("✅ Доставки за %s: %d на %.0f ₽\n", period, len(deliveries), total))
+	if withoutLocation > 0 {
+		text.WriteString(fmt.Sprintf("📍 Без геопозиции: %d\n", withoutLocation))
+	}
+	text.WriteString("\n")
+
+	for i, delivery := range deliveries {
+		order := &delivery.Order
+		text.WriteString(fmt.Sprintf("%d. #%s «%s» (%s)\n", i+1, order.UUID[:8], order.Title, formatRoute(order)))
+		text.WriteString(fmt.Sprintf("   🚚 %s, %s\n", stringValue(order.AssignedDriverName), order.DeliveredAt.Format("02.01.2006 15:04")))
+		text.WriteString(fmt.Sprintf("   📎 Файлов: %d", delivery.PODFiles))
+		if order.DeliveryLatitude != nil && order.DeliveryLongitude != nil {
+			text.WriteString(fmt.Sprintf(", 📍 %.5f, %.5f", *order.DeliveryLatitude, *order.DeliveryLongitude))
+		}
+		text.WriteString(fmt.Sprintf("\n   POD %s\n", order.UUID))
+	}
+	return text.String()
+}
+
+// NotifyOrderDelivered отправляет подтверждение доставки с фото документов и точкой выгрузки
+func (ab *AdminBot) NotifyOrderDelivered(chatID int64, order *domain.Order, podAttachments []domain.Attachment) error {
+	text := fmt.Sprintf("✅ Заказ #%s «%s» доставлен\n📍 %s\n", order.UUID[:8], order.Title, formatRoute(order))
+	if order.AssignedDriverName != nil {
+		text += fmt.Sprintf("🚚 Водитель: %s\n", *order.AssignedDriverName)
+	}
+	if order.DeliveredAt != nil {
+		text += fmt.Sprintf("🕒 %s\n", order.DeliveredAt.Format("02.01.2006 15:04"))
+	}
+	if len(podAttachments) > 0 {
+		text += fmt.Sprintf("📎 Фото документов: %d", len(podAttachments))
+	}
+	if err := ab.sendText(chatID, text); err != nil {
+		return err
+	}
+
+	if order.DeliveryLatitude != nil && order.DeliveryLongitude != nil {
+		if _, err := ab.bot.Send(tgbotapi.NewLocation(chatID, *order.DeliveryLatitude, *order.DeliveryLongitude)); err != nil {
+			log.Printf("Ошибка отправки точки доставки заказа %s: %v", order.UUID, err)
+		}
+	}
+
+	if len(podAttachments) == 0 {
+		return nil
+	}
+	return sendOrderAttachments(ab.bot, ab.attachmentService, domain.AttachmentBotAdmin, chatID, podAttachments)
+}
+
+// formatAssignment форматирует исполнителя заказа для списка заказов
+func formatAssignment(order *domain.Order) string {
+	if order.AssignedDriverName == nil {
+		return ""
+	}
+	result := "🚚 Водитель: " + *order.AssignedDriverName
+	if order.DeliveredAt != nil {
+		result += ", доставлен " + order.DeliveredAt.Format("02.01.2006 15:04")
+	} else if order.AssignedAt != nil {
+		result += ", назначен " + order.AssignedAt.Format("02.01.2006 15:04")
+	}
+	return result
+}
@@ -0,0 +1,118 @@
+package bot
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"dalnoboy/internal/service"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// exportsHelp описывает выгрузки для бухгалтерии
+const exportsHelp = `📤 Выгрузки в CSV / XLSX
+
+EXPORT_ORDERS [xlsx] [фильтры]
+EXPORT_CUSTOMERS [xlsx]
+EXPORT_DRIVERS [xlsx]
+
+Фильтры заказов — как в GET /v1/orders, в виде параметр=значение:
+from=ГГГГ-ММ-ДД to=ГГГГ-ММ-ДД — дата создания (включительно)
+status=active|assigned|delivered|archived|expired
+min_weight, max_weight, near_city, radius_km, direction, cargo_type, requirements, min_price_per_km, max_price_per_km, sort
+
+Пример отчета за месяц:
+EXPORT_ORDERS xlsx from=2025-01-01 to=2025-01-31 status=delivered`
+
+// handleExportCommand обрабатывает команды выгрузок. Возвращает false, если текст не является такой командой.
+func (ab *AdminBot) handleExportCommand(chatID int64, text string) (string, bool) {
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return "", false
+	}
+
+	var kind string
+	switch fields[0] {
+	case "EXPORT_ORDERS":
+		kind = service.ExportOrders
+	case "EXPORT_CUSTOMERS":
+		kind = service.ExportCustomers
+	case "EXPORT_DRIVERS":
+		kind = service.ExportDrivers
+	case "EXPORTS":
+		return exportsHelp, true
+	default:
+		return "", false
+	}
+	// В выгрузках телефоны заказчиков, поэтому они доступны только чатам администраторов
+	if !ab.isAdminChat(chatID) {
+		return "❌ Выгрузки доступны только администраторам", true
+	}
+
+	format, params, err := parseExportArgs(fields[1:])
+	if err != nil {
+		return fmt.Sprintf("❌ %v\n\n%s", err, exportsHelp), true
+	}
+
+	var data bytes.Buffer
+	var rows int
+	switch kind {
+	case service.ExportOrders:
+		filter, err := service.ParseOrderFilter(params)
+		if err != nil {
+			return fmt.Sprintf("❌ %v\n\n%s", err, exportsHelp), true
+		}
+		rows, err = ab.exportService.ExportOrders(&data, format, filter)
+	case service.ExportCustomers:
+		rows, err = ab.exportService.ExportCustomers(&data, format)
+	case service.ExportDrivers:
+		rows, err = ab.exportService.ExportDrivers(&data, format)
+	}
+	if err != nil {
+		if service.IsValidationError(err) {
+			return fmt.Sprintf("❌ %v", err), true
+		}
+		log.Printf("Ошибка выгрузки %s: %v", kind, err)
+		return "❌ Ошибка формирования выгрузки", true
+	}
+
+	document := tgbotapi.NewDocument(chatID, tgbotapi.FileBytes{
+		Name:  service.ExportFileName(kind, format, time.Now()),
+		Bytes: data.Bytes(),
+	})
+	document.Caption = fmt.Sprintf("📤 Строк: %d", rows)
+	if _, err := ab.bot.Send(document); err != nil {
+		log.Printf("Ошибка отправки выгрузки %s: %v", kind, err)
+		return "❌ Не удалось отправить файл выгрузки", true
+	}
+	return "✅ Выгрузка готова", true
+}
+
+// parseExportArgs разбирает формат и фильтры "параметр=значение". Слова без "=" продолжают
+// значение предыдущего параметра, чтобы можно было указать near_city=Нижний Новгород.
+func parseExportArgs(args []string) (string, url.Values, error) {
+	format := service.ExportFormatCSV
+	params := url.Values{}
+	lastKey := ""
+	for _, arg := range args {
+		if key, value, found := strings.Cut(arg, "="); found {
+			params.Set(key, value)
+			lastKey = key
+			continue
+		}
+		if parsed, err := service.ParseExportFormat(arg); err == nil {
+			format = parsed
+			lastKey = ""
+			continue
+		}
+		if lastKey == "" {
+			return "", nil, fmt.Errorf("не понятен параметр '%s', ожидается параметр=значение", arg)
+		}
+		params.Set(lastKey, params.Get(lastKey)+" "+arg)
+	}
+	return format, params, nil
+}
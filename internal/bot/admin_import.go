@@ -0,0 +1,312 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"dalnoboy/internal/domain"
+	"dalnoboy/internal/service"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	// importTargetTTL — сколько после команды IMPORT_ORDERS ожидается файл
+	importTargetTTL = 15 * time.Minute
+	// pendingImportTTL — сколько проверенный импорт ждет подтверждения
+	pendingImportTTL = 30 * time.Minute
+	// importReportLimit ограничивает число ошибок и заказов в отчете проверки
+	importReportLimit = 30
+)
+
+// Данные кнопок подтверждения импорта
+const (
+	importConfirmCallback = "importconfirm:"
+	importCancelCallback  = "importcancel:"
+)
+
+// importHelp описывает импорт заказов из таблицы
+const importHelp = `📥 Импорт заказов из таблицы
+
+Отправьте файл .csv или .xlsx с подписью:
+IMPORT_ORDERS <UUID заказчика>
+
+Или сначала отправьте команду IMPORT_ORDERS <UUID заказчика>, а затем файл в течение 15 минут.
+
+Первая строка таблицы — заголовки. Обязательные колонки: Название, Описание, Вес, Откуда, Куда, Цена. Необязательные: Адрес погрузки, Адрес выгрузки, Дата, Тип груза, Температура, ADR, Теги, Длина, Ширина, Высота, Негабарит, Хрупкий.
+
+Если заголовки в файле другие, укажите сопоставление в следующих строках подписи или команды:
+IMPORT_ORDERS <UUID заказчика>
+Груз = Название
+Сумма, руб = Цена
+
+Сначала бот проверяет все строки и присылает отчет: ошибки по строкам и неизвестные города. Заказы создаются одной транзакцией только после подтверждения:
+CONFIRM_IMPORT — создать заказы без ошибок
+CANCEL_IMPORT — отменить импорт`
+
+// importTarget — заказчик и сопоставление колонок для следующего файла чата
+type importTarget struct {
+	customerUUID string
+	mapping      map[string]string
+	expiresAt    time.Time
+}
+
+// pendingImport — проверенный импорт, ожидающий подтверждения
+type pendingImport struct {
+	orderImport *domain.OrderImport
+	expiresAt   time.Time
+}
+
+// parseImportCommand разбирает команду IMPORT_ORDERS <UUID заказчика> со строками сопоставления колонок
+func parseImportCommand(text string) (importTarget, bool, error) {
+	firstLine, rest, _ := strings.Cut(strings.TrimSpace(text), "\n")
+	fields := strings.Fields(firstLine)
+	if len(fields) == 0 || fields[0] != "IMPORT_ORDERS" {
+		return importTarget{}, false, nil
+	}
+	if len(fields) != 2 {
+		return importTarget{}, true, fmt.Errorf("укажите UUID заказчика: IMPORT_ORDERS <UUID заказчика>")
+	}
+
+	mapping, err := service.ParseImportMapping(strings.Split(rest, "\n"))
+	if err != nil {
+		return importTarget{}, true, err
+	}
+	return importTarget{customerUUID: fields[1], mapping: mapping, expiresAt: time.Now().Add(importTargetTTL)}, true, nil
+}
+
+// currentImportTarget возвращает заказчика, выбранного командой IMPORT_ORDERS, если он еще актуален
+func (ab *AdminBot) currentImportTarget(chatID int64) (importTarget, bool) {
+	ab.importMu.Lock()
+	defer ab.importMu.Unlock()
+	target, ok := ab.importTargets[chatID]
+	if !ok || time.Now().After(target.expiresAt) {
+		delete(ab.importTargets, chatID)
+		return importTarget{}, false
+	}
+	return target, true
+}
+
+// takePendingImport забирает ожидающий подтверждения импорт чата
+func (ab *AdminBot) takePendingImport(chatID int64) *domain.OrderImport {
+	ab.importMu.Lock()
+	defer ab.importMu.Unlock()
+	pending, ok := ab.pendingImports[chatID]
+	delete(ab.pendingImports, chatID)
+	if !ok || time.Now().After(pending.expiresAt) {
+		return nil
+	}
+	return pending.orderImport
+}
+
+// handleImportDocument проверяет таблицу заказов из документа и отправляет отчет.
+// Возвращает false, если документ не относится к импорту (например, это вложение заказа).
+func (ab *AdminBot) handleImportDocument(message *tgbotapi.Message) bool {
+	if message.Document == nil || !ab.isAdminChat(message.Chat.ID) {
+		return false
+	}
+	chatID := message.Chat.ID
+
+	target, isImport, err := parseImportCommand(message.Caption)
+	if !isImport {
+		// Файл без подписи после команды IMPORT_ORDERS; подпись ATTACH оставляем вложениям
+		if strings.TrimSpace(message.Caption) != "" {
+			return false
+		}
+		if target, isImport = ab.currentImportTarget(chatID); !isImport {
+			return false
+		}
+	}
+
+	response := ""
+	if err != nil {
+		response = fmt.Sprintf("❌ %v\n\n%s", err, importHelp)
+	} else {
+		ab.importMu.Lock()
+		delete(ab.importTargets, chatID)
+		ab.importMu.Unlock()
+		response = ab.prepareImport(chatID, message.Document, target)
+	}
+
+	if response != "" {
+		if err := ab.sendText(chatID, response); err != nil {
+			log.Printf("Ошибка отправки сообщения: %v", err)
+		}
+	}
+	return true
+}
+
+// prepareImport скачивает и проверяет таблицу. Если есть заказы без ошибок, отправляет отчет
+// с кнопками подтверждения и возвращает пустую строку, иначе возвращает текст ответа.
+func (ab *AdminBot) prepareImport(chatID int64, document *tgbotapi.Document, target importTarget) string {
+	file, err := ab.DownloadFile(document.FileID)
+	if err != nil {
+		log.Printf("Ошибка загрузки файла импорта %s: %v", document.FileName, err)
+		return "❌ Не удалось скачать файл"
+	}
+	defer file.Close()
+
+	rows, err := service.ReadOrderSheet(document.FileName, file)
+	if err != nil {
+		return fmt.Sprintf("❌ %v", err)
+	}
+
+	orderImport, err := ab.orderService.PrepareOrderImport(target.customerUUID, rows, target.mapping)
+	if err != nil {
+		if service.IsValidationError(err) {
+			return fmt.Sprintf("❌ %v", err)
+		}
+		log.Printf("Ошибка проверки импорта заказов: %v", err)
+		return "❌ Ошибка проверки таблицы"
+	}
+
+	report := formatOrderImport(document.FileName, orderImport)
+	if len(orderImport.Orders) == 0 {
+		return report + "\n\n❌ Нет строк без ошибок, заказы не созданы. Исправьте файл и отправьте его снова."
+	}
+
+	ab.importMu.Lock()
+	ab.pendingImports[chatID] = pendingImport{orderImport: orderImport, expiresAt: time.Now().Add(pendingImportTTL)}
+	ab.importMu.Unlock()
+
+	parts := ab.splitMessage(report+"\n\nСоздать заказы? Кнопки или команды CONFIRM_IMPORT / CANCEL_IMPORT.", 4000)
+	for i, part := range parts {
+		msg := tgbotapi.NewMessage(chatID, part)
+		if i == len(parts)-1 {
+			msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("✅ Создать %d", len(orderImport.Orders)), importConfirmCallback),
+				tgbotapi.NewInlineKeyboardButtonData("❌ Отменить", importCancelCallback),
+			))
+		}
+		if _, err := ab.bot.Send(msg); err != nil {
+			log.Printf("Ошибка отправки отчета импорта: %v", err)
+		}
+	}
+	return ""
+}
+
+// handleImportCommand обрабатывает команды импорта. Возвращает false, если текст не является такой командой.
+func (ab *AdminBot) handleImportCommand(chatID int64, text string) (string, bool) {
+	switch strings.TrimSpace(text) {
+	case "CONFIRM_IMPORT":
+		return ab.confirmImport(chatID), true
+	case "CANCEL_IMPORT":
+		return ab.cancelImport(chatID), true
+	}
+
+	target, isImport, err := parseImportCommand(text)
+	if !isImport {
+		return "", false
+	}
+	if !ab.isAdminChat(chatID) {
+		return "❌ Импорт заказов доступен только администраторам", true
+	}
+	if err != nil {
+		return fmt.Sprintf("❌ %v\n\n%s", err, importHelp), true
+	}
+
+	ab.importMu.Lock()
+	ab.importTargets[chatID] = target
+	ab.importMu.Unlock()
+	return fmt.Sprintf("📥 Отправьте файл .csv или .xlsx в течение 15 минут — заказы будут созданы для заказчика %s после проверки и подтверждения.", target.customerUUID), true
+}
+
+// handleImportCallback обрабатывает кнопки подтверждения и отмены импорта
+func (ab *AdminBot) handleImportCallback(query *tgbotapi.CallbackQuery) string {
+	if query.Message == nil || !ab.isAdminChat(query.Message.Chat.ID) {
+		return ""
+	}
+	chatID := query.Message.Chat.ID
+
+	// Убираем кнопки, чтобы импорт нельзя было подтвердить повторно
+	edit := tgbotapi.NewEditMessageReplyMarkup(chatID, query.Message.MessageID, tgbotapi.InlineKeyboardMarkup{InlineKeyboard: [][]tgbotapi.InlineKeyboardButton{}})
+	if _, err := ab.bot.Request(edit); err != nil {
+		log.Printf("Ошибка удаления кнопок импорта: %v", err)
+	}
+
+	response := ""
+	if strings.HasPrefix(query.Data, importConfirmCallback) {
+		response = ab.confirmImport(chatID)
+	} else {
+		response = ab.cancelImport(chatID)
+	}
+	if err := ab.sendText(chatID, response); err != nil {
+		log.Printf("Ошибка отправки сообщения: %v", err)
+	}
+	return "Готово"
+}
+
+// confirmImport создает заказы проверенного импорта
+func (ab *AdminBot) confirmImport(chatID int64) string {
+	orderImport := ab.takePendingImport(chatID)
+	if orderImport == nil {
+		return "❌ Нет импорта, ожидающего подтверждения. Отправьте файл заново.\n\n" + importHelp
+	}
+
+	if err := ab.orderService.CommitOrderImport(orderImport); err != nil {
+		if service.IsValidationError(err) {
+			return fmt.Sprintf("❌ %v", err)
+		}
+		log.Printf("Ошибка сохранения импорта заказов: %v", err)
+		return "❌ Ошибка сохранения заказов, ни один заказ не создан"
+	}
+	return fmt.Sprintf("✅ Создано заказов: %d. Рассылка водителям запущена.", len(orderImport.Orders))
+}
+
+// cancelImport отменяет ожидающий подтверждения импорт
+func (ab *AdminBot) cancelImport(chatID int64) string {
+	if ab.takePendingImport(chatID) == nil {
+		return "Нет импорта, ожидающего подтверждения"
+	}
+	return "🚫 Импорт отменен, заказы не созданы"
+}
+
+// formatOrderImport формирует отчет проверки импорта
+func formatOrderImport(fileName string, orderImport *domain.OrderImport) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("📥 Проверка файла %s\n\n", fileName))
+	sb.WriteString(fmt.Sprintf("📄 Строк с данными: %d\n✅ Готово к созданию: %d\n⚠️ С ошибками: %d\n",
+		orderImport.TotalRows, len(orderImport.Orders), len(orderImport.Errors)))
+
+	fields := make([]string, 0, len(orderImport.Columns))
+	for field := range orderImport.Columns {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	sb.WriteString("\n🗂 Колонки:\n")
+	for _, field := range fields {
+		sb.WriteString(fmt.Sprintf("• %s ← «%s»\n", service.ImportFieldName(field), orderImport.Columns[field]))
+	}
+
+	if len(orderImport.UnknownCities) > 0 {
+		sb.WriteString(fmt.Sprintf("\n❓ Неизвестные города: %s\nДобавьте их командами ADD_CITY или ADD_CITY_ALIAS и отправьте файл снова.\n",
+			strings.Join(orderImport.UnknownCities, ", ")))
+	}
+
+	if len(orderImport.Errors) > 0 {
+		sb.WriteString("\n⚠️ Ошибки:\n")
+		for i, rowErr := range orderImport.Errors {
+			if i == importReportLimit {
+				sb.WriteString(fmt.Sprintf("... и еще %d\n", len(orderImport.Errors)-importReportLimit))
+				break
+			}
+			sb.WriteString(fmt.Sprintf("Строка %d: %s\n", rowErr.Row, rowErr.Message))
+		}
+	}
+
+	if len(orderImport.Orders) > 0 {
+		sb.WriteString("\n📋 Будут созданы:\n")
+		for i, order := range orderImport.Orders {
+			if i == importReportLimit {
+				sb.WriteString(fmt.Sprintf("... и еще %d\n", len(orderImport.Orders)-importReportLimit))
+				break
+			}
+			sb.WriteString(fmt.Sprintf("• %s, %.1f кг, %s → %s, %.0f ₽\n",
+				order.Title, order.WeightKg, stringValue(order.FromCityName), stringValue(order.ToCityName), order.Price))
+		}
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
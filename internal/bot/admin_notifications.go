@@ -0,0 +1,52 @@
+package bot
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"dalnoboy/internal/domain"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// sendText отправляет сообщение в чат, разбивая его на части при необходимости
+func (ab *AdminBot) sendText(chatID int64, text string) error {
+	for _, part := range ab.splitMessage(text, 4000) {
+		if _, err := ab.bot.Send(tgbotapi.NewMessage(chatID, part)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NotifyOrderExpiring предупреждает, что заказ скоро будет снят с публикации
+func (ab *AdminBot) NotifyOrderExpiring(chatID int64, order *domain.Order, expiresAt time.Time) error {
+	text := fmt.Sprintf("⏰ Заказ #%s «%s» (%s) будет снят с публикации %s.\n\n"+
+		"Чтобы продлить его, измените дату погрузки:\nEDIT_ORDER %s\nДата: ГГГГ-ММ-ДД",
+		order.UUID[:8], order.Title, formatRoute(order), expiresAt.Format("02.01.2006 15:04"), order.UUID)
+	return ab.sendText(chatID, text)
+}
+
+// NotifyOrdersExpired сообщает о заказах, снятых с публикации автоматически
+func (ab *AdminBot) NotifyOrdersExpired(chatID int64, orders []domain.Order) error {
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("⌛ Сняты с публикации заказы с истекшим сроком (%d):\n\n", len(orders)))
+	for _, order := range orders {
+		text.WriteString(fmt.Sprintf("• #%s «%s» (%s)\n", order.UUID[:8], order.Title, formatRoute(&order)))
+	}
+	text.WriteString("\nВернуть заказ: обновите дату (EDIT_ORDER) и выполните ACTIVATE_ORDER <UUID>")
+	return ab.sendText(chatID, text.String())
+}
+
+// formatRoute форматирует маршрут заказа "Откуда → Куда"
+func formatRoute(order *domain.Order) string {
+	from, to := "?", "?"
+	if order.FromCityName != nil {
+		from = *order.FromCityName
+	}
+	if order.ToCityName != nil {
+		to = *order.ToCityName
+	}
+	return fmt.Sprintf("%s → %s", from, to)
+}
@@ -0,0 +1,259 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"dalnoboy/internal/domain"
+	"dalnoboy/internal/service"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// ratingCallbackPrefix — данные кнопки оценки: rate:<UUID заказа>:<оценка>
+const ratingCallbackPrefix = "rate:"
+
+// ratingCommentTTL — сколько после оценки следующее сообщение заказчика считается комментарием
+const ratingCommentTTL = 30 * time.Minute
+
+// driverReviewsLimit — сколько последних отзывов показывает DRIVER_REVIEWS
+const driverReviewsLimit = 10
+
+// ratingsHelp описывает работу с оценками водителей
+const ratingsHelp = `⭐ Оценки водителей
+
+После подтверждения доставки заказчик получает в этом боте кнопки для оценки водителя от 1 до 5 (и ссылку на сайт, если задан ratings.base_url), затем может написать комментарий.
+
+Последние отзывы о водителе:
+DRIVER_REVIEWS <UUID водителя>
+
+Приоритетная рассылка новых заказов: водители с рейтингом не ниже указанного получают заказ сразу, остальные — спустя задержку (если заказ еще активен):
+NOTIFY_PRIORITY <мин. рейтинг> <задержка, например 30m>
+NOTIFY_PRIORITY OFF — отключить
+NOTIFY_PRIORITY — текущая настройка`
+
+// ratingCommentTarget — оценка, к которой относится следующее сообщение заказчика
+type ratingCommentTarget struct {
+	orderUUID string
+	score     int
+	expiresAt time.Time
+}
+
+// RequestDriverRating просит заказчика оценить водителя доставленного заказа
+func (ab *AdminBot) RequestDriverRating(chatID int64, order *domain.Order, link string) error {
+	text := fmt.Sprintf("⭐ Оцените, пожалуйста, водителя по заказу #%s «%s»", order.UUID[:8], order.Title)
+	if order.AssignedDriverName != nil {
+		text += fmt.Sprintf("\n🚚 %s", *order.AssignedDriverName)
+	}
+	if link != "" {
+		text += "\n\nОценить на сайте: " + link
+	}
+
+	buttons := make([]tgbotapi.InlineKeyboardButton, 0, domain.MaxRatingScore)
+	for score := domain.MinRatingScore; score <= domain.MaxRatingScore; score++ {
+		buttons = append(buttons, tgbotapi.NewInlineKeyboardButtonData(
+			strings.Repeat("⭐", score), fmt.Sprintf("%s%s:%d", ratingCallbackPrefix, order.UUID, score)))
+	}
+
+	msg := tgbotapi.NewMessage(chatID, text)
+	// По две-три кнопки в ряд, чтобы звезды помещались на экране телефона
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(buttons[:3], buttons[3:])
+	_, err := ab.bot.Send(msg)
+	return err
+}
+
+// handleCallback обрабатывает нажатия inline-кнопок
+func (ab *AdminBot) handleCallback(query *tgbotapi.CallbackQuery) {
+	answer := ""
+	switch {
+	case strings.HasPrefix(query.Data, ratingCallbackPrefix):
+		answer = ab.handleRatingCallback(query)
+	case strings.HasPrefix(query.Data, chatReplyCallbackPrefix):
+		answer = ab.handleChatReplyCallback(query)
+	case strings.HasPrefix(query.Data, bidAcceptCallbackPrefix), strings.HasPrefix(query.Data, bidsCallbackPrefix):
+		answer = ab.handleBidCallback(query)
+	case strings.HasPrefix(query.Data, importConfirmCallback), strings.HasPrefix(query.Data, importCancelCallback):
+		answer = ab.handleImportCallback(query)
+	}
+
+	if _, err := ab.bot.Request(tgbotapi.NewCallback(query.ID, answer)); err != nil {
+		log.Printf("Ошибка ответа на нажатие кнопки: %v", err)
+	}
+}
+
+// handleRatingCallback сохраняет оценку водителя, выбранную кнопкой, и возвращает короткий ответ
+func (ab *AdminBot) handleRatingCallback(query *tgbotapi.CallbackQuery) string {
+	orderUUID, scoreText, _ := strings.Cut(strings.TrimPrefix(query.Data, ratingCallbackPrefix), ":")
+	score, err := strconv.Atoi(scoreText)
+	if err != nil {
+		return "❌ Некорректная оценка"
+	}
+
+	telegramID := query.From.ID
+	rating, err := ab.ratingService.RateDriver(&domain.RateDriverRequest{
+		OrderUUID:  orderUUID,
+		Score:      score,
+		Source:     domain.RatingSourceBot,
+		TelegramID: &telegramID,
+	})
+	if err != nil {
+		if service.IsValidationError(err) {
+			return fmt.Sprintf("❌ %v", err)
+		}
+		log.Printf("Ошибка сохранения оценки по заказу %s: %v", orderUUID, err)
+		return "❌ Не удалось сохранить оценку"
+	}
+
+	if query.Message != nil {
+		chatID := query.Message.Chat.ID
+		ab.ratingMu.Lock()
+		ab.ratingComments[chatID] = ratingCommentTarget{orderUUID: rating.OrderUUID, score: rating.Score, expiresAt: time.Now().Add(ratingCommentTTL)}
+		ab.ratingMu.Unlock()
+
+		text := fmt.Sprintf("%s\n\nВаша оценка: %s\nЕсли хотите, напишите комментарий одним сообщением.",
+			query.Message.Text, strings.Repeat("⭐", rating.Score))
+		edit := tgbotapi.NewEditMessageText(chatID, query.Message.MessageID, text)
+		if _, err := ab.bot.Send(edit); err != nil {
+			log.Printf("Ошибка обновления сообщения с оценкой: %v", err)
+		}
+	}
+	return "Спасибо за оценку!"
+}
+
+// handleRatingComment сохраняет текст заказчика как комментарий к только что поставленной оценке.
+// Возвращает пустую строку, если сообщение не является комментарием.
+func (ab *AdminBot) handleRatingComment(message *tgbotapi.Message) string {
+	text := strings.TrimSpace(message.Text)
+	chatID := message.Chat.ID
+	if text == "" || strings.HasPrefix(text, "/") || message.From == nil || ab.isAdminChat(chatID) {
+		return ""
+	}
+
+	ab.ratingMu.Lock()
+	target, ok := ab.ratingComments[chatID]
+	delete(ab.ratingComments, chatID)
+	ab.ratingMu.Unlock()
+	if !ok || time.Now().After(target.expiresAt) {
+		return ""
+	}
+
+	telegramID := message.From.ID
+	_, err := ab.ratingService.RateDriver(&domain.RateDriverRequest{
+		OrderUUID:  target.orderUUID,
+		Score:      target.score,
+		Comment:    text,
+		Source:     domain.RatingSourceBot,
+		TelegramID: &telegramID,
+	})
+	if err != nil {
+		if service.IsValidationError(err) {
+			return fmt.Sprintf("❌ %v", err)
+		}
+		log.Printf("Ошибка сохранения комментария к оценке по заказу %s: %v", target.orderUUID, err)
+		return "❌ Не удалось сохранить комментарий"
+	}
+	return "✅ Спасибо, комментарий сохранен!"
+}
+
+// handleRatingCommand обрабатывает команды оценок водителей и приоритета уведомлений.
+// Возвращает false, если текст не является такой командой.
+func (ab *AdminBot) handleRatingCommand(text string) (string, bool) {
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return "", false
+	}
+	args := fields[1:]
+
+	switch fields[0] {
+	case "DRIVER_REVIEWS":
+		if len(args) != 1 {
+			return ratingsHelp, true
+		}
+		ratings, err := ab.ratingService.GetDriverRatings(args[0], driverReviewsLimit)
+		if err != nil {
+			return fmt.Sprintf("❌ %v", err), true
+		}
+		return formatDriverReviews(ratings), true
+	case "NOTIFY_PRIORITY":
+		return ab.handleNotifyPriority(args), true
+	}
+	return "", false
+}
+
+// handleNotifyPriority показывает или меняет приоритетную рассылку новых заказов
+func (ab *AdminBot) handleNotifyPriority(args []string) string {
+	var priority domain.NotificationPriority
+	switch {
+	case len(args) == 0:
+		current, err := ab.notificationService.GetPriority()
+		if err != nil {
+			return fmt.Sprintf("❌ %v", err)
+		}
+		return "🔔 " + formatNotificationPriority(current) + "\n\n" + ratingsHelp
+	case len(args) == 1 && strings.EqualFold(args[0], "OFF"):
+	case len(args) == 2:
+		minRating, err := strconv.ParseFloat(strings.ReplaceAll(args[0], ",", "."), 64)
+		if err != nil {
+			return fmt.Sprintf("❌ Некорректный рейтинг: %s", args[0])
+		}
+		delay, err := parseDelay(args[1])
+		if err != nil {
+			return fmt.Sprintf("❌ Некорректная задержка: %s (например 30m, 1h или число минут)", args[1])
+		}
+		priority = domain.NotificationPriority{MinRating: minRating, Delay: delay}
+	default:
+		return ratingsHelp
+	}
+
+	if err := ab.notificationService.SetPriority(priority); err != nil {
+		return fmt.Sprintf("❌ %v", err)
+	}
+	return "✅ " + formatNotificationPriority(priority)
+}
+
+// parseDelay разбирает задержку в формате Go (30m, 1h30m) или число минут
+func parseDelay(value string) (time.Duration, error) {
+	if minutes, err := strconv.Atoi(value); err == nil {
+		return time.Duration(minutes) * time.Minute, nil
+	}
+	return time.ParseDuration(value)
+}
+
+// formatNotificationPriority описывает настройку приоритетной рассылки
+func formatNotificationPriority(priority domain.NotificationPriority) string {
+	if !priority.Enabled() {
+		return "Приоритетная рассылка выключена: новые заказы получают все подходящие водители сразу (по убыванию рейтинга)"
+	}
+	return fmt.Sprintf("Приоритетная рассылка: водители с рейтингом от %.1f получают заказы сразу, остальные — через %s",
+		priority.MinRating, priority.Delay)
+}
+
+// formatDriverRating форматирует средний рейтинг водителя
+func formatDriverRating(driver *domain.Driver) string {
+	if driver.RatingAvg == nil || driver.RatingCount == 0 {
+		return "⭐ Оценок нет"
+	}
+	return fmt.Sprintf("⭐ %.1f (оценок: %d)", *driver.RatingAvg, driver.RatingCount)
+}
+
+// formatDriverReviews форматирует последние отзывы о водителе
+func formatDriverReviews(ratings []domain.DriverRating) string {
+	if len(ratings) == 0 {
+		return "⭐ У водителя пока нет оценок"
+	}
+
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("⭐ Последние оценки (%d):\n\n", len(ratings)))
+	for i, rating := range ratings {
+		text.WriteString(fmt.Sprintf("%d. %s — %s, заказ #%s «%s»\n", i+1, strings.Repeat("⭐", rating.Score),
+			rating.CustomerName, rating.OrderUUID[:8], rating.OrderTitle))
+		if rating.Comment != nil {
+			text.WriteString(fmt.Sprintf("   💬 %s\n", *rating.Comment))
+		}
+		text.WriteString(fmt.Sprintf("   📅 %s\n", rating.CreatedAt.Format("02.01.2006 15:04")))
+	}
+	return text.String()
+}
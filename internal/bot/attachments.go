@@ -0,0 +1,242 @@
+package bot
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"dalnoboy/internal/domain"
+	"dalnoboy/internal/service"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// maxMediaGroupSize — Telegram принимает в одной медиагруппе от 2 до 10 файлов
+const maxMediaGroupSize = 10
+
+// fileDownloadClient скачивает файлы из Telegram
+var fileDownloadClient = &http.Client{Timeout: 2 * time.Minute}
+
+// attachmentFileFunc возвращает файл вложения для отправки ботом
+type attachmentFileFunc func(attachment *domain.Attachment) (tgbotapi.RequestFileData, error)
+
+// attachmentFromMessage собирает запрос на добавление вложения из фото или документа сообщения.
+// Возвращает nil, если в сообщении нет файла.
+func attachmentFromMessage(message *tgbotapi.Message) *domain.AddAttachmentRequest {
+	switch {
+	case len(message.Photo) > 0:
+		// Telegram присылает несколько размеров фото, последний — самый крупный
+		photo := message.Photo[len(message.Photo)-1]
+		return &domain.AddAttachmentRequest{
+			Kind:             domain.AttachmentKindPhoto,
+			TelegramFileID:   photo.FileID,
+			TelegramUniqueID: photo.FileUniqueID,
+			MimeType:         "image/jpeg",
+			SizeBytes:        int64(photo.FileSize),
+		}
+	case message.Document != nil:
+		return &domain.AddAttachmentRequest{
+			Kind:             domain.AttachmentKindDocument,
+			TelegramFileID:   message.Document.FileID,
+			TelegramUniqueID: message.Document.FileUniqueID,
+			FileName:         message.Document.FileName,
+			MimeType:         message.Document.MimeType,
+			SizeBytes:        int64(message.Document.FileSize),
+		}
+	}
+	return nil
+}
+
+// downloadTelegramFile скачивает файл, полученный ботом, по его file_id
+func downloadTelegramFile(api *tgbotapi.BotAPI, fileID string) (io.ReadCloser, error) {
+	url, err := api.GetFileDirectURL(fileID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения ссылки на файл: %v", err)
+	}
+
+	response, err := fileDownloadClient.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка скачивания файла: %v", err)
+	}
+	if response.StatusCode != http.StatusOK {
+		response.Body.Close()
+		return nil, fmt.Errorf("ошибка скачивания файла: %s", response.Status)
+	}
+	return response.Body, nil
+}
+
+// sendAttachments отправляет вложения медиагруппами: фото и документы отдельно, так как
+// Telegram не смешивает их в одной группе. Возвращает отправленные сообщения в порядке
+// вложений (nil для неотправленных), чтобы вызывающий мог сохранить новые file_id.
+func sendAttachments(api *tgbotapi.BotAPI, chatID int64, attachments []domain.Attachment, file attachmentFileFunc) ([]*tgbotapi.Message, error) {
+	sent := make([]*tgbotapi.Message, len(attachments))
+
+	var photos, documents []int
+	for i := range attachments {
+		if attachments[i].Kind == domain.AttachmentKindPhoto {
+			photos = append(photos, i)
+		} else {
+			documents = append(documents, i)
+		}
+	}
+
+	var firstErr error
+	for _, group := range [][]int{photos, documents} {
+		for start := 0; start < len(group); start += maxMediaGroupSize {
+			end := start + maxMediaGroupSize
+			if end > len(group) {
+				end = len(group)
+			}
+			if err := sendAttachmentChunk(api, chatID, attachments, group[start:end], file, sent); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	return sent, firstErr
+}
+
+// sendAttachmentChunk отправляет до 10 вложений одного вида
+func sendAttachmentChunk(api *tgbotapi.BotAPI, chatID int64, attachments []domain.Attachment, indexes []int, file attachmentFileFunc, sent []*tgbotapi.Message) error {
+	var files []tgbotapi.RequestFileData
+	defer func() {
+		// Закрываем файлы, открытые для загрузки
+		for _, data := range files {
+			if reader, ok := data.(tgbotapi.FileReader); ok {
+				if closer, ok := reader.Reader.(io.Closer); ok {
+					closer.Close()
+				}
+			}
+		}
+	}()
+
+	var ready []int
+	for _, i := range indexes {
+		data, err := file(&attachments[i])
+		if err != nil {
+			return fmt.Errorf("вложение %s: %v", attachments[i].UUID[:8], err)
+		}
+		files = append(files, data)
+		ready = append(ready, i)
+	}
+
+	if len(ready) == 1 {
+		attachment := &attachments[ready[0]]
+		var chattable tgbotapi.Chattable
+		if attachment.Kind == domain.AttachmentKindPhoto {
+			photo := tgbotapi.NewPhoto(chatID, files[0])
+			photo.Caption = stringValue(attachment.Caption)
+			chattable = photo
+		} else {
+			document := tgbotapi.NewDocument(chatID, files[0])
+			document.Caption = stringValue(attachment.Caption)
+			chattable = document
+		}
+		message, err := api.Send(chattable)
+		if err != nil {
+			return fmt.Errorf("ошибка отправки вложения: %v", err)
+		}
+		sent[ready[0]] = &message
+		return nil
+	}
+
+	media := make([]interface{}, len(ready))
+	for n, i := range ready {
+		if attachments[i].Kind == domain.AttachmentKindPhoto {
+			photo := tgbotapi.NewInputMediaPhoto(files[n])
+			photo.Caption = stringValue(attachments[i].Caption)
+			media[n] = photo
+		} else {
+			document := tgbotapi.NewInputMediaDocument(files[n])
+			document.Caption = stringValue(attachments[i].Caption)
+			media[n] = document
+		}
+	}
+
+	messages, err := api.SendMediaGroup(tgbotapi.NewMediaGroup(chatID, media))
+	if err != nil {
+		return fmt.Errorf("ошибка отправки медиагруппы: %v", err)
+	}
+	for n := range messages {
+		if n < len(ready) {
+			sent[ready[n]] = &messages[n]
+		}
+	}
+	return nil
+}
+
+// sentFileID возвращает file_id файла из отправленного ботом сообщения
+func sentFileID(message *tgbotapi.Message) string {
+	switch {
+	case message == nil:
+		return ""
+	case len(message.Photo) > 0:
+		return message.Photo[len(message.Photo)-1].FileID
+	case message.Document != nil:
+		return message.Document.FileID
+	}
+	return ""
+}
+
+// formatAttachments форматирует список вложений заказа
+func formatAttachments(attachments []domain.Attachment) string {
+	if len(attachments) == 0 {
+		return "📎 У заказа нет вложений"
+	}
+
+	result := fmt.Sprintf("📎 Вложения (%d):\n", len(attachments))
+	for i, attachment := range attachments {
+		icon := "🖼"
+		name := "Фото"
+		if attachment.Kind == domain.AttachmentKindDocument {
+			icon = "📄"
+			name = "Документ"
+			if attachment.FileName != nil {
+				name = *attachment.FileName
+			}
+		}
+		author := "администратор"
+		switch attachment.UploadedBy {
+		case domain.AttachmentUploaderCustomer:
+			author = "заказчик"
+		case domain.AttachmentUploaderDriver:
+			author = "водитель"
+		}
+		result += fmt.Sprintf("%d. %s %s (%s, %s)\n   🆔 %s\n", i+1, icon, name, author,
+			attachment.CreatedAt.Format("02.01.2006 15:04"), attachment.UUID)
+	}
+	return result
+}
+
+// sendOrderAttachments отправляет вложения заказа от имени бота bot. Файлы, которых этот бот
+// еще не видел, загружаются заново из хранилища или бота-источника, после чего их file_id
+// запоминается для следующих отправок.
+func sendOrderAttachments(api *tgbotapi.BotAPI, attachmentService *service.AttachmentService, bot string, chatID int64, attachments []domain.Attachment) error {
+	sent, err := sendAttachments(api, chatID, attachments, func(attachment *domain.Attachment) (tgbotapi.RequestFileData, error) {
+		if fileID := attachment.FileIDFor(bot); fileID != "" {
+			return tgbotapi.FileID(fileID), nil
+		}
+
+		content, err := attachmentService.OpenAttachment(attachment)
+		if err != nil {
+			return nil, err
+		}
+		name := stringValue(attachment.FileName)
+		if name == "" {
+			name = attachment.UUID[:8] + ".jpg"
+		}
+		return tgbotapi.FileReader{Name: name, Reader: content}, nil
+	})
+
+	for i := range attachments {
+		fileID := sentFileID(sent[i])
+		if fileID == "" || fileID == attachments[i].FileIDFor(bot) {
+			continue
+		}
+		if err := attachmentService.SetBotFileID(&attachments[i], bot, fileID); err != nil {
+			log.Printf("Ошибка сохранения file_id вложения %s: %v", attachments[i].UUID, err)
+		}
+	}
+	return err
+}
@@ -0,0 +1,259 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"dalnoboy/internal/domain"
+	"dalnoboy/internal/service"
+)
+
+// cargoTypesHelp описывает команды управления справочником типов грузов
+const cargoTypesHelp = `📦 Типы грузов и особые требования
+
+Показать справочник:
+CARGO_TYPES
+
+Добавить тип груза (латинский код для API и название):
+ADD_CARGO_TYPE
+food, Продукты питания
+
+Переименовать тип груза (код не меняется):
+RENAME_CARGO_TYPE
+Продукты питания
+Продукты
+
+Отключить / включить тип (отключенный нельзя выбрать для новых заказов):
+DEACTIVATE_CARGO_TYPE <код или название>
+ACTIVATE_CARGO_TYPE <код или название>
+
+Активные заказы по типу груза или требованию:
+ORDERS_BY_CARGO <тип или требование>[, ...]
+
+В ADD_ORDER и EDIT_ORDER груз описывается строками "Поле: значение":
+Тип груза: Продукты питания
+Температура: +2..+6
+ADR: 3
+Негабарит: да
+Хрупкий: да
+
+Требования для фильтров: реф, adr, негабарит, хрупкий.`
+
+// applyCargoField применяет строку "Поле: значение" с характеристиками груза к запросу.
+// Возвращает false, если поле не относится к грузу.
+func applyCargoField(request *domain.UpdateOrderRequest, key, value string) (bool, error) {
+	// Прочерк очищает тип груза, температуру и класс ADR
+	clearable := value
+	if clearable == "-" {
+		clearable = ""
+	}
+
+	switch key {
+	case "тип груза", "тип":
+		request.CargoType = &clearable
+	case "температура", "температурный режим":
+		request.TemperatureRange = &clearable
+	case "adr", "класс adr", "опасный груз":
+		request.ADRClass = &clearable
+	case "негабарит", "хрупкий":
+		flag, err := parseYesNo(value)
+		if err != nil {
+			return true, fmt.Errorf("поле '%s': %v", key, err)
+		}
+		if key == "негабарит" {
+			request.Oversize = &flag
+		} else {
+			request.Fragile = &flag
+		}
+	default:
+		return false, nil
+	}
+	return true, nil
+}
+
+// formatCargo форматирует тип груза и особые требования заказа в одну строку.
+// Возвращает пустую строку, если ничего не задано.
+func formatCargo(order *domain.Order) string {
+	var parts []string
+	if order.CargoTypeName != nil {
+		parts = append(parts, "📦 "+*order.CargoTypeName)
+	}
+
+	requirements := &order.Requirements
+	if temperature := service.FormatTemperatureRange(requirements.TemperatureMinC, requirements.TemperatureMaxC); temperature != "" {
+		parts = append(parts, "❄️ "+temperature)
+	}
+	if requirements.ADRClass != nil {
+		parts = append(parts, "☢️ ADR "+*requirements.ADRClass)
+	}
+	if requirements.Oversize {
+		parts = append(parts, "📐 Негабарит")
+	}
+	if requirements.Fragile {
+		parts = append(parts, "🥚 Хрупкий")
+	}
+	return strings.Join(parts, " · ")
+}
+
+// formatCargoTypes форматирует справочник типов грузов
+func formatCargoTypes(cargoTypes []domain.CargoType) string {
+	if len(cargoTypes) == 0 {
+		return "📦 Справочник типов грузов пуст"
+	}
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("📦 Типы грузов (%d):\n\n", len(cargoTypes)))
+	for _, cargoType := range cargoTypes {
+		status := "🟢"
+		if !cargoType.IsActive {
+			status = "⚪️"
+		}
+		result.WriteString(fmt.Sprintf("%s %s — %s\n", status, cargoType.Name, cargoType.Code))
+	}
+	return result.String()
+}
+
+// parseCargoFilter разбирает список типов груза и требований через запятую.
+// Каждая часть — требование (реф, adr, негабарит, хрупкий) или тип груза из справочника.
+func parseCargoFilter(cargoService *service.CargoService, text string) (string, []string, error) {
+	var cargoTypeUUID string
+	var requirements []string
+	for _, part := range strings.Split(text, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		if code, err := service.ParseRequirement(part); err == nil {
+			requirements = append(requirements, code)
+			continue
+		}
+
+		cargoType, err := cargoService.GetCargoType(part)
+		if errors.Is(err, service.ErrCargoTypeNotFound) {
+			return "", nil, fmt.Errorf("'%s' не является ни типом груза, ни требованием", part)
+		}
+		if err != nil {
+			return "", nil, err
+		}
+		if cargoTypeUUID != "" {
+			return "", nil, fmt.Errorf("можно указать только один тип груза")
+		}
+		cargoTypeUUID = cargoType.UUID.String()
+	}
+
+	if cargoTypeUUID == "" && len(requirements) == 0 {
+		return "", nil, fmt.Errorf("укажите тип груза или требование")
+	}
+	return cargoTypeUUID, requirements, nil
+}
+
+// handleCargoCommand обрабатывает команды справочника типов грузов.
+// Возвращает false, если текст не является такой командой.
+func (ab *AdminBot) handleCargoCommand(text string) (string, bool) {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	command, argument, _ := strings.Cut(strings.TrimSpace(lines[0]), " ")
+	argument = strings.TrimSpace(argument)
+
+	// line возвращает строку сообщения с указанным номером или пустую строку
+	line := func(i int) string {
+		if i < len(lines) {
+			return strings.TrimSpace(lines[i])
+		}
+		return ""
+	}
+
+	switch command {
+	case "CARGO_TYPES":
+		cargoTypes, err := ab.cargoService.GetCargoTypes(false)
+		if err != nil {
+			log.Printf("Ошибка получения типов грузов: %v", err)
+			return "❌ Ошибка получения справочника типов грузов", true
+		}
+		return formatCargoTypes(cargoTypes) + "\n" + cargoTypesHelp, true
+	case "ADD_CARGO_TYPE":
+		code, name, found := strings.Cut(line(1), ",")
+		if !found {
+			return "❌ Укажите код и название через запятую\n\n" + cargoTypesHelp, true
+		}
+		cargoType, err := ab.cargoService.CreateCargoType(code, name)
+		if err != nil {
+			return fmt.Sprintf("❌ Ошибка добавления типа груза: %v", err), true
+		}
+		return fmt.Sprintf("✅ Тип груза добавлен: %s — %s", cargoType.Name, cargoType.Code), true
+	case "RENAME_CARGO_TYPE":
+		if line(1) == "" || line(2) == "" {
+			return "❌ Укажите текущее и новое название\n\n" + cargoTypesHelp, true
+		}
+		cargoType, err := ab.cargoService.RenameCargoType(line(1), line(2))
+		if err != nil {
+			return fmt.Sprintf("❌ Ошибка переименования типа груза: %v", err), true
+		}
+		return fmt.Sprintf("✅ Тип груза переименован: %s — %s", cargoType.Name, cargoType.Code), true
+	case "DEACTIVATE_CARGO_TYPE", "ACTIVATE_CARGO_TYPE":
+		if argument == "" {
+			return "❌ Укажите код или название типа груза\n\n" + cargoTypesHelp, true
+		}
+		active := command == "ACTIVATE_CARGO_TYPE"
+		cargoType, err := ab.cargoService.SetCargoTypeActive(argument, active)
+		if err != nil {
+			return fmt.Sprintf("❌ Ошибка изменения типа груза: %v", err), true
+		}
+		if active {
+			return fmt.Sprintf("✅ Тип груза '%s' включен", cargoType.Name), true
+		}
+		return fmt.Sprintf("✅ Тип груза '%s' отключен. У существующих заказов он сохранится.", cargoType.Name), true
+	case "ORDERS_BY_CARGO":
+		cargoTypeUUID, requirements, err := parseCargoFilter(ab.cargoService, argument)
+		if err != nil {
+			return fmt.Sprintf("❌ %v\n\n%s", err, cargoTypesHelp), true
+		}
+		orders, err := ab.orderService.GetActiveOrders()
+		if err != nil {
+			log.Printf("Ошибка получения активных заказов: %v", err)
+			return "❌ Ошибка получения заказов из базы данных", true
+		}
+		return ab.formatOrders(service.FilterOrdersByCargo(orders, cargoTypeUUID, requirements)), true
+	}
+	return "", false
+}
+
+// handleCargoCommand показывает водителю активные заказы с нужным типом груза или требованием.
+// Формат: /cargo <тип или требование>[, ...]
+func (db *DriverBot) handleCargoCommand(driver *domain.Driver, args string) string {
+	if strings.TrimSpace(args) == "" {
+		cargoTypes, err := db.cargoService.GetCargoTypes(true)
+		if err != nil {
+			log.Printf("Ошибка получения типов грузов: %v", err)
+			return "❌ Ошибка получения справочника типов грузов"
+		}
+		names := make([]string, len(cargoTypes))
+		for i, cargoType := range cargoTypes {
+			names[i] = cargoType.Name
+		}
+		return fmt.Sprintf("📦 Типы грузов: %s\n\nТребования: реф, adr, негабарит, хрупкий\n\nПример: /cargo Продукты питания, реф",
+			strings.Join(names, ", "))
+	}
+
+	cargoTypeUUID, requirements, err := parseCargoFilter(db.cargoService, args)
+	if err != nil {
+		return fmt.Sprintf("❌ %v", err)
+	}
+
+	orders, err := db.orderService.GetActiveOrders()
+	if err != nil {
+		log.Printf("Ошибка получения активных заказов: %v", err)
+		return "❌ Ошибка получения заказов из базы данных"
+	}
+	orders = service.FilterOrdersForDriverVehicle(orders, driver)
+	return db.formatOrders(service.FilterOrdersByCargo(orders, cargoTypeUUID, requirements), driverContactViewer(driver))
+}
+
+// stringValue возвращает значение строки по указателю или пустую строку
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
@@ -0,0 +1,21 @@
+package bot
+
+import (
+	"fmt"
+
+	"dalnoboy/internal/domain"
+)
+
+// formatRouteRate форматирует расчетное расстояние маршрута и ставку за километр.
+// Возвращает пустую строку, если расстояние неизвестно.
+func formatRouteRate(order *domain.Order) string {
+	if order.DistanceKm == nil {
+		return ""
+	}
+
+	rate := order.PricePerKm()
+	if rate == nil {
+		return fmt.Sprintf("🛣 ~%.0f км", *order.DistanceKm)
+	}
+	return fmt.Sprintf("🛣 ~%.0f км | 📈 %.1f ₽/км", *order.DistanceKm, *rate)
+}
@@ -0,0 +1,84 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+
+	"dalnoboy/internal/domain"
+	"dalnoboy/internal/service"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// orderCommandPrefix — команда открытия заказа: /order_1a2b3c4d
+const orderCommandPrefix = "/order_"
+
+// orderCommand возвращает команду открытия заказа для списка заказов
+func orderCommand(order *domain.Order) string {
+	return orderCommandPrefix + order.UUID[:8]
+}
+
+// handleOrderCommand показывает водителю карточку активного заказа и его вложения медиагруппой.
+// Форматы: /order_1a2b3c4d или /order 1a2b3c4d
+func (db *DriverBot) handleOrderCommand(chatID int64, shortID string) {
+	order, err := db.orderService.FindActiveOrderByShortID(shortID)
+	if err != nil {
+		response := "❌ Ошибка получения заказа из базы данных"
+		if errors.Is(err, service.ErrOrderNotFound) {
+			response = "❌ Заказ не найден или уже снят с публикации"
+		} else if service.IsValidationError(err) {
+			response = fmt.Sprintf("❌ %v", err)
+		} else {
+			log.Printf("Ошибка получения заказа %s: %v", shortID, err)
+		}
+		db.sendOrLog(chatID, response)
+		return
+	}
+
+	card := tgbotapi.NewMessage(chatID, "🚚 Заказ\n"+db.formatOrderDetails(order, domain.DriverViewer(chatID)))
+	card.ReplyMarkup = orderCardMarkup(order)
+	if _, err := db.bot.Send(card); err != nil {
+		log.Printf("Ошибка отправки карточки заказа %s: %v", order.UUID, err)
+	}
+	if order.AttachmentsCount == 0 {
+		return
+	}
+
+	attachments, err := db.attachmentService.GetAttachments(order.UUID)
+	if err != nil {
+		log.Printf("Ошибка получения вложений заказа %s: %v", order.UUID, err)
+		db.sendOrLog(chatID, "❌ Не удалось загрузить фото и документы заказа")
+		return
+	}
+	attachments = service.FilterAttachmentsByPurpose(attachments, domain.AttachmentPurposeCargo)
+
+	if err := sendOrderAttachments(db.bot, db.attachmentService, domain.AttachmentBotDriver, chatID, attachments); err != nil {
+		log.Printf("Ошибка отправки вложений заказа %s: %v", order.UUID, err)
+	}
+}
+
+// DownloadFile скачивает файл, полученный ботом водителей
+func (db *DriverBot) DownloadFile(fileID string) (io.ReadCloser, error) {
+	return downloadTelegramFile(db.bot, fileID)
+}
+
+// sendOrLog отправляет текст водителю и логирует ошибку отправки
+func (db *DriverBot) sendOrLog(chatID int64, text string) {
+	if err := db.sendText(chatID, text); err != nil {
+		log.Printf("Ошибка отправки сообщения: %v", err)
+	}
+}
+
+// parseOrderCommand извлекает номер заказа из /order_1a2b3c4d или /order 1a2b3c4d
+func parseOrderCommand(text string) (string, bool) {
+	if strings.HasPrefix(text, orderCommandPrefix) {
+		return strings.TrimPrefix(text, orderCommandPrefix), true
+	}
+	if command, args, _ := strings.Cut(text, " "); command == "/order" {
+		return strings.TrimSpace(args), true
+	}
+	return "", false
+}
@@ -0,0 +1,181 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"dalnoboy/internal/domain"
+	"dalnoboy/internal/service"
+)
+
+// bidCommandPrefix — команда предложения своей цены: /bid_1a2b3c4d
+const bidCommandPrefix = "/bid_"
+
+// bidCallbackPrefix — данные кнопки «Предложить цену»: bid:<UUID заказа>
+const bidCallbackPrefix = "bid:"
+
+// bidPromptTTL — сколько после выбора заказа следующее сообщение считается предложением
+const bidPromptTTL = 30 * time.Minute
+
+// bidFormatHelp описывает формат предложения
+const bidFormatHelp = "Напишите цену, при желании дату погрузки и комментарий, например:\n45000\n45000 15.11\n45000 15.11.2026 Заберу с утра"
+
+// bidPrompt — заказ, по которому водитель вводит предложение
+type bidPrompt struct {
+	orderRef  string
+	expiresAt time.Time
+}
+
+// bidCommand возвращает команду предложения цены по заказу
+func bidCommand(order *domain.Order) string {
+	return bidCommandPrefix + order.UUID[:8]
+}
+
+// promptBid просит водителя ввести предложение по заказу следующим сообщением
+func (db *DriverBot) promptBid(chatID, telegramID int64, orderRef string) {
+	db.bidMu.Lock()
+	db.bidPrompts[telegramID] = bidPrompt{orderRef: orderRef, expiresAt: time.Now().Add(bidPromptTTL)}
+	db.bidMu.Unlock()
+
+	db.sendOrLog(chatID, fmt.Sprintf("💰 Предложение по заказу #%s\n\n%s", shortOrderRef(orderRef), bidFormatHelp))
+}
+
+// handleBidCommand обрабатывает /bid_1a2b3c4d (цена следующим сообщением)
+// и /bid 1a2b3c4d <цена> [дата] [комментарий]
+func (db *DriverBot) handleBidCommand(chatID, telegramID int64, text string) (string, bool) {
+	if strings.HasPrefix(text, bidCommandPrefix) {
+		db.promptBid(chatID, telegramID, strings.TrimPrefix(text, bidCommandPrefix))
+		return "", true
+	}
+
+	command, args, _ := strings.Cut(text, " ")
+	if command != "/bid" {
+		return "", false
+	}
+	orderRef, offer, _ := strings.Cut(strings.TrimSpace(args), " ")
+	if orderRef == "" {
+		return "💰 Формат: /bid <номер заказа> <цена> [дата ДД.ММ] [комментарий]", true
+	}
+	if strings.TrimSpace(offer) == "" {
+		db.promptBid(chatID, telegramID, orderRef)
+		return "", true
+	}
+	return db.placeBid(telegramID, orderRef, offer), true
+}
+
+// handleBidPrompt принимает предложение, если водитель выбрал заказ кнопкой или командой.
+// Возвращает false, если водитель не вводит предложение.
+func (db *DriverBot) handleBidPrompt(telegramID int64, text string) (string, bool) {
+	if strings.HasPrefix(text, "/") {
+		return "", false
+	}
+
+	// Ожидание предложения одноразовое, чтобы не перехватывать сообщения в открытом чате
+	db.bidMu.Lock()
+	prompt, ok := db.bidPrompts[telegramID]
+	delete(db.bidPrompts, telegramID)
+	db.bidMu.Unlock()
+
+	if !ok || time.Now().After(prompt.expiresAt) {
+		return "", false
+	}
+	return db.placeBid(telegramID, prompt.orderRef, text), true
+}
+
+// placeBid разбирает и сохраняет предложение водителя
+func (db *DriverBot) placeBid(telegramID int64, orderRef, offer string) string {
+	price, pickupDate, comment, err := parseBidOffer(offer)
+	if err != nil {
+		return fmt.Sprintf("❌ %v\n\nПовторите: /bid %s <цена> [дата ДД.ММ] [комментарий]", err, shortOrderRef(orderRef))
+	}
+
+	order, bid, err := db.bidService.PlaceBid(&domain.PlaceBidRequest{
+		DriverTelegramID: telegramID,
+		OrderRef:         orderRef,
+		Price:            price,
+		PickupDate:       pickupDate,
+		Comment:          comment,
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrOrderNotFound) {
+			return "❌ Заказ не найден или уже снят с публикации"
+		}
+		if service.IsValidationError(err) {
+			return fmt.Sprintf("❌ %v", err)
+		}
+		log.Printf("Ошибка сохранения предложения водителя %d по заказу %s: %v", telegramID, orderRef, err)
+		return "❌ Не удалось отправить предложение. Попробуйте позже."
+	}
+
+	response := fmt.Sprintf("✅ Предложение по заказу #%s «%s» отправлено: %.0f ₽", order.UUID[:8], order.Title, bid.Price)
+	if bid.PickupDate != nil {
+		response += fmt.Sprintf(", погрузка %s", bid.PickupDate.Format("02.01.2006"))
+	}
+	return response + "\nМы сообщим, когда заказчик примет решение. Новое предложение по этому заказу заменит текущее."
+}
+
+// NotifyBidAccepted сообщает водителю, что его предложение принято и заказ назначен ему
+func (db *DriverBot) NotifyBidAccepted(telegramID int64, order *domain.Order, bid *domain.Bid) error {
+	return db.sendText(telegramID, fmt.Sprintf("🎉 Ваше предложение принято: %.0f ₽\n📦 Вам назначен заказ\n%s\nПосле выгрузки подтвердите доставку: %s",
+		bid.Price, db.formatOrderDetails(order, domain.DriverViewer(telegramID)), deliverCommand(order)))
+}
+
+// NotifyBidRejected сообщает водителю, что по заказу выбрано другое предложение
+func (db *DriverBot) NotifyBidRejected(telegramID int64, order *domain.Order, bid *domain.Bid) error {
+	return db.sendText(telegramID, fmt.Sprintf("😔 По заказу #%s «%s» (%s) выбрано другое предложение. Ваше предложение: %.0f ₽",
+		order.UUID[:8], order.Title, formatRoute(order), bid.Price))
+}
+
+// parseBidOffer разбирает предложение: <цена> [дата ДД.ММ или ДД.ММ.ГГГГ] [комментарий]
+func parseBidOffer(text string) (float64, *time.Time, string, error) {
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return 0, nil, "", fmt.Errorf("укажите цену")
+	}
+
+	priceText := strings.TrimSuffix(strings.ReplaceAll(fields[0], ",", "."), "₽")
+	price, err := strconv.ParseFloat(priceText, 64)
+	if err != nil || price <= 0 {
+		return 0, nil, "", fmt.Errorf("некорректная цена '%s'", fields[0])
+	}
+
+	var pickupDate *time.Time
+	rest := fields[1:]
+	if len(rest) > 0 {
+		if date, ok := parseBidDate(rest[0]); ok {
+			pickupDate = &date
+			rest = rest[1:]
+		}
+	}
+	return price, pickupDate, strings.Join(rest, " "), nil
+}
+
+// parseBidDate разбирает дату погрузки ДД.ММ.ГГГГ или ДД.ММ (ближайшая такая дата не в прошлом)
+func parseBidDate(text string) (time.Time, bool) {
+	if date, err := time.ParseInLocation("02.01.2006", text, time.Local); err == nil {
+		return date, true
+	}
+	date, err := time.ParseInLocation("02.01", text, time.Local)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	now := time.Now()
+	date = date.AddDate(now.Year(), 0, 0)
+	if date.Before(time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)) {
+		date = date.AddDate(1, 0, 0)
+	}
+	return date, true
+}
+
+// shortOrderRef возвращает короткий номер заказа для сообщений
+func shortOrderRef(orderRef string) string {
+	if len(orderRef) > 8 {
+		return orderRef[:8]
+	}
+	return orderRef
+}
@@ -3,7 +3,9 @@ package bot
 import (
 	"fmt"
 	"log"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"dalnoboy/internal"
@@ -20,10 +22,33 @@ type DriverBot struct {
 	database      *database.Database
 	orderService  *service.OrderService
 	driverService *service.DriverService
+	cargoService  *service.CargoService
+
+	attachmentService *service.AttachmentService
+	deliveryService   *service.DeliveryService
+	podMu             sync.Mutex
+	podSessions       map[int64]*podSession // Незавершенные подтверждения доставки по Telegram ID водителя
+
+	chatService *service.ChatService
+	chatMu      sync.Mutex
+	activeChats map[int64]driverChat // Открытые переписки с заказчиками по Telegram ID водителя
+
+	bidService *service.BidService
+	bidMu      sync.Mutex
+	bidPrompts map[int64]bidPrompt // Заказ, по которому водитель вводит предложение
+
+	digestService *service.DigestService
+
+	termsURL         string // Ссылка на полные условия сервиса для шага регистрации
+	onboardingMu     sync.Mutex
+	onboardingBodies map[int64]string // Тип кузова, выбранный при регистрации, до ответа о грузоподъемности
+
+	settingsMu      sync.Mutex
+	settingsPrompts map[int64]settingsPrompt // Настройка, которую водитель вводит следующим сообщением
 }
 
 // NewDriverBot создает новый экземпляр бота для водителей
-func NewDriverBot(config *internal.Config, db *database.Database, orderService *service.OrderService, driverService *service.DriverService) (*DriverBot, error) {
+func NewDriverBot(config *internal.Config, db *database.Database, orderService *service.OrderService, driverService *service.DriverService, cargoService *service.CargoService, attachmentService *service.AttachmentService, deliveryService *service.DeliveryService, chatService *service.ChatService, bidService *service.BidService, digestService *service.DigestService) (*DriverBot, error) {
 	log.Printf("Инициализация бота для водителей с токеном: %s...", config.Bot.DriverToken[:10]+"...")
 
 	bot, err := tgbotapi.NewBotAPI(config.Bot.DriverToken)
@@ -39,9 +64,32 @@ func NewDriverBot(config *internal.Config, db *database.Database, orderService *
 		database:      db,
 		orderService:  orderService,
 		driverService: driverService,
+		cargoService:  cargoService,
+
+		attachmentService: attachmentService,
+		deliveryService:   deliveryService,
+		podSessions:       make(map[int64]*podSession),
+
+		chatService: chatService,
+		activeChats: make(map[int64]driverChat),
+
+		bidService: bidService,
+		bidPrompts: make(map[int64]bidPrompt),
+
+		digestService: digestService,
+
+		termsURL:         config.Bot.DriverTermsURL,
+		onboardingBodies: make(map[int64]string),
+
+		settingsPrompts: make(map[int64]settingsPrompt),
 	}, nil
 }
 
+// Username возвращает имя бота в Telegram для ссылок t.me
+func (db *DriverBot) Username() string {
+	return db.bot.Self.UserName
+}
+
 // Start запускает бота для водителей
 func (db *DriverBot) Start() error {
 	updateConfig := tgbotapi.NewUpdate(0)
@@ -53,14 +101,21 @@ func (db *DriverBot) Start() error {
 		if update.Message != nil {
 			// Обработка сообщений
 			db.handleMessage(update.Message)
+		} else if update.CallbackQuery != nil {
+			// Нажатия inline-кнопок
+			db.handleCallback(update.CallbackQuery)
+		} else if update.EditedMessage != nil && update.EditedMessage.Location != nil {
+			// Трансляция геопозиции приходит правками исходного сообщения
+			db.handleLiveLocation(update.EditedMessage)
 		}
 	}
 
 	return nil
 }
 
-// formatOrders форматирует заказы для отображения без ID
-func (db *DriverBot) formatOrders(orders []domain.Order) string {
+// formatOrders форматирует заказы для отображения без ID; контакты заказчика показываются
+// по правилам доступа для получателя viewer
+func (db *DriverBot) formatOrders(orders []domain.Order, viewer domain.ContactViewer) string {
 	if len(orders) == 0 {
 		return "📋 Заказов пока нет"
 	}
@@ -69,62 +124,120 @@ func (db *DriverBot) formatOrders(orders []domain.Order) string {
 	result.WriteString(fmt.Sprintf("📋 Список доступных заказов (%d):\n\n", len(orders)))
 
 	for i, order := range orders {
-		// Форматируем локации для межгородских перевозок
-		fromLoc := "Не указано"
-		toLoc := "Не указано"
-
-		if order.FromCityName != nil && order.ToCityName != nil {
-			// Основной маршрут между городами
-			fromLoc = fmt.Sprintf("%s → %s", *order.FromCityName, *order.ToCityName)
-
-			// Адреса в одной строке
-			if order.FromAddress != nil && order.ToAddress != nil {
-				toLoc = fmt.Sprintf("🏠 %s: %s | %s: %s",
-					*order.FromCityName, *order.FromAddress,
-					*order.ToCityName, *order.ToAddress)
-			} else if order.FromAddress != nil {
-				toLoc = fmt.Sprintf("🏠 %s: %s", *order.FromCityName, *order.FromAddress)
-			} else if order.ToAddress != nil {
-				toLoc = fmt.Sprintf("🏠 %s: %s", *order.ToCityName, *order.ToAddress)
-			} else {
-				toLoc = "🏠 Адреса не указаны"
-			}
-		} else if order.FromCityName != nil {
-			fromLoc = *order.FromCityName
-			if order.FromAddress != nil {
-				toLoc = fmt.Sprintf("🏠 Адрес: %s", *order.FromAddress)
-			}
-		} else if order.ToCityName != nil {
-			toLoc = *order.ToCityName
-			if order.ToAddress != nil {
-				fromLoc = fmt.Sprintf("🏠 Адрес: %s", *order.ToAddress)
-			}
-		}
+		result.WriteString(fmt.Sprintf("%d. 🚚 Заказ\n", i+1))
+		result.WriteString(db.formatOrderDetails(&order, viewer))
+		result.WriteString("\n")
+	}
+	return result.String()
+}
 
-		// Форматируем теги
-		tagsStr := "Нет тегов"
-		if len(order.Tags) > 0 {
-			tagsStr = strings.Join(order.Tags, ", ")
+// formatOrderDetails форматирует строки с описанием одного заказа
+func (db *DriverBot) formatOrderDetails(order *domain.Order, viewer domain.ContactViewer) string {
+	// Форматируем локации для межгородских перевозок
+	fromLoc := "Не указано"
+	toLoc := "Не указано"
+
+	if order.FromCityName != nil && order.ToCityName != nil {
+		// Основной маршрут между городами
+		fromLoc = fmt.Sprintf("%s → %s", *order.FromCityName, *order.ToCityName)
+
+		// Адреса в одной строке
+		if order.FromAddress != nil && order.ToAddress != nil {
+			toLoc = fmt.Sprintf("🏠 %s: %s | %s: %s",
+				*order.FromCityName, *order.FromAddress,
+				*order.ToCityName, *order.ToAddress)
+		} else if order.FromAddress != nil {
+			toLoc = fmt.Sprintf("🏠 %s: %s", *order.FromCityName, *order.FromAddress)
+		} else if order.ToAddress != nil {
+			toLoc = fmt.Sprintf("🏠 %s: %s", *order.ToCityName, *order.ToAddress)
+		} else {
+			toLoc = "🏠 Адреса не указаны"
 		}
-
-		result.WriteString(fmt.Sprintf("%d. 🚚 Заказ\n", i+1))
-		result.WriteString(fmt.Sprintf("   📝 %s\n", order.Title))
-		if order.Description != "" {
-			result.WriteString(fmt.Sprintf("   📄 %s\n", order.Description))
+	} else if order.FromCityName != nil {
+		fromLoc = *order.FromCityName
+		if order.FromAddress != nil {
+			toLoc = fmt.Sprintf("🏠 Адрес: %s", *order.FromAddress)
+		}
+	} else if order.ToCityName != nil {
+		toLoc = *order.ToCityName
+		if order.ToAddress != nil {
+			fromLoc = fmt.Sprintf("🏠 Адрес: %s", *order.ToAddress)
 		}
-		result.WriteString(fmt.Sprintf("   %s\n", fromLoc))
-		result.WriteString(fmt.Sprintf("   %s\n", toLoc))
-		result.WriteString(fmt.Sprintf("   ⚖️ %.1f кг | 💰 %.0f ₽\n", order.WeightKg, order.Price))
+	}
+
+	var result strings.Builder
+	if order.Urgent {
+		result.WriteString("   🔥 Срочно\n")
+	}
+	result.WriteString(fmt.Sprintf("   📝 %s\n", order.Title))
+	if order.Description != "" {
+		result.WriteString(fmt.Sprintf("   📄 %s\n", order.Description))
+	}
+	result.WriteString(fmt.Sprintf("   %s\n", fromLoc))
+	result.WriteString(fmt.Sprintf("   %s\n", toLoc))
+	result.WriteString(fmt.Sprintf("   ⚖️ %.1f кг | 💰 %.0f ₽\n", order.WeightKg, order.Price))
+	if cargo := formatCargo(order); cargo != "" {
+		result.WriteString(fmt.Sprintf("   %s\n", cargo))
+	}
+	if routeRate := formatRouteRate(order); routeRate != "" {
+		result.WriteString(fmt.Sprintf("   %s\n", routeRate))
+	}
+	if order.AvailableFrom != nil {
+		result.WriteString(fmt.Sprintf("   📅 %s\n", order.AvailableFrom.Format("02.01.2006")))
+	}
+	// Телефон заказчика виден только назначенному водителю, остальные пишут через бота
+	if service.CanViewCustomerContacts(order, viewer) {
 		result.WriteString(fmt.Sprintf("   👤 %s | 📱 %s\n", order.CustomerName, order.CustomerPhone))
+	} else {
+		result.WriteString(fmt.Sprintf("   👤 %s | 📱 %s (откроется после назначения)\n", order.CustomerName,
+			service.MaskPhone(order.CustomerPhone)))
+	}
+	result.WriteString(fmt.Sprintf("   💬 Написать заказчику: %s\n", chatCommand(order)))
+	if order.Status == domain.OrderStatusActive {
+		result.WriteString(fmt.Sprintf("   💰 Предложить свою цену: %s\n", bidCommand(order)))
+	}
+
+	// Добавляем теги только если они есть
+	if len(order.Tags) > 0 {
+		result.WriteString(fmt.Sprintf("   🏷️ %s\n", strings.Join(order.Tags, ", ")))
+	}
+	if order.AttachmentsCount > 0 {
+		result.WriteString(fmt.Sprintf("   📎 Фото и документы: %d\n", order.AttachmentsCount))
+	}
+	result.WriteString(fmt.Sprintf("   🔎 Подробнее: %s\n", orderCommand(order)))
+
+	return result.String()
+}
 
-		// Добавляем теги только если они есть
-		if len(order.Tags) > 0 {
-			result.WriteString(fmt.Sprintf("   🏷️ %s\n", tagsStr))
+// sendText отправляет водителю текстовое сообщение, разбивая его на части при необходимости
+func (db *DriverBot) sendText(chatID int64, text string) error {
+	for _, part := range db.splitMessage(text, 4000) {
+		if _, err := db.bot.Send(tgbotapi.NewMessage(chatID, part)); err != nil {
+			return err
 		}
+	}
+	return nil
+}
 
-		result.WriteString("\n")
+// NotifyNewOrder отправляет водителю уведомление о новом заказе
+func (db *DriverBot) NotifyNewOrder(telegramID int64, order *domain.Order) error {
+	header := "🆕 Новый заказ\n"
+	if order.Urgent {
+		header = "🔥 Срочный заказ\n"
 	}
-	return result.String()
+	return db.sendText(telegramID, header+db.formatOrderDetails(order, domain.DriverViewer(telegramID)))
+}
+
+// NotifyOrderUpdated сообщает водителю об изменении заказа, о котором он уже знает
+func (db *DriverBot) NotifyOrderUpdated(telegramID int64, order *domain.Order, changes []string) error {
+	var text strings.Builder
+	text.WriteString("✏️ Заказ изменен\n")
+	for _, change := range changes {
+		text.WriteString(fmt.Sprintf("   %s\n", change))
+	}
+	text.WriteString("\nАктуальные данные:\n")
+	text.WriteString(db.formatOrderDetails(order, domain.DriverViewer(telegramID)))
+	return db.sendText(telegramID, text.String())
 }
 
 // splitMessage разбивает длинное сообщение на части для Telegram
@@ -203,20 +316,35 @@ func (db *DriverBot) handleMessage(message *tgbotapi.Message) {
 	if name == "" {
 		name = message.From.UserName
 	}
-	driver, ensureErr := db.driverService.EnsureDriverExistsByTelegram(name, telegramID, tag)
+	// Ссылка t.me/<бот>?start=<параметр>, с которой водитель открыл бота, — источник привлечения
+	start, isStart := parseStartCommand(text)
+	driver, ensureErr := db.driverService.EnsureDriverExistsByTelegram(name, telegramID, tag, start.source)
 	if ensureErr != nil {
 		log.Printf("Не удалось авто-регистрировать водителя %d: %v", telegramID, ensureErr)
 	}
 
+	// Фото документов и местоположение во время подтверждения доставки
+	if db.handleDeliveryMessage(message) {
+		return
+	}
+
+	// Новый водитель сначала проходит регистрацию: телефон, город, транспорт и условия
+	if driver != nil && driver.IsOnboarding() {
+		db.handleOnboarding(message, driver, start, isStart)
+		return
+	}
+
+	if isStart {
+		db.handleStartCommand(chatID, start)
+		return
+	}
+
 	var response string
 	var keyboard tgbotapi.ReplyKeyboardMarkup
 
 	switch text {
-	case "/start":
-		response = "Добро пожаловать! Вы водитель. Выберите действие."
-		keyboard = driverMainMenuKeyboard()
 	case "/help", "❓ Помощь":
-		response = "Доступные команды:\n/start - Начать работу\n/help - Показать помощь\n/orders - Посмотреть заказы\n🔔 Включить уведомления - Получать новые заказы\n🔕 Выключить уведомления - Отключить получение заказов"
+		response = "Доступные команды:\n/start - Начать работу\n/help - Показать помощь\n/orders - Посмотреть заказы\n/find <слова> - Поиск заказов по названию, описанию, адресу и городу\n📍 Заказы рядом - Заказы с погрузкой или выгрузкой рядом с вашим городом\n🚛 Мой транспорт - Кузов, грузоподъемность и габариты для подбора заказов\n📈 Выгодные заказы - Активные заказы по убыванию ставки ₽/км\n/rate <мин ₽/км> [макс ₽/км] - Заказы со ставкой в заданных пределах\n/order <номер> - Карточка заказа с фото и документами\n🚚 Мои перевозки - Назначенные вам заказы\n/deliver <номер> - Подтвердить доставку фото документов и геопозицией\n/chat <номер> - Написать заказчику (ваши контакты заказчику не видны)\n/bid <номер> <цена> [дата ДД.ММ] [комментарий] - Предложить свою цену и дату погрузки\n🗞 Сводка - Сохраненные поиски и ежедневная сводка заказов (/search_add, /digest)\n/cargo <тип груза или требование> - Заказы по типу груза (реф, adr, негабарит, хрупкий)\n/radius <погрузка км> [выгрузка км] - Радиус поиска вокруг вашего города (\"-\" - отключить)\n⚙️ Настройки - Профиль, город, тихие часы, частота уведомлений и удаление аккаунта\n🔔 Включить уведомления - Получать новые заказы\n🔕 Выключить уведомления - Отключить получение заказов"
 	case "/orders", "📋 Заказы":
 		// Получаем только активные заказы через сервис
 		orders, err := db.orderService.GetActiveOrders()
@@ -224,9 +352,37 @@ func (db *DriverBot) handleMessage(message *tgbotapi.Message) {
 			log.Printf("Ошибка получения активных заказов: %v", err)
 			response = "❌ Ошибка получения заказов из базы данных"
 		} else {
-			response = db.formatOrders(orders)
+			// Водителю с заполненным транспортом показываем только подходящие по вместимости заказы
+			fitting := service.FilterOrdersForDriverVehicle(orders, driver)
+			response = db.formatOrders(fitting, domain.DriverViewer(telegramID))
+			if hidden := len(orders) - len(fitting); hidden > 0 {
+				response += fmt.Sprintf("\n🚛 Скрыто заказов, не подходящих вашему транспорту: %d", hidden)
+			}
+		}
+		keyboard = driverMainMenuKeyboard()
+	case "/nearby", "📍 Заказы рядом":
+		if driver == nil || driver.CityUUID == nil || driver.CityName == nil {
+			response = "❌ Ваш город не указан. Укажите его в «" + settingsButton + "» → «" + settingsCityButton + "»."
+		} else if orders, err := db.orderService.GetActiveOrdersForDriver(driver); err != nil {
+			log.Printf("Ошибка подбора заказов для водителя %s: %v", driver.UUID, err)
+			response = "❌ Ошибка получения заказов из базы данных"
+		} else {
+			response = fmt.Sprintf("📍 Ваш город: %s (%s)\n\n%s",
+				*driver.CityName, formatDriverRadius(driver), db.formatOrders(orders, domain.DriverViewer(telegramID)))
 		}
 		keyboard = driverMainMenuKeyboard()
+	case "/best", "📈 Выгодные заказы":
+		response = db.formatBestOrders(driver, nil)
+		keyboard = driverMainMenuKeyboard()
+	case "/my_orders", "🚚 Мои перевозки":
+		response = db.handleAssignedOrders(telegramID)
+		keyboard = driverMainMenuKeyboard()
+	case "🗞 Сводка":
+		response = db.formatDigestSettings(telegramID)
+		keyboard = driverMainMenuKeyboard()
+	case "🚛 Мой транспорт":
+		response = db.handleVehicleCommand(driver, "/vehicle")
+		keyboard = driverMainMenuKeyboard()
 	case "🔔 Включить уведомления":
 		// Включаем уведомления для текущего водителя
 		if driver == nil {
@@ -278,10 +434,75 @@ func (db *DriverBot) handleMessage(message *tgbotapi.Message) {
 			keyboard = filterMenuKeyboard()
 	*/
 
+	case chatFinishButton:
+		response = db.finishChat(telegramID)
+		keyboard = driverMainMenuKeyboard()
 	case "⬅️ Назад":
+		db.clearSettingsPrompt(telegramID)
 		response = "Главное меню"
 		keyboard = driverMainMenuKeyboard()
 	default:
+		if settingsResponse, settingsKeyboard, ok := db.handleSettings(chatID, driver, text); ok {
+			if settingsResponse == "" {
+				return
+			}
+			response = settingsResponse
+			keyboard = settingsKeyboard
+			break
+		}
+		if shortID, ok := parseDeliverCommand(text); ok {
+			db.startDelivery(chatID, telegramID, shortID)
+			return
+		}
+		if shortID, ok := parseChatCommand(text); ok {
+			db.startChat(chatID, telegramID, shortID)
+			return
+		}
+		if digestResponse, ok := db.handleDigestCommand(telegramID, text); ok {
+			response = digestResponse
+			keyboard = driverMainMenuKeyboard()
+			break
+		}
+		if bidResponse, ok := db.handleBidCommand(chatID, telegramID, text); ok {
+			if bidResponse == "" {
+				return
+			}
+			response = bidResponse
+			break
+		}
+		if bidResponse, ok := db.handleBidPrompt(telegramID, text); ok {
+			response = bidResponse
+			break
+		}
+		if chatResponse, ok := db.handleChatText(message); ok {
+			response = chatResponse
+			break
+		}
+		if shortID, ok := parseOrderCommand(text); ok {
+			db.handleOrderCommand(chatID, shortID)
+			return
+		}
+		if command, args, _ := strings.Cut(text, " "); command == "/radius" {
+			response = db.handleRadiusCommand(driver, args)
+			keyboard = driverMainMenuKeyboard()
+			break
+		} else if command == "/vehicle" || strings.HasPrefix(text, "/vehicle\n") {
+			response = db.handleVehicleCommand(driver, text)
+			keyboard = driverMainMenuKeyboard()
+			break
+		} else if command == "/rate" {
+			response = db.handleRateCommand(driver, args)
+			keyboard = driverMainMenuKeyboard()
+			break
+		} else if command == "/find" {
+			response = db.handleFindCommand(driver, strings.TrimSpace(args))
+			keyboard = driverMainMenuKeyboard()
+			break
+		} else if command == "/cargo" {
+			response = db.handleCargoCommand(driver, args)
+			keyboard = driverMainMenuKeyboard()
+			break
+		}
 		response = "Неизвестная команда. Используйте кнопки меню или /help для списка команд."
 	}
 
@@ -317,3 +538,99 @@ func (db *DriverBot) handleMessage(message *tgbotapi.Message) {
 		}
 	}
 }
+
+// handleRadiusCommand задает радиусы поиска заказов вокруг города водителя.
+// Формат: /radius <погрузка км> [выгрузка км]
+func (db *DriverBot) handleRadiusCommand(driver *domain.Driver, args string) string {
+	if driver == nil {
+		return "❌ Не удалось изменить радиус: водитель не найден."
+	}
+
+	params := strings.Fields(args)
+	if len(params) == 0 {
+		return fmt.Sprintf("📍 Текущие настройки: %s\n\nПример: /radius 50 100 — погрузка в пределах 50 км, выгрузка в пределах 100 км от вашего города. \"-\" отключает фильтр.",
+			formatDriverRadius(driver))
+	}
+
+	request := &domain.SetDriverRadiusRequest{DriverUUID: driver.UUID}
+	var err error
+	if request.PickupRadiusKm, err = parseRadius(params[0]); err != nil {
+		return fmt.Sprintf("❌ %v", err)
+	}
+	if len(params) > 1 {
+		if request.DeliveryRadiusKm, err = parseRadius(params[1]); err != nil {
+			return fmt.Sprintf("❌ %v", err)
+		}
+	}
+
+	if err := db.driverService.SetDriverRadius(request); err != nil {
+		log.Printf("Ошибка обновления радиусов водителя %s: %v", driver.UUID, err)
+		return fmt.Sprintf("❌ Не удалось изменить радиус: %v", err)
+	}
+	return fmt.Sprintf("✅ Радиус поиска обновлен: %s", formatRadius(request.PickupRadiusKm, request.DeliveryRadiusKm))
+}
+
+// maxBestOrders ограничивает длину списка выгодных заказов
+const maxBestOrders = 20
+
+// formatBestOrders показывает подходящие транспорту водителя активные заказы с известным расстоянием
+// по убыванию ставки за километр
+func (db *DriverBot) formatBestOrders(driver *domain.Driver, filter func([]domain.Order) []domain.Order) string {
+	orders, err := db.orderService.GetActiveOrders()
+	if err != nil {
+		log.Printf("Ошибка получения активных заказов: %v", err)
+		return "❌ Ошибка получения заказов из базы данных"
+	}
+
+	orders = service.FilterOrdersForDriverVehicle(orders, driver)
+	if filter != nil {
+		orders = filter(orders)
+	}
+	var rated []domain.Order
+	for _, order := range orders {
+		if order.PricePerKm() != nil {
+			rated = append(rated, order)
+		}
+	}
+	if err := service.SortOrders(rated, service.OrderSortPricePerKm); err != nil {
+		return fmt.Sprintf("❌ %v", err)
+	}
+	if len(rated) > maxBestOrders {
+		rated = rated[:maxBestOrders]
+	}
+
+	return "📈 Сначала заказы с самой высокой ставкой за километр\n\n" + db.formatOrders(rated, driverContactViewer(driver))
+}
+
+// handleRateCommand показывает заказы со ставкой за километр в заданных пределах.
+// Формат: /rate <мин ₽/км> [макс ₽/км]
+func (db *DriverBot) handleRateCommand(driver *domain.Driver, args string) string {
+	params := strings.Fields(args)
+	if len(params) == 0 {
+		return "Пример: /rate 30 — заказы от 30 ₽/км, /rate 30 60 — от 30 до 60 ₽/км"
+	}
+
+	var bounds [2]*float64
+	for i := 0; i < len(params) && i < 2; i++ {
+		if params[i] == "-" {
+			continue
+		}
+		value, err := strconv.ParseFloat(strings.ReplaceAll(params[i], ",", "."), 64)
+		if err != nil || value < 0 {
+			return fmt.Sprintf("❌ Некорректная ставка '%s': укажите число рублей за километр", params[i])
+		}
+		bounds[i] = &value
+	}
+
+	return db.formatBestOrders(driver, func(orders []domain.Order) []domain.Order {
+		return service.FilterOrdersByPricePerKm(orders, bounds[0], bounds[1])
+	})
+}
+
+// driverContactViewer возвращает получателя заказов для водителя; nil — анонимный получатель
+func driverContactViewer(driver *domain.Driver) domain.ContactViewer {
+	if driver == nil {
+		return domain.ContactViewer{}
+	}
+	return domain.DriverViewer(driver.TelegramID)
+}
@@ -0,0 +1,182 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"dalnoboy/internal/domain"
+	"dalnoboy/internal/service"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// chatCommandPrefix — команда начала переписки с заказчиком: /chat_1a2b3c4d
+const chatCommandPrefix = "/chat_"
+
+// chatCallbackPrefix — данные кнопки «Написать заказчику»: chat:<UUID заказа>
+const chatCallbackPrefix = "chat:"
+
+// chatFinishButton завершает переписку и возвращает главное меню
+const chatFinishButton = "❌ Завершить чат"
+
+// driverChatTTL — сколько после последнего сообщения текст водителя пересылается в открытый чат
+const driverChatTTL = 2 * time.Hour
+
+// driverChat — открытая переписка водителя
+type driverChat struct {
+	conversationUUID string
+	expiresAt        time.Time
+}
+
+// chatCommand возвращает команду начала переписки по заказу
+func chatCommand(order *domain.Order) string {
+	return chatCommandPrefix + order.UUID[:8]
+}
+
+// orderCardMarkup возвращает кнопки под карточкой заказа: переписка с заказчиком и предложение цены
+func orderCardMarkup(order *domain.Order) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("💬 Написать заказчику", chatCallbackPrefix+order.UUID),
+		tgbotapi.NewInlineKeyboardButtonData("💰 Предложить цену", bidCallbackPrefix+order.UUID),
+	))
+}
+
+// chatKeyboard возвращает меню открытой переписки
+func chatKeyboard() tgbotapi.ReplyKeyboardMarkup {
+	return tgbotapi.NewReplyKeyboard(
+		tgbotapi.NewKeyboardButtonRow(tgbotapi.NewKeyboardButton(chatFinishButton)),
+	)
+}
+
+// handleCallback обрабатывает нажатия inline-кнопок бота водителей
+func (db *DriverBot) handleCallback(query *tgbotapi.CallbackQuery) {
+	answer := ""
+	if query.Message != nil {
+		switch {
+		case strings.HasPrefix(query.Data, chatCallbackPrefix):
+			db.startChat(query.Message.Chat.ID, query.From.ID, strings.TrimPrefix(query.Data, chatCallbackPrefix))
+		case strings.HasPrefix(query.Data, bidCallbackPrefix):
+			db.promptBid(query.Message.Chat.ID, query.From.ID, strings.TrimPrefix(query.Data, bidCallbackPrefix))
+		}
+	}
+	if _, err := db.bot.Request(tgbotapi.NewCallback(query.ID, answer)); err != nil {
+		log.Printf("Ошибка ответа на нажатие кнопки: %v", err)
+	}
+}
+
+// startChat открывает переписку водителя с заказчиком по заказу
+func (db *DriverBot) startChat(chatID, telegramID int64, orderRef string) {
+	conversation, err := db.chatService.StartConversation(telegramID, orderRef)
+	if err != nil {
+		response := "❌ Не удалось открыть переписку"
+		if errors.Is(err, service.ErrOrderNotFound) {
+			response = "❌ Заказ не найден или уже снят с публикации"
+		} else if service.IsValidationError(err) {
+			response = fmt.Sprintf("❌ %v", err)
+		} else {
+			log.Printf("Ошибка открытия переписки водителя %d по заказу %s: %v", telegramID, orderRef, err)
+		}
+		db.sendOrLog(chatID, response)
+		return
+	}
+
+	db.setActiveChat(telegramID, conversation.UUID)
+	db.sendWithKeyboard(chatID, fmt.Sprintf("💬 Чат с заказчиком по заказу #%s «%s» (чат #%s)\n\n"+
+		"Пишите сообщения — бот перешлет их заказчику. Ваш телефон и Telegram заказчику не видны.\n"+
+		"Ответы заказчика придут сюда.", conversation.OrderUUID[:8], conversation.OrderTitle, conversation.ShortID()),
+		chatKeyboard())
+}
+
+// finishChat закрывает открытую переписку водителя
+func (db *DriverBot) finishChat(telegramID int64) string {
+	db.chatMu.Lock()
+	delete(db.activeChats, telegramID)
+	db.chatMu.Unlock()
+	return "💬 Чат завершен. Чтобы продолжить переписку, откройте заказ и нажмите «Написать заказчику»."
+}
+
+// setActiveChat делает переписку открытой для следующих сообщений водителя
+func (db *DriverBot) setActiveChat(telegramID int64, conversationUUID string) {
+	db.chatMu.Lock()
+	db.activeChats[telegramID] = driverChat{conversationUUID: conversationUUID, expiresAt: time.Now().Add(driverChatTTL)}
+	db.chatMu.Unlock()
+}
+
+// activeChat возвращает открытую переписку водителя или пустую строку
+func (db *DriverBot) activeChat(telegramID int64) string {
+	db.chatMu.Lock()
+	defer db.chatMu.Unlock()
+
+	chat, ok := db.activeChats[telegramID]
+	if !ok {
+		return ""
+	}
+	if time.Now().After(chat.expiresAt) {
+		delete(db.activeChats, telegramID)
+		return ""
+	}
+	return chat.conversationUUID
+}
+
+// handleChatText пересылает текст водителя в переписку: ответ (reply) на сообщение заказчика
+// или сообщение в открытом чате. Возвращает false, если текст не относится к переписке.
+func (db *DriverBot) handleChatText(message *tgbotapi.Message) (string, bool) {
+	text := strings.TrimSpace(message.Text)
+	if text == "" || strings.HasPrefix(text, "/") {
+		return "", false
+	}
+
+	conversationRef := ""
+	if reply := message.ReplyToMessage; reply != nil && reply.From != nil && reply.From.ID == db.bot.Self.ID {
+		if match := chatCodePattern.FindStringSubmatch(reply.Text); match != nil {
+			conversationRef = match[1]
+		}
+	}
+	if conversationRef == "" {
+		conversationRef = db.activeChat(message.From.ID)
+	}
+	if conversationRef == "" {
+		return "", false
+	}
+
+	conversation, err := db.chatService.SendFromDriver(message.From.ID, conversationRef, text)
+	if err != nil {
+		if service.IsValidationError(err) {
+			return fmt.Sprintf("❌ %v", err), true
+		}
+		log.Printf("Ошибка пересылки сообщения водителя %d: %v", message.From.ID, err)
+		return "❌ Не удалось отправить сообщение заказчику", true
+	}
+	db.setActiveChat(message.From.ID, conversation.UUID)
+	return fmt.Sprintf("✅ Отправлено заказчику (чат #%s)", conversation.ShortID()), true
+}
+
+// RelayToDriver пересылает водителю сообщение заказчика или диспетчера
+func (db *DriverBot) RelayToDriver(telegramID int64, conversation *domain.Conversation, sender, text string) error {
+	author := "Заказчик"
+	if sender == domain.ChatSenderAdmin {
+		author = "Диспетчер"
+	}
+
+	// Ответ открывает чат, если водитель сейчас не переписывается по другому заказу
+	if db.activeChat(telegramID) == "" {
+		db.setActiveChat(telegramID, conversation.UUID)
+	}
+
+	return db.sendText(telegramID, fmt.Sprintf("💬 %s по заказу #%s «%s»\n🔒 Чат #%s\n\n%s",
+		author, conversation.OrderUUID[:8], conversation.OrderTitle, conversation.ShortID(), text))
+}
+
+// parseChatCommand извлекает номер заказа из /chat_1a2b3c4d или /chat 1a2b3c4d
+func parseChatCommand(text string) (string, bool) {
+	if strings.HasPrefix(text, chatCommandPrefix) {
+		return strings.TrimPrefix(text, chatCommandPrefix), true
+	}
+	if command, args, _ := strings.Cut(text, " "); command == "/chat" {
+		return strings.TrimSpace(args), true
+	}
+	return "", false
+}
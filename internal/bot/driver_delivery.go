@@ -0,0 +1,264 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"dalnoboy/internal/domain"
+	"dalnoboy/internal/service"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// deliverCommandPrefix — команда подтверждения доставки: /deliver_1a2b3c4d
+const deliverCommandPrefix = "/deliver_"
+
+// podSessionTTL — сколько живет незавершенное подтверждение доставки
+const podSessionTTL = 2 * time.Hour
+
+// podSession собирает фото документов и координаты, пока водитель подтверждает доставку
+type podSession struct {
+	order        *domain.Order
+	files        []domain.AddAttachmentRequest
+	latitude     *float64
+	longitude    *float64
+	liveLocation bool   // Координаты обновляются трансляцией геопозиции
+	mediaGroupID string // Последний альбом, о котором уже ответили
+	expiresAt    time.Time
+}
+
+// driverDeliveryHelp описывает водителю порядок подтверждения доставки
+const driverDeliveryHelp = `📸 Подтверждение доставки заказа #%s
+
+1. Сфотографируйте подписанные документы (ТТН, акт) и отправьте фото сюда — можно альбомом.
+2. По желанию отправьте местоположение кнопкой ниже или включите трансляцию геопозиции через 📎 → Геопозиция.
+3. Нажмите «✅ Подтвердить доставку».
+
+Заказчик получит фото документов и отметку о доставке.`
+
+// NotifyOrderAssigned сообщает водителю, что ему назначен заказ
+func (db *DriverBot) NotifyOrderAssigned(telegramID int64, order *domain.Order) error {
+	return db.sendText(telegramID, fmt.Sprintf("📦 Вам назначен заказ\n%s\nПосле выгрузки подтвердите доставку: %s",
+		db.formatOrderDetails(order, domain.DriverViewer(telegramID)), deliverCommand(order)))
+}
+
+// NotifyOrderUnassigned сообщает водителю, что заказ с него снят
+func (db *DriverBot) NotifyOrderUnassigned(telegramID int64, order *domain.Order) error {
+	db.podMu.Lock()
+	if session := db.podSessions[telegramID]; session != nil && session.order.UUID == order.UUID {
+		delete(db.podSessions, telegramID)
+	}
+	db.podMu.Unlock()
+
+	return db.sendText(telegramID, fmt.Sprintf("↩️ Заказ #%s «%s» (%s) снят с вас администратором",
+		order.UUID[:8], order.Title, formatRoute(order)))
+}
+
+// deliverCommand возвращает команду подтверждения доставки заказа
+func deliverCommand(order *domain.Order) string {
+	return deliverCommandPrefix + order.UUID[:8]
+}
+
+// formatAssignedOrders форматирует заказы, которые водитель везет сейчас
+func formatAssignedOrders(orders []domain.Order, viewer domain.ContactViewer) string {
+	if len(orders) == 0 {
+		return "🚚 У вас нет назначенных заказов"
+	}
+
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("🚚 Ваши перевозки (%d):\n\n", len(orders)))
+	for i, order := range orders {
+		text.WriteString(fmt.Sprintf("%d. #%s «%s»\n", i+1, order.UUID[:8], order.Title))
+		text.WriteString(fmt.Sprintf("   📍 %s\n", formatRoute(&order)))
+		if order.ToAddress != nil {
+			text.WriteString(fmt.Sprintf("   🏠 Выгрузка: %s\n", *order.ToAddress))
+		}
+		text.WriteString(fmt.Sprintf("   👤 %s | 📱 %s | 💬 Написать: %s\n", order.CustomerName,
+			service.CustomerPhoneFor(&order, viewer), chatCommand(&order)))
+		text.WriteString(fmt.Sprintf("   💰 %.0f ₽\n", order.Price))
+		text.WriteString(fmt.Sprintf("   ✅ Подтвердить доставку: %s\n\n", deliverCommand(&order)))
+	}
+	return text.String()
+}
+
+// handleAssignedOrders показывает водителю его назначенные заказы
+func (db *DriverBot) handleAssignedOrders(telegramID int64) string {
+	orders, err := db.deliveryService.GetAssignedOrders(telegramID)
+	if err != nil {
+		if service.IsValidationError(err) {
+			return fmt.Sprintf("❌ %v", err)
+		}
+		log.Printf("Ошибка получения перевозок водителя %d: %v", telegramID, err)
+		return "❌ Ошибка получения заказов из базы данных"
+	}
+	return formatAssignedOrders(orders, domain.DriverViewer(telegramID))
+}
+
+// startDelivery начинает подтверждение доставки назначенного водителю заказа
+func (db *DriverBot) startDelivery(chatID, telegramID int64, shortID string) {
+	orders, err := db.deliveryService.GetAssignedOrders(telegramID)
+	if err != nil {
+		log.Printf("Ошибка получения перевозок водителя %d: %v", telegramID, err)
+		db.sendOrLog(chatID, "❌ Ошибка получения заказов из базы данных")
+		return
+	}
+
+	shortID = strings.ToLower(strings.TrimSpace(shortID))
+	var order *domain.Order
+	for i := range orders {
+		if shortID != "" && strings.HasPrefix(orders[i].UUID, shortID) {
+			order = &orders[i]
+			break
+		}
+	}
+	if order == nil {
+		db.sendOrLog(chatID, "❌ Заказ не найден среди ваших перевозок\n\n"+formatAssignedOrders(orders, domain.DriverViewer(telegramID)))
+		return
+	}
+
+	db.podMu.Lock()
+	db.podSessions[telegramID] = &podSession{order: order, expiresAt: time.Now().Add(podSessionTTL)}
+	db.podMu.Unlock()
+
+	db.sendWithKeyboard(chatID, fmt.Sprintf(driverDeliveryHelp, order.UUID[:8]), deliveryKeyboard())
+}
+
+// activePODSession возвращает незавершенное подтверждение доставки водителя
+func (db *DriverBot) activePODSession(telegramID int64) *podSession {
+	db.podMu.Lock()
+	defer db.podMu.Unlock()
+
+	session := db.podSessions[telegramID]
+	if session != nil && time.Now().After(session.expiresAt) {
+		delete(db.podSessions, telegramID)
+		return nil
+	}
+	return session
+}
+
+// handleDeliveryMessage обрабатывает сообщения водителя во время подтверждения доставки:
+// фото и документы, местоположение, подтверждение и отмену. Возвращает false, если
+// подтверждение не начато или сообщение к нему не относится.
+func (db *DriverBot) handleDeliveryMessage(message *tgbotapi.Message) bool {
+	telegramID := message.From.ID
+	chatID := message.Chat.ID
+
+	session := db.activePODSession(telegramID)
+	if session == nil {
+		return false
+	}
+
+	if request := attachmentFromMessage(message); request != nil {
+		request.Caption = message.Caption
+		db.podMu.Lock()
+		session.files = append(session.files, *request)
+		count := len(session.files)
+		// На альбом отвечаем один раз
+		repeated := message.MediaGroupID != "" && message.MediaGroupID == session.mediaGroupID
+		session.mediaGroupID = message.MediaGroupID
+		db.podMu.Unlock()
+
+		if !repeated {
+			db.sendWithKeyboard(chatID, fmt.Sprintf("📎 Файл получен (%d). Отправьте еще или нажмите «✅ Подтвердить доставку».", count), deliveryKeyboard())
+		}
+		return true
+	}
+
+	if message.Location != nil {
+		db.setPODLocation(session, message.Location, message.Location.LivePeriod > 0)
+		db.sendWithKeyboard(chatID, "📍 Местоположение получено", deliveryKeyboard())
+		return true
+	}
+
+	switch message.Text {
+	case "✅ Подтвердить доставку":
+		db.confirmDelivery(chatID, telegramID, session)
+		return true
+	case "❌ Отменить подтверждение":
+		db.podMu.Lock()
+		delete(db.podSessions, telegramID)
+		db.podMu.Unlock()
+		db.sendWithKeyboard(chatID, "Подтверждение доставки отменено", driverMainMenuKeyboard())
+		return true
+	}
+	return false
+}
+
+// handleLiveLocation обновляет координаты подтверждения по трансляции геопозиции
+func (db *DriverBot) handleLiveLocation(message *tgbotapi.Message) {
+	if message.From == nil || message.Location == nil {
+		return
+	}
+	if session := db.activePODSession(message.From.ID); session != nil {
+		db.setPODLocation(session, message.Location, true)
+	}
+}
+
+func (db *DriverBot) setPODLocation(session *podSession, location *tgbotapi.Location, live bool) {
+	latitude, longitude := location.Latitude, location.Longitude
+
+	db.podMu.Lock()
+	defer db.podMu.Unlock()
+	session.latitude = &latitude
+	session.longitude = &longitude
+	session.liveLocation = live
+}
+
+// confirmDelivery завершает подтверждение доставки
+func (db *DriverBot) confirmDelivery(chatID, telegramID int64, session *podSession) {
+	db.podMu.Lock()
+	request := &domain.ConfirmDeliveryRequest{
+		OrderUUID:        session.order.UUID,
+		DriverTelegramID: telegramID,
+		Files:            append([]domain.AddAttachmentRequest(nil), session.files...),
+		Latitude:         session.latitude,
+		Longitude:        session.longitude,
+	}
+	db.podMu.Unlock()
+
+	order, err := db.deliveryService.ConfirmDelivery(request)
+	if err != nil {
+		response := "❌ Не удалось подтвердить доставку. Попробуйте позже."
+		if service.IsValidationError(err) {
+			response = fmt.Sprintf("❌ %v", err)
+		} else {
+			log.Printf("Ошибка подтверждения доставки заказа %s: %v", request.OrderUUID, err)
+		}
+		db.sendWithKeyboard(chatID, response, deliveryKeyboard())
+		return
+	}
+
+	db.podMu.Lock()
+	delete(db.podSessions, telegramID)
+	db.podMu.Unlock()
+
+	response := fmt.Sprintf("✅ Доставка заказа #%s «%s» подтверждена. Спасибо!", order.UUID[:8], order.Title)
+	if request.Latitude == nil {
+		response += "\n📍 Местоположение не передано"
+	} else if session.liveLocation {
+		response += "\n📍 Трансляцию геопозиции можно остановить"
+	}
+	db.sendWithKeyboard(chatID, response, driverMainMenuKeyboard())
+}
+
+// sendWithKeyboard отправляет водителю сообщение с клавиатурой и логирует ошибку отправки
+func (db *DriverBot) sendWithKeyboard(chatID int64, text string, keyboard tgbotapi.ReplyKeyboardMarkup) {
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ReplyMarkup = keyboard
+	if _, err := db.bot.Send(msg); err != nil {
+		log.Printf("Ошибка отправки сообщения: %v", err)
+	}
+}
+
+// parseDeliverCommand извлекает номер заказа из /deliver_1a2b3c4d или /deliver 1a2b3c4d
+func parseDeliverCommand(text string) (string, bool) {
+	if strings.HasPrefix(text, deliverCommandPrefix) {
+		return strings.TrimPrefix(text, deliverCommandPrefix), true
+	}
+	if command, args, _ := strings.Cut(text, " "); command == "/deliver" {
+		return strings.TrimSpace(args), true
+	}
+	return "", false
+}
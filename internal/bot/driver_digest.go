@@ -0,0 +1,203 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"dalnoboy/internal/domain"
+	"dalnoboy/internal/service"
+)
+
+// searchDeleteCommandPrefix — команда удаления сохраненного поиска: /search_del_1a2b3c4d
+const searchDeleteCommandPrefix = "/search_del_"
+
+// digestHelp описывает сохраненные поиски и ежедневную сводку
+const digestHelp = `Сохранить поиск (город, радиус км, погрузка/выгрузка/любое, минимальная цена или "-"):
+/search_add Казань, 100, погрузка, 40000
+
+Включить сводку в 8 утра по вашему времени (часовой пояс можно не указывать, по умолчанию — пояс из настроек или вашего города):
+/digest 8 Asia/Yekaterinburg
+Выключить сводку: /digest off
+
+Сводка приходит даже при выключенных уведомлениях и содержит новые заказы, о которых вам еще не сообщали. Без сохраненных поисков заказы подбираются по вашему городу и радиусам.`
+
+// searchDirectionNames содержит названия направлений поиска для водителя
+var searchDirectionNames = map[string]string{
+	service.SearchDirectionPickup:   "погрузка",
+	service.SearchDirectionDelivery: "выгрузка",
+	service.SearchDirectionAny:      "погрузка или выгрузка",
+}
+
+// handleDigestCommand обрабатывает команды поисков и сводки. Возвращает false, если текст
+// не является такой командой.
+func (db *DriverBot) handleDigestCommand(telegramID int64, text string) (string, bool) {
+	if strings.HasPrefix(text, searchDeleteCommandPrefix) {
+		return db.deleteSearch(telegramID, strings.TrimPrefix(text, searchDeleteCommandPrefix)), true
+	}
+
+	command, args, _ := strings.Cut(text, " ")
+	args = strings.TrimSpace(args)
+	switch command {
+	case "/searches", "/digest_settings":
+		return db.formatDigestSettings(telegramID), true
+	case "/search_add":
+		return db.addSearch(telegramID, args), true
+	case "/search_del":
+		return db.deleteSearch(telegramID, args), true
+	case "/digest":
+		return db.setDigest(telegramID, args), true
+	}
+	return "", false
+}
+
+// formatDigestSettings показывает сохраненные поиски и настройки сводки
+func (db *DriverBot) formatDigestSettings(telegramID int64) string {
+	driver, searches, err := db.digestService.GetSearches(telegramID)
+	if err != nil {
+		return formatDigestError(err)
+	}
+
+	var text strings.Builder
+	if driver.DigestHour != nil {
+		text.WriteString(fmt.Sprintf("🗞 Сводка: ежедневно в %02d:00 (%s)\n\n", *driver.DigestHour, driver.TimezoneName()))
+	} else {
+		text.WriteString("🗞 Сводка выключена\n\n")
+	}
+
+	if len(searches) == 0 {
+		text.WriteString("🔎 Сохраненных поисков нет\n\n")
+	} else {
+		text.WriteString(fmt.Sprintf("🔎 Сохраненные поиски (%d):\n", len(searches)))
+		for i := range searches {
+			text.WriteString(fmt.Sprintf("%d. %s\n   ❌ Удалить: %s%s\n", i+1, formatSavedSearch(&searches[i]),
+				searchDeleteCommandPrefix, searches[i].ShortID()))
+		}
+		text.WriteString("\n")
+	}
+	text.WriteString(digestHelp)
+	return text.String()
+}
+
+// addSearch сохраняет поиск: <город>, <радиус км>, [направление], [мин. цена]
+func (db *DriverBot) addSearch(telegramID int64, args string) string {
+	parts := strings.Split(args, ",")
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+	if len(parts) < 2 || parts[0] == "" {
+		return "❌ Формат: /search_add <город>, <радиус км>, [погрузка|выгрузка|любое], [мин. цена]"
+	}
+
+	radius, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return fmt.Sprintf("❌ Некорректный радиус '%s'", parts[1])
+	}
+
+	request := &domain.SavedSearchRequest{
+		DriverTelegramID: telegramID,
+		CityName:         parts[0],
+		RadiusKm:         radius,
+	}
+	if len(parts) > 2 {
+		direction, ok := parseSearchDirection(parts[2])
+		if !ok {
+			return fmt.Sprintf("❌ Неизвестное направление '%s': используйте погрузка, выгрузка или любое", parts[2])
+		}
+		request.Direction = direction
+	}
+	if len(parts) > 3 && parts[3] != "" && parts[3] != "-" {
+		minPrice, err := strconv.ParseFloat(strings.ReplaceAll(parts[3], " ", ""), 64)
+		if err != nil {
+			return fmt.Sprintf("❌ Некорректная цена '%s'", parts[3])
+		}
+		request.MinPrice = &minPrice
+	}
+
+	search, err := db.digestService.AddSearch(request)
+	if err != nil {
+		return formatDigestError(err)
+	}
+	return fmt.Sprintf("✅ Поиск сохранен: %s\n\nВключите ежедневную сводку, если еще не включили: /digest 8", formatSavedSearch(search))
+}
+
+// deleteSearch удаляет сохраненный поиск
+func (db *DriverBot) deleteSearch(telegramID int64, ref string) string {
+	if err := db.digestService.DeleteSearch(telegramID, ref); err != nil {
+		return formatDigestError(err)
+	}
+	return "✅ Поиск удален"
+}
+
+// setDigest включает или выключает сводку: /digest <час> [часовой пояс] | off
+func (db *DriverBot) setDigest(telegramID int64, args string) string {
+	fields := strings.Fields(args)
+	if len(fields) == 0 {
+		return db.formatDigestSettings(telegramID)
+	}
+
+	var hour *int
+	timezone := ""
+	if !strings.EqualFold(fields[0], "off") && fields[0] != "выкл" {
+		value, err := strconv.Atoi(strings.TrimSuffix(fields[0], ":00"))
+		if err != nil {
+			return fmt.Sprintf("❌ Некорректный час '%s', укажите число от 0 до 23", fields[0])
+		}
+		hour = &value
+		if len(fields) > 1 {
+			timezone = fields[1]
+		}
+	}
+
+	driver, err := db.digestService.SetDigest(telegramID, hour, timezone)
+	if err != nil {
+		return formatDigestError(err)
+	}
+	if driver.DigestHour == nil {
+		return "🔕 Ежедневная сводка выключена"
+	}
+	return fmt.Sprintf("✅ Сводка будет приходить ежедневно в %02d:00 (%s)", *driver.DigestHour, driver.TimezoneName())
+}
+
+// SendDigest отправляет водителю ежедневную сводку заказов
+func (db *DriverBot) SendDigest(telegramID int64, orders []domain.Order, total int) error {
+	text := "🗞 Сводка новых заказов\n\n" + db.formatOrders(orders, domain.DriverViewer(telegramID))
+	if total > len(orders) {
+		text += fmt.Sprintf("\nИ еще подходящих заказов: %d — смотрите «📋 Заказы»", total-len(orders))
+	}
+	return db.sendText(telegramID, text)
+}
+
+// formatSavedSearch форматирует сохраненный поиск одной строкой
+func formatSavedSearch(search *domain.SavedSearch) string {
+	text := fmt.Sprintf("%s, %d км, %s", search.CityName, search.RadiusKm, searchDirectionNames[search.Direction])
+	if search.MinPrice != nil {
+		text += fmt.Sprintf(", от %.0f ₽", *search.MinPrice)
+	}
+	return text
+}
+
+// formatDigestError преобразует ошибку сервиса сводок в ответ бота
+func formatDigestError(err error) string {
+	var cityErr *service.CityNotFoundError
+	if service.IsValidationError(err) || errors.As(err, &cityErr) {
+		return fmt.Sprintf("❌ %v", err)
+	}
+	log.Printf("Ошибка настройки сводки: %v", err)
+	return "❌ Ошибка сохранения настроек. Попробуйте позже."
+}
+
+// parseSearchDirection разбирает направление поиска
+func parseSearchDirection(text string) (string, bool) {
+	switch strings.ToLower(text) {
+	case "", "любое", "any", "-":
+		return service.SearchDirectionAny, true
+	case "погрузка", "pickup":
+		return service.SearchDirectionPickup, true
+	case "выгрузка", "delivery":
+		return service.SearchDirectionDelivery, true
+	}
+	return "", false
+}
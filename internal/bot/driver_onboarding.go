@@ -0,0 +1,295 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"dalnoboy/internal/domain"
+	"dalnoboy/internal/service"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Кнопки регистрации водителя
+const (
+	onboardingPhoneButton   = "📱 Отправить мой номер"
+	onboardingSkipButton    = "⏭ Пропустить"
+	onboardingBackButton    = "⬅️ Другой кузов"
+	onboardingAcceptButton  = "✅ Принимаю условия"
+	onboardingPayloadPrompt = "⚖️ Какая грузоподъемность? Например: 1.5 т или 1500 кг"
+)
+
+// onboardingPayloadChoices — частые значения грузоподъемности для кнопок
+var onboardingPayloadChoices = []string{"1.5 т", "3 т", "5 т", "10 т", "20 т"}
+
+// onboardingTerms — условия сервиса, которые водитель принимает в конце регистрации
+const onboardingTerms = `📄 Условия сервиса
+
+• Бот присылает заказы, подходящие вашему городу и транспорту. Договариваетесь о перевозке вы с заказчиком напрямую.
+• Телефон заказчика открывается после того, как администратор назначит вам заказ; до этого пишите через бота (/chat).
+• Ваш телефон видят только администраторы сервиса, заказчикам он не показывается.
+• После доставки подтвердите ее фото документов (/deliver) — заказчик сможет вас оценить.
+• Уведомления можно выключить в любой момент кнопкой 🔕.`
+
+// handleOnboarding ведет водителя по шагам регистрации: телефон, город, транспорт, условия.
+// Шаг хранится в базе, поэтому после перерыва или перезапуска регистрация продолжается с того же места.
+func (db *DriverBot) handleOnboarding(message *tgbotapi.Message, driver *domain.Driver, start startPayload, isStart bool) {
+	chatID := message.Chat.ID
+	text := strings.TrimSpace(message.Text)
+
+	if isStart {
+		intro := "👋 Добро пожаловать! Чтобы получать заказы, пройдите короткую регистрацию: телефон, домашний город, транспорт и условия сервиса."
+		if *driver.OnboardingStep != domain.OnboardingStepPhone {
+			intro = "👋 С возвращением! Продолжим регистрацию с того места, где вы остановились."
+		}
+		db.sendOrLog(chatID, intro)
+		if start.orderID != "" {
+			db.handleOrderCommand(chatID, start.orderID)
+		}
+		db.sendOnboardingStep(chatID, driver, "")
+		return
+	}
+	if text == "/help" || text == "❓ Помощь" {
+		db.sendOnboardingStep(chatID, driver, "ℹ️ Сначала завершите регистрацию — после нее откроются все команды бота.")
+		return
+	}
+
+	switch *driver.OnboardingStep {
+	case domain.OnboardingStepPhone:
+		db.handleOnboardingPhone(message, driver)
+	case domain.OnboardingStepCity:
+		db.handleOnboardingCity(chatID, driver, text)
+	case domain.OnboardingStepVehicle:
+		db.handleOnboardingVehicle(chatID, driver, text)
+	case domain.OnboardingStepTerms:
+		db.handleOnboardingTerms(chatID, driver, text)
+	}
+}
+
+// handleOnboardingPhone сохраняет телефон из контакта Telegram или набранный вручную
+func (db *DriverBot) handleOnboardingPhone(message *tgbotapi.Message, driver *domain.Driver) {
+	chatID := message.Chat.ID
+	phone := strings.TrimSpace(message.Text)
+	if message.Contact != nil {
+		// Контакт другого человека не подтверждает номер водителя
+		if message.Contact.UserID != message.From.ID {
+			db.sendOnboardingStep(chatID, driver, "❌ Это не ваш контакт. Нажмите кнопку ниже, чтобы отправить свой номер.")
+			return
+		}
+		phone = message.Contact.PhoneNumber
+		if !strings.HasPrefix(phone, "+") {
+			phone = "+" + phone
+		}
+	}
+	if phone == "" {
+		db.sendOnboardingStep(chatID, driver, "")
+		return
+	}
+
+	if err := db.driverService.SubmitOnboardingPhone(driver, phone); err != nil {
+		db.sendOnboardingStep(chatID, driver, formatOnboardingError(driver, err))
+		return
+	}
+	db.sendOnboardingStep(chatID, driver, "✅ Телефон сохранен.")
+}
+
+// handleOnboardingCity сохраняет домашний город; при опечатке предлагает похожие города кнопками
+func (db *DriverBot) handleOnboardingCity(chatID int64, driver *domain.Driver, text string) {
+	if text == "" {
+		db.sendOnboardingStep(chatID, driver, "")
+		return
+	}
+
+	city, err := db.driverService.SubmitOnboardingCity(driver, text)
+	if err != nil {
+		var cityErr *service.CityNotFoundError
+		if errors.As(err, &cityErr) && len(cityErr.Suggestions) > 0 {
+			names := make([]string, len(cityErr.Suggestions))
+			for i, suggestion := range cityErr.Suggestions {
+				names[i] = suggestion.Name
+			}
+			db.sendWithKeyboard(chatID, fmt.Sprintf("❓ Город «%s» не найден. Возможно, вы имели в виду:", text),
+				onboardingChoicesKeyboard(names))
+			return
+		}
+		db.sendOnboardingStep(chatID, driver, formatOnboardingError(driver, err))
+		return
+	}
+	db.sendOnboardingStep(chatID, driver, fmt.Sprintf("✅ Домашний город: %s.", city.Name))
+}
+
+// handleOnboardingVehicle спрашивает тип кузова, затем грузоподъемность. Выбранный кузов хранится
+// в памяти до ответа о грузоподъемности; после перезапуска бот спросит его снова.
+func (db *DriverBot) handleOnboardingVehicle(chatID int64, driver *domain.Driver, text string) {
+	telegramID := driver.TelegramID
+	db.onboardingMu.Lock()
+	bodyType, hasBody := db.onboardingBodies[telegramID]
+	db.onboardingMu.Unlock()
+
+	switch {
+	case text == onboardingSkipButton:
+		db.clearOnboardingBody(telegramID)
+		if err := db.driverService.SubmitOnboardingVehicle(driver, nil); err != nil {
+			db.sendOnboardingStep(chatID, driver, formatOnboardingError(driver, err))
+			return
+		}
+		db.sendOnboardingStep(chatID, driver, "👌 Транспорт можно заполнить позже кнопкой «🚛 Мой транспорт». Пока вы будете видеть все заказы.")
+	case text == onboardingBackButton || (!hasBody && text == ""):
+		db.clearOnboardingBody(telegramID)
+		db.sendOnboardingStep(chatID, driver, "")
+	case !hasBody:
+		parsed, err := service.ParseBodyType(text)
+		if err != nil {
+			db.sendOnboardingStep(chatID, driver, fmt.Sprintf("❌ %v", err))
+			return
+		}
+		db.onboardingMu.Lock()
+		db.onboardingBodies[telegramID] = parsed
+		db.onboardingMu.Unlock()
+		db.sendWithKeyboard(chatID, fmt.Sprintf("🚛 Кузов: %s\n\n%s", domain.BodyTypeNames[parsed], onboardingPayloadPrompt),
+			onboardingChoicesKeyboard(onboardingPayloadChoices, onboardingBackButton))
+	default:
+		payloadKg, err := parseWeightKg(text)
+		if err != nil {
+			db.sendWithKeyboard(chatID, fmt.Sprintf("❌ %v\n\n%s", err, onboardingPayloadPrompt),
+				onboardingChoicesKeyboard(onboardingPayloadChoices, onboardingBackButton))
+			return
+		}
+		if err := db.driverService.SubmitOnboardingVehicle(driver, &domain.VehicleRequest{BodyType: &bodyType, PayloadKg: &payloadKg}); err != nil {
+			if service.IsValidationError(err) {
+				db.sendWithKeyboard(chatID, fmt.Sprintf("❌ %v\n\n%s", err, onboardingPayloadPrompt),
+					onboardingChoicesKeyboard(onboardingPayloadChoices, onboardingBackButton))
+				return
+			}
+			db.sendOnboardingStep(chatID, driver, formatOnboardingError(driver, err))
+			return
+		}
+		db.clearOnboardingBody(telegramID)
+		db.sendOnboardingStep(chatID, driver, "✅ Транспорт сохранен:\n"+formatVehicleCard(driver.Vehicle))
+	}
+}
+
+// handleOnboardingTerms завершает регистрацию после принятия условий и включает уведомления
+func (db *DriverBot) handleOnboardingTerms(chatID int64, driver *domain.Driver, text string) {
+	if text != onboardingAcceptButton {
+		db.sendOnboardingStep(chatID, driver, "")
+		return
+	}
+	if err := db.driverService.AcceptTerms(driver); err != nil {
+		db.sendOnboardingStep(chatID, driver, formatOnboardingError(driver, err))
+		return
+	}
+
+	var summary strings.Builder
+	summary.WriteString("🎉 Регистрация завершена! Уведомления о новых заказах включены.\n\n")
+	if driver.CityName != nil {
+		summary.WriteString(fmt.Sprintf("🏙️ Город: %s (%s)\n", *driver.CityName, formatDriverRadius(driver)))
+	}
+	summary.WriteString(fmt.Sprintf("🚛 Транспорт: %s\n\n", formatVehicleShort(driver.Vehicle)))
+	summary.WriteString("Смотрите заказы кнопками меню, /help — список всех команд.")
+	db.sendWithKeyboard(chatID, summary.String(), driverMainMenuKeyboard())
+}
+
+// sendOnboardingStep отправляет вопрос текущего шага регистрации; prefix — ответ на предыдущее сообщение
+func (db *DriverBot) sendOnboardingStep(chatID int64, driver *domain.Driver, prefix string) {
+	if !driver.IsOnboarding() {
+		db.sendWithKeyboard(chatID, strings.TrimSpace(prefix+"\n\nРегистрация завершена."), driverMainMenuKeyboard())
+		return
+	}
+
+	var question string
+	var keyboard tgbotapi.ReplyKeyboardMarkup
+	switch *driver.OnboardingStep {
+	case domain.OnboardingStepPhone:
+		question = "Шаг 1 из 4. 📱 Поделитесь номером телефона кнопкой ниже — по нему администратор свяжется с вами по заказам. Можно и написать номер сообщением."
+		keyboard = tgbotapi.NewReplyKeyboard(tgbotapi.NewKeyboardButtonRow(tgbotapi.NewKeyboardButtonContact(onboardingPhoneButton)))
+	case domain.OnboardingStepCity:
+		question = "Шаг 2 из 4. 🏙️ В каком городе вы обычно берете заказы? Выберите из списка или напишите название."
+		cities, err := db.driverService.GetOnboardingCityChoices()
+		if err != nil {
+			log.Printf("Ошибка получения городов для регистрации: %v", err)
+		}
+		keyboard = onboardingChoicesKeyboard(cities)
+	case domain.OnboardingStepVehicle:
+		question = "Шаг 3 из 4. 🚛 Какой у вас кузов? По нему и грузоподъемности бот подберет подходящие заказы."
+		names := make([]string, len(domain.BodyTypes))
+		for i, bodyType := range domain.BodyTypes {
+			names[i] = domain.BodyTypeNames[bodyType]
+		}
+		keyboard = onboardingChoicesKeyboard(names, onboardingSkipButton)
+	case domain.OnboardingStepTerms:
+		question = "Шаг 4 из 4. " + onboardingTerms
+		if db.termsURL != "" {
+			question += "\n\nПолные условия: " + db.termsURL
+		}
+		keyboard = tgbotapi.NewReplyKeyboard(tgbotapi.NewKeyboardButtonRow(tgbotapi.NewKeyboardButton(onboardingAcceptButton)))
+	}
+
+	if prefix != "" {
+		question = prefix + "\n\n" + question
+	}
+	msg := tgbotapi.NewMessage(chatID, question)
+	if keyboard.Keyboard != nil {
+		keyboard.ResizeKeyboard = true
+		msg.ReplyMarkup = keyboard
+	} else {
+		// Убираем кнопки предыдущего шага
+		msg.ReplyMarkup = tgbotapi.NewRemoveKeyboard(false)
+	}
+	if _, err := db.bot.Send(msg); err != nil {
+		log.Printf("Ошибка отправки шага регистрации: %v", err)
+	}
+}
+
+// clearOnboardingBody забывает выбранный при регистрации тип кузова
+func (db *DriverBot) clearOnboardingBody(telegramID int64) {
+	db.onboardingMu.Lock()
+	delete(db.onboardingBodies, telegramID)
+	db.onboardingMu.Unlock()
+}
+
+// onboardingChoicesKeyboard раскладывает варианты ответа по два в ряд, extra — отдельным рядом
+func onboardingChoicesKeyboard(choices []string, extra ...string) tgbotapi.ReplyKeyboardMarkup {
+	var rows [][]tgbotapi.KeyboardButton
+	for i := 0; i < len(choices); i += 2 {
+		row := tgbotapi.NewKeyboardButtonRow(tgbotapi.NewKeyboardButton(choices[i]))
+		if i+1 < len(choices) {
+			row = append(row, tgbotapi.NewKeyboardButton(choices[i+1]))
+		}
+		rows = append(rows, row)
+	}
+	if len(extra) > 0 {
+		row := make([]tgbotapi.KeyboardButton, len(extra))
+		for i, text := range extra {
+			row[i] = tgbotapi.NewKeyboardButton(text)
+		}
+		rows = append(rows, row)
+	}
+	return tgbotapi.ReplyKeyboardMarkup{Keyboard: rows, ResizeKeyboard: true}
+}
+
+// formatOnboardingError возвращает текст ошибки шага регистрации: ошибки ввода показываются водителю
+func formatOnboardingError(driver *domain.Driver, err error) string {
+	if service.IsValidationError(err) {
+		return fmt.Sprintf("❌ %v", err)
+	}
+	log.Printf("Ошибка регистрации водителя %s: %v", driver.UUID, err)
+	return "❌ Не удалось сохранить ответ. Попробуйте еще раз."
+}
+
+// formatOnboardingStep возвращает название шага регистрации для администраторов
+func formatOnboardingStep(step string) string {
+	switch step {
+	case domain.OnboardingStepPhone:
+		return "ждем телефон"
+	case domain.OnboardingStepCity:
+		return "ждем город"
+	case domain.OnboardingStepVehicle:
+		return "ждем транспорт"
+	case domain.OnboardingStepTerms:
+		return "ждем принятия условий"
+	}
+	return step
+}
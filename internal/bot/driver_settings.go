@@ -0,0 +1,403 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"dalnoboy/internal/domain"
+	"dalnoboy/internal/service"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Кнопки меню настроек водителя
+const (
+	settingsButton              = "⚙️ Настройки"
+	settingsProfileButton       = "👤 Профиль"
+	settingsCityButton          = "🏙️ Сменить город"
+	settingsQuietHoursButton    = "🌙 Тихие часы"
+	settingsModeButton          = "⏱ Частота уведомлений"
+	settingsTimezoneButton      = "🕐 Часовой пояс"
+	settingsDeleteButton        = "🗑 Удалить аккаунт"
+	settingsModeInstantButton   = "⚡ Сразу"
+	settingsModeDigestButton    = "🗞 Раз в день"
+	settingsQuietOffButton      = "🔔 Без тихих часов"
+	settingsTimezoneCityButton  = "🏙️ По городу"
+	settingsDeleteConfirmButton = "🗑 Да, удалить аккаунт"
+	settingsCancelButton        = "❌ Отмена"
+)
+
+// settingsPromptTTL — сколько после нажатия кнопки следующее сообщение считается ответом настройки
+const settingsPromptTTL = 10 * time.Minute
+
+// Настройки, которые водитель вводит следующим сообщением
+const (
+	settingsPromptCity       = "city"
+	settingsPromptQuietHours = "quiet_hours"
+	settingsPromptTimezone   = "timezone"
+	settingsPromptDelete     = "delete"
+)
+
+// settingsQuietHoursChoices — частые варианты тихих часов для кнопок
+var settingsQuietHoursChoices = []string{"23–7", "22–8", "0–6", "22–6"}
+
+// settingsTimezoneChoice — часовой пояс России на кнопке выбора
+type settingsTimezoneChoice struct {
+	label string
+	zone  string
+}
+
+// settingsTimezoneChoices — часовые пояса России от МСК−1 до МСК+9
+var settingsTimezoneChoices = []settingsTimezoneChoice{
+	{"Калининград, МСК−1", "Europe/Kaliningrad"},
+	{"Москва, МСК", "Europe/Moscow"},
+	{"Самара, МСК+1", "Europe/Samara"},
+	{"Екатеринбург, МСК+2", "Asia/Yekaterinburg"},
+	{"Омск, МСК+3", "Asia/Omsk"},
+	{"Новосибирск, МСК+4", "Asia/Novosibirsk"},
+	{"Иркутск, МСК+5", "Asia/Irkutsk"},
+	{"Якутск, МСК+6", "Asia/Yakutsk"},
+	{"Владивосток, МСК+7", "Asia/Vladivostok"},
+	{"Магадан, МСК+8", "Asia/Magadan"},
+	{"Камчатка, МСК+9", "Asia/Kamchatka"},
+}
+
+// quietHoursPattern разбирает тихие часы: "23-7", "23:00–07:00", "с 23 до 7"
+var quietHoursPattern = regexp.MustCompile(`^(?:с\s*)?(\d{1,2})(?::00)?\s*(?:-|–|—|до)\s*(\d{1,2})(?::00)?$`)
+
+// settingsPrompt — настройка, которую водитель вводит следующим сообщением
+type settingsPrompt struct {
+	kind      string
+	expiresAt time.Time
+}
+
+// settingsKeyboard возвращает меню настроек водителя
+func settingsKeyboard() tgbotapi.ReplyKeyboardMarkup {
+	return tgbotapi.ReplyKeyboardMarkup{
+		Keyboard: [][]tgbotapi.KeyboardButton{
+			{
+				{Text: settingsProfileButton},
+				{Text: settingsCityButton},
+			},
+			{
+				{Text: settingsQuietHoursButton},
+				{Text: settingsModeButton},
+			},
+			{
+				{Text: settingsTimezoneButton},
+				{Text: settingsDeleteButton},
+			},
+			{
+				{Text: "⬅️ Назад"},
+			},
+		},
+		ResizeKeyboard:  true,
+		OneTimeKeyboard: false,
+	}
+}
+
+// handleSettings обрабатывает меню настроек и ответы на его вопросы. Возвращает false, если
+// сообщение не относится к настройкам; пустой ответ означает, что сообщение уже отправлено.
+func (db *DriverBot) handleSettings(chatID int64, driver *domain.Driver, text string) (string, tgbotapi.ReplyKeyboardMarkup, bool) {
+	switch text {
+	case settingsButton, "/settings", settingsProfileButton, "/profile", settingsCityButton, settingsQuietHoursButton,
+		settingsModeButton, settingsModeInstantButton, settingsModeDigestButton, settingsQuietOffButton,
+		settingsTimezoneButton, settingsTimezoneCityButton, settingsDeleteButton, settingsDeleteConfirmButton, settingsCancelButton:
+		if driver == nil {
+			return "❌ Не удалось открыть настройки: водитель не найден.", driverMainMenuKeyboard(), true
+		}
+	default:
+		return db.handleSettingsPrompt(driver, text)
+	}
+
+	telegramID := driver.TelegramID
+	switch text {
+	case settingsButton, "/settings":
+		db.clearSettingsPrompt(telegramID)
+		return formatDriverProfile(driver) + "\n\nВыберите, что изменить:", settingsKeyboard(), true
+	case settingsProfileButton, "/profile":
+		return formatDriverProfile(driver), settingsKeyboard(), true
+	case settingsCityButton:
+		db.setSettingsPrompt(telegramID, settingsPromptCity)
+		cities, err := db.driverService.GetOnboardingCityChoices()
+		if err != nil {
+			log.Printf("Ошибка получения городов для настроек: %v", err)
+		}
+		current := "не указан"
+		if driver.CityName != nil {
+			current = *driver.CityName
+		}
+		return fmt.Sprintf("🏙️ Ваш город: %s\n\nНапишите новый город или выберите из списка. Заказы рядом и уведомления подбираются вокруг него.", current),
+			onboardingChoicesKeyboard(cities, settingsCancelButton), true
+	case settingsQuietHoursButton:
+		db.setSettingsPrompt(telegramID, settingsPromptQuietHours)
+		response := fmt.Sprintf("🌙 Тихие часы: %s\n\nВ тихие часы уведомления о заказах откладываются и приходят, когда тихие часы закончатся. "+
+			"Срочные заказы приходят сразу. Напишите интервал по вашему времени (%s), например 23-7, или выберите вариант.",
+			formatQuietHours(driver), driver.TimezoneName())
+		return response, onboardingChoicesKeyboard(settingsQuietHoursChoices, settingsQuietOffButton, settingsCancelButton), true
+	case settingsQuietOffButton:
+		db.clearSettingsPrompt(telegramID)
+		if err := db.driverService.SetQuietHours(driver, nil, nil); err != nil {
+			return formatSettingsError(driver, err), settingsKeyboard(), true
+		}
+		return "🔔 Тихие часы выключены: уведомления приходят в любое время.", settingsKeyboard(), true
+	case settingsTimezoneButton:
+		db.setSettingsPrompt(telegramID, settingsPromptTimezone)
+		labels := make([]string, len(settingsTimezoneChoices))
+		for i, choice := range settingsTimezoneChoices {
+			labels[i] = choice.label
+		}
+		response := fmt.Sprintf("🕐 Часовой пояс: %s\n\nПо нему считаются тихие часы и время сводки. "+
+			"Выберите пояс или напишите его название, например Asia/Novosibirsk.", formatDriverTimezone(driver))
+		return response, onboardingChoicesKeyboard(labels, settingsTimezoneCityButton, settingsCancelButton), true
+	case settingsTimezoneCityButton:
+		db.clearSettingsPrompt(telegramID)
+		if err := db.driverService.SetDriverTimezone(driver, ""); err != nil {
+			return formatSettingsError(driver, err), settingsKeyboard(), true
+		}
+		return "✅ Часовой пояс: " + formatDriverTimezone(driver), settingsKeyboard(), true
+	case settingsModeButton:
+		db.clearSettingsPrompt(telegramID)
+		keyboard := onboardingChoicesKeyboard([]string{settingsModeInstantButton, settingsModeDigestButton}, settingsCancelButton)
+		return fmt.Sprintf("⏱ Сейчас: %s\n\n%s — каждый подходящий заказ сразу.\n%s — одна сводка лучших заказов в день, без мгновенных уведомлений.",
+			formatNotificationMode(driver), settingsModeInstantButton, settingsModeDigestButton), keyboard, true
+	case settingsModeInstantButton, settingsModeDigestButton:
+		mode := domain.NotificationModeInstant
+		if text == settingsModeDigestButton {
+			mode = domain.NotificationModeDigest
+		}
+		if err := db.driverService.SetNotificationMode(driver, mode); err != nil {
+			return formatSettingsError(driver, err), settingsKeyboard(), true
+		}
+		response := "✅ Частота уведомлений: " + formatNotificationMode(driver)
+		if mode == domain.NotificationModeDigest {
+			response += "\n\nИзменить час сводки: /digest <час>, например /digest 7"
+		}
+		if !driver.NotificationEnabled && mode == domain.NotificationModeInstant {
+			response += "\n\n🔕 Уведомления сейчас выключены — включите их кнопкой «🔔 Включить уведомления»."
+		}
+		return response, settingsKeyboard(), true
+	case settingsDeleteButton:
+		db.setSettingsPrompt(telegramID, settingsPromptDelete)
+		keyboard := tgbotapi.NewReplyKeyboard(tgbotapi.NewKeyboardButtonRow(
+			tgbotapi.NewKeyboardButton(settingsDeleteConfirmButton),
+			tgbotapi.NewKeyboardButton(settingsCancelButton),
+		))
+		return "⚠️ Удалить аккаунт? Будут удалены телефон, город, транспорт, сохраненные поиски, предложения, переписка и оценки. " +
+			"Отменить удаление нельзя.", keyboard, true
+	case settingsDeleteConfirmButton:
+		if !db.takeSettingsPrompt(telegramID, settingsPromptDelete) {
+			return "Чтобы удалить аккаунт, откройте «⚙️ Настройки» и нажмите «🗑 Удалить аккаунт».", settingsKeyboard(), true
+		}
+		db.deleteDriverAccount(chatID, driver)
+		return "", tgbotapi.ReplyKeyboardMarkup{}, true
+	default: // settingsCancelButton
+		db.clearSettingsPrompt(telegramID)
+		return "Настройки не изменены.", settingsKeyboard(), true
+	}
+}
+
+// handleSettingsPrompt принимает город, часовой пояс или тихие часы, которые водитель ввел после нажатия кнопки.
+// Команды и ответы после истечения ожидания не перехватываются.
+func (db *DriverBot) handleSettingsPrompt(driver *domain.Driver, text string) (string, tgbotapi.ReplyKeyboardMarkup, bool) {
+	if driver == nil || text == "" || strings.HasPrefix(text, "/") {
+		return "", tgbotapi.ReplyKeyboardMarkup{}, false
+	}
+
+	telegramID := driver.TelegramID
+	db.settingsMu.Lock()
+	prompt, ok := db.settingsPrompts[telegramID]
+	if ok && (time.Now().After(prompt.expiresAt) || prompt.kind == settingsPromptDelete) {
+		// Удаление подтверждается только кнопкой, любое другое сообщение его отменяет
+		delete(db.settingsPrompts, telegramID)
+		ok = false
+	}
+	db.settingsMu.Unlock()
+	if !ok {
+		return "", tgbotapi.ReplyKeyboardMarkup{}, false
+	}
+
+	switch prompt.kind {
+	case settingsPromptCity:
+		city, err := db.driverService.ChangeDriverCity(driver, text)
+		if err != nil {
+			var cityErr *service.CityNotFoundError
+			if errors.As(err, &cityErr) && len(cityErr.Suggestions) > 0 {
+				names := make([]string, len(cityErr.Suggestions))
+				for i, suggestion := range cityErr.Suggestions {
+					names[i] = suggestion.Name
+				}
+				return fmt.Sprintf("❓ Город «%s» не найден. Возможно, вы имели в виду:", text),
+					onboardingChoicesKeyboard(names, settingsCancelButton), true
+			}
+			return formatSettingsError(driver, err) + "\n\nНапишите город еще раз или нажмите «❌ Отмена».",
+				onboardingChoicesKeyboard(nil, settingsCancelButton), true
+		}
+		db.clearSettingsPrompt(telegramID)
+		return fmt.Sprintf("✅ Ваш город: %s (%s). Заказы рядом и уведомления теперь подбираются вокруг него.",
+			city.Name, formatDriverRadius(driver)), settingsKeyboard(), true
+	case settingsPromptTimezone:
+		zone := text
+		for _, choice := range settingsTimezoneChoices {
+			if text == choice.label {
+				zone = choice.zone
+			}
+		}
+		if err := db.driverService.SetDriverTimezone(driver, zone); err != nil {
+			return formatSettingsError(driver, err) + "\n\nВыберите пояс из списка или нажмите «❌ Отмена».",
+				onboardingChoicesKeyboard(nil, settingsCancelButton), true
+		}
+		db.clearSettingsPrompt(telegramID)
+		return "✅ Часовой пояс: " + formatDriverTimezone(driver), settingsKeyboard(), true
+	default: // settingsPromptQuietHours
+		start, end, err := parseQuietHours(text)
+		if err == nil {
+			err = db.driverService.SetQuietHours(driver, &start, &end)
+		}
+		if err != nil {
+			return formatSettingsError(driver, err) + "\n\nНапишите интервал, например 23-7, или нажмите «❌ Отмена».",
+				onboardingChoicesKeyboard(settingsQuietHoursChoices, settingsQuietOffButton, settingsCancelButton), true
+		}
+		db.clearSettingsPrompt(telegramID)
+		response := fmt.Sprintf("🌙 Тихие часы: %s. Уведомления в это время придут после окончания тихих часов, срочные — сразу.",
+			formatQuietHours(driver))
+		return response, settingsKeyboard(), true
+	}
+}
+
+// deleteDriverAccount удаляет аккаунт водителя и убирает клавиатуру
+func (db *DriverBot) deleteDriverAccount(chatID int64, driver *domain.Driver) {
+	response := "✅ Аккаунт удален. Спасибо, что были с нами! Чтобы зарегистрироваться снова, отправьте /start."
+	if err := db.driverService.DeleteDriverAccount(driver); err != nil {
+		db.sendWithKeyboard(chatID, formatSettingsError(driver, err), settingsKeyboard())
+		return
+	}
+	db.clearOnboardingBody(driver.TelegramID)
+
+	msg := tgbotapi.NewMessage(chatID, response)
+	msg.ReplyMarkup = tgbotapi.NewRemoveKeyboard(false)
+	if _, err := db.bot.Send(msg); err != nil {
+		log.Printf("Ошибка отправки сообщения: %v", err)
+	}
+}
+
+// setSettingsPrompt ждет от водителя значение настройки следующим сообщением
+func (db *DriverBot) setSettingsPrompt(telegramID int64, kind string) {
+	db.settingsMu.Lock()
+	db.settingsPrompts[telegramID] = settingsPrompt{kind: kind, expiresAt: time.Now().Add(settingsPromptTTL)}
+	db.settingsMu.Unlock()
+}
+
+// takeSettingsPrompt снимает ожидание настройки и проверяет, что ждали именно kind
+func (db *DriverBot) takeSettingsPrompt(telegramID int64, kind string) bool {
+	db.settingsMu.Lock()
+	defer db.settingsMu.Unlock()
+	prompt, ok := db.settingsPrompts[telegramID]
+	delete(db.settingsPrompts, telegramID)
+	return ok && prompt.kind == kind && time.Now().Before(prompt.expiresAt)
+}
+
+// clearSettingsPrompt отменяет ожидание настройки
+func (db *DriverBot) clearSettingsPrompt(telegramID int64) {
+	db.settingsMu.Lock()
+	delete(db.settingsPrompts, telegramID)
+	db.settingsMu.Unlock()
+}
+
+// parseQuietHours разбирает интервал тихих часов, например "23-7"
+func parseQuietHours(text string) (int, int, error) {
+	match := quietHoursPattern.FindStringSubmatch(strings.ToLower(strings.TrimSpace(text)))
+	if match == nil {
+		return 0, 0, fmt.Errorf("не удалось разобрать интервал '%s'", text)
+	}
+	start, _ := strconv.Atoi(match[1])
+	end, _ := strconv.Atoi(match[2])
+	// 24 — то же, что полночь
+	if end == 24 {
+		end = 0
+	}
+	return start, end, nil
+}
+
+// formatDriverProfile показывает водителю его данные и настройки уведомлений
+func formatDriverProfile(driver *domain.Driver) string {
+	phone := "не указан"
+	if driver.Phone != nil {
+		phone = *driver.Phone
+	}
+	city := "не указан"
+	if driver.CityName != nil {
+		city = fmt.Sprintf("%s (%s)", *driver.CityName, formatDriverRadius(driver))
+	}
+	notifications := "🔔 Уведомления: включены"
+	if !driver.NotificationEnabled {
+		notifications = "🔕 Уведомления: выключены"
+	}
+
+	var profile strings.Builder
+	profile.WriteString(fmt.Sprintf("👤 %s\n\n", driver.Name))
+	profile.WriteString(fmt.Sprintf("☎️ Телефон: %s\n", phone))
+	profile.WriteString(fmt.Sprintf("🏙️ Город: %s\n", city))
+	profile.WriteString(fmt.Sprintf("🚛 Транспорт: %s\n", formatVehicleShort(driver.Vehicle)))
+	profile.WriteString(formatDriverRating(driver) + "\n")
+	profile.WriteString(notifications + "\n")
+	profile.WriteString(fmt.Sprintf("⏱ Частота: %s\n", formatNotificationMode(driver)))
+	profile.WriteString(fmt.Sprintf("🌙 Тихие часы: %s\n", formatQuietHours(driver)))
+	profile.WriteString(fmt.Sprintf("🕐 Часовой пояс: %s\n", formatDriverTimezone(driver)))
+	profile.WriteString(fmt.Sprintf("📅 С нами с %s", driver.CreatedAt.Format("02.01.2006")))
+	return profile.String()
+}
+
+// formatNotificationMode описывает частоту уведомлений водителя
+func formatNotificationMode(driver *domain.Driver) string {
+	if driver.NotificationMode == domain.NotificationModeDigest {
+		if driver.DigestHour != nil {
+			return fmt.Sprintf("раз в день, сводка в %02d:00 (%s)", *driver.DigestHour, driver.TimezoneName())
+		}
+		return "раз в день"
+	}
+	if driver.DigestHour != nil {
+		return fmt.Sprintf("сразу, плюс сводка в %02d:00 (%s)", *driver.DigestHour, driver.TimezoneName())
+	}
+	return "сразу"
+}
+
+// formatQuietHours описывает тихие часы водителя
+func formatQuietHours(driver *domain.Driver) string {
+	if !driver.HasQuietHours() {
+		return "не заданы"
+	}
+	return fmt.Sprintf("%02d:00–%02d:00 (%s)", *driver.QuietHoursStart, *driver.QuietHoursEnd, driver.TimezoneName())
+}
+
+// formatDriverTimezone описывает часовой пояс водителя и откуда он взят
+func formatDriverTimezone(driver *domain.Driver) string {
+	zone := driver.TimezoneName()
+	for _, choice := range settingsTimezoneChoices {
+		if choice.zone == zone {
+			zone = fmt.Sprintf("%s (%s)", choice.zone, choice.label)
+		}
+	}
+	switch {
+	case driver.Timezone != nil:
+		return zone
+	case driver.CityTimezone != nil:
+		return zone + ", по городу"
+	default:
+		return zone + ", по умолчанию"
+	}
+}
+
+// formatSettingsError возвращает текст ошибки настройки: ошибки ввода показываются водителю
+func formatSettingsError(driver *domain.Driver, err error) string {
+	if service.IsValidationError(err) {
+		return fmt.Sprintf("❌ %v", err)
+	}
+	log.Printf("Ошибка изменения настроек водителя %s: %v", driver.UUID, err)
+	return "❌ Не удалось сохранить настройки. Попробуйте позже."
+}
@@ -0,0 +1,72 @@
+package bot
+
+import (
+	"log"
+	"regexp"
+	"strings"
+
+	"dalnoboy/internal/domain"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// driverWelcomeText — приветствие водителя по команде /start
+const driverWelcomeText = "Добро пожаловать! Вы водитель. Выберите действие."
+
+// Префиксы параметров ссылок t.me/<бот>?start=<параметр>
+const (
+	startOrderPrefix    = "order_"
+	startReferralPrefix = domain.DriverSourceReferralPrefix
+)
+
+// referralCodePattern — допустимый код реферальной ссылки или рекламной кампании
+var referralCodePattern = regexp.MustCompile(`^[a-z0-9_-]{1,48}$`)
+
+// startPayload — разобранный параметр ссылки, по которой водитель открыл бота
+type startPayload struct {
+	orderID string  // Заказ, который нужно показать
+	source  *string // Источник привлечения для новых водителей
+}
+
+// parseStartCommand разбирает /start и /start <параметр>. Telegram присылает параметр
+// ссылки t.me/<бот>?start=<параметр> через пробел после команды.
+func parseStartCommand(text string) (startPayload, bool) {
+	command, args, _ := strings.Cut(strings.TrimSpace(text), " ")
+	if command != "/start" {
+		return startPayload{}, false
+	}
+
+	var payload startPayload
+	param := strings.TrimSpace(args)
+	switch {
+	case param == "":
+	case strings.HasPrefix(param, startOrderPrefix):
+		payload.orderID = strings.TrimPrefix(param, startOrderPrefix)
+		source := domain.DriverSourceOrderLink
+		payload.source = &source
+	case strings.HasPrefix(strings.ToLower(param), startReferralPrefix):
+		code := strings.ToLower(param)[len(startReferralPrefix):]
+		if !referralCodePattern.MatchString(code) {
+			log.Printf("Некорректный код реферальной ссылки: %q", param)
+			break
+		}
+		source := startReferralPrefix + code
+		payload.source = &source
+	default:
+		log.Printf("Неизвестный параметр команды /start: %q", param)
+	}
+	return payload, true
+}
+
+// handleStartCommand приветствует водителя и открывает заказ из ссылки, если он указан
+func (db *DriverBot) handleStartCommand(chatID int64, payload startPayload) {
+	welcome := tgbotapi.NewMessage(chatID, driverWelcomeText)
+	welcome.ReplyMarkup = driverMainMenuKeyboard()
+	if _, err := db.bot.Send(welcome); err != nil {
+		log.Printf("Ошибка отправки приветствия водителю: %v", err)
+	}
+
+	if payload.orderID != "" {
+		db.handleOrderCommand(chatID, payload.orderID)
+	}
+}
@@ -0,0 +1,53 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+
+	"dalnoboy/internal/domain"
+)
+
+func TestParseStartCommand(t *testing.T) {
+	const orderUUID = "3f2b8c1e-9a4d-4e7b-8c2a-1d5e6f7a8b9c"
+
+	tests := []struct {
+		name        string
+		text        string
+		wantStart   bool
+		wantOrderID string
+		wantSource  string // Пустая строка — источник не задан
+	}{
+		{"простой /start", "/start", true, "", ""},
+		{"пробелы вокруг команды", "  /start  ", true, "", ""},
+		{"ссылка на заказ", "/start order_" + orderUUID, true, orderUUID, domain.DriverSourceOrderLink},
+		{"реферальный код", "/start ref_abc", true, "", "ref_abc"},
+		{"реферальный код приводится к нижнему регистру", "/start REF_ABC", true, "", "ref_abc"},
+		{"реферальный код с дефисом и цифрами", "/start ref_Promo-2026_spring", true, "", "ref_promo-2026_spring"},
+		{"некорректный реферальный код", "/start ref_abc!", true, "", ""},
+		{"пустой реферальный код", "/start ref_", true, "", ""},
+		{"слишком длинный реферальный код", "/start ref_" + strings.Repeat("a", 49), true, "", ""},
+		{"неизвестный параметр", "/start promo2026", true, "", ""},
+		{"похожая команда", "/startx", false, "", ""},
+		{"похожая команда с параметром", "/startx ref_abc", false, "", ""},
+		{"другая команда", "/orders", false, "", ""},
+		{"обычный текст", "start", false, "", ""},
+	}
+
+	for _, tt := range tests {
+		payload, ok := parseStartCommand(tt.text)
+		if ok != tt.wantStart {
+			t.Errorf("%s: parseStartCommand(%q) считается /start = %v, ожидалось %v", tt.name, tt.text, ok, tt.wantStart)
+			continue
+		}
+		if payload.orderID != tt.wantOrderID {
+			t.Errorf("%s: orderID = %q, ожидалось %q", tt.name, payload.orderID, tt.wantOrderID)
+		}
+		source := ""
+		if payload.source != nil {
+			source = *payload.source
+		}
+		if source != tt.wantSource {
+			t.Errorf("%s: источник = %q, ожидалось %q", tt.name, source, tt.wantSource)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"dalnoboy/internal/domain"
+	"dalnoboy/internal/service"
+)
+
+// vehicleHelp описывает формат заполнения транспорта в боте водителя
+var vehicleHelp = `Чтобы заполнить или изменить транспорт, отправьте сообщение (любые строки можно пропустить):
+
+/vehicle
+Кузов: тент
+Грузоподъемность: 1500
+Объем: 9
+Размеры: 300x180x180
+ADR: нет
+
+Грузоподъемность — в кг (можно "1.5 т"), объем — в м³, размеры кузова внутри — длина×ширина×высота в см.
+0 очищает объем или размеры. Удалить транспорт: /vehicle delete
+
+Типы кузова: ` + service.BodyTypeList()
+
+// handleVehicleCommand показывает или изменяет транспорт водителя
+func (db *DriverBot) handleVehicleCommand(driver *domain.Driver, text string) string {
+	if driver == nil {
+		return "❌ Не удалось изменить транспорт: водитель не найден."
+	}
+
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	_, argument, _ := strings.Cut(strings.TrimSpace(lines[0]), " ")
+	argument = strings.TrimSpace(argument)
+
+	if strings.EqualFold(argument, "delete") || strings.EqualFold(argument, "удалить") {
+		if err := db.driverService.DeleteVehicle(driver); err != nil {
+			log.Printf("Ошибка удаления транспорта водителя %s: %v", driver.UUID, err)
+			return "❌ Не удалось удалить транспорт. Попробуйте позже."
+		}
+		return "🗑 Транспорт удален. Теперь вы видите все заказы без учета вместимости."
+	}
+
+	if len(lines) == 1 {
+		return formatVehicleCard(driver.Vehicle) + "\n\n" + vehicleHelp
+	}
+
+	request, err := parseVehicleLines(lines[1:])
+	if err != nil {
+		return fmt.Sprintf("❌ %v\n\n%s", err, vehicleHelp)
+	}
+
+	vehicle, err := db.driverService.SaveVehicle(driver, request)
+	if err != nil {
+		return fmt.Sprintf("❌ Не удалось сохранить транспорт: %v", err)
+	}
+	return "✅ Транспорт сохранен! Заказы и уведомления будут подбираться с учетом вместимости.\n\n" + formatVehicleCard(vehicle)
+}
+
+// parseVehicleLines разбирает строки "Поле: значение" с характеристиками транспорта
+func parseVehicleLines(lines []string) (*domain.VehicleRequest, error) {
+	request := &domain.VehicleRequest{}
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		field, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, fmt.Errorf("строка '%s' должна иметь формат 'Поле: значение'", line)
+		}
+		value = strings.TrimSpace(value)
+
+		switch strings.ToLower(strings.TrimSpace(field)) {
+		case "кузов", "тип кузова":
+			request.BodyType = &value
+		case "грузоподъемность", "грузоподъёмность":
+			payload, err := parseWeightKg(value)
+			if err != nil {
+				return nil, err
+			}
+			request.PayloadKg = &payload
+		case "объем", "объём":
+			volume, err := parseNumber(strings.TrimSuffix(strings.TrimSuffix(value, "м³"), "м3"))
+			if err != nil {
+				return nil, fmt.Errorf("некорректный объем: %v", err)
+			}
+			request.VolumeM3 = &volume
+		case "размеры", "габариты":
+			dims, err := parseDimensions(value)
+			if err != nil {
+				return nil, err
+			}
+			request.LengthCm, request.WidthCm, request.HeightCm = &dims[0], &dims[1], &dims[2]
+		case "adr", "допог":
+			adr, err := parseYesNo(value)
+			if err != nil {
+				return nil, err
+			}
+			request.ADR = &adr
+		default:
+			return nil, fmt.Errorf("неизвестное поле '%s'", strings.TrimSpace(field))
+		}
+	}
+	return request, nil
+}
+
+// parseWeightKg разбирает вес в килограммах или тоннах ("1500", "1500 кг", "1.5 т")
+func parseWeightKg(text string) (float64, error) {
+	text = strings.ToLower(strings.TrimSpace(text))
+	multiplier := 1.0
+	switch {
+	case strings.HasSuffix(text, "кг"):
+		text = strings.TrimSuffix(text, "кг")
+	case strings.HasSuffix(text, "т"):
+		text = strings.TrimSuffix(text, "т")
+		multiplier = 1000
+	}
+
+	value, err := parseNumber(text)
+	if err != nil {
+		return 0, fmt.Errorf("некорректная грузоподъемность: %v", err)
+	}
+	return value * multiplier, nil
+}
+
+// parseDimensions разбирает размеры "300x180x180" (допускаются "х", "×" и "*"); 0 очищает размеры
+func parseDimensions(text string) ([3]float64, error) {
+	var dims [3]float64
+	text = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(text), "см"))
+	if text == "0" {
+		return dims, nil
+	}
+
+	parts := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return r == 'x' || r == 'х' || r == '×' || r == '*'
+	})
+	if len(parts) != 3 {
+		return dims, fmt.Errorf("размеры указываются как длина×ширина×высота в см, например 300x180x180")
+	}
+	for i, part := range parts {
+		value, err := parseNumber(part)
+		if err != nil {
+			return dims, fmt.Errorf("некорректные размеры: %v", err)
+		}
+		dims[i] = value
+	}
+	return dims, nil
+}
+
+// parseNumber разбирает число, допуская запятую в качестве десятичного разделителя
+func parseNumber(text string) (float64, error) {
+	return strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(text), ",", "."), 64)
+}
+
+// parseYesNo разбирает ответ "да"/"нет"
+func parseYesNo(text string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(text)) {
+	case "да", "есть", "yes", "true", "1", "+":
+		return true, nil
+	case "нет", "no", "false", "0", "-":
+		return false, nil
+	}
+	return false, fmt.Errorf("ожидается 'да' или 'нет', получено '%s'", text)
+}
+
+// formatVehicleCard форматирует карточку транспорта
+func formatVehicleCard(vehicle *domain.Vehicle) string {
+	if vehicle == nil {
+		return "🚛 Транспорт не заполнен — вы видите все заказы без учета вместимости."
+	}
+
+	var result strings.Builder
+	result.WriteString("🚛 Ваш транспорт\n")
+	result.WriteString(fmt.Sprintf("   Кузов: %s\n", domain.BodyTypeNames[vehicle.BodyType]))
+	result.WriteString(fmt.Sprintf("   Грузоподъемность: %.0f кг\n", vehicle.PayloadKg))
+	if vehicle.VolumeM3 != nil {
+		result.WriteString(fmt.Sprintf("   Объем: %.1f м³\n", *vehicle.VolumeM3))
+	}
+	if vehicle.LengthCm != nil && vehicle.WidthCm != nil && vehicle.HeightCm != nil {
+		result.WriteString(fmt.Sprintf("   Размеры кузова: %.0f×%.0f×%.0f см\n", *vehicle.LengthCm, *vehicle.WidthCm, *vehicle.HeightCm))
+	}
+	adr := "нет"
+	if vehicle.ADR {
+		adr = "есть"
+	}
+	result.WriteString(fmt.Sprintf("   ADR: %s", adr))
+	return result.String()
+}
+
+// formatVehicleShort форматирует транспорт одной строкой
+func formatVehicleShort(vehicle *domain.Vehicle) string {
+	if vehicle == nil {
+		return "не указан"
+	}
+
+	text := fmt.Sprintf("%s, %.1f т", domain.BodyTypeNames[vehicle.BodyType], vehicle.PayloadKg/1000)
+	if vehicle.VolumeM3 != nil {
+		text += fmt.Sprintf(", %.0f м³", *vehicle.VolumeM3)
+	}
+	if vehicle.ADR {
+		text += ", ADR"
+	}
+	return text
+}
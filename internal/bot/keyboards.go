@@ -2,7 +2,7 @@ package bot
 
 import tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 
-// adminMainMenuKeyboard возвращает главное меню админского бота с кнопками "Заказы", "Заказчики" и "Водители"
+// adminMainMenuKeyboard возвращает главное меню админского бота с кнопками "Заказы", "Заказчики", "Водители" и "Города"
 func adminMainMenuKeyboard() tgbotapi.ReplyKeyboardMarkup {
 	return tgbotapi.ReplyKeyboardMarkup{
 		Keyboard: [][]tgbotapi.KeyboardButton{
@@ -12,6 +12,7 @@ func adminMainMenuKeyboard() tgbotapi.ReplyKeyboardMarkup {
 			},
 			{
 				{Text: "🚚 Водители"},
+				{Text: "🏙️ Города"},
 			},
 		},
 		ResizeKeyboard:  true,
@@ -19,17 +20,29 @@ func adminMainMenuKeyboard() tgbotapi.ReplyKeyboardMarkup {
 	}
 }
 
-// driverMainMenuKeyboard возвращает главное меню водительского бота с кнопками заказов и уведомлений
+// driverMainMenuKeyboard возвращает главное меню водительского бота с кнопками заказов, уведомлений и настроек
 func driverMainMenuKeyboard() tgbotapi.ReplyKeyboardMarkup {
 	return tgbotapi.ReplyKeyboardMarkup{
 		Keyboard: [][]tgbotapi.KeyboardButton{
 			{
 				{Text: "📋 Заказы"},
+				{Text: "📍 Заказы рядом"},
+			},
+			{
+				{Text: "📈 Выгодные заказы"},
+				{Text: "🚛 Мой транспорт"},
+			},
+			{
+				{Text: "🚚 Мои перевозки"},
+				{Text: "🗞 Сводка"},
 			},
 			{
 				{Text: "🔔 Включить уведомления"},
 				{Text: "🔕 Выключить уведомления"},
 			},
+			{
+				{Text: settingsButton},
+			},
 		},
 		ResizeKeyboard:  true,
 		OneTimeKeyboard: false,
@@ -44,12 +57,21 @@ func ordersMenuKeyboard() tgbotapi.ReplyKeyboardMarkup {
 				{Text: "🟢 Активные заказы"},
 				{Text: "🔴 Архивные заказы"},
 			},
+			{
+				{Text: "⌛ Истекшие заказы"},
+				{Text: "✏️ Редактировать заказ"},
+			},
 			// Закомментировано - убираем фильтры
 			// {
 			// 	{Text: "⚙️ Фильтр"},
 			// 	{Text: "⬅️ Назад"},
 			// },
 			{
+				{Text: "🚚 В пути"},
+				{Text: "✅ Доставленные"},
+			},
+			{
+				{Text: "📦 Типы грузов"},
 				{Text: "⬅️ Назад"},
 			},
 		},
@@ -105,6 +127,9 @@ func filterMenuKeyboard() tgbotapi.ReplyKeyboardMarkup {
 func usersMenuKeyboard() tgbotapi.ReplyKeyboardMarkup {
 	return tgbotapi.ReplyKeyboardMarkup{
 		Keyboard: [][]tgbotapi.KeyboardButton{
+			{
+				{Text: "🛠 Управление заказчиками"},
+			},
 			{
 				{Text: "⬅️ Назад"},
 			},
@@ -126,3 +151,36 @@ func driversMenuKeyboard() tgbotapi.ReplyKeyboardMarkup {
 		OneTimeKeyboard: false,
 	}
 }
+
+// citiesMenuKeyboard возвращает меню для раздела справочника городов
+func citiesMenuKeyboard() tgbotapi.ReplyKeyboardMarkup {
+	return tgbotapi.ReplyKeyboardMarkup{
+		Keyboard: [][]tgbotapi.KeyboardButton{
+			{
+				{Text: "🛠 Управление городами"},
+			},
+			{
+				{Text: "⬅️ Назад"},
+			},
+		},
+		ResizeKeyboard:  true,
+		OneTimeKeyboard: false,
+	}
+}
+
+// deliveryKeyboard возвращает меню подтверждения доставки
+func deliveryKeyboard() tgbotapi.ReplyKeyboardMarkup {
+	return tgbotapi.ReplyKeyboardMarkup{
+		Keyboard: [][]tgbotapi.KeyboardButton{
+			{
+				{Text: "📍 Отправить местоположение", RequestLocation: true},
+			},
+			{
+				{Text: "✅ Подтвердить доставку"},
+				{Text: "❌ Отменить подтверждение"},
+			},
+		},
+		ResizeKeyboard:  true,
+		OneTimeKeyboard: false,
+	}
+}
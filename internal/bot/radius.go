@@ -0,0 +1,45 @@
+package bot
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"dalnoboy/internal/domain"
+)
+
+// parseRadius разбирает радиус в километрах; пустая строка или "-" отключают фильтр
+func parseRadius(text string) (*int, error) {
+	text = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(text), "км"))
+	if text == "" || text == "-" {
+		return nil, nil
+	}
+
+	radius, err := strconv.Atoi(text)
+	if err != nil {
+		return nil, fmt.Errorf("некорректный радиус '%s': укажите целое число километров", text)
+	}
+	return &radius, nil
+}
+
+// formatRadius форматирует радиусы поиска погрузки и выгрузки
+func formatRadius(pickupRadiusKm, deliveryRadiusKm *int) string {
+	format := func(radius *int) string {
+		if radius == nil {
+			return "только свой город"
+		}
+		return fmt.Sprintf("%d км", *radius)
+	}
+
+	pickup := format(pickupRadiusKm)
+	delivery := "не учитывается"
+	if deliveryRadiusKm != nil {
+		delivery = format(deliveryRadiusKm)
+	}
+	return fmt.Sprintf("погрузка: %s, выгрузка: %s", pickup, delivery)
+}
+
+// formatDriverRadius форматирует радиусы поиска водителя
+func formatDriverRadius(driver *domain.Driver) string {
+	return formatRadius(driver.PickupRadiusKm, driver.DeliveryRadiusKm)
+}
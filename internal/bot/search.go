@@ -0,0 +1,88 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"dalnoboy/internal/domain"
+	"dalnoboy/internal/service"
+)
+
+// maxFoundOrders ограничивает число заказов в ответе на /find
+const maxFoundOrders = 20
+
+// findHelp описывает команду полнотекстового поиска заказов
+const findHelp = `🔎 Поиск заказов
+
+/find <слова> — ищет по названию, описанию, адресам, городам и имени заказчика.
+Слова можно писать в любой форме и не до конца: /find холодильник, /find ленина 15, /find питер`
+
+// parseFindCommand выделяет текст запроса из команды /find
+func parseFindCommand(text string) (string, bool) {
+	command, args, _ := strings.Cut(strings.TrimSpace(text), " ")
+	if command != "/find" {
+		return "", false
+	}
+	return strings.TrimSpace(args), true
+}
+
+// handleFindCommand ищет заказы в любом статусе. В найденных заказах телефоны заказчиков,
+// поэтому поиск доступен только чатам администраторов.
+func (ab *AdminBot) handleFindCommand(chatID int64, text string) (string, bool) {
+	query, ok := parseFindCommand(text)
+	if !ok {
+		return "", false
+	}
+	if !ab.isAdminChat(chatID) {
+		return "❌ Поиск заказов доступен только администраторам", true
+	}
+	if query == "" {
+		return findHelp, true
+	}
+
+	orders, err := ab.orderService.SearchOrders(query, "")
+	if err != nil {
+		return formatSearchError(err), true
+	}
+	if len(orders) == 0 {
+		return fmt.Sprintf("🔎 По запросу «%s» заказов не найдено", query), true
+	}
+	shown, note := limitFoundOrders(orders)
+	return fmt.Sprintf("🔎 Найдено по запросу «%s»:\n\n%s%s", query, ab.formatOrders(shown), note), true
+}
+
+// handleFindCommand ищет среди активных заказов, подходящих транспорту водителя
+func (db *DriverBot) handleFindCommand(driver *domain.Driver, query string) string {
+	if query == "" {
+		return findHelp
+	}
+
+	orders, err := db.orderService.SearchOrders(query, domain.OrderStatusActive)
+	if err != nil {
+		return formatSearchError(err)
+	}
+	orders = service.FilterOrdersForDriverVehicle(orders, driver)
+	if len(orders) == 0 {
+		return fmt.Sprintf("🔎 По запросу «%s» активных заказов не найдено", query)
+	}
+	shown, note := limitFoundOrders(orders)
+	return fmt.Sprintf("🔎 Найдено по запросу «%s»:\n\n%s%s", query, db.formatOrders(shown, driverContactViewer(driver)), note)
+}
+
+// limitFoundOrders оставляет самые подходящие заказы и возвращает примечание о скрытых
+func limitFoundOrders(orders []domain.Order) ([]domain.Order, string) {
+	if len(orders) <= maxFoundOrders {
+		return orders, ""
+	}
+	return orders[:maxFoundOrders], fmt.Sprintf("\nПоказаны %d самых подходящих из %d — уточните запрос", maxFoundOrders, len(orders))
+}
+
+// formatSearchError возвращает текст ошибки поиска: ошибки запроса показываются пользователю
+func formatSearchError(err error) string {
+	if service.IsValidationError(err) {
+		return fmt.Sprintf("❌ %v\n\n%s", err, findHelp)
+	}
+	log.Printf("Ошибка поиска заказов: %v", err)
+	return "❌ Ошибка поиска заказов"
+}
@@ -3,14 +3,19 @@ package internal
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
 
 // BotConfig представляет конфигурацию ботов
 type BotConfig struct {
-	AdminToken  string
-	DriverToken string
+	AdminToken     string
+	DriverToken    string
+	AdminChatIDs   []int64 `yaml:"admin_chat_ids"`   // Чаты администраторов для служебных уведомлений
+	DriverTermsURL string  `yaml:"driver_terms_url"` // Ссылка на полные условия сервиса для водителей
 }
 
 // DatabaseConfig представляет конфигурацию базы данных
@@ -30,11 +35,77 @@ type RedisConfig struct {
 	DB       int    `yaml:"db"`
 }
 
+// APIConfig представляет конфигурацию HTTP API
+type APIConfig struct {
+	AdminKeys []string `yaml:"admin_keys"` // Ключи для изменяющих методов API
+}
+
+// ExpiryConfig представляет настройки автоматического истечения заказов
+type ExpiryConfig struct {
+	Enabled       bool          `yaml:"enabled"`
+	CheckInterval time.Duration `yaml:"check_interval"` // Периодичность проверки
+	GracePeriod   time.Duration `yaml:"grace_period"`   // Сколько заказ живет после даты погрузки
+	MaxAge        time.Duration `yaml:"max_age"`        // Максимальный возраст заказа с момента создания
+	WarnBefore    time.Duration `yaml:"warn_before"`    // За сколько до истечения предупреждать
+}
+
+// StorageConfig представляет настройки хранилища копий вложений заказов.
+// Пустой тип отключает зеркалирование: файлы остаются только в Telegram.
+type StorageConfig struct {
+	Type     string `yaml:"type"`      // "", "local" или "s3"
+	LocalDir string `yaml:"local_dir"` // Директория для типа local
+	S3       struct {
+		Endpoint  string `yaml:"endpoint"`
+		Region    string `yaml:"region"`
+		Bucket    string `yaml:"bucket"`
+		AccessKey string `yaml:"access_key"`
+		SecretKey string `yaml:"secret_key"`
+	} `yaml:"s3"`
+}
+
+// RatingsConfig представляет настройки оценок водителей заказчиками
+type RatingsConfig struct {
+	LinkSecret string `yaml:"link_secret"` // Ключ подписи ссылок на оценку (лучше задавать через RATING_LINK_SECRET)
+	BaseURL    string `yaml:"base_url"`    // Публичный адрес сайта, например https://dalnoboy.ru
+}
+
+// DigestConfig представляет настройки ежедневных сводок заказов для водителей
+type DigestConfig struct {
+	Enabled       bool          `yaml:"enabled"`
+	CheckInterval time.Duration `yaml:"check_interval"` // Как часто проверять, у кого наступил час сводки
+}
+
+// QuietHoursConfig представляет настройки отправки отложенных уведомлений: на тихие часы водителей и по приоритету рейтинга
+type QuietHoursConfig struct {
+	CheckInterval time.Duration `yaml:"check_interval"` // Как часто отправлять уведомления, время которых наступило
+}
+
+// AnalyticsConfig представляет настройки еженедельной сводки аналитики для администраторов
+type AnalyticsConfig struct {
+	WeeklySummary  bool          `yaml:"weekly_summary"`
+	SummaryWeekday string        `yaml:"summary_weekday"` // День недели сводки: monday … sunday
+	SummaryHour    int           `yaml:"summary_hour"`    // Час отправки по времени сервера
+	CheckInterval  time.Duration `yaml:"check_interval"`  // Как часто проверять, не пора ли отправить сводку
+}
+
+// WebsiteConfig представляет настройки сайта
+type WebsiteConfig struct {
+	Dir string `yaml:"dir"` // Директория с файлами сайта для разработки; пусто — файлы, встроенные при сборке
+}
+
 // Config представляет общую конфигурацию приложения
 type Config struct {
-	Bot      BotConfig
-	Database DatabaseConfig `yaml:"database"`
-	Redis    RedisConfig    `yaml:"redis"`
+	Bot        BotConfig        `yaml:"bot"`
+	Database   DatabaseConfig   `yaml:"database"`
+	Redis      RedisConfig      `yaml:"redis"`
+	API        APIConfig        `yaml:"api"`
+	Expiry     ExpiryConfig     `yaml:"expiry"`
+	Storage    StorageConfig    `yaml:"storage"`
+	Ratings    RatingsConfig    `yaml:"ratings"`
+	Digest     DigestConfig     `yaml:"digest"`
+	QuietHours QuietHoursConfig `yaml:"quiet_hours"`
+	Analytics  AnalyticsConfig  `yaml:"analytics"`
+	Website    WebsiteConfig    `yaml:"website"`
 }
 
 // NewConfig создает новый экземпляр конфига из YAML файла и переменных окружения
@@ -66,6 +137,20 @@ func NewConfig() (*Config, error) {
 	config.Bot.AdminToken = os.Getenv("ADMIN_BOT_TOKEN")
 	config.Bot.DriverToken = os.Getenv("DRIVER_BOT_TOKEN")
 
+	// Чаты администраторов из переменной окружения дополняют чаты из файла
+	if chatIDs := os.Getenv("ADMIN_CHAT_IDS"); chatIDs != "" {
+		for _, value := range strings.Split(chatIDs, ",") {
+			if value = strings.TrimSpace(value); value == "" {
+				continue
+			}
+			chatID, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("некорректный ID чата в ADMIN_CHAT_IDS: %s", value)
+			}
+			config.Bot.AdminChatIDs = append(config.Bot.AdminChatIDs, chatID)
+		}
+	}
+
 	// Загружаем настройки Redis из переменных окружения (приоритет над файлом)
 	if redisHost := os.Getenv("REDIS_HOST"); redisHost != "" {
 		config.Redis.Host = redisHost
@@ -86,9 +171,100 @@ func NewConfig() (*Config, error) {
 		}
 	}
 
+	// Ключи администраторов API из переменной окружения дополняют ключи из файла
+	if apiKeys := os.Getenv("ADMIN_API_KEYS"); apiKeys != "" {
+		for _, key := range strings.Split(apiKeys, ",") {
+			if key = strings.TrimSpace(key); key != "" {
+				config.API.AdminKeys = append(config.API.AdminKeys, key)
+			}
+		}
+	}
+
+	// Ключи S3 хранилища из переменных окружения (приоритет над файлом)
+	if accessKey := os.Getenv("S3_ACCESS_KEY"); accessKey != "" {
+		config.Storage.S3.AccessKey = accessKey
+	}
+	if secretKey := os.Getenv("S3_SECRET_KEY"); secretKey != "" {
+		config.Storage.S3.SecretKey = secretKey
+	}
+
+	// Ключ подписи ссылок на оценку водителя из переменной окружения (приоритет над файлом)
+	if secret := os.Getenv("RATING_LINK_SECRET"); secret != "" {
+		config.Ratings.LinkSecret = secret
+	}
+
+	// Директория файлов сайта для разработки из переменной окружения (приоритет над файлом)
+	if dir := os.Getenv("WEBSITE_DIR"); dir != "" {
+		config.Website.Dir = dir
+	}
+
+	config.Expiry.applyDefaults()
+	config.Digest.applyDefaults()
+	config.QuietHours.applyDefaults()
+	config.Analytics.applyDefaults()
+
 	return &config, nil
 }
 
+// applyDefaults задает значения по умолчанию для незаполненных настроек истечения заказов
+func (c *ExpiryConfig) applyDefaults() {
+	if c.CheckInterval <= 0 {
+		c.CheckInterval = time.Hour
+	}
+	if c.GracePeriod <= 0 {
+		c.GracePeriod = 48 * time.Hour
+	}
+	if c.MaxAge <= 0 {
+		c.MaxAge = 30 * 24 * time.Hour
+	}
+	if c.WarnBefore < 0 {
+		c.WarnBefore = 0
+	}
+}
+
+// applyDefaults задает значения по умолчанию для незаполненных настроек сводок
+func (c *DigestConfig) applyDefaults() {
+	if c.CheckInterval <= 0 {
+		c.CheckInterval = 10 * time.Minute
+	}
+}
+
+// applyDefaults задает значения по умолчанию для незаполненных настроек тихих часов
+func (c *QuietHoursConfig) applyDefaults() {
+	if c.CheckInterval <= 0 {
+		c.CheckInterval = 5 * time.Minute
+	}
+}
+
+// weekdays сопоставляет названия дней недели в конфиге с time.Weekday
+var weekdays = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
+
+// applyDefaults задает значения по умолчанию для незаполненных настроек сводки аналитики
+func (c *AnalyticsConfig) applyDefaults() {
+	if _, ok := weekdays[strings.ToLower(c.SummaryWeekday)]; !ok {
+		c.SummaryWeekday = "monday"
+	}
+	if c.SummaryHour < 0 || c.SummaryHour > 23 {
+		c.SummaryHour = 9
+	}
+	if c.CheckInterval <= 0 {
+		c.CheckInterval = 10 * time.Minute
+	}
+}
+
+// Weekday возвращает день недели еженедельной сводки
+func (c *AnalyticsConfig) Weekday() time.Weekday {
+	return weekdays[strings.ToLower(c.SummaryWeekday)]
+}
+
 // isLocalDevelopment определяет, запущено ли приложение в локальной среде разработки
 func isLocalDevelopment() bool {
 	// Проверяем наличие переменной окружения
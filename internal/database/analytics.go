@@ -0,0 +1,210 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"dalnoboy/internal/domain"
+)
+
+// GetDailyVolume возвращает по дням периода [from, to) число созданных, назначенных и доставленных заказов.
+// from и to должны приходиться на начало суток; дни без заказов возвращаются с нулями.
+func (d *Database) GetDailyVolume(from, to time.Time) ([]domain.DailyVolume, error) {
+	query := `
+		WITH days AS (
+			SELECT generate_series($1::timestamp, $2::timestamp - interval '1 day', interval '1 day') AS day
+		),
+		created AS (
+			SELECT date_trunc('day', created_at) AS day, COUNT(*) AS total FROM orders
+			WHERE created_at >= $1 AND created_at < $2 GROUP BY 1
+		),
+		assigned AS (
+			SELECT date_trunc('day', assigned_at) AS day, COUNT(*) AS total FROM orders
+			WHERE assigned_at >= $1 AND assigned_at < $2 GROUP BY 1
+		),
+		delivered AS (
+			SELECT date_trunc('day', delivered_at) AS day, COUNT(*) AS total FROM orders
+			WHERE status = 'delivered' AND delivered_at >= $1 AND delivered_at < $2 GROUP BY 1
+		)
+		SELECT to_char(days.day, 'YYYY-MM-DD'), COALESCE(c.total, 0), COALESCE(a.total, 0), COALESCE(dl.total, 0)
+		FROM days
+		LEFT JOIN created c ON c.day = days.day
+		LEFT JOIN assigned a ON a.day = days.day
+		LEFT JOIN delivered dl ON dl.day = days.day
+		ORDER BY days.day
+	`
+
+	rows, err := d.DB.Query(query, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения заказов по дням: %v", err)
+	}
+	defer rows.Close()
+
+	var volume []domain.DailyVolume
+	for rows.Next() {
+		var day domain.DailyVolume
+		if err := rows.Scan(&day.Date, &day.Created, &day.Assigned, &day.Delivered); err != nil {
+			return nil, fmt.Errorf("ошибка сканирования заказов по дням: %v", err)
+		}
+		volume = append(volume, day)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка чтения заказов по дням: %v", err)
+	}
+	return volume, nil
+}
+
+// GetAssignmentTime возвращает медиану и среднее время до назначения водителя по заказам,
+// назначенным в периоде, и долю назначенных среди заказов, созданных в периоде
+func (d *Database) GetAssignmentTime(from, to time.Time) (*domain.AssignmentTime, error) {
+	query := `
+		SELECT
+			(SELECT COUNT(*) FROM orders WHERE assigned_at >= $1 AND assigned_at < $2),
+			(SELECT percentile_cont(0.5) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM assigned_at - created_at)) / 3600
+				FROM orders WHERE assigned_at >= $1 AND assigned_at < $2),
+			(SELECT AVG(EXTRACT(EPOCH FROM assigned_at - created_at)) / 3600
+				FROM orders WHERE assigned_at >= $1 AND assigned_at < $2),
+			(SELECT COUNT(*) FROM orders WHERE created_at >= $1 AND created_at < $2),
+			(SELECT COUNT(*) FROM orders WHERE created_at >= $1 AND created_at < $2 AND assigned_at IS NOT NULL)
+	`
+
+	var result domain.AssignmentTime
+	err := d.DB.QueryRow(query, from, to).Scan(&result.Orders, &result.MedianHours, &result.AverageHours,
+		&result.Created, &result.Converted)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения времени назначения заказов: %v", err)
+	}
+	return &result, nil
+}
+
+// GetTopRoutes возвращает самые частые направления заказов, созданных в периоде
+func (d *Database) GetTopRoutes(from, to time.Time, limit int) ([]domain.RouteStats, error) {
+	query := `
+		SELECT fc.name, tc.name, COUNT(*), COUNT(*) FILTER (WHERE o.assigned_at IS NOT NULL),
+			AVG(o.price), AVG(o.price / NULLIF(o.distance_km, 0))
+		FROM orders o
+		JOIN cities fc ON fc.uuid = o.from_city_uuid
+		JOIN cities tc ON tc.uuid = o.to_city_uuid
+		WHERE o.created_at >= $1 AND o.created_at < $2
+		GROUP BY fc.name, tc.name
+		ORDER BY COUNT(*) DESC, fc.name, tc.name
+		LIMIT $3
+	`
+
+	rows, err := d.DB.Query(query, from, to, limit)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения популярных направлений: %v", err)
+	}
+	defer rows.Close()
+
+	var routes []domain.RouteStats
+	for rows.Next() {
+		var route domain.RouteStats
+		if err := rows.Scan(&route.FromCity, &route.ToCity, &route.Orders, &route.Assigned,
+			&route.AvgPrice, &route.AvgPricePerKm); err != nil {
+			return nil, fmt.Errorf("ошибка сканирования направления: %v", err)
+		}
+		routes = append(routes, route)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка чтения популярных направлений: %v", err)
+	}
+	return routes, nil
+}
+
+// GetPriceStats возвращает среднюю цену заказа, килограмма и километра по заказам, созданным в периоде
+func (d *Database) GetPriceStats(from, to time.Time) (*domain.PriceStats, error) {
+	query := `
+		SELECT COUNT(*), AVG(price), AVG(price / NULLIF(weight_kg, 0)), AVG(price / NULLIF(distance_km, 0))
+		FROM orders
+		WHERE created_at >= $1 AND created_at < $2
+	`
+
+	var stats domain.PriceStats
+	err := d.DB.QueryRow(query, from, to).Scan(&stats.Orders, &stats.AvgPrice, &stats.AvgPricePerKg, &stats.AvgPricePerKm)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения статистики цен: %v", err)
+	}
+	return &stats, nil
+}
+
+// GetDriverActivity возвращает водителей, которые в периоде делали предложения, получали
+// или доставляли заказы. Самые активные — первыми.
+func (d *Database) GetDriverActivity(from, to time.Time) ([]domain.DriverActivity, error) {
+	query := `
+		WITH bid_counts AS (
+			SELECT driver_uuid, COUNT(*) AS total FROM bids
+			WHERE created_at >= $1 AND created_at < $2 GROUP BY driver_uuid
+		),
+		assigned_counts AS (
+			SELECT assigned_driver_uuid AS driver_uuid, COUNT(*) AS total FROM orders
+			WHERE assigned_at >= $1 AND assigned_at < $2 AND assigned_driver_uuid IS NOT NULL GROUP BY 1
+		),
+		delivered_counts AS (
+			SELECT assigned_driver_uuid AS driver_uuid, COUNT(*) AS total, SUM(price) AS revenue FROM orders
+			WHERE status = 'delivered' AND delivered_at >= $1 AND delivered_at < $2 AND assigned_driver_uuid IS NOT NULL
+			GROUP BY 1
+		)
+		SELECT d.uuid, d.name, COALESCE(b.total, 0), COALESCE(a.total, 0), COALESCE(dc.total, 0), COALESCE(dc.revenue, 0),
+			(SELECT AVG(r.score) FROM driver_ratings r WHERE r.driver_uuid = d.uuid)
+		FROM drivers d
+		LEFT JOIN bid_counts b ON b.driver_uuid = d.uuid
+		LEFT JOIN assigned_counts a ON a.driver_uuid = d.uuid
+		LEFT JOIN delivered_counts dc ON dc.driver_uuid = d.uuid
+		WHERE b.driver_uuid IS NOT NULL OR a.driver_uuid IS NOT NULL OR dc.driver_uuid IS NOT NULL
+		ORDER BY COALESCE(dc.total, 0) DESC, COALESCE(a.total, 0) DESC, COALESCE(b.total, 0) DESC, d.name
+	`
+
+	rows, err := d.DB.Query(query, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения активности водителей: %v", err)
+	}
+	defer rows.Close()
+
+	var activity []domain.DriverActivity
+	for rows.Next() {
+		var driver domain.DriverActivity
+		if err := rows.Scan(&driver.DriverUUID, &driver.Name, &driver.Bids, &driver.Assigned, &driver.Delivered,
+			&driver.Revenue, &driver.RatingAvg); err != nil {
+			return nil, fmt.Errorf("ошибка сканирования активности водителя: %v", err)
+		}
+		activity = append(activity, driver)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка чтения активности водителей: %v", err)
+	}
+	return activity, nil
+}
+
+// GetDriverSources возвращает водителей, зарегистрированных за период, по источникам привлечения.
+// Активными считаются водители, которые хотя бы раз сделали предложение или получили заказ.
+func (d *Database) GetDriverSources(from, to time.Time) ([]domain.DriverSourceStats, error) {
+	query := `
+		SELECT COALESCE(d.source, ''), COUNT(*),
+			COUNT(*) FILTER (WHERE EXISTS (SELECT 1 FROM bids b WHERE b.driver_uuid = d.uuid)
+				OR EXISTS (SELECT 1 FROM orders o WHERE o.assigned_driver_uuid = d.uuid))
+		FROM drivers d
+		WHERE d.created_at >= $1 AND d.created_at < $2
+		GROUP BY 1
+		ORDER BY 2 DESC, 1
+	`
+
+	rows, err := d.DB.Query(query, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения источников водителей: %v", err)
+	}
+	defer rows.Close()
+
+	var sources []domain.DriverSourceStats
+	for rows.Next() {
+		var source domain.DriverSourceStats
+		if err := rows.Scan(&source.Source, &source.Drivers, &source.Active); err != nil {
+			return nil, fmt.Errorf("ошибка сканирования источника водителей: %v", err)
+		}
+		sources = append(sources, source)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка чтения источников водителей: %v", err)
+	}
+	return sources, nil
+}
@@ -0,0 +1,145 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+
+	"dalnoboy/internal/domain"
+)
+
+// attachmentSelectQuery содержит общую часть запроса вложений заказов
+const attachmentSelectQuery = `
+		SELECT uuid, order_uuid, kind, purpose, source_bot, telegram_file_id, telegram_file_unique_id,
+			admin_file_id, driver_file_id,
+			file_name, mime_type, size_bytes, caption, storage_key, uploaded_by, uploader_telegram_id, created_at
+		FROM order_attachments
+	`
+
+// scanAttachment сканирует одну строку результата attachmentSelectQuery
+func scanAttachment(row rowScanner) (*domain.Attachment, error) {
+	var attachment domain.Attachment
+	err := row.Scan(
+		&attachment.UUID,
+		&attachment.OrderUUID,
+		&attachment.Kind,
+		&attachment.Purpose,
+		&attachment.SourceBot,
+		&attachment.TelegramFileID,
+		&attachment.TelegramUniqueID,
+		&attachment.AdminFileID,
+		&attachment.DriverFileID,
+		&attachment.FileName,
+		&attachment.MimeType,
+		&attachment.SizeBytes,
+		&attachment.Caption,
+		&attachment.StorageKey,
+		&attachment.UploadedBy,
+		&attachment.UploaderTelegramID,
+		&attachment.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+// CreateAttachment сохраняет вложение заказа
+func (d *Database) CreateAttachment(attachment *domain.Attachment) error {
+	query := `
+		INSERT INTO order_attachments (
+			uuid, order_uuid, kind, purpose, source_bot, telegram_file_id, telegram_file_unique_id,
+			file_name, mime_type, size_bytes, caption, storage_key, uploaded_by, uploader_telegram_id, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+	`
+
+	_, err := d.DB.Exec(query,
+		attachment.UUID,
+		attachment.OrderUUID,
+		attachment.Kind,
+		attachment.Purpose,
+		attachment.SourceBot,
+		attachment.TelegramFileID,
+		attachment.TelegramUniqueID,
+		attachment.FileName,
+		attachment.MimeType,
+		attachment.SizeBytes,
+		attachment.Caption,
+		attachment.StorageKey,
+		attachment.UploadedBy,
+		attachment.UploaderTelegramID,
+		attachment.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("ошибка сохранения вложения: %v", err)
+	}
+
+	return nil
+}
+
+// GetAttachmentsByOrder возвращает вложения заказа в порядке добавления
+func (d *Database) GetAttachmentsByOrder(orderUUID string) ([]domain.Attachment, error) {
+	rows, err := d.DB.Query(attachmentSelectQuery+" WHERE order_uuid = $1 ORDER BY created_at", orderUUID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка выполнения запроса: %v", err)
+	}
+	defer rows.Close()
+
+	var attachments []domain.Attachment
+	for rows.Next() {
+		attachment, err := scanAttachment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка сканирования строки: %v", err)
+		}
+		attachments = append(attachments, *attachment)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка при итерации по строкам: %v", err)
+	}
+
+	return attachments, nil
+}
+
+// GetAttachmentByUUID возвращает вложение по UUID
+func (d *Database) GetAttachmentByUUID(attachmentUUID string) (*domain.Attachment, error) {
+	attachment, err := scanAttachment(d.DB.QueryRow(attachmentSelectQuery+" WHERE uuid = $1", attachmentUUID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // Вложение не найдено
+		}
+		return nil, fmt.Errorf("ошибка получения вложения: %v", err)
+	}
+
+	return attachment, nil
+}
+
+// UpdateAttachmentBotFileID сохраняет file_id вложения в указанном боте
+func (d *Database) UpdateAttachmentBotFileID(attachmentUUID, bot, fileID string) error {
+	column := "driver_file_id"
+	if bot == domain.AttachmentBotAdmin {
+		column = "admin_file_id"
+	}
+	_, err := d.DB.Exec("UPDATE order_attachments SET "+column+" = $1 WHERE uuid = $2", fileID, attachmentUUID)
+	if err != nil {
+		return fmt.Errorf("ошибка обновления вложения: %v", err)
+	}
+	return nil
+}
+
+// UpdateAttachmentStorageKey сохраняет ключ копии файла в хранилище
+func (d *Database) UpdateAttachmentStorageKey(attachmentUUID, storageKey string) error {
+	_, err := d.DB.Exec("UPDATE order_attachments SET storage_key = $1 WHERE uuid = $2", storageKey, attachmentUUID)
+	if err != nil {
+		return fmt.Errorf("ошибка обновления вложения: %v", err)
+	}
+	return nil
+}
+
+// DeleteAttachment удаляет вложение
+func (d *Database) DeleteAttachment(attachmentUUID string) error {
+	_, err := d.DB.Exec("DELETE FROM order_attachments WHERE uuid = $1", attachmentUUID)
+	if err != nil {
+		return fmt.Errorf("ошибка удаления вложения: %v", err)
+	}
+	return nil
+}
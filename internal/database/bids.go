@@ -0,0 +1,144 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"dalnoboy/internal/domain"
+)
+
+// bidSelectQuery содержит общую часть запроса предложений водителей
+const bidSelectQuery = `
+		SELECT uuid, order_uuid, driver_uuid, price, pickup_date, comment, status, created_at, updated_at
+		FROM bids
+	`
+
+// scanBid сканирует одну строку результата bidSelectQuery
+func scanBid(row rowScanner) (*domain.Bid, error) {
+	var bid domain.Bid
+	err := row.Scan(
+		&bid.UUID,
+		&bid.OrderUUID,
+		&bid.DriverUUID,
+		&bid.Price,
+		&bid.PickupDate,
+		&bid.Comment,
+		&bid.Status,
+		&bid.CreatedAt,
+		&bid.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &bid, nil
+}
+
+// queryBids выполняет запрос предложений и сканирует все строки
+func (d *Database) queryBids(query string, args ...interface{}) ([]domain.Bid, error) {
+	rows, err := d.DB.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка выполнения запроса: %v", err)
+	}
+	defer rows.Close()
+
+	var bids []domain.Bid
+	for rows.Next() {
+		bid, err := scanBid(rows)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка сканирования строки: %v", err)
+		}
+		bids = append(bids, *bid)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка при итерации по строкам: %v", err)
+	}
+
+	return bids, nil
+}
+
+// SaveBid сохраняет предложение водителя. Повторное предложение по тому же заказу заменяет
+// цену, дату и комментарий и снова переводит предложение в ожидание.
+func (d *Database) SaveBid(bid *domain.Bid) (*domain.Bid, error) {
+	query := `
+		INSERT INTO bids (order_uuid, driver_uuid, price, pickup_date, comment)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (order_uuid, driver_uuid) DO UPDATE SET
+			price = EXCLUDED.price,
+			pickup_date = EXCLUDED.pickup_date,
+			comment = EXCLUDED.comment,
+			status = 'pending',
+			updated_at = now()
+		RETURNING uuid, order_uuid, driver_uuid, price, pickup_date, comment, status, created_at, updated_at
+	`
+
+	saved, err := scanBid(d.DB.QueryRow(query, bid.OrderUUID, bid.DriverUUID, bid.Price, pickupDateValue(bid.PickupDate), bid.Comment))
+	if err != nil {
+		return nil, fmt.Errorf("ошибка сохранения предложения: %v", err)
+	}
+	return saved, nil
+}
+
+// GetBidsByUUIDPrefix возвращает предложения, UUID которых начинается с prefix
+func (d *Database) GetBidsByUUIDPrefix(prefix string) ([]domain.Bid, error) {
+	return d.queryBids(bidSelectQuery+" WHERE uuid::text LIKE $1 || '%' ORDER BY created_at", prefix)
+}
+
+// GetBidsByOrder возвращает предложения по заказу: сначала ожидающие, затем по возрастанию цены
+func (d *Database) GetBidsByOrder(orderUUID string) ([]domain.Bid, error) {
+	return d.queryBids(bidSelectQuery+" WHERE order_uuid = $1 ORDER BY status <> 'pending', price, created_at", orderUUID)
+}
+
+// AcceptBid принимает предложение: назначает активный заказ водителю по цене предложения
+// (и дате погрузки, если она указана) и отклоняет остальные ожидающие предложения.
+// Возвращает false, если заказ уже не активен или предложение не ожидает решения.
+func (d *Database) AcceptBid(bid *domain.Bid) (bool, error) {
+	tx, err := d.DB.Begin()
+	if err != nil {
+		return false, fmt.Errorf("ошибка начала транзакции: %v", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		UPDATE bids SET status = 'accepted', updated_at = now()
+		WHERE uuid = $1 AND status = 'pending'
+	`, bid.UUID)
+	if err != nil {
+		return false, fmt.Errorf("ошибка принятия предложения: %v", err)
+	}
+	if accepted, err := result.RowsAffected(); err != nil || accepted == 0 {
+		return false, err
+	}
+
+	result, err = tx.Exec(`
+		UPDATE orders SET status = $1, assigned_driver_uuid = $2, assigned_at = now(),
+			price = $3, available_from = COALESCE($4, available_from)
+		WHERE uuid = $5 AND status = 'active'
+	`, domain.OrderStatusAssigned, bid.DriverUUID, bid.Price, pickupDateValue(bid.PickupDate), bid.OrderUUID)
+	if err != nil {
+		return false, fmt.Errorf("ошибка назначения заказа: %v", err)
+	}
+	if assigned, err := result.RowsAffected(); err != nil || assigned == 0 {
+		return false, err
+	}
+
+	if _, err := tx.Exec(`
+		UPDATE bids SET status = 'rejected', updated_at = now()
+		WHERE order_uuid = $1 AND uuid <> $2 AND status = 'pending'
+	`, bid.OrderUUID, bid.UUID); err != nil {
+		return false, fmt.Errorf("ошибка отклонения предложений: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("ошибка принятия предложения: %v", err)
+	}
+	return true, nil
+}
+
+// pickupDateValue возвращает дату погрузки без времени для записи в колонку DATE
+func pickupDateValue(date *time.Time) interface{} {
+	if date == nil {
+		return nil
+	}
+	return date.Format("2006-01-02")
+}
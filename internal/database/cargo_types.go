@@ -0,0 +1,77 @@
+package database
+
+import (
+	"fmt"
+
+	"dalnoboy/internal/domain"
+)
+
+// cargoTypeSelectQuery содержит общую часть запроса типов грузов
+const cargoTypeSelectQuery = `
+		SELECT uuid, code, name, is_active
+		FROM cargo_types
+	`
+
+// scanCargoType сканирует одну строку результата cargoTypeSelectQuery
+func scanCargoType(row rowScanner) (*domain.CargoType, error) {
+	var cargoType domain.CargoType
+	if err := row.Scan(&cargoType.UUID, &cargoType.Code, &cargoType.Name, &cargoType.IsActive); err != nil {
+		return nil, err
+	}
+	return &cargoType, nil
+}
+
+// GetAllCargoTypes возвращает справочник типов грузов, отсортированный по названию
+func (d *Database) GetAllCargoTypes() ([]domain.CargoType, error) {
+	rows, err := d.DB.Query(cargoTypeSelectQuery + " ORDER BY name")
+	if err != nil {
+		return nil, fmt.Errorf("ошибка выполнения запроса: %v", err)
+	}
+	defer rows.Close()
+
+	var cargoTypes []domain.CargoType
+	for rows.Next() {
+		cargoType, err := scanCargoType(rows)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка сканирования строки: %v", err)
+		}
+		cargoTypes = append(cargoTypes, *cargoType)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка при итерации по строкам: %v", err)
+	}
+
+	return cargoTypes, nil
+}
+
+// CreateCargoType создает новый тип груза
+func (d *Database) CreateCargoType(cargoType *domain.CargoType) error {
+	query := `
+		INSERT INTO cargo_types (uuid, code, name, is_active)
+		VALUES ($1, $2, $3, $4)
+	`
+
+	_, err := d.DB.Exec(query, cargoType.UUID, cargoType.Code, cargoType.Name, cargoType.IsActive)
+	if err != nil {
+		return fmt.Errorf("ошибка создания типа груза: %v", err)
+	}
+
+	return nil
+}
+
+// UpdateCargoType сохраняет код, название и активность типа груза
+func (d *Database) UpdateCargoType(cargoType *domain.CargoType) error {
+	query := `
+		UPDATE cargo_types
+		SET code = $1, name = $2, is_active = $3
+		WHERE uuid = $4
+	`
+
+	_, err := d.DB.Exec(query, cargoType.Code, cargoType.Name, cargoType.IsActive, cargoType.UUID)
+	if err != nil {
+		return fmt.Errorf("ошибка обновления типа груза: %v", err)
+	}
+
+	return nil
+}
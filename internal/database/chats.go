@@ -0,0 +1,163 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+
+	"dalnoboy/internal/domain"
+)
+
+// conversationSelectQuery содержит общую часть запроса переписок с названием заказа и данными водителя
+const conversationSelectQuery = `
+		SELECT cv.uuid, cv.order_uuid, o.title, cv.driver_uuid, d.name, d.telegram_id,
+			(SELECT COUNT(*) FROM conversation_messages m WHERE m.conversation_uuid = cv.uuid) as messages_count,
+			cv.created_at, cv.last_message_at
+		FROM order_conversations cv
+		JOIN orders o ON cv.order_uuid = o.uuid
+		JOIN drivers d ON cv.driver_uuid = d.uuid
+	`
+
+// scanConversation сканирует одну строку результата conversationSelectQuery
+func scanConversation(row rowScanner) (*domain.Conversation, error) {
+	var conversation domain.Conversation
+	err := row.Scan(
+		&conversation.UUID,
+		&conversation.OrderUUID,
+		&conversation.OrderTitle,
+		&conversation.DriverUUID,
+		&conversation.DriverName,
+		&conversation.DriverTelegramID,
+		&conversation.MessagesCount,
+		&conversation.CreatedAt,
+		&conversation.LastMessageAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &conversation, nil
+}
+
+// queryConversations выполняет запрос переписок и сканирует все строки
+func (d *Database) queryConversations(query string, args ...interface{}) ([]domain.Conversation, error) {
+	rows, err := d.DB.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка выполнения запроса: %v", err)
+	}
+	defer rows.Close()
+
+	var conversations []domain.Conversation
+	for rows.Next() {
+		conversation, err := scanConversation(rows)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка сканирования строки: %v", err)
+		}
+		conversations = append(conversations, *conversation)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка при итерации по строкам: %v", err)
+	}
+
+	return conversations, nil
+}
+
+// GetOrCreateConversation возвращает переписку водителя по заказу, создавая ее при первом обращении
+func (d *Database) GetOrCreateConversation(orderUUID, driverUUID string) (*domain.Conversation, error) {
+	query := `
+		INSERT INTO order_conversations (order_uuid, driver_uuid)
+		VALUES ($1, $2)
+		ON CONFLICT (order_uuid, driver_uuid) DO NOTHING
+	`
+	if _, err := d.DB.Exec(query, orderUUID, driverUUID); err != nil {
+		return nil, fmt.Errorf("ошибка создания переписки: %v", err)
+	}
+
+	conversation, err := scanConversation(d.DB.QueryRow(conversationSelectQuery+" WHERE cv.order_uuid = $1 AND cv.driver_uuid = $2", orderUUID, driverUUID))
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения переписки: %v", err)
+	}
+	return conversation, nil
+}
+
+// GetConversationByUUID возвращает переписку по UUID
+func (d *Database) GetConversationByUUID(conversationUUID string) (*domain.Conversation, error) {
+	conversation, err := scanConversation(d.DB.QueryRow(conversationSelectQuery+" WHERE cv.uuid = $1", conversationUUID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("ошибка получения переписки: %v", err)
+	}
+	return conversation, nil
+}
+
+// GetConversationsByUUIDPrefix возвращает переписки, UUID которых начинается с префикса
+func (d *Database) GetConversationsByUUIDPrefix(prefix string) ([]domain.Conversation, error) {
+	return d.queryConversations(conversationSelectQuery+" WHERE cv.uuid::text LIKE $1 || '%'", prefix)
+}
+
+// GetConversationsByOrder возвращает переписки по заказу
+func (d *Database) GetConversationsByOrder(orderUUID string) ([]domain.Conversation, error) {
+	return d.queryConversations(conversationSelectQuery+" WHERE cv.order_uuid = $1 ORDER BY cv.created_at", orderUUID)
+}
+
+// GetRecentConversations возвращает переписки с последними сообщениями
+func (d *Database) GetRecentConversations(limit int) ([]domain.Conversation, error) {
+	return d.queryConversations(conversationSelectQuery+" ORDER BY cv.last_message_at DESC LIMIT $1", limit)
+}
+
+// CreateChatMessage сохраняет сообщение переписки и обновляет время последнего сообщения
+func (d *Database) CreateChatMessage(message *domain.ChatMessage) error {
+	tx, err := d.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("ошибка начала транзакции: %v", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO conversation_messages (uuid, conversation_uuid, sender, text, delivered, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	if _, err := tx.Exec(query, message.UUID, message.ConversationUUID, message.Sender, message.Text, message.Delivered, message.CreatedAt); err != nil {
+		return fmt.Errorf("ошибка сохранения сообщения: %v", err)
+	}
+	if _, err := tx.Exec("UPDATE order_conversations SET last_message_at = $1 WHERE uuid = $2", message.CreatedAt, message.ConversationUUID); err != nil {
+		return fmt.Errorf("ошибка обновления переписки: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("ошибка сохранения сообщения: %v", err)
+	}
+	return nil
+}
+
+// GetChatMessages возвращает сообщения переписки в хронологическом порядке
+func (d *Database) GetChatMessages(conversationUUID string) ([]domain.ChatMessage, error) {
+	query := `
+		SELECT uuid, conversation_uuid, sender, text, delivered, created_at
+		FROM conversation_messages
+		WHERE conversation_uuid = $1
+		ORDER BY created_at
+	`
+
+	rows, err := d.DB.Query(query, conversationUUID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка выполнения запроса: %v", err)
+	}
+	defer rows.Close()
+
+	var messages []domain.ChatMessage
+	for rows.Next() {
+		var message domain.ChatMessage
+		if err := rows.Scan(&message.UUID, &message.ConversationUUID, &message.Sender, &message.Text, &message.Delivered, &message.CreatedAt); err != nil {
+			return nil, fmt.Errorf("ошибка сканирования строки: %v", err)
+		}
+		messages = append(messages, message)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка при итерации по строкам: %v", err)
+	}
+
+	return messages, nil
+}
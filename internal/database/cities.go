@@ -0,0 +1,145 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+
+	"dalnoboy/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// citySelectQuery содержит общую часть запроса городов
+const citySelectQuery = `
+		SELECT uuid, name, aliases, region, latitude, longitude
+		FROM cities
+	`
+
+// scanCity сканирует одну строку результата citySelectQuery
+func scanCity(row rowScanner) (*domain.City, error) {
+	var city domain.City
+	var uuidStr string
+	var aliases pq.StringArray
+	if err := row.Scan(&uuidStr, &city.Name, &aliases, &city.Region, &city.Latitude, &city.Longitude); err != nil {
+		return nil, err
+	}
+
+	// Парсим UUID из строки
+	cityUUID, err := uuid.Parse(uuidStr)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка парсинга UUID города: %v", err)
+	}
+	city.UUID = cityUUID
+	city.Aliases = []string(aliases)
+
+	return &city, nil
+}
+
+// GetCityByName возвращает город по точному названию
+func (d *Database) GetCityByName(cityName string) (*domain.City, error) {
+	city, err := scanCity(d.DB.QueryRow(citySelectQuery+" WHERE name = $1", cityName))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // Город не найден
+		}
+		return nil, fmt.Errorf("ошибка получения города по названию: %v", err)
+	}
+
+	return city, nil
+}
+
+// GetCityByUUID возвращает город по UUID
+func (d *Database) GetCityByUUID(cityUUID uuid.UUID) (*domain.City, error) {
+	city, err := scanCity(d.DB.QueryRow(citySelectQuery+" WHERE uuid = $1", cityUUID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // Город не найден
+		}
+		return nil, fmt.Errorf("ошибка получения города по UUID: %v", err)
+	}
+
+	return city, nil
+}
+
+// GetAllCities возвращает весь справочник городов, отсортированный по названию
+func (d *Database) GetAllCities() ([]domain.City, error) {
+	rows, err := d.DB.Query(citySelectQuery + " ORDER BY name")
+	if err != nil {
+		return nil, fmt.Errorf("ошибка выполнения запроса: %v", err)
+	}
+	defer rows.Close()
+
+	var cities []domain.City
+	for rows.Next() {
+		city, err := scanCity(rows)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка сканирования строки: %v", err)
+		}
+		cities = append(cities, *city)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка при итерации по строкам: %v", err)
+	}
+
+	return cities, nil
+}
+
+// CreateCity создает новый город в базе данных
+func (d *Database) CreateCity(city *domain.City) error {
+	query := `
+		INSERT INTO cities (uuid, name, aliases, region, latitude, longitude)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err := d.DB.Exec(query, city.UUID, city.Name, pq.Array(city.Aliases), city.Region, city.Latitude, city.Longitude)
+	if err != nil {
+		return fmt.Errorf("ошибка создания города: %v", err)
+	}
+
+	return nil
+}
+
+// UpdateCity сохраняет название, псевдонимы, регион и координаты города
+func (d *Database) UpdateCity(city *domain.City) error {
+	query := `
+		UPDATE cities
+		SET name = $1, aliases = $2, region = $3, latitude = $4, longitude = $5
+		WHERE uuid = $6
+	`
+
+	_, err := d.DB.Exec(query, city.Name, pq.Array(city.Aliases), city.Region, city.Latitude, city.Longitude, city.UUID)
+	if err != nil {
+		return fmt.Errorf("ошибка обновления города: %v", err)
+	}
+
+	return nil
+}
+
+// CountCityReferences возвращает количество заказов, водителей и сохраненных поисков, ссылающихся на город
+func (d *Database) CountCityReferences(cityUUID uuid.UUID) (int, error) {
+	query := `
+		SELECT
+			(SELECT COUNT(*) FROM orders WHERE from_city_uuid = $1 OR to_city_uuid = $1) +
+			(SELECT COUNT(*) FROM drivers WHERE city_uuid = $1) +
+			(SELECT COUNT(*) FROM driver_saved_searches WHERE city_uuid = $1)
+	`
+
+	var count int
+	if err := d.DB.QueryRow(query, cityUUID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("ошибка подсчета ссылок на город: %v", err)
+	}
+
+	return count, nil
+}
+
+// DeleteCity удаляет город из справочника
+func (d *Database) DeleteCity(cityUUID uuid.UUID) error {
+	_, err := d.DB.Exec("DELETE FROM cities WHERE uuid = $1", cityUUID)
+	if err != nil {
+		return fmt.Errorf("ошибка удаления города: %v", err)
+	}
+
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 
 	"dalnoboy/internal"
 	"dalnoboy/internal/domain"
+	"dalnoboy/internal/geo"
 
 	"github.com/google/uuid"
 	"github.com/lib/pq"
@@ -16,6 +17,8 @@ import (
 // Database представляет подключение к базе данных
 type Database struct {
 	DB *sql.DB
+
+	connStr string // Для отдельного соединения LISTEN (события ленты заказов)
 }
 
 // Ensure Database implements OrderRepository, CustomerRepository and DriverRepository
@@ -40,7 +43,7 @@ func New(config *internal.Config) (*Database, error) {
 
 	log.Println("✅ Подключение к базе данных PostgreSQL установлено успешно")
 
-	return &Database{DB: db}, nil
+	return &Database{DB: db, connStr: connStr}, nil
 }
 
 // Close закрывает подключение к базе данных
@@ -77,9 +80,8 @@ func (d *Database) GetCustomersCount() (int, error) {
 	return count, nil
 }
 
-// GetAllOrders возвращает все заказы с информацией о клиентах
-func (d *Database) GetAllOrders() ([]domain.Order, error) {
-	query := `
+// orderSelectQuery содержит общую часть запроса заказов с информацией о клиентах и городах
+const orderSelectQuery = `
 		SELECT 
 			o.uuid,
 			o.customer_uuid,
@@ -103,111 +105,112 @@ func (d *Database) GetAllOrders() ([]domain.Order, error) {
 			c.telegram_id as customer_telegram_id,
 			c.telegram_tag as customer_telegram_tag,
 			COALESCE(fc.name, '') as from_city_name,
-			COALESCE(tc.name, '') as to_city_name
+			COALESCE(tc.name, '') as to_city_name,
+			o.distance_km,
+			o.cargo_type_uuid,
+			ct.name as cargo_type_name,
+			o.temperature_min_c,
+			o.temperature_max_c,
+			o.adr_class,
+			o.is_oversize,
+			o.is_fragile,
+			(SELECT COUNT(*) FROM order_attachments oa WHERE oa.order_uuid = o.uuid) as attachments_count,
+			o.assigned_driver_uuid,
+			ad.name as assigned_driver_name,
+			ad.telegram_id as assigned_driver_telegram_id,
+			o.assigned_at,
+			o.delivered_at,
+			o.delivery_latitude,
+			o.delivery_longitude,
+			o.is_urgent,
+			o.activated_at
 		FROM orders o
 		JOIN customers c ON o.customer_uuid = c.uuid
 		LEFT JOIN cities fc ON o.from_city_uuid = fc.uuid
 		LEFT JOIN cities tc ON o.to_city_uuid = tc.uuid
-		ORDER BY o.created_at DESC
+		LEFT JOIN cargo_types ct ON o.cargo_type_uuid = ct.uuid
+		LEFT JOIN drivers ad ON o.assigned_driver_uuid = ad.uuid
 	`
 
-	rows, err := d.DB.Query(query)
-	if err != nil {
-		return nil, fmt.Errorf("ошибка выполнения запроса: %v", err)
-	}
-	defer rows.Close()
+// rowScanner объединяет *sql.Row и *sql.Rows для сканирования
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
 
-	var orders []domain.Order
-	for rows.Next() {
-		var order domain.Order
-		var tags pq.StringArray
-		var fromCityName, toCityName string
-
-		err := rows.Scan(
-			&order.UUID,
-			&order.CustomerUUID,
-			&order.Title,
-			&order.Description,
-			&order.WeightKg,
-			&order.LengthCm,
-			&order.WidthCm,
-			&order.HeightCm,
-			&order.FromCityUUID,
-			&order.FromAddress,
-			&order.ToCityUUID,
-			&order.ToAddress,
-			&tags,
-			&order.Price,
-			&order.AvailableFrom,
-			&order.Status,
-			&order.CreatedAt,
-			&order.CustomerName,
-			&order.CustomerPhone,
-			&order.CustomerTelegramID,
-			&order.CustomerTelegramTag,
-			&fromCityName,
-			&toCityName,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("ошибка сканирования строки: %v", err)
-		}
+// sqlExecer — общий интерфейс *sql.DB и *sql.Tx для запросов без результата
+type sqlExecer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
 
-		order.Tags = []string(tags)
+// scanOrder сканирует одну строку результата orderSelectQuery
+func scanOrder(row rowScanner) (*domain.Order, error) {
+	var order domain.Order
+	var tags pq.StringArray
+	var fromCityName, toCityName string
+
+	err := row.Scan(
+		&order.UUID,
+		&order.CustomerUUID,
+		&order.Title,
+		&order.Description,
+		&order.WeightKg,
+		&order.LengthCm,
+		&order.WidthCm,
+		&order.HeightCm,
+		&order.FromCityUUID,
+		&order.FromAddress,
+		&order.ToCityUUID,
+		&order.ToAddress,
+		&tags,
+		&order.Price,
+		&order.AvailableFrom,
+		&order.Status,
+		&order.CreatedAt,
+		&order.CustomerName,
+		&order.CustomerPhone,
+		&order.CustomerTelegramID,
+		&order.CustomerTelegramTag,
+		&fromCityName,
+		&toCityName,
+		&order.DistanceKm,
+		&order.CargoTypeUUID,
+		&order.CargoTypeName,
+		&order.Requirements.TemperatureMinC,
+		&order.Requirements.TemperatureMaxC,
+		&order.Requirements.ADRClass,
+		&order.Requirements.Oversize,
+		&order.Requirements.Fragile,
+		&order.AttachmentsCount,
+		&order.AssignedDriverUUID,
+		&order.AssignedDriverName,
+		&order.AssignedDriverTgID,
+		&order.AssignedAt,
+		&order.DeliveredAt,
+		&order.DeliveryLatitude,
+		&order.DeliveryLongitude,
+		&order.Urgent,
+		&order.ActivatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
 
-		// Устанавливаем названия городов
-		if fromCityName != "" {
-			order.FromCityName = &fromCityName
-		}
-		if toCityName != "" {
-			order.ToCityName = &toCityName
-		}
+	order.Tags = []string(tags)
 
-		orders = append(orders, order)
+	// Устанавливаем названия городов
+	if fromCityName != "" {
+		order.FromCityName = &fromCityName
 	}
-
-	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("ошибка при итерации по строкам: %v", err)
+	if toCityName != "" {
+		order.ToCityName = &toCityName
 	}
 
-	return orders, nil
+	return &order, nil
 }
 
-// GetActiveOrders возвращает только активные заказы
-func (d *Database) GetActiveOrders() ([]domain.Order, error) {
-	query := `
-		SELECT 
-			o.uuid,
-			o.customer_uuid,
-			o.title,
-			o.description,
-			o.weight_kg,
-			o.length_cm,
-			o.width_cm,
-			o.height_cm,
-			o.from_city_uuid,
-			o.from_address,
-			o.to_city_uuid,
-			o.to_address,
-			o.tags,
-			o.price,
-			o.available_from,
-			o.status,
-			o.created_at,
-			c.name as customer_name,
-			c.phone as customer_phone,
-			c.telegram_id as customer_telegram_id,
-			c.telegram_tag as customer_telegram_tag,
-			COALESCE(fc.name, '') as from_city_name,
-			COALESCE(tc.name, '') as to_city_name
-		FROM orders o
-		JOIN customers c ON o.customer_uuid = c.uuid
-		LEFT JOIN cities fc ON o.from_city_uuid = fc.uuid
-		LEFT JOIN cities tc ON o.to_city_uuid = tc.uuid
-		WHERE o.status = 'active'
-		ORDER BY o.created_at DESC
-	`
-
-	rows, err := d.DB.Query(query)
+// queryOrders выполняет запрос заказов и сканирует все строки
+func (d *Database) queryOrders(query string, args ...interface{}) ([]domain.Order, error) {
+	rows, err := d.DB.Query(query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("ошибка выполнения запроса: %v", err)
 	}
@@ -215,50 +218,11 @@ func (d *Database) GetActiveOrders() ([]domain.Order, error) {
 
 	var orders []domain.Order
 	for rows.Next() {
-		var order domain.Order
-		var tags pq.StringArray
-		var fromCityName, toCityName string
-
-		err := rows.Scan(
-			&order.UUID,
-			&order.CustomerUUID,
-			&order.Title,
-			&order.Description,
-			&order.WeightKg,
-			&order.LengthCm,
-			&order.WidthCm,
-			&order.HeightCm,
-			&order.FromCityUUID,
-			&order.FromAddress,
-			&order.ToCityUUID,
-			&order.ToAddress,
-			&tags,
-			&order.Price,
-			&order.AvailableFrom,
-			&order.Status,
-			&order.CreatedAt,
-			&order.CustomerName,
-			&order.CustomerPhone,
-			&order.CustomerTelegramID,
-			&order.CustomerTelegramTag,
-			&fromCityName,
-			&toCityName,
-		)
+		order, err := scanOrder(rows)
 		if err != nil {
 			return nil, fmt.Errorf("ошибка сканирования строки: %v", err)
 		}
-
-		order.Tags = []string(tags)
-
-		// Устанавливаем названия городов
-		if fromCityName != "" {
-			order.FromCityName = &fromCityName
-		}
-		if toCityName != "" {
-			order.ToCityName = &toCityName
-		}
-
-		orders = append(orders, order)
+		orders = append(orders, *order)
 	}
 
 	if err = rows.Err(); err != nil {
@@ -268,100 +232,37 @@ func (d *Database) GetActiveOrders() ([]domain.Order, error) {
 	return orders, nil
 }
 
+// GetAllOrders возвращает все заказы с информацией о клиентах
+func (d *Database) GetAllOrders() ([]domain.Order, error) {
+	return d.queryOrders(orderSelectQuery + " ORDER BY o.created_at DESC")
+}
+
+// GetActiveOrders возвращает только активные заказы
+func (d *Database) GetActiveOrders() ([]domain.Order, error) {
+	return d.queryOrders(orderSelectQuery + " WHERE o.status = 'active' ORDER BY o.created_at DESC")
+}
+
 // GetOrdersByStatus возвращает заказы по указанному статусу
 func (d *Database) GetOrdersByStatus(status string) ([]domain.Order, error) {
-	query := `
-		SELECT 
-			o.uuid,
-			o.customer_uuid,
-			o.title,
-			o.description,
-			o.weight_kg,
-			o.length_cm,
-			o.width_cm,
-			o.height_cm,
-			o.from_city_uuid,
-			o.from_address,
-			o.to_city_uuid,
-			o.to_address,
-			o.tags,
-			o.price,
-			o.available_from,
-			o.status,
-			o.created_at,
-			c.name as customer_name,
-			c.phone as customer_phone,
-			c.telegram_id as customer_telegram_id,
-			c.telegram_tag as customer_telegram_tag,
-			COALESCE(fc.name, '') as from_city_name,
-			COALESCE(tc.name, '') as to_city_name
-		FROM orders o
-		JOIN customers c ON o.customer_uuid = c.uuid
-		LEFT JOIN cities fc ON o.from_city_uuid = fc.uuid
-		LEFT JOIN cities tc ON o.to_city_uuid = tc.uuid
-		WHERE o.status = $1
-		ORDER BY o.created_at DESC
-	`
+	return d.queryOrders(orderSelectQuery+" WHERE o.status = $1 ORDER BY o.created_at DESC", status)
+}
 
-	rows, err := d.DB.Query(query, status)
+// GetOrderByUUID возвращает заказ по UUID
+func (d *Database) GetOrderByUUID(orderUUID string) (*domain.Order, error) {
+	order, err := scanOrder(d.DB.QueryRow(orderSelectQuery+" WHERE o.uuid = $1", orderUUID))
 	if err != nil {
-		return nil, fmt.Errorf("ошибка выполнения запроса: %v", err)
-	}
-	defer rows.Close()
-
-	var orders []domain.Order
-	for rows.Next() {
-		var order domain.Order
-		var tags pq.StringArray
-		var fromCityName, toCityName string
-
-		err := rows.Scan(
-			&order.UUID,
-			&order.CustomerUUID,
-			&order.Title,
-			&order.Description,
-			&order.WeightKg,
-			&order.LengthCm,
-			&order.WidthCm,
-			&order.HeightCm,
-			&order.FromCityUUID,
-			&order.FromAddress,
-			&order.ToCityUUID,
-			&order.ToAddress,
-			&tags,
-			&order.Price,
-			&order.AvailableFrom,
-			&order.Status,
-			&order.CreatedAt,
-			&order.CustomerName,
-			&order.CustomerPhone,
-			&order.CustomerTelegramID,
-			&order.CustomerTelegramTag,
-			&fromCityName,
-			&toCityName,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("ошибка сканирования строки: %v", err)
-		}
-
-		order.Tags = []string(tags)
-
-		// Устанавливаем названия городов
-		if fromCityName != "" {
-			order.FromCityName = &fromCityName
-		}
-		if toCityName != "" {
-			order.ToCityName = &toCityName
+		if err == sql.ErrNoRows {
+			return nil, nil // Заказ не найден
 		}
-
-		orders = append(orders, order)
+		return nil, fmt.Errorf("ошибка получения заказа по UUID: %v", err)
 	}
 
-	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("ошибка при итерации по строкам: %v", err)
-	}
+	return order, nil
+}
 
-	return orders, nil
+// GetActiveOrdersByUUIDPrefix возвращает активные заказы, UUID которых начинается с префикса
+func (d *Database) GetActiveOrdersByUUIDPrefix(prefix string) ([]domain.Order, error) {
+	return d.queryOrders(orderSelectQuery+" WHERE o.status = 'active' AND o.uuid::text LIKE $1 || '%' ORDER BY o.created_at DESC", prefix)
 }
 
 // GetActiveOrdersCount возвращает количество активных заказов
@@ -379,7 +280,20 @@ func (d *Database) GetActiveOrdersCount() (int, error) {
 
 // UpdateOrderStatus обновляет статус заказа
 func (d *Database) UpdateOrderStatus(orderUUID string, status string) error {
-	query := "UPDATE orders SET status = $1 WHERE uuid = $2"
+	// При повторной активации срок жизни заказа отсчитывается заново, он снова может получить
+	// предупреждение об истечении, а данные назначения и доставки сбрасываются
+	query := `
+		UPDATE orders SET
+			status = $1,
+			activated_at = CASE WHEN $1 = 'active' AND status <> 'active' THEN now() ELSE activated_at END,
+			expiry_warned_at = CASE WHEN $1 = 'active' THEN NULL ELSE expiry_warned_at END,
+			assigned_driver_uuid = CASE WHEN $1 = 'active' THEN NULL ELSE assigned_driver_uuid END,
+			assigned_at = CASE WHEN $1 = 'active' THEN NULL ELSE assigned_at END,
+			delivered_at = CASE WHEN $1 = 'active' THEN NULL ELSE delivered_at END,
+			delivery_latitude = CASE WHEN $1 = 'active' THEN NULL ELSE delivery_latitude END,
+			delivery_longitude = CASE WHEN $1 = 'active' THEN NULL ELSE delivery_longitude END
+		WHERE uuid = $2
+	`
 
 	_, err := d.DB.Exec(query, status, orderUUID)
 	if err != nil {
@@ -389,131 +303,125 @@ func (d *Database) UpdateOrderStatus(orderUUID string, status string) error {
 	return nil
 }
 
+// SetOrderUrgent отмечает заказ как срочный или снимает отметку
+func (d *Database) SetOrderUrgent(orderUUID string, urgent bool) error {
+	query := "UPDATE orders SET is_urgent = $1 WHERE uuid = $2"
+
+	if _, err := d.DB.Exec(query, urgent, orderUUID); err != nil {
+		return fmt.Errorf("ошибка обновления срочности заказа: %v", err)
+	}
+	return nil
+}
+
+// UpdateOrder сохраняет редактируемые поля заказа
+func (d *Database) UpdateOrder(order *domain.Order) error {
+	query := `
+		UPDATE orders SET
+			title = $1,
+			description = $2,
+			weight_kg = $3,
+			length_cm = $4,
+			width_cm = $5,
+			height_cm = $6,
+			from_city_uuid = $7,
+			from_address = $8,
+			to_city_uuid = $9,
+			to_address = $10,
+			price = $11,
+			available_from = $12,
+			distance_km = $13,
+			cargo_type_uuid = $14,
+			temperature_min_c = $15,
+			temperature_max_c = $16,
+			adr_class = $17,
+			is_oversize = $18,
+			is_fragile = $19,
+			expiry_warned_at = NULL
+		WHERE uuid = $20
+	`
+
+	result, err := d.DB.Exec(query,
+		order.Title,
+		order.Description,
+		order.WeightKg,
+		order.LengthCm,
+		order.WidthCm,
+		order.HeightCm,
+		order.FromCityUUID,
+		order.FromAddress,
+		order.ToCityUUID,
+		order.ToAddress,
+		order.Price,
+		order.AvailableFrom,
+		order.DistanceKm,
+		order.CargoTypeUUID,
+		order.Requirements.TemperatureMinC,
+		order.Requirements.TemperatureMaxC,
+		order.Requirements.ADRClass,
+		order.Requirements.Oversize,
+		order.Requirements.Fragile,
+		order.UUID,
+	)
+	if err != nil {
+		return fmt.Errorf("ошибка обновления заказа: %v", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("ошибка обновления заказа: %v", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("заказ %s не найден", order.UUID)
+	}
+
+	return nil
+}
+
+// UpdateOrderDistance сохраняет расчетное расстояние маршрута заказа (NULL, если рассчитать нельзя)
+func (d *Database) UpdateOrderDistance(orderUUID string, distanceKm *float64) error {
+	_, err := d.DB.Exec("UPDATE orders SET distance_km = $1 WHERE uuid = $2", distanceKm, orderUUID)
+	if err != nil {
+		return fmt.Errorf("ошибка обновления расстояния заказа: %v", err)
+	}
+
+	return nil
+}
+
 // GetOrdersByWeightRange возвращает заказы в указанном диапазоне веса
 func (d *Database) GetOrdersByWeightRange(minWeight, maxWeight *float64) ([]domain.Order, error) {
 	var query string
 	var args []interface{}
 
-	// Базовый запрос
-	baseQuery := `
-		SELECT 
-			o.uuid,
-			o.customer_uuid,
-			o.title,
-			o.description,
-			o.weight_kg,
-			o.length_cm,
-			o.width_cm,
-			o.height_cm,
-			o.from_city_uuid,
-			o.from_address,
-			o.to_city_uuid,
-			o.to_address,
-			o.tags,
-			o.price,
-			o.available_from,
-			o.status,
-			o.created_at,
-			c.name as customer_name,
-			c.phone as customer_phone,
-			c.telegram_id as customer_telegram_id,
-			c.telegram_tag as customer_telegram_tag,
-			COALESCE(fc.name, '') as from_city_name,
-			COALESCE(tc.name, '') as to_city_name
-		FROM orders o
-		JOIN customers c ON o.customer_uuid = c.uuid
-		LEFT JOIN cities fc ON o.from_city_uuid = fc.uuid
-		LEFT JOIN cities tc ON o.to_city_uuid = tc.uuid
-	`
-
 	// Формируем WHERE условие в зависимости от переданных параметров
 	if minWeight != nil && maxWeight != nil {
 		// Оба параметра указаны
-		query = baseQuery + " WHERE o.weight_kg >= $1 AND o.weight_kg <= $2 ORDER BY o.created_at DESC"
+		query = orderSelectQuery + " WHERE o.weight_kg >= $1 AND o.weight_kg <= $2 ORDER BY o.created_at DESC"
 		args = []interface{}{*minWeight, *maxWeight}
 	} else if minWeight != nil {
 		// Только минимальный вес
-		query = baseQuery + " WHERE o.weight_kg >= $1 ORDER BY o.created_at DESC"
+		query = orderSelectQuery + " WHERE o.weight_kg >= $1 ORDER BY o.created_at DESC"
 		args = []interface{}{*minWeight}
 	} else if maxWeight != nil {
 		// Только максимальный вес
-		query = baseQuery + " WHERE o.weight_kg <= $1 ORDER BY o.created_at DESC"
+		query = orderSelectQuery + " WHERE o.weight_kg <= $1 ORDER BY o.created_at DESC"
 		args = []interface{}{*maxWeight}
 	} else {
 		// Ни один параметр не указан - возвращаем все заказы
-		query = baseQuery + " ORDER BY o.created_at DESC"
+		query = orderSelectQuery + " ORDER BY o.created_at DESC"
 		args = []interface{}{}
 	}
 
-	rows, err := d.DB.Query(query, args...)
-	if err != nil {
-		return nil, fmt.Errorf("ошибка выполнения запроса: %v", err)
-	}
-	defer rows.Close()
-
-	var orders []domain.Order
-	for rows.Next() {
-		var order domain.Order
-		var tags pq.StringArray
-		var fromCityName, toCityName string
-
-		err := rows.Scan(
-			&order.UUID,
-			&order.CustomerUUID,
-			&order.Title,
-			&order.Description,
-			&order.WeightKg,
-			&order.LengthCm,
-			&order.WidthCm,
-			&order.HeightCm,
-			&order.FromCityUUID,
-			&order.FromAddress,
-			&order.ToCityUUID,
-			&order.ToAddress,
-			&tags,
-			&order.Price,
-			&order.AvailableFrom,
-			&order.Status,
-			&order.CreatedAt,
-			&order.CustomerName,
-			&order.CustomerPhone,
-			&order.CustomerTelegramID,
-			&order.CustomerTelegramTag,
-			&fromCityName,
-			&toCityName,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("ошибка сканирования строки: %v", err)
-		}
-
-		order.Tags = []string(tags)
-
-		// Устанавливаем названия городов
-		if fromCityName != "" {
-			order.FromCityName = &fromCityName
-		}
-		if toCityName != "" {
-			order.ToCityName = &toCityName
-		}
-
-		orders = append(orders, order)
-	}
-
-	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("ошибка при итерации по строкам: %v", err)
-	}
-
-	return orders, nil
+	return d.queryOrders(query, args...)
 }
 
 // CreateCustomer создает нового заказчика в базе данных
 func (d *Database) CreateCustomer(customer *domain.Customer) error {
 	query := `
-		INSERT INTO customers (uuid, name, phone, telegram_id, telegram_tag, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO customers (uuid, name, phone, telegram_id, telegram_tag, is_active, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
 	`
 
-	_, err := d.DB.Exec(query, customer.UUID.String(), customer.Name, customer.Phone, customer.TelegramID, customer.TelegramTag, customer.CreatedAt)
+	_, err := d.DB.Exec(query, customer.UUID.String(), customer.Name, customer.Phone, customer.TelegramID, customer.TelegramTag, customer.IsActive, customer.CreatedAt)
 	if err != nil {
 		return fmt.Errorf("ошибка создания заказчика: %v", err)
 	}
@@ -521,29 +429,27 @@ func (d *Database) CreateCustomer(customer *domain.Customer) error {
 	return nil
 }
 
-// GetCustomerByPhone возвращает заказчика по номеру телефона
-func (d *Database) GetCustomerByPhone(phone string) (*domain.Customer, error) {
-	query := `
-		SELECT uuid, name, phone, telegram_id, telegram_tag, created_at
+// customerSelectQuery содержит общую часть запроса заказчиков
+const customerSelectQuery = `
+		SELECT uuid, name, phone, telegram_id, telegram_tag, is_active, created_at
 		FROM customers
-		WHERE phone = $1
 	`
 
+// scanCustomer сканирует одну строку результата customerSelectQuery
+func scanCustomer(row rowScanner) (*domain.Customer, error) {
 	var customer domain.Customer
 	var uuidStr string
-	err := d.DB.QueryRow(query, phone).Scan(
+	err := row.Scan(
 		&uuidStr,
 		&customer.Name,
 		&customer.Phone,
 		&customer.TelegramID,
 		&customer.TelegramTag,
+		&customer.IsActive,
 		&customer.CreatedAt,
 	)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, nil // Заказчик не найден
-		}
-		return nil, fmt.Errorf("ошибка получения заказчика по телефону: %v", err)
+		return nil, err
 	}
 
 	// Парсим UUID из строки
@@ -556,24 +462,22 @@ func (d *Database) GetCustomerByPhone(phone string) (*domain.Customer, error) {
 	return &customer, nil
 }
 
+// GetCustomerByPhone возвращает заказчика по номеру телефона
+func (d *Database) GetCustomerByPhone(phone string) (*domain.Customer, error) {
+	customer, err := scanCustomer(d.DB.QueryRow(customerSelectQuery+" WHERE phone = $1", phone))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // Заказчик не найден
+		}
+		return nil, fmt.Errorf("ошибка получения заказчика по телефону: %v", err)
+	}
+
+	return customer, nil
+}
+
 // GetCustomerByTelegramID возвращает заказчика по Telegram ID
 func (d *Database) GetCustomerByTelegramID(telegramID int64) (*domain.Customer, error) {
-	query := `
-		SELECT uuid, name, phone, telegram_id, telegram_tag, created_at
-		FROM customers
-		WHERE telegram_id = $1
-	`
-
-	var customer domain.Customer
-	var uuidStr string
-	err := d.DB.QueryRow(query, telegramID).Scan(
-		&uuidStr,
-		&customer.Name,
-		&customer.Phone,
-		&customer.TelegramID,
-		&customer.TelegramTag,
-		&customer.CreatedAt,
-	)
+	customer, err := scanCustomer(d.DB.QueryRow(customerSelectQuery+" WHERE telegram_id = $1", telegramID))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil // Заказчик не найден
@@ -581,25 +485,25 @@ func (d *Database) GetCustomerByTelegramID(telegramID int64) (*domain.Customer,
 		return nil, fmt.Errorf("ошибка получения заказчика по Telegram ID: %v", err)
 	}
 
-	// Парсим UUID из строки
-	customerUUID, err := uuid.Parse(uuidStr)
+	return customer, nil
+}
+
+// GetCustomerByUUID возвращает заказчика по UUID
+func (d *Database) GetCustomerByUUID(customerUUID uuid.UUID) (*domain.Customer, error) {
+	customer, err := scanCustomer(d.DB.QueryRow(customerSelectQuery+" WHERE uuid = $1", customerUUID))
 	if err != nil {
-		return nil, fmt.Errorf("ошибка парсинга UUID: %v", err)
+		if err == sql.ErrNoRows {
+			return nil, nil // Заказчик не найден
+		}
+		return nil, fmt.Errorf("ошибка получения заказчика по UUID: %v", err)
 	}
-	customer.UUID = customerUUID
 
-	return &customer, nil
+	return customer, nil
 }
 
 // GetAllCustomers возвращает всех заказчиков
 func (d *Database) GetAllCustomers() ([]domain.Customer, error) {
-	query := `
-		SELECT uuid, name, phone, telegram_id, telegram_tag, created_at
-		FROM customers
-		ORDER BY created_at DESC
-	`
-
-	rows, err := d.DB.Query(query)
+	rows, err := d.DB.Query(customerSelectQuery + " ORDER BY created_at DESC")
 	if err != nil {
 		return nil, fmt.Errorf("ошибка выполнения запроса: %v", err)
 	}
@@ -607,28 +511,11 @@ func (d *Database) GetAllCustomers() ([]domain.Customer, error) {
 
 	var customers []domain.Customer
 	for rows.Next() {
-		var customer domain.Customer
-		var uuidStr string
-		err := rows.Scan(
-			&uuidStr,
-			&customer.Name,
-			&customer.Phone,
-			&customer.TelegramID,
-			&customer.TelegramTag,
-			&customer.CreatedAt,
-		)
+		customer, err := scanCustomer(rows)
 		if err != nil {
 			return nil, fmt.Errorf("ошибка сканирования строки: %v", err)
 		}
-
-		// Парсим UUID из строки
-		customerUUID, err := uuid.Parse(uuidStr)
-		if err != nil {
-			return nil, fmt.Errorf("ошибка парсинга UUID: %v", err)
-		}
-		customer.UUID = customerUUID
-
-		customers = append(customers, customer)
+		customers = append(customers, *customer)
 	}
 
 	if err = rows.Err(); err != nil {
@@ -638,17 +525,130 @@ func (d *Database) GetAllCustomers() ([]domain.Customer, error) {
 	return customers, nil
 }
 
+// UpdateCustomer сохраняет имя, телефон и Telegram данные заказчика
+func (d *Database) UpdateCustomer(customer *domain.Customer) error {
+	query := `
+		UPDATE customers
+		SET name = $1, phone = $2, telegram_id = $3, telegram_tag = $4
+		WHERE uuid = $5
+	`
+
+	_, err := d.DB.Exec(query, customer.Name, customer.Phone, customer.TelegramID, customer.TelegramTag, customer.UUID)
+	if err != nil {
+		return fmt.Errorf("ошибка обновления заказчика: %v", err)
+	}
+
+	return nil
+}
+
+// SetCustomerActive активирует или деактивирует заказчика
+func (d *Database) SetCustomerActive(customerUUID uuid.UUID, active bool) error {
+	query := "UPDATE customers SET is_active = $1 WHERE uuid = $2"
+
+	_, err := d.DB.Exec(query, active, customerUUID)
+	if err != nil {
+		return fmt.Errorf("ошибка изменения статуса заказчика: %v", err)
+	}
+
+	return nil
+}
+
+// MergeCustomers переносит заказы и оценки водителей дубликата на основного заказчика, дополняет
+// недостающие Telegram данные и удаляет дубликат в одной транзакции.
+// Возвращает количество перенесенных заказов.
+func (d *Database) MergeCustomers(duplicateUUID, targetUUID uuid.UUID) (int64, error) {
+	tx, err := d.DB.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("ошибка начала транзакции: %v", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("UPDATE orders SET customer_uuid = $1 WHERE customer_uuid = $2", targetUUID, duplicateUUID)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка переноса заказов: %v", err)
+	}
+	moved, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("ошибка переноса заказов: %v", err)
+	}
+
+	// Оценки водителей остаются за основным заказчиком, иначе удаление дубликата их потеряет
+	if _, err := tx.Exec("UPDATE driver_ratings SET customer_uuid = $1 WHERE customer_uuid = $2", targetUUID, duplicateUUID); err != nil {
+		return 0, fmt.Errorf("ошибка переноса оценок водителей: %v", err)
+	}
+
+	// Сначала читаем Telegram данные дубликата, затем удаляем его: telegram_id может быть уникальным
+	var telegramID sql.NullInt64
+	var telegramTag sql.NullString
+	err = tx.QueryRow("SELECT telegram_id, telegram_tag FROM customers WHERE uuid = $1", duplicateUUID).Scan(&telegramID, &telegramTag)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка получения дубликата заказчика: %v", err)
+	}
+
+	if _, err := tx.Exec("DELETE FROM customers WHERE uuid = $1", duplicateUUID); err != nil {
+		return 0, fmt.Errorf("ошибка удаления дубликата заказчика: %v", err)
+	}
+
+	query := `
+		UPDATE customers
+		SET telegram_id = COALESCE(telegram_id, $1),
+		    telegram_tag = COALESCE(telegram_tag, $2)
+		WHERE uuid = $3
+	`
+	if _, err := tx.Exec(query, telegramID, telegramTag, targetUUID); err != nil {
+		return 0, fmt.Errorf("ошибка обновления основного заказчика: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("ошибка фиксации транзакции: %v", err)
+	}
+
+	return moved, nil
+}
+
+// GetOrdersByCustomer возвращает все заказы заказчика, начиная с новых
+func (d *Database) GetOrdersByCustomer(customerUUID uuid.UUID) ([]domain.Order, error) {
+	return d.queryOrders(orderSelectQuery+" WHERE o.customer_uuid = $1 ORDER BY o.created_at DESC", customerUUID)
+}
+
 // CreateOrder создает новый заказ в базе данных
 func (d *Database) CreateOrder(order *domain.Order) error {
+	return insertOrder(d.DB, order)
+}
+
+// CreateOrders сохраняет несколько заказов в одной транзакции: либо все, либо ни одного
+func (d *Database) CreateOrders(orders []*domain.Order) error {
+	tx, err := d.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("ошибка начала транзакции: %v", err)
+	}
+	defer tx.Rollback()
+
+	for _, order := range orders {
+		if err := insertOrder(tx, order); err != nil {
+			return fmt.Errorf("заказ «%s»: %v", order.Title, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("ошибка сохранения заказов: %v", err)
+	}
+	return nil
+}
+
+// insertOrder добавляет заказ через соединение или транзакцию
+func insertOrder(exec sqlExecer, order *domain.Order) error {
 	query := `
 		INSERT INTO orders (
 			uuid, customer_uuid, title, description, weight_kg, 
 			length_cm, width_cm, height_cm, from_city_uuid, from_address, 
-			to_city_uuid, to_address, tags, price, available_from, status, created_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
+			to_city_uuid, to_address, tags, price, available_from, status, created_at, distance_km,
+			cargo_type_uuid, temperature_min_c, temperature_max_c, adr_class, is_oversize, is_fragile, is_urgent
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18,
+			$19, $20, $21, $22, $23, $24, $25)
 	`
 
-	_, err := d.DB.Exec(query,
+	_, err := exec.Exec(query,
 		order.UUID,
 		order.CustomerUUID,
 		order.Title,
@@ -666,6 +666,14 @@ func (d *Database) CreateOrder(order *domain.Order) error {
 		order.AvailableFrom,
 		order.Status,
 		order.CreatedAt,
+		order.DistanceKm,
+		order.CargoTypeUUID,
+		order.Requirements.TemperatureMinC,
+		order.Requirements.TemperatureMaxC,
+		order.Requirements.ADRClass,
+		order.Requirements.Oversize,
+		order.Requirements.Fragile,
+		order.Urgent,
 	)
 	if err != nil {
 		return fmt.Errorf("ошибка создания заказа: %v", err)
@@ -687,24 +695,140 @@ func (d *Database) GetDriversCount() (int, error) {
 	return count, nil
 }
 
-// GetAllDrivers возвращает всех водителей с информацией о городах
-func (d *Database) GetAllDrivers() ([]domain.Driver, error) {
-	query := `
+// driverSelectQuery содержит общую часть запроса водителей с информацией о городах
+const driverSelectQuery = `
 		SELECT 
 			d.uuid,
 			d.name,
 			d.telegram_id,
 			d.telegram_tag,
+			d.phone,
 			d.notification_enabled,
 			d.city_uuid,
 			d.created_at,
-			c.name as city_name
+			c.name as city_name,
+			c.region as city_region,
+			d.pickup_radius_km,
+			d.delivery_radius_km,
+			d.timezone,
+			d.digest_hour,
+			d.last_digest_at,
+			d.notification_mode,
+			d.quiet_hours_start,
+			d.quiet_hours_end,
+			d.source,
+			d.onboarding_step,
+			d.terms_accepted_at,
+			v.uuid,
+			v.body_type,
+			v.payload_kg,
+			v.volume_m3,
+			v.length_cm,
+			v.width_cm,
+			v.height_cm,
+			v.adr,
+			v.updated_at,
+			(SELECT AVG(r.score) FROM driver_ratings r WHERE r.driver_uuid = d.uuid) as rating_avg,
+			(SELECT COUNT(*) FROM driver_ratings r WHERE r.driver_uuid = d.uuid) as rating_count
 		FROM drivers d
 		LEFT JOIN cities c ON d.city_uuid = c.uuid
-		ORDER BY d.created_at DESC
+		LEFT JOIN vehicles v ON v.driver_uuid = d.uuid
 	`
 
-	rows, err := d.DB.Query(query)
+// scanDriver сканирует одну строку результата driverSelectQuery
+func scanDriver(row rowScanner) (*domain.Driver, error) {
+	var driver domain.Driver
+	var uuidStr string
+	var cityUUIDStr sql.NullString
+	var pickupRadius, deliveryRadius, digestHour, quietStart, quietEnd sql.NullInt64
+	var cityRegion sql.NullString
+	var vehicle nullableVehicle
+	err := row.Scan(
+		&uuidStr,
+		&driver.Name,
+		&driver.TelegramID,
+		&driver.TelegramTag,
+		&driver.Phone,
+		&driver.NotificationEnabled,
+		&cityUUIDStr,
+		&driver.CreatedAt,
+		&driver.CityName,
+		&cityRegion,
+		&pickupRadius,
+		&deliveryRadius,
+		&driver.Timezone,
+		&digestHour,
+		&driver.LastDigestAt,
+		&driver.NotificationMode,
+		&quietStart,
+		&quietEnd,
+		&driver.Source,
+		&driver.OnboardingStep,
+		&driver.TermsAcceptedAt,
+		&vehicle.uuid,
+		&vehicle.bodyType,
+		&vehicle.payloadKg,
+		&vehicle.volumeM3,
+		&vehicle.lengthCm,
+		&vehicle.widthCm,
+		&vehicle.heightCm,
+		&vehicle.adr,
+		&vehicle.updatedAt,
+		&driver.RatingAvg,
+		&driver.RatingCount,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if pickupRadius.Valid {
+		radius := int(pickupRadius.Int64)
+		driver.PickupRadiusKm = &radius
+	}
+	if deliveryRadius.Valid {
+		radius := int(deliveryRadius.Int64)
+		driver.DeliveryRadiusKm = &radius
+	}
+	if digestHour.Valid {
+		hour := int(digestHour.Int64)
+		driver.DigestHour = &hour
+	}
+	// Часовой пояс города определяется по региону и используется, пока водитель не выбрал свой
+	if zone := geo.RegionTimezone(cityRegion.String); zone != "" {
+		driver.CityTimezone = &zone
+	}
+	if quietStart.Valid && quietEnd.Valid {
+		start, end := int(quietStart.Int64), int(quietEnd.Int64)
+		driver.QuietHoursStart, driver.QuietHoursEnd = &start, &end
+	}
+
+	// Парсим UUID из строки
+	driverUUID, err := uuid.Parse(uuidStr)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка парсинга UUID водителя: %v", err)
+	}
+	driver.UUID = driverUUID
+
+	// Обрабатываем city_uuid (может быть NULL)
+	if cityUUIDStr.Valid && cityUUIDStr.String != "" {
+		cityUUID, err := uuid.Parse(cityUUIDStr.String)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка парсинга UUID города: %v", err)
+		}
+		driver.CityUUID = &cityUUID
+	}
+
+	// Транспорт (LEFT JOIN, может отсутствовать)
+	if driver.Vehicle, err = vehicle.toDomain(driver.UUID); err != nil {
+		return nil, err
+	}
+
+	return &driver, nil
+}
+
+// queryDrivers выполняет запрос водителей и сканирует все строки
+func (d *Database) queryDrivers(query string, args ...interface{}) ([]domain.Driver, error) {
+	rows, err := d.DB.Query(query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("ошибка выполнения запроса: %v", err)
 	}
@@ -712,42 +836,11 @@ func (d *Database) GetAllDrivers() ([]domain.Driver, error) {
 
 	var drivers []domain.Driver
 	for rows.Next() {
-		var driver domain.Driver
-		var uuidStr string
-		var cityUUIDStr sql.NullString
-		err := rows.Scan(
-			&uuidStr,
-			&driver.Name,
-			&driver.TelegramID,
-			&driver.TelegramTag,
-			&driver.NotificationEnabled,
-			&cityUUIDStr,
-			&driver.CreatedAt,
-			&driver.CityName,
-		)
+		driver, err := scanDriver(rows)
 		if err != nil {
 			return nil, fmt.Errorf("ошибка сканирования строки: %v", err)
 		}
-
-		// Парсим UUID из строки
-		driverUUID, err := uuid.Parse(uuidStr)
-		if err != nil {
-			return nil, fmt.Errorf("ошибка парсинга UUID водителя: %v", err)
-		}
-		driver.UUID = driverUUID
-
-		// Обрабатываем city_uuid (может быть NULL)
-		if cityUUIDStr.Valid && cityUUIDStr.String != "" {
-			cityUUID, err := uuid.Parse(cityUUIDStr.String)
-			if err != nil {
-				return nil, fmt.Errorf("ошибка парсинга UUID города: %v", err)
-			}
-			driver.CityUUID = &cityUUID
-		} else {
-			driver.CityUUID = nil
-		}
-
-		drivers = append(drivers, driver)
+		drivers = append(drivers, *driver)
 	}
 
 	if err = rows.Err(); err != nil {
@@ -757,36 +850,14 @@ func (d *Database) GetAllDrivers() ([]domain.Driver, error) {
 	return drivers, nil
 }
 
+// GetAllDrivers возвращает всех водителей с информацией о городах
+func (d *Database) GetAllDrivers() ([]domain.Driver, error) {
+	return d.queryDrivers(driverSelectQuery + " ORDER BY d.created_at DESC")
+}
+
 // GetDriverByTelegramID возвращает водителя по Telegram ID
 func (d *Database) GetDriverByTelegramID(telegramID int64) (*domain.Driver, error) {
-	query := `
-		SELECT 
-			d.uuid,
-			d.name,
-			d.telegram_id,
-			d.telegram_tag,
-			d.notification_enabled,
-			d.city_uuid,
-			d.created_at,
-			c.name as city_name
-		FROM drivers d
-		LEFT JOIN cities c ON d.city_uuid = c.uuid
-		WHERE d.telegram_id = $1
-	`
-
-	var driver domain.Driver
-	var uuidStr string
-	var cityUUIDStr sql.NullString
-	err := d.DB.QueryRow(query, telegramID).Scan(
-		&uuidStr,
-		&driver.Name,
-		&driver.TelegramID,
-		&driver.TelegramTag,
-		&driver.NotificationEnabled,
-		&cityUUIDStr,
-		&driver.CreatedAt,
-		&driver.CityName,
-	)
+	driver, err := scanDriver(d.DB.QueryRow(driverSelectQuery+" WHERE d.telegram_id = $1", telegramID))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -794,29 +865,28 @@ func (d *Database) GetDriverByTelegramID(telegramID int64) (*domain.Driver, erro
 		return nil, fmt.Errorf("ошибка получения водителя по Telegram ID: %v", err)
 	}
 
-	parsedUUID, err := uuid.Parse(uuidStr)
-	if err != nil {
-		return nil, fmt.Errorf("ошибка парсинга UUID водителя: %v", err)
-	}
-	driver.UUID = parsedUUID
+	return driver, nil
+}
 
-	if cityUUIDStr.Valid && cityUUIDStr.String != "" {
-		parsedCityUUID, err := uuid.Parse(cityUUIDStr.String)
-		if err != nil {
-			return nil, fmt.Errorf("ошибка парсинга UUID города: %v", err)
+// GetDriverByUUID возвращает водителя по UUID
+func (d *Database) GetDriverByUUID(driverUUID string) (*domain.Driver, error) {
+	driver, err := scanDriver(d.DB.QueryRow(driverSelectQuery+" WHERE d.uuid = $1", driverUUID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
 		}
-		driver.CityUUID = &parsedCityUUID
+		return nil, fmt.Errorf("ошибка получения водителя по UUID: %v", err)
 	}
 
-	return &driver, nil
+	return driver, nil
 }
 
 // CreateDriver создает нового водителя в базе данных
 func (d *Database) CreateDriver(driver *domain.Driver) error {
 	query := `
 		INSERT INTO drivers (
-			uuid, name, telegram_id, telegram_tag, notification_enabled, city_uuid, created_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7)
+			uuid, name, telegram_id, telegram_tag, notification_enabled, notification_mode, city_uuid, source, onboarding_step, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 	`
 
 	_, err := d.DB.Exec(
@@ -826,7 +896,10 @@ func (d *Database) CreateDriver(driver *domain.Driver) error {
 		driver.TelegramID,
 		driver.TelegramTag,
 		driver.NotificationEnabled,
+		driver.NotificationMode,
 		driver.CityUUID,
+		driver.Source,
+		driver.OnboardingStep,
 		driver.CreatedAt,
 	)
 	if err != nil {
@@ -835,62 +908,6 @@ func (d *Database) CreateDriver(driver *domain.Driver) error {
 	return nil
 }
 
-// GetCityByName возвращает город по названию
-func (d *Database) GetCityByName(cityName string) (*domain.City, error) {
-	query := `
-		SELECT uuid, name
-		FROM cities
-		WHERE name = $1
-	`
-
-	var city domain.City
-	var uuidStr string
-	err := d.DB.QueryRow(query, cityName).Scan(&uuidStr, &city.Name)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, nil // Город не найден
-		}
-		return nil, fmt.Errorf("ошибка получения города по названию: %v", err)
-	}
-
-	// Парсим UUID из строки
-	cityUUID, err := uuid.Parse(uuidStr)
-	if err != nil {
-		return nil, fmt.Errorf("ошибка парсинга UUID города: %v", err)
-	}
-	city.UUID = cityUUID
-
-	return &city, nil
-}
-
-// GetCityByUUID возвращает город по UUID
-func (d *Database) GetCityByUUID(cityUUID uuid.UUID) (*domain.City, error) {
-	query := `
-		SELECT uuid, name
-		FROM cities
-		WHERE uuid = $1
-	`
-
-	var city domain.City
-	var uuidStr string
-	err := d.DB.QueryRow(query, cityUUID).Scan(&uuidStr, &city.Name)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, nil // Город не найден
-		}
-		return nil, fmt.Errorf("ошибка получения города по UUID: %v", err)
-	}
-
-	// Парсим UUID из строки
-	cityUUIDParsed, err := uuid.Parse(uuidStr)
-	if err != nil {
-		return nil, fmt.Errorf("ошибка парсинга UUID города: %v", err)
-	}
-	city.UUID = cityUUIDParsed
-
-	return &city, nil
-}
-
 // UpdateDriverCity обновляет город водителя
 func (d *Database) UpdateDriverCity(driverUUID uuid.UUID, cityUUID *uuid.UUID) error {
 	var query string
@@ -957,27 +974,32 @@ func (d *Database) UpdateDriverCityAndNotifications(driverUUID uuid.UUID, cityUU
 	return nil
 }
 
-// UpdateDriverIdentity обновляет имя и тег Telegram водителя
-func (d *Database) UpdateDriverIdentity(driverUUID uuid.UUID, name string, telegramTag *string) error {
-	query := "UPDATE drivers SET name = $1, telegram_tag = $2 WHERE uuid = $3"
-	_, err := d.DB.Exec(query, name, telegramTag, driverUUID)
+// UpdateDriverRadius обновляет радиусы поиска погрузки и выгрузки водителя (NULL отключает фильтр)
+func (d *Database) UpdateDriverRadius(driverUUID uuid.UUID, pickupRadiusKm, deliveryRadiusKm *int) error {
+	query := "UPDATE drivers SET pickup_radius_km = $1, delivery_radius_km = $2 WHERE uuid = $3"
+
+	result, err := d.DB.Exec(query, pickupRadiusKm, deliveryRadiusKm, driverUUID)
 	if err != nil {
-		return fmt.Errorf("ошибка обновления имени/тега водителя: %v", err)
+		return fmt.Errorf("ошибка обновления радиусов водителя: %v", err)
 	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("ошибка получения количества обновленных строк: %v", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("водитель с UUID %s не найден", driverUUID)
+	}
+
 	return nil
 }
 
-// CreateCity создает новый город в базе данных
-func (d *Database) CreateCity(city *domain.City) error {
-	query := `
-		INSERT INTO cities (uuid, name)
-		VALUES ($1, $2)
-	`
-
-	_, err := d.DB.Exec(query, city.UUID, city.Name)
+// UpdateDriverIdentity обновляет имя и тег Telegram водителя
+func (d *Database) UpdateDriverIdentity(driverUUID uuid.UUID, name string, telegramTag *string) error {
+	query := "UPDATE drivers SET name = $1, telegram_tag = $2 WHERE uuid = $3"
+	_, err := d.DB.Exec(query, name, telegramTag, driverUUID)
 	if err != nil {
-		return fmt.Errorf("ошибка создания города: %v", err)
+		return fmt.Errorf("ошибка обновления имени/тега водителя: %v", err)
 	}
-
 	return nil
 }
@@ -0,0 +1,90 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"dalnoboy/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// DeferNotification откладывает уведомление водителя о заказе до DeliverAt. Повторные изменения
+// заказа дописываются к уже отложенному уведомлению, время отправки берется из последнего вызова.
+func (d *Database) DeferNotification(notification *domain.DeferredNotification) error {
+	query := `
+		INSERT INTO deferred_notifications (order_uuid, driver_uuid, kind, changes, deliver_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (order_uuid, driver_uuid) DO UPDATE
+		SET changes = deferred_notifications.changes || EXCLUDED.changes,
+		    deliver_at = EXCLUDED.deliver_at
+	`
+
+	changes := notification.Changes
+	if changes == nil {
+		changes = []string{}
+	}
+
+	_, err := d.DB.Exec(query, notification.OrderUUID, notification.DriverUUID, notification.Kind,
+		pq.Array(changes), notification.DeliverAt)
+	if err != nil {
+		return fmt.Errorf("ошибка сохранения отложенного уведомления: %v", err)
+	}
+	return nil
+}
+
+// GetDueDeferredNotifications возвращает отложенные уведомления, время которых наступило к now
+func (d *Database) GetDueDeferredNotifications(now time.Time) ([]domain.DeferredNotification, error) {
+	return d.queryDeferredNotifications(`
+		SELECT order_uuid, driver_uuid, kind, changes, deliver_at
+		FROM deferred_notifications
+		WHERE deliver_at <= $1
+		ORDER BY deliver_at, created_at
+	`, now)
+}
+
+// GetDeferredNotificationsForOrder возвращает все отложенные уведомления о заказе
+func (d *Database) GetDeferredNotificationsForOrder(orderUUID string) ([]domain.DeferredNotification, error) {
+	return d.queryDeferredNotifications(`
+		SELECT order_uuid, driver_uuid, kind, changes, deliver_at
+		FROM deferred_notifications
+		WHERE order_uuid = $1
+		ORDER BY created_at
+	`, orderUUID)
+}
+
+// DeleteDeferredNotification удаляет отправленное или неактуальное отложенное уведомление
+func (d *Database) DeleteDeferredNotification(orderUUID string, driverUUID uuid.UUID) error {
+	query := "DELETE FROM deferred_notifications WHERE order_uuid = $1 AND driver_uuid = $2"
+
+	if _, err := d.DB.Exec(query, orderUUID, driverUUID); err != nil {
+		return fmt.Errorf("ошибка удаления отложенного уведомления: %v", err)
+	}
+	return nil
+}
+
+// queryDeferredNotifications выполняет запрос и сканирует отложенные уведомления
+func (d *Database) queryDeferredNotifications(query string, args ...interface{}) ([]domain.DeferredNotification, error) {
+	rows, err := d.DB.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения отложенных уведомлений: %v", err)
+	}
+	defer rows.Close()
+
+	var notifications []domain.DeferredNotification
+	for rows.Next() {
+		var notification domain.DeferredNotification
+		var changes pq.StringArray
+		if err := rows.Scan(&notification.OrderUUID, &notification.DriverUUID, &notification.Kind, &changes,
+			&notification.DeliverAt); err != nil {
+			return nil, fmt.Errorf("ошибка сканирования отложенного уведомления: %v", err)
+		}
+		notification.Changes = []string(changes)
+		notifications = append(notifications, notification)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка чтения отложенных уведомлений: %v", err)
+	}
+	return notifications, nil
+}
@@ -0,0 +1,100 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"dalnoboy/internal/domain"
+
+	"github.com/lib/pq"
+)
+
+// AssignOrder назначает активный заказ водителю. Возвращает false, если заказ
+// уже не активен (назначен другому водителю, снят или архивирован).
+func (d *Database) AssignOrder(orderUUID, driverUUID string) (bool, error) {
+	query := `
+		UPDATE orders SET status = $1, assigned_driver_uuid = $2, assigned_at = now()
+		WHERE uuid = $3 AND status = 'active'
+		RETURNING uuid
+	`
+
+	updated, err := d.updateOrdersReturning(query, domain.OrderStatusAssigned, driverUUID, orderUUID)
+	if err != nil {
+		return false, fmt.Errorf("ошибка назначения заказа: %v", err)
+	}
+	return len(updated) > 0, nil
+}
+
+// UnassignOrder снимает назначение и возвращает заказ в активные.
+// Возвращает false, если заказ не был назначен.
+func (d *Database) UnassignOrder(orderUUID string) (bool, error) {
+	query := `
+		UPDATE orders SET status = $1, assigned_driver_uuid = NULL, assigned_at = NULL
+		WHERE uuid = $2 AND status = 'assigned'
+		RETURNING uuid
+	`
+
+	updated, err := d.updateOrdersReturning(query, domain.OrderStatusActive, orderUUID)
+	if err != nil {
+		return false, fmt.Errorf("ошибка снятия назначения заказа: %v", err)
+	}
+	return len(updated) > 0, nil
+}
+
+// MarkOrderDelivered переводит назначенный водителю заказ в статус delivered и сохраняет
+// координаты подтверждения. Возвращает false, если заказ не назначен этому водителю.
+func (d *Database) MarkOrderDelivered(orderUUID, driverUUID string, latitude, longitude *float64) (bool, error) {
+	query := `
+		UPDATE orders SET status = $1, delivered_at = now(), delivery_latitude = $2, delivery_longitude = $3
+		WHERE uuid = $4 AND status = 'assigned' AND assigned_driver_uuid = $5
+		RETURNING uuid
+	`
+
+	updated, err := d.updateOrdersReturning(query, domain.OrderStatusDelivered, latitude, longitude, orderUUID, driverUUID)
+	if err != nil {
+		return false, fmt.Errorf("ошибка подтверждения доставки: %v", err)
+	}
+	return len(updated) > 0, nil
+}
+
+// GetOrdersByAssignedDriver возвращает заказы водителя в пути
+func (d *Database) GetOrdersByAssignedDriver(driverUUID string) ([]domain.Order, error) {
+	return d.queryOrders(orderSelectQuery+" WHERE o.assigned_driver_uuid = $1 AND o.status = 'assigned' ORDER BY o.assigned_at", driverUUID)
+}
+
+// GetDeliveredOrders возвращает заказы, доставленные в интервале [from, to)
+func (d *Database) GetDeliveredOrders(from, to time.Time) ([]domain.Order, error) {
+	return d.queryOrders(orderSelectQuery+" WHERE o.status = 'delivered' AND o.delivered_at >= $1 AND o.delivered_at < $2 ORDER BY o.delivered_at", from, to)
+}
+
+// GetPODAttachmentCounts возвращает количество вложений подтверждения доставки по заказам
+func (d *Database) GetPODAttachmentCounts(orderUUIDs []string) (map[string]int, error) {
+	query := `
+		SELECT order_uuid, COUNT(*)
+		FROM order_attachments
+		WHERE order_uuid = ANY($1::uuid[]) AND purpose = $2
+		GROUP BY order_uuid
+	`
+
+	rows, err := d.DB.Query(query, pq.Array(orderUUIDs), domain.AttachmentPurposePOD)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка выполнения запроса: %v", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var orderUUID string
+		var count int
+		if err := rows.Scan(&orderUUID, &count); err != nil {
+			return nil, fmt.Errorf("ошибка сканирования строки: %v", err)
+		}
+		counts[orderUUID] = count
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка при итерации по строкам: %v", err)
+	}
+
+	return counts, nil
+}
@@ -0,0 +1,79 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UpdateDriverPhone сохраняет телефон водителя и следующий шаг регистрации
+func (d *Database) UpdateDriverPhone(driverUUID uuid.UUID, phone string, nextStep *string) error {
+	query := "UPDATE drivers SET phone = $1, onboarding_step = $2 WHERE uuid = $3"
+
+	if _, err := d.DB.Exec(query, phone, nextStep, driverUUID); err != nil {
+		return fmt.Errorf("ошибка обновления телефона водителя: %v", err)
+	}
+	return nil
+}
+
+// UpdateDriverOnboardingStep переводит водителя на шаг регистрации; nil завершает регистрацию
+func (d *Database) UpdateDriverOnboardingStep(driverUUID uuid.UUID, step *string) error {
+	query := "UPDATE drivers SET onboarding_step = $1 WHERE uuid = $2"
+
+	if _, err := d.DB.Exec(query, step, driverUUID); err != nil {
+		return fmt.Errorf("ошибка обновления шага регистрации водителя: %v", err)
+	}
+	return nil
+}
+
+// CompleteDriverOnboarding отмечает принятие условий, завершает регистрацию и включает уведомления
+func (d *Database) CompleteDriverOnboarding(driverUUID uuid.UUID, acceptedAt time.Time) error {
+	query := `
+		UPDATE drivers
+		SET terms_accepted_at = $1, onboarding_step = NULL, notification_enabled = true
+		WHERE uuid = $2
+	`
+
+	if _, err := d.DB.Exec(query, acceptedAt, driverUUID); err != nil {
+		return fmt.Errorf("ошибка завершения регистрации водителя: %v", err)
+	}
+	return nil
+}
+
+// GetPopularCityNames возвращает города, которые чаще всего встречаются в маршрутах заказов,
+// затем остальные города по алфавиту
+func (d *Database) GetPopularCityNames(limit int) ([]string, error) {
+	query := `
+		WITH route_cities AS (
+			SELECT from_city_uuid AS city_uuid FROM orders WHERE from_city_uuid IS NOT NULL
+			UNION ALL
+			SELECT to_city_uuid FROM orders WHERE to_city_uuid IS NOT NULL
+		)
+		SELECT c.name
+		FROM cities c
+		LEFT JOIN route_cities r ON r.city_uuid = c.uuid
+		GROUP BY c.uuid, c.name
+		ORDER BY COUNT(r.city_uuid) DESC, c.name
+		LIMIT $1
+	`
+
+	rows, err := d.DB.Query(query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения популярных городов: %v", err)
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("ошибка сканирования города: %v", err)
+		}
+		names = append(names, name)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка чтения популярных городов: %v", err)
+	}
+	return names, nil
+}
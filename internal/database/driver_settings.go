@@ -0,0 +1,46 @@
+package database
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// UpdateDriverNotificationMode задает частоту уведомлений о новых заказах и час ежедневной сводки (nil — выключена)
+func (d *Database) UpdateDriverNotificationMode(driverUUID uuid.UUID, mode string, digestHour *int) error {
+	query := "UPDATE drivers SET notification_mode = $1, digest_hour = $2 WHERE uuid = $3"
+
+	if _, err := d.DB.Exec(query, mode, digestHour, driverUUID); err != nil {
+		return fmt.Errorf("ошибка обновления частоты уведомлений водителя: %v", err)
+	}
+	return nil
+}
+
+// UpdateDriverQuietHours задает тихие часы водителя; nil убирает их
+func (d *Database) UpdateDriverQuietHours(driverUUID uuid.UUID, start, end *int) error {
+	query := "UPDATE drivers SET quiet_hours_start = $1, quiet_hours_end = $2 WHERE uuid = $3"
+
+	if _, err := d.DB.Exec(query, start, end, driverUUID); err != nil {
+		return fmt.Errorf("ошибка обновления тихих часов водителя: %v", err)
+	}
+	return nil
+}
+
+// UpdateDriverTimezone задает часовой пояс водителя; nil — пояс определяется по городу
+func (d *Database) UpdateDriverTimezone(driverUUID uuid.UUID, timezone *string) error {
+	query := "UPDATE drivers SET timezone = $1 WHERE uuid = $2"
+
+	if _, err := d.DB.Exec(query, timezone, driverUUID); err != nil {
+		return fmt.Errorf("ошибка обновления часового пояса водителя: %v", err)
+	}
+	return nil
+}
+
+// DeleteDriver удаляет водителя вместе с транспортом, поисками, предложениями, перепиской и оценками.
+// В выполненных заказах водитель перестает быть указан.
+func (d *Database) DeleteDriver(driverUUID uuid.UUID) error {
+	if _, err := d.DB.Exec("DELETE FROM drivers WHERE uuid = $1", driverUUID); err != nil {
+		return fmt.Errorf("ошибка удаления водителя: %v", err)
+	}
+	return nil
+}
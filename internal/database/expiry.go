@@ -0,0 +1,76 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"dalnoboy/internal/domain"
+
+	"github.com/lib/pq"
+)
+
+// GetActiveOrdersForExpiry возвращает активные заказы с датой погрузки раньше availableBefore
+// или активные с момента раньше createdBefore. Начало активности — создание или повторная активация;
+// повторно активированный заказ с прошедшей датой снимается не раньше availableBefore от активации.
+// При onlyUnwarned пропускает заказы, о которых уже предупреждали.
+func (d *Database) GetActiveOrdersForExpiry(availableBefore, createdBefore time.Time, onlyUnwarned bool) ([]domain.Order, error) {
+	query := orderSelectQuery + `
+		WHERE o.status = 'active'
+		  AND ((o.available_from IS NOT NULL AND o.available_from < $1 AND COALESCE(o.activated_at, o.created_at) < $1)
+		    OR COALESCE(o.activated_at, o.created_at) < $2)
+	`
+	if onlyUnwarned {
+		query += " AND o.expiry_warned_at IS NULL"
+	}
+	query += " ORDER BY o.created_at"
+
+	return d.queryOrders(query, availableBefore, createdBefore)
+}
+
+// ExpireOrders переводит активные заказы в статус expired и возвращает UUID заказов,
+// которые были изменены этим вызовом (уже измененные другим экземпляром пропускаются)
+func (d *Database) ExpireOrders(orderUUIDs []string) ([]string, error) {
+	query := `
+		UPDATE orders SET status = $1
+		WHERE uuid = ANY($2::uuid[]) AND status = 'active'
+		RETURNING uuid
+	`
+
+	return d.updateOrdersReturning(query, domain.OrderStatusExpired, pq.Array(orderUUIDs))
+}
+
+// MarkOrdersExpiryWarned отмечает, что о скором истечении заказов предупредили,
+// и возвращает UUID заказов, отмеченных этим вызовом
+func (d *Database) MarkOrdersExpiryWarned(orderUUIDs []string) ([]string, error) {
+	query := `
+		UPDATE orders SET expiry_warned_at = now()
+		WHERE uuid = ANY($1::uuid[]) AND status = 'active' AND expiry_warned_at IS NULL
+		RETURNING uuid
+	`
+
+	return d.updateOrdersReturning(query, pq.Array(orderUUIDs))
+}
+
+// updateOrdersReturning выполняет UPDATE ... RETURNING uuid и собирает измененные UUID
+func (d *Database) updateOrdersReturning(query string, args ...interface{}) ([]string, error) {
+	rows, err := d.DB.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка обновления заказов: %v", err)
+	}
+	defer rows.Close()
+
+	var updated []string
+	for rows.Next() {
+		var orderUUID string
+		if err := rows.Scan(&orderUUID); err != nil {
+			return nil, fmt.Errorf("ошибка сканирования строки: %v", err)
+		}
+		updated = append(updated, orderUUID)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка при итерации по строкам: %v", err)
+	}
+
+	return updated, nil
+}
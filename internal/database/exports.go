@@ -0,0 +1,146 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+
+	"dalnoboy/internal/domain"
+)
+
+// prefixTSQuery превращает поисковый запрос в tsquery, где каждое слово может быть началом
+// слова в заказе: "холод ленин" → "холод:* & ленин:*". Пустая строка — запрос без слов.
+func prefixTSQuery(query string) string {
+	words := domain.SearchWords(query)
+	for i, word := range words {
+		words[i] = word + ":*"
+	}
+	return strings.Join(words, " & ")
+}
+
+// StreamOrders построчно передает в fn заказы, подходящие под фильтры веса, цены, даты погрузки,
+// статуса, даты создания и поисковый запрос.
+// Остальные фильтры применяет вызывающий код; заказы не накапливаются в памяти.
+func (d *Database) StreamOrders(filter *domain.OrderFilter, fn func(order *domain.Order) error) error {
+	var conditions []string
+	var args []interface{}
+	addCondition := func(condition string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.MinWeight != nil {
+		addCondition("o.weight_kg >= $%d", *filter.MinWeight)
+	}
+	if filter.MaxWeight != nil {
+		addCondition("o.weight_kg <= $%d", *filter.MaxWeight)
+	}
+	if filter.MinPrice != nil {
+		addCondition("o.price >= $%d", *filter.MinPrice)
+	}
+	if filter.MaxPrice != nil {
+		addCondition("o.price <= $%d", *filter.MaxPrice)
+	}
+	if filter.PickupFrom != nil {
+		addCondition("o.available_from >= $%d", *filter.PickupFrom)
+	}
+	if filter.PickupTo != nil {
+		addCondition("o.available_from < $%d", *filter.PickupTo)
+	}
+	if filter.Status != "" {
+		addCondition("o.status = $%d", filter.Status)
+	}
+	if filter.CreatedFrom != nil {
+		addCondition("o.created_at >= $%d", *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		addCondition("o.created_at < $%d", *filter.CreatedTo)
+	}
+	searchArg := 0
+	if tsQuery := prefixTSQuery(filter.Query); tsQuery != "" {
+		addCondition("o.search_vector @@ to_tsquery('russian', $%d)", tsQuery)
+		searchArg = len(args)
+	}
+
+	query := orderSelectQuery
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	switch {
+	case filter.Sort == domain.OrderSortPricePerKm:
+		// Заказы без расстояния — в конце, как в SortOrders
+		query += " ORDER BY o.price / NULLIF(o.distance_km, 0) DESC NULLS LAST, o.created_at DESC"
+	case searchArg > 0 && filter.Sort != domain.OrderSortNewest:
+		query += fmt.Sprintf(" ORDER BY ts_rank(o.search_vector, to_tsquery('russian', $%d)) DESC, o.created_at DESC", searchArg)
+	default:
+		query += " ORDER BY o.created_at DESC"
+	}
+
+	rows, err := d.DB.Query(query, args...)
+	if err != nil {
+		return fmt.Errorf("ошибка выполнения запроса: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		order, err := scanOrder(rows)
+		if err != nil {
+			return fmt.Errorf("ошибка сканирования строки: %v", err)
+		}
+		if err := fn(order); err != nil {
+			return err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("ошибка при итерации по строкам: %v", err)
+	}
+	return nil
+}
+
+// StreamCustomers построчно передает в fn всех заказчиков
+func (d *Database) StreamCustomers(fn func(customer *domain.Customer) error) error {
+	rows, err := d.DB.Query(customerSelectQuery + " ORDER BY created_at")
+	if err != nil {
+		return fmt.Errorf("ошибка выполнения запроса: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		customer, err := scanCustomer(rows)
+		if err != nil {
+			return fmt.Errorf("ошибка сканирования строки: %v", err)
+		}
+		if err := fn(customer); err != nil {
+			return err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("ошибка при итерации по строкам: %v", err)
+	}
+	return nil
+}
+
+// StreamDrivers построчно передает в fn всех водителей с городом, транспортом и рейтингом
+func (d *Database) StreamDrivers(fn func(driver *domain.Driver) error) error {
+	rows, err := d.DB.Query(driverSelectQuery + " ORDER BY d.created_at")
+	if err != nil {
+		return fmt.Errorf("ошибка выполнения запроса: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		driver, err := scanDriver(rows)
+		if err != nil {
+			return fmt.Errorf("ошибка сканирования строки: %v", err)
+		}
+		if err := fn(driver); err != nil {
+			return err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("ошибка при итерации по строкам: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package database
+
+import (
+	"fmt"
+
+	"dalnoboy/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// GetDriversToNotifyAboutOrder возвращает водителей с включенными мгновенными уведомлениями и указанным городом,
+// которые еще не получали уведомление о заказе. Отбор по расстоянию выполняется в сервисе.
+func (d *Database) GetDriversToNotifyAboutOrder(order *domain.Order) ([]domain.Driver, error) {
+	query := driverSelectQuery + `
+		WHERE d.notification_enabled = true
+		  AND d.notification_mode = 'instant'
+		  AND d.city_uuid IS NOT NULL
+		  AND NOT EXISTS (
+			SELECT 1 FROM order_notifications n
+			WHERE n.order_uuid = $1 AND n.driver_uuid = d.uuid
+		  )
+		ORDER BY d.created_at
+	`
+
+	return d.queryDrivers(query, order.UUID)
+}
+
+// GetNotifiedDriversForOrder возвращает водителей, которым уже отправлялось уведомление о заказе
+func (d *Database) GetNotifiedDriversForOrder(orderUUID string) ([]domain.Driver, error) {
+	query := driverSelectQuery + `
+		JOIN order_notifications n ON n.driver_uuid = d.uuid
+		WHERE n.order_uuid = $1
+		ORDER BY n.notified_at
+	`
+
+	return d.queryDrivers(query, orderUUID)
+}
+
+// RecordOrderNotification отмечает, что водитель получил уведомление о заказе
+func (d *Database) RecordOrderNotification(orderUUID string, driverUUID uuid.UUID) error {
+	query := `
+		INSERT INTO order_notifications (order_uuid, driver_uuid, notified_at)
+		VALUES ($1, $2, now())
+		ON CONFLICT (order_uuid, driver_uuid) DO UPDATE SET notified_at = now()
+	`
+
+	_, err := d.DB.Exec(query, orderUUID, driverUUID)
+	if err != nil {
+		return fmt.Errorf("ошибка сохранения уведомления о заказе: %v", err)
+	}
+
+	return nil
+}
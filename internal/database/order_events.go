@@ -0,0 +1,55 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"dalnoboy/internal/domain"
+
+	"github.com/lib/pq"
+)
+
+// orderEventsChannel — канал LISTEN/NOTIFY, в который триггер orders_notify_event пишет события ленты заказов
+const orderEventsChannel = "order_events"
+
+// ListenOrderEvents передает в fn события ленты заказов до отмены контекста. Соединение LISTEN
+// восстанавливается автоматически; после восстановления fn получает событие resync.
+func (d *Database) ListenOrderEvents(ctx context.Context, fn func(event domain.OrderEvent)) error {
+	listener := pq.NewListener(d.connStr, time.Second, time.Minute, func(event pq.ListenerEventType, err error) {
+		if err != nil {
+			log.Printf("Соединение событий заказов: %v", err)
+		}
+	})
+	defer listener.Close()
+
+	if err := listener.Listen(orderEventsChannel); err != nil {
+		return fmt.Errorf("ошибка подписки на события заказов: %v", err)
+	}
+
+	ping := time.NewTicker(time.Minute)
+	defer ping.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case notification := <-listener.Notify:
+			if notification == nil {
+				// pq присылает nil после переподключения: уведомления за время разрыва потеряны
+				fn(domain.OrderEvent{Type: domain.OrderEventResync})
+				continue
+			}
+			var event domain.OrderEvent
+			if err := json.Unmarshal([]byte(notification.Extra), &event); err != nil {
+				log.Printf("Некорректное событие заказа %q: %v", notification.Extra, err)
+				continue
+			}
+			fn(event)
+		case <-ping.C:
+			// Проверка соединения: при разрыве pq переподключится сам
+			go listener.Ping()
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+
+	"dalnoboy/internal/domain"
+)
+
+// SaveDriverRating сохраняет оценку водителя по заказу. Повторная оценка того же заказа
+// заменяет предыдущую; пустой комментарий не стирает ранее оставленный.
+func (d *Database) SaveDriverRating(rating *domain.DriverRating) error {
+	query := `
+		INSERT INTO driver_ratings (uuid, order_uuid, driver_uuid, customer_uuid, score, comment, source, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $8)
+		ON CONFLICT (order_uuid) DO UPDATE SET
+			score = EXCLUDED.score,
+			comment = COALESCE(EXCLUDED.comment, driver_ratings.comment),
+			source = EXCLUDED.source,
+			updated_at = EXCLUDED.updated_at
+		RETURNING uuid, comment, created_at
+	`
+
+	err := d.DB.QueryRow(query,
+		rating.UUID,
+		rating.OrderUUID,
+		rating.DriverUUID,
+		rating.CustomerUUID,
+		rating.Score,
+		rating.Comment,
+		rating.Source,
+		rating.CreatedAt,
+	).Scan(&rating.UUID, &rating.Comment, &rating.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("ошибка сохранения оценки водителя: %v", err)
+	}
+
+	return nil
+}
+
+// ratingSelectQuery содержит общую часть запроса оценок водителей с названием заказа и именем заказчика
+const ratingSelectQuery = `
+		SELECT r.uuid, r.order_uuid, r.driver_uuid, r.customer_uuid, r.score, r.comment, r.source, r.created_at,
+			o.title, c.name
+		FROM driver_ratings r
+		JOIN orders o ON r.order_uuid = o.uuid
+		JOIN customers c ON r.customer_uuid = c.uuid
+	`
+
+// scanRating сканирует одну строку результата ratingSelectQuery
+func scanRating(row rowScanner) (*domain.DriverRating, error) {
+	var rating domain.DriverRating
+	err := row.Scan(
+		&rating.UUID,
+		&rating.OrderUUID,
+		&rating.DriverUUID,
+		&rating.CustomerUUID,
+		&rating.Score,
+		&rating.Comment,
+		&rating.Source,
+		&rating.CreatedAt,
+		&rating.OrderTitle,
+		&rating.CustomerName,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &rating, nil
+}
+
+// GetRatingByOrder возвращает оценку водителя по заказу
+func (d *Database) GetRatingByOrder(orderUUID string) (*domain.DriverRating, error) {
+	rating, err := scanRating(d.DB.QueryRow(ratingSelectQuery+" WHERE r.order_uuid = $1", orderUUID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("ошибка получения оценки по заказу: %v", err)
+	}
+	return rating, nil
+}
+
+// GetDriverRatings возвращает последние оценки водителя
+func (d *Database) GetDriverRatings(driverUUID string, limit int) ([]domain.DriverRating, error) {
+	rows, err := d.DB.Query(ratingSelectQuery+" WHERE r.driver_uuid = $1 ORDER BY r.created_at DESC LIMIT $2", driverUUID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка выполнения запроса: %v", err)
+	}
+	defer rows.Close()
+
+	var ratings []domain.DriverRating
+	for rows.Next() {
+		rating, err := scanRating(rows)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка сканирования строки: %v", err)
+		}
+		ratings = append(ratings, *rating)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка при итерации по строкам: %v", err)
+	}
+
+	return ratings, nil
+}
@@ -0,0 +1,105 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"dalnoboy/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// savedSearchSelectQuery содержит общую часть запроса сохраненных поисков с названием города
+const savedSearchSelectQuery = `
+		SELECT s.uuid, s.driver_uuid, s.city_uuid, c.name, s.radius_km, s.direction, s.min_price, s.created_at
+		FROM driver_saved_searches s
+		JOIN cities c ON s.city_uuid = c.uuid
+	`
+
+// CreateSavedSearch сохраняет поиск водителя
+func (d *Database) CreateSavedSearch(search *domain.SavedSearch) error {
+	query := `
+		INSERT INTO driver_saved_searches (uuid, driver_uuid, city_uuid, radius_km, direction, min_price, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err := d.DB.Exec(query, search.UUID, search.DriverUUID, search.CityUUID, search.RadiusKm,
+		search.Direction, search.MinPrice, search.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("ошибка сохранения поиска: %v", err)
+	}
+	return nil
+}
+
+// GetSavedSearches возвращает сохраненные поиски водителя в порядке создания
+func (d *Database) GetSavedSearches(driverUUID string) ([]domain.SavedSearch, error) {
+	rows, err := d.DB.Query(savedSearchSelectQuery+" WHERE s.driver_uuid = $1 ORDER BY s.created_at", driverUUID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка выполнения запроса: %v", err)
+	}
+	defer rows.Close()
+
+	var searches []domain.SavedSearch
+	for rows.Next() {
+		var search domain.SavedSearch
+		if err := rows.Scan(&search.UUID, &search.DriverUUID, &search.CityUUID, &search.CityName,
+			&search.RadiusKm, &search.Direction, &search.MinPrice, &search.CreatedAt); err != nil {
+			return nil, fmt.Errorf("ошибка сканирования строки: %v", err)
+		}
+		searches = append(searches, search)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка при итерации по строкам: %v", err)
+	}
+
+	return searches, nil
+}
+
+// DeleteSavedSearch удаляет поиск водителя по UUID или его первым символам.
+// Возвращает число удаленных поисков.
+func (d *Database) DeleteSavedSearch(driverUUID, prefix string) (int64, error) {
+	result, err := d.DB.Exec("DELETE FROM driver_saved_searches WHERE driver_uuid = $1 AND uuid::text LIKE $2 || '%'", driverUUID, prefix)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка удаления поиска: %v", err)
+	}
+	return result.RowsAffected()
+}
+
+// UpdateDriverDigest задает час ежедневной сводки (nil — выключить) и часовой пояс водителя
+func (d *Database) UpdateDriverDigest(driverUUID uuid.UUID, digestHour *int, timezone *string) error {
+	query := "UPDATE drivers SET digest_hour = $1, timezone = COALESCE($2, timezone) WHERE uuid = $3"
+
+	if _, err := d.DB.Exec(query, digestHour, timezone, driverUUID); err != nil {
+		return fmt.Errorf("ошибка обновления сводки водителя: %v", err)
+	}
+	return nil
+}
+
+// GetDriversWithDigest возвращает водителей с включенной ежедневной сводкой
+func (d *Database) GetDriversWithDigest() ([]domain.Driver, error) {
+	return d.queryDrivers(driverSelectQuery + " WHERE d.digest_hour IS NOT NULL ORDER BY d.created_at")
+}
+
+// MarkDigestSent запоминает время отправки сводки водителю
+func (d *Database) MarkDigestSent(driverUUID uuid.UUID, sentAt time.Time) error {
+	if _, err := d.DB.Exec("UPDATE drivers SET last_digest_at = $1 WHERE uuid = $2", sentAt, driverUUID); err != nil {
+		return fmt.Errorf("ошибка сохранения времени сводки: %v", err)
+	}
+	return nil
+}
+
+// GetDigestOrders возвращает активные заказы, созданные после since, о которых водитель
+// еще не получал уведомлений
+func (d *Database) GetDigestOrders(driverUUID uuid.UUID, since time.Time) ([]domain.Order, error) {
+	query := orderSelectQuery + `
+		WHERE o.status = 'active' AND o.created_at > $1
+		  AND NOT EXISTS (
+			SELECT 1 FROM order_notifications n
+			WHERE n.order_uuid = o.uuid AND n.driver_uuid = $2
+		  )
+		ORDER BY o.created_at DESC
+	`
+
+	return d.queryOrders(query, since, driverUUID)
+}
@@ -0,0 +1,33 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// GetSetting возвращает значение настройки или пустую строку, если она не задана
+func (d *Database) GetSetting(key string) (string, error) {
+	var value string
+	err := d.DB.QueryRow("SELECT value FROM app_settings WHERE key = $1", key).Scan(&value)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", nil
+		}
+		return "", fmt.Errorf("ошибка получения настройки %s: %v", key, err)
+	}
+	return value, nil
+}
+
+// SetSetting сохраняет значение настройки
+func (d *Database) SetSetting(key, value string) error {
+	query := `
+		INSERT INTO app_settings (key, value, updated_at)
+		VALUES ($1, $2, now())
+		ON CONFLICT (key) DO UPDATE SET value = EXCLUDED.value, updated_at = now()
+	`
+
+	if _, err := d.DB.Exec(query, key, value); err != nil {
+		return fmt.Errorf("ошибка сохранения настройки %s: %v", key, err)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+
+	"dalnoboy/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// nullableVehicle принимает колонки транспорта из LEFT JOIN в driverSelectQuery
+type nullableVehicle struct {
+	uuid      sql.NullString
+	bodyType  sql.NullString
+	payloadKg sql.NullFloat64
+	volumeM3  *float64
+	lengthCm  *float64
+	widthCm   *float64
+	heightCm  *float64
+	adr       sql.NullBool
+	updatedAt sql.NullTime
+}
+
+// toDomain возвращает транспорт водителя или nil, если он не заполнен
+func (v *nullableVehicle) toDomain(driverUUID uuid.UUID) (*domain.Vehicle, error) {
+	if !v.uuid.Valid {
+		return nil, nil
+	}
+
+	vehicleUUID, err := uuid.Parse(v.uuid.String)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка парсинга UUID транспорта: %v", err)
+	}
+
+	return &domain.Vehicle{
+		UUID:       vehicleUUID,
+		DriverUUID: driverUUID,
+		BodyType:   v.bodyType.String,
+		PayloadKg:  v.payloadKg.Float64,
+		VolumeM3:   v.volumeM3,
+		LengthCm:   v.lengthCm,
+		WidthCm:    v.widthCm,
+		HeightCm:   v.heightCm,
+		ADR:        v.adr.Bool,
+		UpdatedAt:  v.updatedAt.Time,
+	}, nil
+}
+
+// SaveVehicle создает или заменяет транспорт водителя (у водителя один транспорт)
+func (d *Database) SaveVehicle(vehicle *domain.Vehicle) error {
+	query := `
+		INSERT INTO vehicles (
+			uuid, driver_uuid, body_type, payload_kg, volume_m3, length_cm, width_cm, height_cm, adr, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (driver_uuid) DO UPDATE SET
+			body_type = EXCLUDED.body_type,
+			payload_kg = EXCLUDED.payload_kg,
+			volume_m3 = EXCLUDED.volume_m3,
+			length_cm = EXCLUDED.length_cm,
+			width_cm = EXCLUDED.width_cm,
+			height_cm = EXCLUDED.height_cm,
+			adr = EXCLUDED.adr,
+			updated_at = EXCLUDED.updated_at
+		RETURNING uuid
+	`
+
+	var vehicleUUID string
+	err := d.DB.QueryRow(query,
+		vehicle.UUID,
+		vehicle.DriverUUID,
+		vehicle.BodyType,
+		vehicle.PayloadKg,
+		vehicle.VolumeM3,
+		vehicle.LengthCm,
+		vehicle.WidthCm,
+		vehicle.HeightCm,
+		vehicle.ADR,
+		vehicle.UpdatedAt,
+	).Scan(&vehicleUUID)
+	if err != nil {
+		return fmt.Errorf("ошибка сохранения транспорта: %v", err)
+	}
+
+	// При обновлении существующей записи сохраняется ее прежний UUID
+	if vehicle.UUID, err = uuid.Parse(vehicleUUID); err != nil {
+		return fmt.Errorf("ошибка парсинга UUID транспорта: %v", err)
+	}
+
+	return nil
+}
+
+// DeleteVehicle удаляет транспорт водителя
+func (d *Database) DeleteVehicle(driverUUID uuid.UUID) error {
+	_, err := d.DB.Exec("DELETE FROM vehicles WHERE driver_uuid = $1", driverUUID)
+	if err != nil {
+		return fmt.Errorf("ошибка удаления транспорта: %v", err)
+	}
+
+	return nil
+}
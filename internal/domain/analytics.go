@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"time"
+)
+
+// AnalyticsPeriod представляет период аналитики: From включительно, To — не включая
+type AnalyticsPeriod struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+// DailyVolume представляет число заказов за день: созданных, назначенных водителям и доставленных
+type DailyVolume struct {
+	Date      string `json:"date"` // ГГГГ-ММ-ДД
+	Created   int    `json:"created"`
+	Assigned  int    `json:"assigned"`
+	Delivered int    `json:"delivered"`
+}
+
+// AssignmentTime представляет время от создания заказа до назначения водителя
+// по заказам, назначенным за период
+type AssignmentTime struct {
+	Orders       int      `json:"orders"`
+	MedianHours  *float64 `json:"median_hours"`
+	AverageHours *float64 `json:"average_hours"`
+	Created      int      `json:"created"`         // Создано заказов за период
+	Converted    int      `json:"converted"`       // Из них уже назначено водителям
+	Conversion   *float64 `json:"conversion_rate"` // Доля назначенных среди созданных, от 0 до 1
+}
+
+// RouteStats представляет популярное направление между городами
+type RouteStats struct {
+	FromCity      string   `json:"from_city"`
+	ToCity        string   `json:"to_city"`
+	Orders        int      `json:"orders"`
+	Assigned      int      `json:"assigned"`
+	AvgPrice      float64  `json:"avg_price"`
+	AvgPricePerKm *float64 `json:"avg_price_per_km"`
+}
+
+// PriceStats представляет средние цены заказов, созданных за период
+type PriceStats struct {
+	Orders        int      `json:"orders"`
+	AvgPrice      *float64 `json:"avg_price"`
+	AvgPricePerKg *float64 `json:"avg_price_per_kg"`
+	AvgPricePerKm *float64 `json:"avg_price_per_km"`
+}
+
+// DriverActivity представляет активность водителя за период
+type DriverActivity struct {
+	DriverUUID string   `json:"driver_uuid"`
+	Name       string   `json:"name"`
+	Bids       int      `json:"bids"`
+	Assigned   int      `json:"assigned"`
+	Delivered  int      `json:"delivered"`
+	Revenue    float64  `json:"revenue"` // Сумма цен доставленных заказов
+	RatingAvg  *float64 `json:"rating_avg"`
+}
+
+// DriverSourceStats представляет водителей, пришедших за период из одного источника
+type DriverSourceStats struct {
+	Source  string `json:"source"`  // ref_<код>, order_link или пустая строка — без метки
+	Drivers int    `json:"drivers"` // Зарегистрировались в боте
+	Active  int    `json:"active"`  // Из них сделали предложение или получили заказ
+}
+
+// AnalyticsSummary представляет сводку аналитики за период (еженедельный отчет администраторам)
+type AnalyticsSummary struct {
+	Period        AnalyticsPeriod     `json:"period"`
+	Created       int                 `json:"created"`
+	Assigned      int                 `json:"assigned"`
+	Delivered     int                 `json:"delivered"`
+	Assignment    AssignmentTime      `json:"assignment"`
+	Prices        PriceStats          `json:"prices"`
+	TopRoutes     []RouteStats        `json:"top_routes"`
+	TopDrivers    []DriverActivity    `json:"top_drivers"`
+	ActiveDrivers int                 `json:"active_drivers"`
+	DriverSources []DriverSourceStats `json:"driver_sources"`
+}
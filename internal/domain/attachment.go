@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"time"
+)
+
+// Виды вложений заказа
+const (
+	AttachmentKindPhoto    = "photo"
+	AttachmentKindDocument = "document"
+)
+
+// Кто добавил вложение
+const (
+	AttachmentUploaderAdmin    = "admin"
+	AttachmentUploaderCustomer = "customer"
+	AttachmentUploaderDriver   = "driver"
+)
+
+// Назначение вложения
+const (
+	AttachmentPurposeCargo = "cargo" // Фото груза, накладные от заказчика
+	AttachmentPurposePOD   = "pod"   // Подтверждение доставки от водителя
+)
+
+// Боты, через которые принимаются и отправляются файлы. file_id в Telegram
+// действителен только для бота, который его получил.
+const (
+	AttachmentBotAdmin  = "admin"
+	AttachmentBotDriver = "driver"
+)
+
+// Attachment представляет фото или документ, приложенный к заказу (фото груза, накладная)
+type Attachment struct {
+	UUID               string    `json:"uuid"`
+	OrderUUID          string    `json:"order_uuid"`
+	Kind               string    `json:"kind"`
+	Purpose            string    `json:"purpose"`
+	SourceBot          string    `json:"-"` // Бот, через который файл был загружен
+	TelegramFileID     string    `json:"-"` // file_id в боте-источнике
+	TelegramUniqueID   string    `json:"-"` // file_unique_id, одинаковый для всех ботов
+	AdminFileID        *string   `json:"-"` // file_id в админском боте после первой отправки
+	DriverFileID       *string   `json:"-"` // file_id в боте водителей после первой отправки
+	FileName           *string   `json:"file_name"`
+	MimeType           *string   `json:"mime_type"`
+	SizeBytes          *int64    `json:"size_bytes"`
+	Caption            *string   `json:"caption"`
+	StorageKey         *string   `json:"-"` // Ключ копии файла в хранилище, если зеркалирование включено
+	UploadedBy         string    `json:"uploaded_by"`
+	UploaderTelegramID int64     `json:"-"`
+	CreatedAt          time.Time `json:"created_at"`
+}
+
+// FileIDFor возвращает file_id вложения, действительный для указанного бота, или пустую строку
+func (a *Attachment) FileIDFor(bot string) string {
+	if bot == a.SourceBot {
+		return a.TelegramFileID
+	}
+	switch bot {
+	case AttachmentBotAdmin:
+		if a.AdminFileID != nil {
+			return *a.AdminFileID
+		}
+	case AttachmentBotDriver:
+		if a.DriverFileID != nil {
+			return *a.DriverFileID
+		}
+	}
+	return ""
+}
+
+// AddAttachmentRequest представляет запрос на добавление вложения к заказу
+type AddAttachmentRequest struct {
+	OrderUUID          string
+	Kind               string
+	TelegramFileID     string
+	TelegramUniqueID   string
+	FileName           string
+	MimeType           string
+	SizeBytes          int64
+	Caption            string
+	UploaderTelegramID int64
+	CustomerUUID       *string // Если задан, заказ должен принадлежать этому заказчику
+}
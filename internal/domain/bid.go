@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"time"
+)
+
+// Статусы предложений водителей
+const (
+	BidStatusPending  = "pending"
+	BidStatusAccepted = "accepted"
+	BidStatusRejected = "rejected"
+)
+
+// BidStatusNames содержит русские названия статусов предложений
+var BidStatusNames = map[string]string{
+	BidStatusPending:  "ожидает",
+	BidStatusAccepted: "принято",
+	BidStatusRejected: "отклонено",
+}
+
+// Bid представляет предложение водителя по заказу: своя цена и, по желанию, дата погрузки.
+// У водителя одно предложение на заказ, повторное предложение заменяет предыдущее.
+type Bid struct {
+	UUID       string     `json:"uuid"`
+	OrderUUID  string     `json:"order_uuid"`
+	DriverUUID string     `json:"driver_uuid"`
+	Driver     *Driver    `json:"driver,omitempty"` // Заполняется при показе списка предложений
+	Price      float64    `json:"price"`
+	PickupDate *time.Time `json:"pickup_date"` // nil — водитель согласен с датой заказа
+	Comment    *string    `json:"comment"`
+	Status     string     `json:"status"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// ShortID возвращает короткий номер предложения для команд ботов
+func (b *Bid) ShortID() string {
+	return b.UUID[:8]
+}
+
+// PlaceBidRequest представляет предложение водителя по заказу
+type PlaceBidRequest struct {
+	DriverTelegramID int64
+	OrderRef         string // UUID заказа или его первые символы
+	Price            float64
+	PickupDate       *time.Time
+	Comment          string
+}
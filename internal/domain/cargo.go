@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"github.com/google/uuid"
+)
+
+// Коды особых требований к перевозке
+const (
+	RequirementRefrigerated = "refrigerated" // Температурный режим
+	RequirementDangerous    = "adr"          // Опасный груз (класс ДОПОГ)
+	RequirementOversize     = "oversize"     // Негабарит
+	RequirementFragile      = "fragile"      // Хрупкий груз
+)
+
+// RequirementNames содержит русские названия особых требований
+var RequirementNames = map[string]string{
+	RequirementRefrigerated: "Температурный режим",
+	RequirementDangerous:    "Опасный груз (ADR)",
+	RequirementOversize:     "Негабарит",
+	RequirementFragile:      "Хрупкий",
+}
+
+// CargoType представляет тип груза из управляемого справочника
+type CargoType struct {
+	UUID     uuid.UUID `json:"uuid"`
+	Code     string    `json:"code"` // Латинский код для API: "food", "building"
+	Name     string    `json:"name"`
+	IsActive bool      `json:"is_active"` // Неактивные типы нельзя выбрать для новых заказов
+}
+
+// CargoRequirements описывает особые требования к перевозке груза
+type CargoRequirements struct {
+	TemperatureMinC *float64 `json:"temperature_min_c"`
+	TemperatureMaxC *float64 `json:"temperature_max_c"`
+	ADRClass        *string  `json:"adr_class"` // Класс опасности ДОПОГ: "3", "2.1"
+	Oversize        bool     `json:"oversize"`
+	Fragile         bool     `json:"fragile"`
+}
+
+// Refrigerated проверяет, задан ли температурный режим
+func (r *CargoRequirements) Refrigerated() bool {
+	return r.TemperatureMinC != nil || r.TemperatureMaxC != nil
+}
+
+// Has проверяет наличие требования по его коду
+func (r *CargoRequirements) Has(code string) bool {
+	switch code {
+	case RequirementRefrigerated:
+		return r.Refrigerated()
+	case RequirementDangerous:
+		return r.ADRClass != nil
+	case RequirementOversize:
+		return r.Oversize
+	case RequirementFragile:
+		return r.Fragile
+	}
+	return false
+}
+
+// Codes возвращает коды заданных требований
+func (r *CargoRequirements) Codes() []string {
+	var codes []string
+	for _, code := range []string{RequirementRefrigerated, RequirementDangerous, RequirementOversize, RequirementFragile} {
+		if r.Has(code) {
+			codes = append(codes, code)
+		}
+	}
+	return codes
+}
@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"time"
+)
+
+// Участники переписки по заказу
+const (
+	ChatSenderDriver   = "driver"
+	ChatSenderCustomer = "customer"
+	ChatSenderAdmin    = "admin" // Диспетчер отвечает, если у заказчика нет Telegram
+)
+
+// Conversation представляет анонимную переписку водителя с заказчиком по заказу.
+// Сообщения пересылаются ботами, стороны не видят Telegram ID и телефонов друг друга.
+type Conversation struct {
+	UUID             string    `json:"uuid"`
+	OrderUUID        string    `json:"order_uuid"`
+	OrderTitle       string    `json:"order_title"`
+	DriverUUID       string    `json:"driver_uuid"`
+	DriverName       string    `json:"driver_name"`
+	DriverTelegramID int64     `json:"-"`
+	MessagesCount    int       `json:"messages_count"`
+	CreatedAt        time.Time `json:"created_at"`
+	LastMessageAt    time.Time `json:"last_message_at"`
+}
+
+// ShortID возвращает короткий номер переписки, который показывается в пересланных сообщениях
+func (c *Conversation) ShortID() string {
+	return c.UUID[:8]
+}
+
+// ChatMessage представляет одно сообщение переписки
+type ChatMessage struct {
+	UUID             string    `json:"uuid"`
+	ConversationUUID string    `json:"conversation_uuid"`
+	Sender           string    `json:"sender"`
+	Text             string    `json:"text"`
+	Delivered        bool      `json:"delivered"` // Удалось ли переслать сообщение получателю
+	CreatedAt        time.Time `json:"created_at"`
+}
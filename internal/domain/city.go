@@ -6,6 +6,25 @@ import (
 
 // City представляет доменную модель города
 type City struct {
-	UUID uuid.UUID `json:"uuid"`
-	Name string    `json:"name"`
+	UUID      uuid.UUID `json:"uuid"`
+	Name      string    `json:"name"`
+	Aliases   []string  `json:"aliases"` // Альтернативные названия: "Питер", "СПб"
+	Region    *string   `json:"region"`
+	Latitude  *float64  `json:"latitude"`
+	Longitude *float64  `json:"longitude"`
+}
+
+// HasCoordinates проверяет, известны ли координаты города
+func (c *City) HasCoordinates() bool {
+	return c.Latitude != nil && c.Longitude != nil
+}
+
+// CityRequest представляет запрос на создание или изменение города.
+// При изменении поля со значением nil не меняются.
+type CityRequest struct {
+	Name      *string   `json:"name"`
+	Aliases   *[]string `json:"aliases"`
+	Region    *string   `json:"region"`   // Пустая строка очищает регион
+	Latitude  *float64  `json:"latitude"` // Задается вместе с Longitude
+	Longitude *float64  `json:"longitude"`
 }
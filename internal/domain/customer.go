@@ -13,5 +13,15 @@ type Customer struct {
 	Phone       string    `json:"phone"`
 	TelegramID  *int64    `json:"telegram_id"`
 	TelegramTag *string   `json:"telegram_tag"`
+	IsActive    bool      `json:"is_active"`
 	CreatedAt   time.Time `json:"created_at"`
 }
+
+// UpdateCustomerRequest представляет запрос на изменение данных заказчика.
+// Поля со значением nil не изменяются; пустой тег и нулевой Telegram ID очищают значение.
+type UpdateCustomerRequest struct {
+	Name        *string `json:"name"`
+	Phone       *string `json:"phone"`
+	TelegramID  *int64  `json:"telegram_id"`
+	TelegramTag *string `json:"telegram_tag"`
+}
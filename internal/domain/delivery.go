@@ -0,0 +1,17 @@
+package domain
+
+// ConfirmDeliveryRequest представляет подтверждение доставки водителем:
+// фото подписанных документов и, по возможности, координаты точки выгрузки
+type ConfirmDeliveryRequest struct {
+	OrderUUID        string
+	DriverTelegramID int64
+	Files            []AddAttachmentRequest
+	Latitude         *float64
+	Longitude        *float64
+}
+
+// Delivery представляет доставленный заказ для сводки подтверждений доставки
+type Delivery struct {
+	Order    Order
+	PODFiles int // Количество фото и документов подтверждения доставки
+}
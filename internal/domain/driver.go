@@ -12,15 +12,120 @@ type Driver struct {
 	Name                string     `json:"name"`
 	TelegramID          int64      `json:"telegram_id"`
 	TelegramTag         *string    `json:"telegram_tag"`
+	Phone               *string    `json:"phone"` // Телефон из контакта Telegram, nil — не указан
 	NotificationEnabled bool       `json:"notification_enabled"`
 	CityUUID            *uuid.UUID `json:"city_uuid"`
 	CityName            *string    `json:"city_name"`
+	PickupRadiusKm      *int       `json:"pickup_radius_km"`   // Погрузка в пределах N км от своего города
+	DeliveryRadiusKm    *int       `json:"delivery_radius_km"` // Выгрузка в пределах N км от своего города
+	Vehicle             *Vehicle   `json:"vehicle"`            // nil, если транспорт не заполнен
+	RatingAvg           *float64   `json:"rating_avg"`         // Средняя оценка заказчиков, nil — оценок нет
+	RatingCount         int        `json:"rating_count"`
+	Timezone            *string    `json:"timezone"`          // Часовой пояс IANA, выбранный водителем; nil — пояс города
+	CityTimezone        *string    `json:"city_timezone"`     // Часовой пояс города водителя по его региону
+	DigestHour          *int       `json:"digest_hour"`       // Час ежедневной сводки по местному времени, nil — выключена
+	LastDigestAt        *time.Time `json:"last_digest_at"`    // Когда отправлена последняя сводка
+	NotificationMode    string     `json:"notification_mode"` // Как присылать новые заказы: сразу или в сводке
+	QuietHoursStart     *int       `json:"quiet_hours_start"` // Начало тихих часов по местному времени, nil — без тихих часов
+	QuietHoursEnd       *int       `json:"quiet_hours_end"`   // Конец тихих часов (не включая)
+	Source              *string    `json:"source"`            // Источник привлечения, nil — пришел без метки
+	OnboardingStep      *string    `json:"onboarding_step"`   // Незавершенный шаг регистрации, nil — регистрация пройдена
+	TermsAcceptedAt     *time.Time `json:"terms_accepted_at"` // Когда водитель принял условия сервиса
 	CreatedAt           time.Time  `json:"created_at"`
 }
 
+// Шаги регистрации водителя в боте по порядку
+const (
+	OnboardingStepPhone   = "phone"
+	OnboardingStepCity    = "city"
+	OnboardingStepVehicle = "vehicle"
+	OnboardingStepTerms   = "terms"
+)
+
+// IsOnboarding проверяет, что водитель еще не завершил регистрацию в боте
+func (d *Driver) IsOnboarding() bool {
+	return d.OnboardingStep != nil
+}
+
+// Частота уведомлений о новых заказах
+const (
+	NotificationModeInstant = "instant" // Каждый подходящий заказ сразу
+	NotificationModeDigest  = "digest"  // Только ежедневная сводка
+)
+
+// Источники привлечения водителя, с которыми он впервые открыл бота
+const (
+	DriverSourceOrderLink      = "order_link" // Кнопка «Открыть в боте» на сайте или ссылка на заказ
+	DriverSourceReferralPrefix = "ref_"       // Реферальная ссылка или рекламная кампания: ref_<код>
+)
+
 // SetCityAndNotificationRequest представляет запрос на обновление города и уведомлений водителя
 type SetCityAndNotificationRequest struct {
 	DriverUUID          uuid.UUID `json:"driver_uuid"`
 	CityName            string    `json:"city_name"`
 	NotificationEnabled *bool     `json:"notification_enabled"`
 }
+
+// SetDriverRadiusRequest представляет запрос на изменение радиусов поиска заказов водителя.
+// Значение nil отключает соответствующий фильтр.
+type SetDriverRadiusRequest struct {
+	DriverUUID       uuid.UUID `json:"driver_uuid"`
+	PickupRadiusKm   *int      `json:"pickup_radius_km"`
+	DeliveryRadiusKm *int      `json:"delivery_radius_km"`
+}
+
+// DefaultDriverTimezone — часовой пояс водителя, если он не выбран и не известен по городу
+const DefaultDriverTimezone = "Europe/Moscow"
+
+// TimezoneName возвращает часовой пояс, выбранный водителем, иначе пояс его города или пояс по умолчанию
+func (d *Driver) TimezoneName() string {
+	if d.Timezone != nil && *d.Timezone != "" {
+		return *d.Timezone
+	}
+	if d.CityTimezone != nil && *d.CityTimezone != "" {
+		return *d.CityTimezone
+	}
+	return DefaultDriverTimezone
+}
+
+// Location возвращает часовой пояс водителя; неизвестное название заменяется поясом по умолчанию
+func (d *Driver) Location() *time.Location {
+	location, err := time.LoadLocation(d.TimezoneName())
+	if err != nil {
+		location, _ = time.LoadLocation(DefaultDriverTimezone)
+	}
+	return location
+}
+
+// HasQuietHours проверяет, что водитель задал тихие часы
+func (d *Driver) HasQuietHours() bool {
+	return d.QuietHoursStart != nil && d.QuietHoursEnd != nil
+}
+
+// InQuietHours проверяет, что в момент now у водителя тихие часы по его местному времени.
+// Интервал может переходить через полночь: 23–7 — с 23:00 до 07:00.
+func (d *Driver) InQuietHours(now time.Time) bool {
+	if !d.HasQuietHours() {
+		return false
+	}
+	hour := now.In(d.Location()).Hour()
+	start, end := *d.QuietHoursStart, *d.QuietHoursEnd
+	if start < end {
+		return hour >= start && hour < end
+	}
+	return hour >= start || hour < end
+}
+
+// QuietUntil возвращает, когда закончатся текущие тихие часы водителя.
+// false — в момент now тихих часов нет.
+func (d *Driver) QuietUntil(now time.Time) (time.Time, bool) {
+	if !d.InQuietHours(now) {
+		return time.Time{}, false
+	}
+	local := now.In(d.Location())
+	end := time.Date(local.Year(), local.Month(), local.Day(), *d.QuietHoursEnd, 0, 0, 0, local.Location())
+	if !end.After(local) {
+		end = end.AddDate(0, 0, 1)
+	}
+	return end, true
+}
@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata" // Тесты не зависят от базы часовых поясов в системе
+)
+
+func TestDriverQuietHours(t *testing.T) {
+	hour := func(value int) *int { return &value }
+	zone := func(name string) *string { return &name }
+	utc := func(value string) time.Time {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return parsed
+	}
+
+	tests := []struct {
+		name      string
+		driver    Driver
+		now       string // Момент проверки в UTC
+		wantQuiet bool
+		wantUntil string // Конец тихих часов в UTC
+	}{
+		{"без тихих часов", Driver{}, "2026-06-01T21:00:00Z", false, ""},
+
+		// 23–7 по Москве (UTC+3): через полночь
+		{"вечер в начале окна", Driver{QuietHoursStart: hour(23), QuietHoursEnd: hour(7)},
+			"2026-06-01T20:30:00Z", true, "2026-06-02T04:00:00Z"},
+		{"ночь после полуночи", Driver{QuietHoursStart: hour(23), QuietHoursEnd: hour(7)},
+			"2026-06-02T00:00:00Z", true, "2026-06-02T04:00:00Z"},
+		{"ровно в конце окна", Driver{QuietHoursStart: hour(23), QuietHoursEnd: hour(7)},
+			"2026-06-02T04:00:00Z", false, ""},
+		{"за минуту до начала", Driver{QuietHoursStart: hour(23), QuietHoursEnd: hour(7)},
+			"2026-06-01T19:59:00Z", false, ""},
+
+		// 1–5 по Москве: внутри одних суток
+		{"внутри окна без перехода", Driver{QuietHoursStart: hour(1), QuietHoursEnd: hour(5)},
+			"2026-06-01T01:59:00Z", true, "2026-06-01T02:00:00Z"},
+		{"до окна без перехода", Driver{QuietHoursStart: hour(1), QuietHoursEnd: hour(5)},
+			"2026-05-31T21:30:00Z", false, ""},
+
+		// Пояс водителя важнее пояса города, пояс города — пояса по умолчанию
+		{"пояс водителя", Driver{QuietHoursStart: hour(23), QuietHoursEnd: hour(7), Timezone: zone("Asia/Vladivostok"),
+			CityTimezone: zone("Europe/Kaliningrad")}, "2026-06-01T14:00:00Z", true, "2026-06-01T21:00:00Z"},
+		{"пояс города", Driver{QuietHoursStart: hour(23), QuietHoursEnd: hour(7), CityTimezone: zone("Asia/Vladivostok")},
+			"2026-06-01T14:00:00Z", true, "2026-06-01T21:00:00Z"},
+		{"днем по Москве, ночью во Владивостоке", Driver{QuietHoursStart: hour(23), QuietHoursEnd: hour(7)},
+			"2026-06-01T14:00:00Z", false, ""},
+		{"неизвестный пояс — Москва", Driver{QuietHoursStart: hour(23), QuietHoursEnd: hour(7), Timezone: zone("Mars/Olympus")},
+			"2026-06-01T20:30:00Z", true, "2026-06-02T04:00:00Z"},
+
+		// Переход на летнее время в Берлине 29.03.2026: после 02:00 CET сразу 03:00 CEST
+		{"перевод часов вперед", Driver{QuietHoursStart: hour(1), QuietHoursEnd: hour(3), Timezone: zone("Europe/Berlin")},
+			"2026-03-29T00:30:00Z", true, "2026-03-29T01:00:00Z"},
+		// Переход на зимнее время 25.10.2026: после 03:00 CEST снова 02:00 CET, ночь длиннее на час
+		{"перевод часов назад", Driver{QuietHoursStart: hour(0), QuietHoursEnd: hour(4), Timezone: zone("Europe/Berlin")},
+			"2026-10-24T23:00:00Z", true, "2026-10-25T03:00:00Z"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := utc(tt.now)
+			if got := tt.driver.InQuietHours(now); got != tt.wantQuiet {
+				t.Fatalf("InQuietHours(%s) = %v, ожидалось %v", tt.now, got, tt.wantQuiet)
+			}
+
+			until, quiet := tt.driver.QuietUntil(now)
+			if quiet != tt.wantQuiet {
+				t.Fatalf("QuietUntil(%s) вернул %v, ожидалось %v", tt.now, quiet, tt.wantQuiet)
+			}
+			if !quiet {
+				return
+			}
+			if want := utc(tt.wantUntil); !until.Equal(want) {
+				t.Fatalf("QuietUntil(%s) = %s, ожидалось %s", tt.now, until.UTC().Format(time.RFC3339), tt.wantUntil)
+			}
+		})
+	}
+}
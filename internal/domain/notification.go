@@ -0,0 +1,22 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Виды отложенных уведомлений водителю
+const (
+	DeferredNotificationNew     = "new"     // Новый заказ
+	DeferredNotificationUpdated = "updated" // Изменение заказа, о котором водитель уже знает
+)
+
+// DeferredNotification — уведомление о заказе, отложенное до конца тихих часов водителя
+type DeferredNotification struct {
+	OrderUUID  string    `json:"order_uuid"`
+	DriverUUID uuid.UUID `json:"driver_uuid"`
+	Kind       string    `json:"kind"`
+	Changes    []string  `json:"changes"` // Изменения заказа для Kind = updated
+	DeliverAt  time.Time `json:"deliver_at"`
+}
@@ -5,35 +5,70 @@ import (
 )
 
 const (
-	OrderStatusActive   = "active"
-	OrderStatusArchived = "archived"
+	OrderStatusActive    = "active"
+	OrderStatusArchived  = "archived"
+	OrderStatusExpired   = "expired"   // Снят автоматически: прошла дата погрузки или истек срок жизни
+	OrderStatusAssigned  = "assigned"  // Назначен водителю и находится в пути
+	OrderStatusDelivered = "delivered" // Доставлен, водитель подтвердил доставку фото документов
 )
 
+// OrderStatusNames содержит русские названия статусов заказов
+var OrderStatusNames = map[string]string{
+	OrderStatusActive:    "Активный",
+	OrderStatusArchived:  "Архивный",
+	OrderStatusExpired:   "Истек срок",
+	OrderStatusAssigned:  "В пути",
+	OrderStatusDelivered: "Доставлен",
+}
+
 // Order представляет доменную модель заказа
 type Order struct {
-	UUID                string     `json:"uuid"`
-	CustomerUUID        string     `json:"customer_uuid"`
-	Title               string     `json:"title"`
-	Description         string     `json:"description"`
-	WeightKg            float64    `json:"weight_kg"`
-	LengthCm            *float64   `json:"length_cm"`
-	WidthCm             *float64   `json:"width_cm"`
-	HeightCm            *float64   `json:"height_cm"`
-	FromCityUUID        *string    `json:"from_city_uuid"`
-	FromAddress         *string    `json:"from_address"`
-	FromCityName        *string    `json:"from_city_name"`
-	ToCityUUID          *string    `json:"to_city_uuid"`
-	ToAddress           *string    `json:"to_address"`
-	ToCityName          *string    `json:"to_city_name"`
-	Tags                []string   `json:"tags"`
-	Price               float64    `json:"price"`
-	AvailableFrom       *time.Time `json:"available_from"`
-	Status              string     `json:"status"`
-	CreatedAt           time.Time  `json:"created_at"`
-	CustomerName        string     `json:"customer_name"`
-	CustomerPhone       string     `json:"customer_phone"`
-	CustomerTelegramID  *int64     `json:"customer_telegram_id"`
-	CustomerTelegramTag *string    `json:"customer_telegram_tag"`
+	UUID                string            `json:"uuid"`
+	CustomerUUID        string            `json:"customer_uuid"`
+	Title               string            `json:"title"`
+	Description         string            `json:"description"`
+	WeightKg            float64           `json:"weight_kg"`
+	LengthCm            *float64          `json:"length_cm"`
+	WidthCm             *float64          `json:"width_cm"`
+	HeightCm            *float64          `json:"height_cm"`
+	FromCityUUID        *string           `json:"from_city_uuid"`
+	FromAddress         *string           `json:"from_address"`
+	FromCityName        *string           `json:"from_city_name"`
+	ToCityUUID          *string           `json:"to_city_uuid"`
+	ToAddress           *string           `json:"to_address"`
+	ToCityName          *string           `json:"to_city_name"`
+	Tags                []string          `json:"tags"`
+	CargoTypeUUID       *string           `json:"cargo_type_uuid"`
+	CargoTypeName       *string           `json:"cargo_type_name"`
+	Requirements        CargoRequirements `json:"requirements"`
+	AttachmentsCount    int               `json:"attachments_count"`
+	Price               float64           `json:"price"`
+	AvailableFrom       *time.Time        `json:"available_from"`
+	DistanceKm          *float64          `json:"distance_km"` // Расчетное расстояние маршрута по дорогам
+	Status              string            `json:"status"`
+	AssignedDriverUUID  *string           `json:"assigned_driver_uuid"`
+	AssignedDriverName  *string           `json:"assigned_driver_name"`
+	AssignedDriverTgID  *int64            `json:"-"`
+	AssignedAt          *time.Time        `json:"assigned_at"`
+	DeliveredAt         *time.Time        `json:"delivered_at"`
+	DeliveryLatitude    *float64          `json:"delivery_latitude"` // Координаты водителя при подтверждении доставки
+	DeliveryLongitude   *float64          `json:"delivery_longitude"`
+	Urgent              bool              `json:"urgent"`       // Срочный заказ: уведомления приходят и в тихие часы водителей
+	ActivatedAt         *time.Time        `json:"activated_at"` // Когда заказ вернули в активные; срок жизни считается от этого момента
+	CreatedAt           time.Time         `json:"created_at"`
+	CustomerName        string            `json:"customer_name"`
+	CustomerPhone       string            `json:"customer_phone"`
+	CustomerTelegramID  *int64            `json:"customer_telegram_id"`
+	CustomerTelegramTag *string           `json:"customer_telegram_tag"`
+}
+
+// PricePerKm возвращает ставку за километр или nil, если расстояние неизвестно
+func (o *Order) PricePerKm() *float64 {
+	if o.DistanceKm == nil || *o.DistanceKm <= 0 {
+		return nil
+	}
+	rate := o.Price / *o.DistanceKm
+	return &rate
 }
 
 // CreateOrderTgRequest представляет упрощенный запрос на создание заказа через Telegram
@@ -47,4 +82,48 @@ type CreateOrderTgRequest struct {
 	ToAddress    string  `json:"to_address"`
 	Price        float64 `json:"price"`
 	CustomerUUID string  `json:"customer_uuid"`
+
+	// Необязательные характеристики груза
+	CargoType        string `json:"cargo_type"`        // Код или название типа груза
+	TemperatureRange string `json:"temperature_range"` // "+2..+6", "-18"
+	ADRClass         string `json:"adr_class"`
+	Oversize         bool   `json:"oversize"`
+	Fragile          bool   `json:"fragile"`
+
+	Urgent bool `json:"urgent"` // Срочный заказ: уведомления приходят и в тихие часы водителей
+}
+
+// UpdateOrderRequest представляет запрос на частичное изменение заказа.
+// Поля со значением nil не изменяются.
+type UpdateOrderRequest struct {
+	Title         *string  `json:"title"`
+	Description   *string  `json:"description"`
+	WeightKg      *float64 `json:"weight_kg"`
+	LengthCm      *float64 `json:"length_cm"`
+	WidthCm       *float64 `json:"width_cm"`
+	HeightCm      *float64 `json:"height_cm"`
+	FromCityName  *string  `json:"from_city_name"`
+	FromAddress   *string  `json:"from_address"`
+	ToCityName    *string  `json:"to_city_name"`
+	ToAddress     *string  `json:"to_address"`
+	Price         *float64 `json:"price"`
+	AvailableFrom *string  `json:"available_from"` // Формат YYYY-MM-DD, пустая строка очищает дату
+
+	// Характеристики груза: пустая строка очищает тип, температуру и класс ADR
+	CargoType        *string `json:"cargo_type"`
+	TemperatureRange *string `json:"temperature_range"`
+	ADRClass         *string `json:"adr_class"`
+	Oversize         *bool   `json:"oversize"`
+	Fragile          *bool   `json:"fragile"`
+}
+
+// IsEmpty сообщает, что в запросе нет ни одного изменяемого поля
+func (r *UpdateOrderRequest) IsEmpty() bool {
+	return r.Title == nil && r.Description == nil && r.WeightKg == nil &&
+		r.LengthCm == nil && r.WidthCm == nil && r.HeightCm == nil &&
+		r.FromCityName == nil && r.FromAddress == nil &&
+		r.ToCityName == nil && r.ToAddress == nil &&
+		r.Price == nil && r.AvailableFrom == nil &&
+		r.CargoType == nil && r.TemperatureRange == nil && r.ADRClass == nil &&
+		r.Oversize == nil && r.Fragile == nil
 }
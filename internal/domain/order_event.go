@@ -0,0 +1,17 @@
+package domain
+
+// Виды событий ленты заказов
+const (
+	OrderEventCreated = "created" // Заказ появился среди активных: создан или снова активирован
+	OrderEventUpdated = "updated" // Активный заказ изменен
+	OrderEventRemoved = "removed" // Заказ больше не активен: назначен, доставлен, архивирован, истек или удален
+	OrderEventResync  = "resync"  // События могли потеряться при разрыве соединения: список нужно загрузить заново
+)
+
+// OrderEvent представляет изменение в ленте активных заказов. События публикует триггер
+// базы данных, поэтому их видят все экземпляры приложения.
+type OrderEvent struct {
+	Type      string `json:"type"`
+	OrderUUID string `json:"uuid"`
+	Status    string `json:"status"`
+}
@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Порядок сортировки заказов
+const (
+	OrderSortNewest     = "newest"       // Сначала новые (по умолчанию)
+	OrderSortPricePerKm = "price_per_km" // Сначала самые выгодные по ставке за километр
+	OrderSortRelevance  = "relevance"    // Сначала лучше подходящие под поисковый запрос (по умолчанию при поиске)
+)
+
+// OrderFilter описывает фильтры списка заказов: GET /v1/orders, выгрузки и команды экспорта.
+// Пустые поля не ограничивают выборку.
+type OrderFilter struct {
+	MinWeight *float64
+	MaxWeight *float64
+
+	FromCity string // Город погрузки (название, альтернативное название или UUID)
+	ToCity   string // Город выгрузки
+
+	MinPrice *float64
+	MaxPrice *float64
+
+	PickupFrom *time.Time // Дата погрузки не раньше (заказы без даты не подходят)
+	PickupTo   *time.Time // Дата погрузки раньше (не включительно)
+
+	// Погрузка и/или выгрузка (Direction) не дальше RadiusKm от города NearCity
+	NearCity  string
+	RadiusKm  int
+	Direction string
+
+	CargoType    string   // Код, название или UUID типа груза
+	Requirements []string // Коды особых требований
+
+	MinPricePerKm *float64
+	MaxPricePerKm *float64
+
+	// Полнотекстовый поиск по названию, описанию, адресам, городам и имени заказчика
+	Query string
+
+	Status      string
+	CreatedFrom *time.Time // Включительно
+	CreatedTo   *time.Time // Не включительно
+	Sort        string
+}
+
+// SearchWords разбивает поисковый запрос на слова из букв и цифр в нижнем регистре.
+// Ё заменяется на е, как и в поисковом индексе заказов.
+func SearchWords(query string) []string {
+	query = strings.NewReplacer("ё", "е", "Ё", "Е").Replace(strings.ToLower(query))
+	return strings.FieldsFunc(query, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
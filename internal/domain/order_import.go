@@ -0,0 +1,39 @@
+package domain
+
+// Поля заказа, которые можно сопоставить колонкам импортируемой таблицы
+const (
+	ImportFieldTitle       = "title"
+	ImportFieldDescription = "description"
+	ImportFieldWeight      = "weight"
+	ImportFieldFromCity    = "from_city"
+	ImportFieldFromAddress = "from_address"
+	ImportFieldToCity      = "to_city"
+	ImportFieldToAddress   = "to_address"
+	ImportFieldPrice       = "price"
+	ImportFieldDate        = "date"
+	ImportFieldCargoType   = "cargo_type"
+	ImportFieldTemperature = "temperature"
+	ImportFieldADR         = "adr"
+	ImportFieldTags        = "tags"
+	ImportFieldLength      = "length"
+	ImportFieldWidth       = "width"
+	ImportFieldHeight      = "height"
+	ImportFieldOversize    = "oversize"
+	ImportFieldFragile     = "fragile"
+)
+
+// OrderImportRowError описывает ошибку в строке импортируемой таблицы
+type OrderImportRowError struct {
+	Row     int // Номер строки в таблице, начиная с 1 (с учетом заголовка)
+	Message string
+}
+
+// OrderImport представляет проверенный, но еще не сохраненный импорт заказов
+type OrderImport struct {
+	CustomerUUID  string
+	Orders        []*Order              // Заказы из строк без ошибок
+	Errors        []OrderImportRowError // Строки, которые не будут импортированы
+	UnknownCities []string              // Названия, не найденные в справочнике городов
+	TotalRows     int                   // Непустые строки данных
+	Columns       map[string]string     // Поле заказа → заголовок колонки
+}
@@ -0,0 +1,17 @@
+package domain
+
+// ContactViewer описывает, кто смотрит заказ: от этого зависит, видны ли контакты заказчика
+type ContactViewer struct {
+	Admin            bool  // Администратор (админский бот или ключ администратора API)
+	DriverTelegramID int64 // Водитель, которому показывается заказ; 0 — анонимный посетитель
+}
+
+// AdminViewer возвращает получателя с полным доступом к контактам
+func AdminViewer() ContactViewer {
+	return ContactViewer{Admin: true}
+}
+
+// DriverViewer возвращает получателя-водителя по Telegram ID
+func DriverViewer(telegramID int64) ContactViewer {
+	return ContactViewer{DriverTelegramID: telegramID}
+}
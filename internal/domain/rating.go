@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"time"
+)
+
+// Источники оценки водителя
+const (
+	RatingSourceBot = "bot" // Кнопки в админском боте
+	RatingSourceWeb = "web" // Подписанная ссылка на сайте
+)
+
+// Допустимые оценки водителя
+const (
+	MinRatingScore = 1
+	MaxRatingScore = 5
+)
+
+// DriverRating представляет оценку водителя заказчиком после доставки заказа
+type DriverRating struct {
+	UUID         string    `json:"uuid"`
+	OrderUUID    string    `json:"order_uuid"`
+	DriverUUID   string    `json:"driver_uuid"`
+	CustomerUUID string    `json:"customer_uuid"`
+	Score        int       `json:"score"`
+	Comment      *string   `json:"comment"`
+	Source       string    `json:"source"`
+	CreatedAt    time.Time `json:"created_at"`
+	OrderTitle   string    `json:"order_title"`
+	CustomerName string    `json:"customer_name"`
+}
+
+// RateDriverRequest представляет оценку водителя по доставленному заказу
+type RateDriverRequest struct {
+	OrderUUID  string
+	Score      int
+	Comment    string
+	Source     string
+	TelegramID *int64 // Если задан, заказ должен принадлежать заказчику с этим Telegram ID
+}
+
+// NotificationPriority задает очередность уведомлений о новых заказах: водители с рейтингом
+// не ниже MinRating получают заказ сразу, остальные — спустя Delay
+type NotificationPriority struct {
+	MinRating float64       `json:"min_rating"` // 0 — приоритет отключен
+	Delay     time.Duration `json:"delay"`
+}
+
+// Enabled сообщает, включена ли приоритетная рассылка
+func (p NotificationPriority) Enabled() bool {
+	return p.MinRating > 0 && p.Delay > 0
+}
+
+// IsPriority сообщает, получает ли водитель уведомления в первую очередь
+func (p NotificationPriority) IsPriority(driver *Driver) bool {
+	return driver.RatingAvg != nil && *driver.RatingAvg >= p.MinRating
+}
@@ -13,7 +13,9 @@ type OrderRepository interface {
 	GetOrdersByWeightRange(minWeight, maxWeight *float64) ([]Order, error)
 	GetOrdersCount() (int, error)
 	GetActiveOrdersCount() (int, error)
+	GetOrderByUUID(orderUUID string) (*Order, error)
 	UpdateOrderStatus(orderUUID string, status string) error
+	UpdateOrder(order *Order) error
 }
 
 // CustomerRepository определяет интерфейс для работы с заказчиками
@@ -21,8 +23,13 @@ type CustomerRepository interface {
 	CreateCustomer(customer *Customer) error
 	GetCustomerByPhone(phone string) (*Customer, error)
 	GetCustomerByTelegramID(telegramID int64) (*Customer, error)
+	GetCustomerByUUID(customerUUID uuid.UUID) (*Customer, error)
 	GetAllCustomers() ([]Customer, error)
 	GetCustomersCount() (int, error)
+	UpdateCustomer(customer *Customer) error
+	SetCustomerActive(customerUUID uuid.UUID, active bool) error
+	MergeCustomers(duplicateUUID, targetUUID uuid.UUID) (int64, error)
+	GetOrdersByCustomer(customerUUID uuid.UUID) ([]Order, error)
 }
 
 // DriverRepository определяет интерфейс для работы с водителями
@@ -37,4 +44,16 @@ type DriverRepository interface {
 type CityRepository interface {
 	GetCityByName(cityName string) (*City, error)
 	GetCityByUUID(cityUUID uuid.UUID) (*City, error)
+	GetAllCities() ([]City, error)
+	CreateCity(city *City) error
+	UpdateCity(city *City) error
+	CountCityReferences(cityUUID uuid.UUID) (int, error)
+	DeleteCity(cityUUID uuid.UUID) error
+}
+
+// CargoTypeRepository определяет интерфейс для работы со справочником типов грузов
+type CargoTypeRepository interface {
+	GetAllCargoTypes() ([]CargoType, error)
+	CreateCargoType(cargoType *CargoType) error
+	UpdateCargoType(cargoType *CargoType) error
 }
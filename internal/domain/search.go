@@ -0,0 +1,32 @@
+package domain
+
+import (
+	"time"
+)
+
+// SavedSearch представляет сохраненный поиск водителя: заказы с погрузкой и/или выгрузкой
+// в пределах радиуса от города и ценой не ниже заданной. Используется в ежедневной сводке.
+type SavedSearch struct {
+	UUID       string    `json:"uuid"`
+	DriverUUID string    `json:"driver_uuid"`
+	CityUUID   string    `json:"city_uuid"`
+	CityName   string    `json:"city_name"`
+	RadiusKm   int       `json:"radius_km"`
+	Direction  string    `json:"direction"` // pickup, delivery или any
+	MinPrice   *float64  `json:"min_price"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// ShortID возвращает короткий номер поиска для команд бота
+func (s *SavedSearch) ShortID() string {
+	return s.UUID[:8]
+}
+
+// SavedSearchRequest представляет запрос на сохранение поиска
+type SavedSearchRequest struct {
+	DriverTelegramID int64
+	CityName         string
+	RadiusKm         int
+	Direction        string
+	MinPrice         *float64
+}
@@ -0,0 +1,130 @@
+package domain
+
+import (
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Типы кузова
+const (
+	BodyTypeTent         = "tent"
+	BodyTypeRefrigerator = "refrigerator"
+	BodyTypeIsotherm     = "isotherm"
+	BodyTypeVan          = "van"
+	BodyTypeFlatbed      = "flatbed"
+	BodyTypeContainer    = "container"
+	BodyTypeTipper       = "tipper"
+	BodyTypeLowboy       = "lowboy"
+	BodyTypeCarCarrier   = "car_carrier"
+	BodyTypeTanker       = "tanker"
+)
+
+// BodyTypes перечисляет типы кузова в порядке отображения
+var BodyTypes = []string{
+	BodyTypeTent,
+	BodyTypeRefrigerator,
+	BodyTypeIsotherm,
+	BodyTypeVan,
+	BodyTypeFlatbed,
+	BodyTypeContainer,
+	BodyTypeTipper,
+	BodyTypeLowboy,
+	BodyTypeCarCarrier,
+	BodyTypeTanker,
+}
+
+// BodyTypeNames содержит русские названия типов кузова
+var BodyTypeNames = map[string]string{
+	BodyTypeTent:         "Тент",
+	BodyTypeRefrigerator: "Рефрижератор",
+	BodyTypeIsotherm:     "Изотерм",
+	BodyTypeVan:          "Цельнометаллический фургон",
+	BodyTypeFlatbed:      "Бортовой",
+	BodyTypeContainer:    "Контейнеровоз",
+	BodyTypeTipper:       "Самосвал",
+	BodyTypeLowboy:       "Трал (низкорамник)",
+	BodyTypeCarCarrier:   "Автовоз",
+	BodyTypeTanker:       "Цистерна",
+}
+
+// Vehicle представляет транспортное средство водителя
+type Vehicle struct {
+	UUID       uuid.UUID `json:"uuid"`
+	DriverUUID uuid.UUID `json:"driver_uuid"`
+	BodyType   string    `json:"body_type"`
+	PayloadKg  float64   `json:"payload_kg"`
+	VolumeM3   *float64  `json:"volume_m3"`
+	LengthCm   *float64  `json:"length_cm"` // Внутренние размеры кузова
+	WidthCm    *float64  `json:"width_cm"`
+	HeightCm   *float64  `json:"height_cm"`
+	ADR        bool      `json:"adr"` // Допуск к перевозке опасных грузов
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// VehicleRequest представляет изменение транспорта водителя.
+// Поля со значением nil не меняются, 0 очищает необязательные характеристики.
+type VehicleRequest struct {
+	BodyType  *string  `json:"body_type"`
+	PayloadKg *float64 `json:"payload_kg"`
+	VolumeM3  *float64 `json:"volume_m3"`
+	LengthCm  *float64 `json:"length_cm"`
+	WidthCm   *float64 `json:"width_cm"`
+	HeightCm  *float64 `json:"height_cm"`
+	ADR       *bool    `json:"adr"`
+}
+
+// CanCarry проверяет, помещается ли груз заказа в транспорт: вес, объем и габариты.
+// Габариты сравниваются с учетом поворота груза. Неизвестные характеристики не ограничивают.
+func (v *Vehicle) CanCarry(order *Order) bool {
+	if order.WeightKg > v.PayloadKg {
+		return false
+	}
+
+	// Негабарит по определению выходит за размеры кузова и везется с выступом
+	if order.Requirements.Oversize {
+		return true
+	}
+
+	if order.LengthCm == nil || order.WidthCm == nil || order.HeightCm == nil {
+		return true
+	}
+
+	if v.VolumeM3 != nil {
+		volume := *order.LengthCm * *order.WidthCm * *order.HeightCm / 1e6
+		if volume > *v.VolumeM3 {
+			return false
+		}
+	}
+
+	if v.LengthCm != nil && v.WidthCm != nil && v.HeightCm != nil {
+		cargo := []float64{*order.LengthCm, *order.WidthCm, *order.HeightCm}
+		body := []float64{*v.LengthCm, *v.WidthCm, *v.HeightCm}
+		sort.Sort(sort.Reverse(sort.Float64Slice(cargo)))
+		sort.Sort(sort.Reverse(sort.Float64Slice(body)))
+		for i := range cargo {
+			if cargo[i] > body[i] {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// MeetsRequirements проверяет, подходит ли транспорт под особые требования груза:
+// температурный режим — только рефрижератор, опасный груз — только с допуском ДОПОГ,
+// негабарит — только открытые площадки (бортовой или трал)
+func (v *Vehicle) MeetsRequirements(requirements *CargoRequirements) bool {
+	if requirements.Refrigerated() && v.BodyType != BodyTypeRefrigerator {
+		return false
+	}
+	if requirements.ADRClass != nil && !v.ADR {
+		return false
+	}
+	if requirements.Oversize && v.BodyType != BodyTypeFlatbed && v.BodyType != BodyTypeLowboy {
+		return false
+	}
+	return true
+}
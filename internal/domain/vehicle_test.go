@@ -0,0 +1,79 @@
+package domain
+
+import "testing"
+
+func TestVehicleCanCarry(t *testing.T) {
+	float := func(value float64) *float64 { return &value }
+
+	// Еврофура: 20 т, 82 м³, кузов 13,6 × 2,45 × 2,45 м
+	truck := &Vehicle{
+		BodyType:  BodyTypeTent,
+		PayloadKg: 20000,
+		VolumeM3:  float(82),
+		LengthCm:  float(1360),
+		WidthCm:   float(245),
+		HeightCm:  float(245),
+	}
+	// Газель без известных объема и размеров
+	small := &Vehicle{BodyType: BodyTypeVan, PayloadKg: 1500}
+
+	tests := []struct {
+		name    string
+		vehicle *Vehicle
+		order   Order
+		want    bool
+	}{
+		{"вес в пределах", truck, Order{WeightKg: 20000}, true},
+		{"перегруз", truck, Order{WeightKg: 20001}, false},
+		{"без размеров груза", truck, Order{WeightKg: 1000, LengthCm: float(2000)}, true},
+		{"помещается", truck, Order{WeightKg: 1000, LengthCm: float(600), WidthCm: float(240), HeightCm: float(200)}, true},
+		{"помещается с поворотом", truck, Order{WeightKg: 1000, LengthCm: float(200), WidthCm: float(1200), HeightCm: float(240)}, true},
+		{"длиннее кузова", truck, Order{WeightKg: 1000, LengthCm: float(1400), WidthCm: float(100), HeightCm: float(100)}, false},
+		{"шире кузова при любом повороте", truck, Order{WeightKg: 1000, LengthCm: float(300), WidthCm: float(250), HeightCm: float(250)}, false},
+		{"больше объема", &Vehicle{PayloadKg: 20000, VolumeM3: float(10)}, Order{WeightKg: 1000, LengthCm: float(300), WidthCm: float(200), HeightCm: float(200)}, false},
+		{"негабарит не ограничен размерами", truck, Order{WeightKg: 1000, LengthCm: float(2000), WidthCm: float(300), HeightCm: float(300),
+			Requirements: CargoRequirements{Oversize: true}}, true},
+		{"негабарит ограничен весом", truck, Order{WeightKg: 25000, Requirements: CargoRequirements{Oversize: true}}, false},
+		{"неизвестные размеры кузова", small, Order{WeightKg: 1500, LengthCm: float(1000), WidthCm: float(300), HeightCm: float(300)}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.vehicle.CanCarry(&tt.order); got != tt.want {
+				t.Fatalf("CanCarry() = %v, ожидалось %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVehicleMeetsRequirements(t *testing.T) {
+	temperature := 2.0
+	adrClass := "3"
+
+	tests := []struct {
+		name         string
+		vehicle      Vehicle
+		requirements CargoRequirements
+		want         bool
+	}{
+		{"без требований", Vehicle{BodyType: BodyTypeTent}, CargoRequirements{}, true},
+		{"хрупкий груз в любом кузове", Vehicle{BodyType: BodyTypeTent}, CargoRequirements{Fragile: true}, true},
+		{"температура в рефрижераторе", Vehicle{BodyType: BodyTypeRefrigerator}, CargoRequirements{TemperatureMinC: &temperature}, true},
+		{"температура в изотерме", Vehicle{BodyType: BodyTypeIsotherm}, CargoRequirements{TemperatureMaxC: &temperature}, false},
+		{"ADR с допуском", Vehicle{BodyType: BodyTypeTanker, ADR: true}, CargoRequirements{ADRClass: &adrClass}, true},
+		{"ADR без допуска", Vehicle{BodyType: BodyTypeTanker}, CargoRequirements{ADRClass: &adrClass}, false},
+		{"негабарит на борту", Vehicle{BodyType: BodyTypeFlatbed}, CargoRequirements{Oversize: true}, true},
+		{"негабарит на трале", Vehicle{BodyType: BodyTypeLowboy}, CargoRequirements{Oversize: true}, true},
+		{"негабарит в тенте", Vehicle{BodyType: BodyTypeTent}, CargoRequirements{Oversize: true}, false},
+		{"реф с опасным грузом без допуска", Vehicle{BodyType: BodyTypeRefrigerator},
+			CargoRequirements{TemperatureMinC: &temperature, ADRClass: &adrClass}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.vehicle.MeetsRequirements(&tt.requirements); got != tt.want {
+				t.Fatalf("MeetsRequirements() = %v, ожидалось %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package geo
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+//go:embed data/ru_cities.csv
+var ruCitiesCSV string
+
+// Place представляет населенный пункт из встроенного справочника
+type Place struct {
+	Name   string
+	Region string
+	Point  Point
+}
+
+// BundledRussianCities возвращает встроенный офлайн-справочник крупных городов России
+func BundledRussianCities() ([]Place, error) {
+	reader := csv.NewReader(strings.NewReader(ruCitiesCSV))
+	reader.Comment = '#'
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения справочника городов: %v", err)
+	}
+
+	places := make([]Place, 0, len(records))
+	for i, record := range records {
+		if i == 0 {
+			continue // Заголовок
+		}
+		if len(record) != 4 {
+			return nil, fmt.Errorf("строка %d справочника городов: ожидается 4 поля", i+1)
+		}
+
+		lat, err := strconv.ParseFloat(record[2], 64)
+		if err != nil {
+			return nil, fmt.Errorf("строка %d справочника городов: некорректная широта: %v", i+1, err)
+		}
+		lon, err := strconv.ParseFloat(record[3], 64)
+		if err != nil {
+			return nil, fmt.Errorf("строка %d справочника городов: некорректная долгота: %v", i+1, err)
+		}
+
+		places = append(places, Place{
+			Name:   record[0],
+			Region: record[1],
+			Point:  Point{Latitude: lat, Longitude: lon},
+		})
+	}
+
+	return places, nil
+}
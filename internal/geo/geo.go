@@ -0,0 +1,43 @@
+package geo
+
+import "math"
+
+// earthRadiusKm средний радиус Земли в километрах
+const earthRadiusKm = 6371.0
+
+// RoadCoefficient переводит расстояние по прямой в оценку пути по дорогам:
+// для междугородних маршрутов в России дорога в среднем на четверть длиннее
+const RoadCoefficient = 1.25
+
+// Point представляет географическую точку в градусах
+type Point struct {
+	Latitude  float64
+	Longitude float64
+}
+
+// DistanceKm возвращает расстояние по дуге большого круга между точками (формула гаверсинуса)
+func DistanceKm(a, b Point) float64 {
+	lat1 := degreesToRadians(a.Latitude)
+	lat2 := degreesToRadians(b.Latitude)
+	dLat := lat2 - lat1
+	dLon := degreesToRadians(b.Longitude - a.Longitude)
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// EstimateRoadDistanceKm оценивает расстояние по дорогам между точками
+func EstimateRoadDistanceKm(a, b Point) float64 {
+	return DistanceKm(a, b) * RoadCoefficient
+}
+
+// ValidPoint проверяет, что координаты находятся в допустимых пределах
+func ValidPoint(p Point) bool {
+	return p.Latitude >= -90 && p.Latitude <= 90 && p.Longitude >= -180 && p.Longitude <= 180
+}
+
+func degreesToRadians(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
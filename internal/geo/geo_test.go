@@ -0,0 +1,68 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+var (
+	moscow      = Point{Latitude: 55.7558, Longitude: 37.6173}
+	petersburg  = Point{Latitude: 59.9343, Longitude: 30.3351}
+	kazan       = Point{Latitude: 55.7963, Longitude: 49.1088}
+	vladivostok = Point{Latitude: 43.1155, Longitude: 131.8855}
+)
+
+func TestDistanceKm(t *testing.T) {
+	tests := []struct {
+		name      string
+		a, b      Point
+		want      float64
+		tolerance float64
+	}{
+		{"Москва — Санкт-Петербург", moscow, petersburg, 634, 2},
+		{"Москва — Казань", moscow, kazan, 718, 2},
+		{"Москва — Владивосток", moscow, vladivostok, 6415, 5},
+		{"одна и та же точка", moscow, moscow, 0, 1e-9},
+		{"нулевые координаты", Point{}, Point{}, 0, 1e-9},
+		{"через линию перемены дат", Point{Longitude: 179.5}, Point{Longitude: -179.5}, 111.2, 0.1},
+		{"противоположные точки", Point{}, Point{Longitude: 180}, math.Pi * earthRadiusKm, 1e-6},
+	}
+
+	for _, tt := range tests {
+		if got := DistanceKm(tt.a, tt.b); math.Abs(got-tt.want) > tt.tolerance {
+			t.Errorf("%s: DistanceKm = %.2f, ожидалось %.2f ± %g", tt.name, got, tt.want, tt.tolerance)
+		}
+		if forward, back := DistanceKm(tt.a, tt.b), DistanceKm(tt.b, tt.a); math.Abs(forward-back) > 1e-9 {
+			t.Errorf("%s: расстояние зависит от направления: %.6f и %.6f", tt.name, forward, back)
+		}
+	}
+}
+
+func TestEstimateRoadDistanceKm(t *testing.T) {
+	straight := DistanceKm(moscow, petersburg)
+	if got := EstimateRoadDistanceKm(moscow, petersburg); math.Abs(got-straight*RoadCoefficient) > 1e-9 {
+		t.Errorf("EstimateRoadDistanceKm = %.2f, ожидалось %.2f", got, straight*RoadCoefficient)
+	}
+	if got := EstimateRoadDistanceKm(moscow, moscow); got != 0 {
+		t.Errorf("EstimateRoadDistanceKm для одной точки = %.2f, ожидалось 0", got)
+	}
+}
+
+func TestValidPoint(t *testing.T) {
+	tests := []struct {
+		point Point
+		want  bool
+	}{
+		{moscow, true},
+		{Point{Latitude: 90, Longitude: 180}, true},
+		{Point{Latitude: -90, Longitude: -180}, true},
+		{Point{Latitude: 90.1, Longitude: 0}, false},
+		{Point{Latitude: 0, Longitude: -180.1}, false},
+	}
+
+	for _, tt := range tests {
+		if got := ValidPoint(tt.point); got != tt.want {
+			t.Errorf("ValidPoint(%+v) = %v, ожидалось %v", tt.point, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package geo
+
+import "strings"
+
+// regionTimezones сопоставляет субъекты России с часовыми поясами IANA. Для регионов с несколькими
+// поясами (Якутия) указан пояс столицы региона.
+var regionTimezones = map[string]string{
+	// МСК−1
+	"калининградская область": "Europe/Kaliningrad",
+
+	// МСК
+	"москва":                              "Europe/Moscow",
+	"московская область":                  "Europe/Moscow",
+	"санкт-петербург":                     "Europe/Moscow",
+	"ленинградская область":               "Europe/Moscow",
+	"севастополь":                         "Europe/Simferopol",
+	"республика крым":                     "Europe/Simferopol",
+	"архангельская область":               "Europe/Moscow",
+	"ненецкий автономный округ":           "Europe/Moscow",
+	"белгородская область":                "Europe/Moscow",
+	"брянская область":                    "Europe/Moscow",
+	"владимирская область":                "Europe/Moscow",
+	"вологодская область":                 "Europe/Moscow",
+	"воронежская область":                 "Europe/Moscow",
+	"ивановская область":                  "Europe/Moscow",
+	"калужская область":                   "Europe/Moscow",
+	"кировская область":                   "Europe/Kirov",
+	"костромская область":                 "Europe/Moscow",
+	"курская область":                     "Europe/Moscow",
+	"липецкая область":                    "Europe/Moscow",
+	"мурманская область":                  "Europe/Moscow",
+	"нижегородская область":               "Europe/Moscow",
+	"новгородская область":                "Europe/Moscow",
+	"орловская область":                   "Europe/Moscow",
+	"пензенская область":                  "Europe/Moscow",
+	"псковская область":                   "Europe/Moscow",
+	"ростовская область":                  "Europe/Moscow",
+	"рязанская область":                   "Europe/Moscow",
+	"смоленская область":                  "Europe/Moscow",
+	"тамбовская область":                  "Europe/Moscow",
+	"тверская область":                    "Europe/Moscow",
+	"тульская область":                    "Europe/Moscow",
+	"ярославская область":                 "Europe/Moscow",
+	"волгоградская область":               "Europe/Volgograd",
+	"краснодарский край":                  "Europe/Moscow",
+	"ставропольский край":                 "Europe/Moscow",
+	"республика адыгея":                   "Europe/Moscow",
+	"республика дагестан":                 "Europe/Moscow",
+	"республика ингушетия":                "Europe/Moscow",
+	"кабардино-балкарская республика":     "Europe/Moscow",
+	"республика калмыкия":                 "Europe/Moscow",
+	"карачаево-черкесская республика":     "Europe/Moscow",
+	"республика карелия":                  "Europe/Moscow",
+	"республика коми":                     "Europe/Moscow",
+	"республика марий эл":                 "Europe/Moscow",
+	"республика мордовия":                 "Europe/Moscow",
+	"республика северная осетия — алания": "Europe/Moscow",
+	"республика татарстан":                "Europe/Moscow",
+	"чеченская республика":                "Europe/Moscow",
+	"чувашская республика":                "Europe/Moscow",
+	"донецкая народная республика":        "Europe/Moscow",
+	"луганская народная республика":       "Europe/Moscow",
+	"запорожская область":                 "Europe/Moscow",
+	"херсонская область":                  "Europe/Moscow",
+
+	// МСК+1
+	"астраханская область":  "Europe/Astrakhan",
+	"самарская область":     "Europe/Samara",
+	"саратовская область":   "Europe/Saratov",
+	"ульяновская область":   "Europe/Ulyanovsk",
+	"удмуртская республика": "Europe/Samara",
+
+	// МСК+2
+	"свердловская область":                     "Asia/Yekaterinburg",
+	"челябинская область":                      "Asia/Yekaterinburg",
+	"курганская область":                       "Asia/Yekaterinburg",
+	"тюменская область":                        "Asia/Yekaterinburg",
+	"оренбургская область":                     "Asia/Yekaterinburg",
+	"пермский край":                            "Asia/Yekaterinburg",
+	"республика башкортостан":                  "Asia/Yekaterinburg",
+	"ханты-мансийский автономный округ — югра": "Asia/Yekaterinburg",
+	"ямало-ненецкий автономный округ":          "Asia/Yekaterinburg",
+
+	// МСК+3
+	"омская область": "Asia/Omsk",
+
+	// МСК+4
+	"новосибирская область": "Asia/Novosibirsk",
+	"томская область":       "Asia/Tomsk",
+	"кемеровская область":   "Asia/Novokuznetsk",
+	"алтайский край":        "Asia/Barnaul",
+	"республика алтай":      "Asia/Barnaul",
+	"красноярский край":     "Asia/Krasnoyarsk",
+	"республика тыва":       "Asia/Krasnoyarsk",
+	"республика хакасия":    "Asia/Krasnoyarsk",
+
+	// МСК+5
+	"иркутская область":  "Asia/Irkutsk",
+	"республика бурятия": "Asia/Irkutsk",
+
+	// МСК+6
+	"забайкальский край":       "Asia/Chita",
+	"амурская область":         "Asia/Yakutsk",
+	"республика саха (якутия)": "Asia/Yakutsk",
+
+	// МСК+7
+	"приморский край":              "Asia/Vladivostok",
+	"хабаровский край":             "Asia/Vladivostok",
+	"еврейская автономная область": "Asia/Vladivostok",
+
+	// МСК+8
+	"магаданская область": "Asia/Magadan",
+	"сахалинская область": "Asia/Sakhalin",
+
+	// МСК+9
+	"камчатский край":            "Asia/Kamchatka",
+	"чукотский автономный округ": "Asia/Kamchatka",
+}
+
+// RegionTimezone возвращает часовой пояс IANA субъекта России или пустую строку, если регион неизвестен
+func RegionTimezone(region string) string {
+	key := strings.ToLower(strings.TrimSpace(region))
+	key = strings.ReplaceAll(key, "ё", "е")
+	// В регионах встречаются и длинное тире, и дефис: "Северная Осетия — Алания"
+	if zone, ok := regionTimezones[key]; ok {
+		return zone
+	}
+	return regionTimezones[strings.ReplaceAll(key, " - ", " — ")]
+}
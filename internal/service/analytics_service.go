@@ -0,0 +1,254 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"time"
+
+	"dalnoboy/internal/database"
+	"dalnoboy/internal/domain"
+)
+
+const (
+	// analyticsDefaultDays — период аналитики без параметров from и to
+	analyticsDefaultDays = 30
+	// analyticsMaxDays ограничивает период, чтобы график по дням оставался читаемым
+	analyticsMaxDays = 366
+	// analyticsDefaultLimit и analyticsMaxLimit ограничивают списки направлений и водителей
+	analyticsDefaultLimit = 10
+	analyticsMaxLimit     = 100
+	// summaryTopLimit — сколько направлений и водителей попадает в еженедельную сводку
+	summaryTopLimit = 5
+)
+
+// AnalyticsNotifier доставляет администраторам сводку аналитики (админский бот)
+type AnalyticsNotifier interface {
+	SendAnalyticsSummary(chatID int64, summary *domain.AnalyticsSummary) error
+}
+
+// AnalyticsService представляет сервис аналитики по заказам и водителям: объемы по дням,
+// время до назначения водителя, популярные направления, средние цены и активность водителей
+type AnalyticsService struct {
+	database     *database.Database
+	adminChatIDs []int64
+	notifier     AnalyticsNotifier
+}
+
+// NewAnalyticsService создает новый экземпляр сервиса аналитики
+func NewAnalyticsService(db *database.Database, adminChatIDs []int64) *AnalyticsService {
+	return &AnalyticsService{
+		database:     db,
+		adminChatIDs: adminChatIDs,
+	}
+}
+
+// SetNotifier задает канал доставки еженедельной сводки (админский бот создается позже сервисов)
+func (as *AnalyticsService) SetNotifier(notifier AnalyticsNotifier) {
+	as.notifier = notifier
+}
+
+// ParseAnalyticsPeriod разбирает период ГГГГ-ММ-ДД, обе даты включительно.
+// Без дат возвращает последние 30 дней, включая сегодняшний.
+func ParseAnalyticsPeriod(from, to string, now time.Time) (domain.AnalyticsPeriod, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	period := domain.AnalyticsPeriod{To: today.AddDate(0, 0, 1)}
+
+	if to != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", to, time.Local)
+		if err != nil {
+			return period, newValidationError("некорректная дата '%s', ожидается ГГГГ-ММ-ДД", to)
+		}
+		period.To = parsed.AddDate(0, 0, 1)
+	}
+	period.From = period.To.AddDate(0, 0, -analyticsDefaultDays)
+	if from != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", from, time.Local)
+		if err != nil {
+			return period, newValidationError("некорректная дата '%s', ожидается ГГГГ-ММ-ДД", from)
+		}
+		period.From = parsed
+	}
+
+	if !period.From.Before(period.To) {
+		return period, newValidationError("начало периода должно быть не позже конца")
+	}
+	if period.To.Sub(period.From) > analyticsMaxDays*24*time.Hour+time.Hour { // Час на переход на летнее время
+		return period, newValidationError("период аналитики не может быть длиннее %d дней", analyticsMaxDays)
+	}
+	return period, nil
+}
+
+// ParseAnalyticsLimit разбирает размер списка направлений или водителей; пустое значение — 10
+func ParseAnalyticsLimit(value string) (int, error) {
+	if value == "" {
+		return analyticsDefaultLimit, nil
+	}
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit <= 0 || limit > analyticsMaxLimit {
+		return 0, newValidationError("limit должен быть числом от 1 до %d", analyticsMaxLimit)
+	}
+	return limit, nil
+}
+
+// WeeklyPeriod возвращает прошедшую неделю: семь полных дней до начала сегодняшнего
+func WeeklyPeriod(now time.Time) domain.AnalyticsPeriod {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	return domain.AnalyticsPeriod{From: today.AddDate(0, 0, -7), To: today}
+}
+
+// GetDailyVolume возвращает число созданных, назначенных и доставленных заказов по дням периода
+func (as *AnalyticsService) GetDailyVolume(period domain.AnalyticsPeriod) ([]domain.DailyVolume, error) {
+	volume, err := as.database.GetDailyVolume(period.From, period.To)
+	if err != nil {
+		return nil, err
+	}
+	if volume == nil {
+		volume = []domain.DailyVolume{}
+	}
+	return volume, nil
+}
+
+// GetAssignmentTime возвращает медиану времени до назначения водителя и конверсию созданных заказов
+func (as *AnalyticsService) GetAssignmentTime(period domain.AnalyticsPeriod) (*domain.AssignmentTime, error) {
+	result, err := as.database.GetAssignmentTime(period.From, period.To)
+	if err != nil {
+		return nil, err
+	}
+	result.MedianHours = roundAnalytics(result.MedianHours)
+	result.AverageHours = roundAnalytics(result.AverageHours)
+	if result.Created > 0 {
+		conversion := float64(result.Converted) / float64(result.Created)
+		result.Conversion = roundAnalytics(&conversion)
+	}
+	return result, nil
+}
+
+// GetTopRoutes возвращает самые частые направления между городами
+func (as *AnalyticsService) GetTopRoutes(period domain.AnalyticsPeriod, limit int) ([]domain.RouteStats, error) {
+	routes, err := as.database.GetTopRoutes(period.From, period.To, limit)
+	if err != nil {
+		return nil, err
+	}
+	if routes == nil {
+		routes = []domain.RouteStats{}
+	}
+	for i := range routes {
+		routes[i].AvgPrice = math.Round(routes[i].AvgPrice)
+		routes[i].AvgPricePerKm = roundAnalytics(routes[i].AvgPricePerKm)
+	}
+	return routes, nil
+}
+
+// GetPriceStats возвращает среднюю цену заказа, килограмма и километра
+func (as *AnalyticsService) GetPriceStats(period domain.AnalyticsPeriod) (*domain.PriceStats, error) {
+	stats, err := as.database.GetPriceStats(period.From, period.To)
+	if err != nil {
+		return nil, err
+	}
+	stats.AvgPrice = roundAnalytics(stats.AvgPrice)
+	stats.AvgPricePerKg = roundAnalytics(stats.AvgPricePerKg)
+	stats.AvgPricePerKm = roundAnalytics(stats.AvgPricePerKm)
+	return stats, nil
+}
+
+// GetDriverActivity возвращает самых активных водителей периода и общее число активных водителей
+func (as *AnalyticsService) GetDriverActivity(period domain.AnalyticsPeriod, limit int) ([]domain.DriverActivity, int, error) {
+	activity, err := as.database.GetDriverActivity(period.From, period.To)
+	if err != nil {
+		return nil, 0, err
+	}
+	total := len(activity)
+	if len(activity) > limit {
+		activity = activity[:limit]
+	}
+	if activity == nil {
+		activity = []domain.DriverActivity{}
+	}
+	for i := range activity {
+		activity[i].RatingAvg = roundAnalytics(activity[i].RatingAvg)
+	}
+	return activity, total, nil
+}
+
+// GetDriverSources возвращает новых водителей периода по источникам привлечения
+func (as *AnalyticsService) GetDriverSources(period domain.AnalyticsPeriod) ([]domain.DriverSourceStats, error) {
+	sources, err := as.database.GetDriverSources(period.From, period.To)
+	if err != nil {
+		return nil, err
+	}
+	if sources == nil {
+		sources = []domain.DriverSourceStats{}
+	}
+	return sources, nil
+}
+
+// GetSummary собирает сводку аналитики за период
+func (as *AnalyticsService) GetSummary(period domain.AnalyticsPeriod) (*domain.AnalyticsSummary, error) {
+	summary := &domain.AnalyticsSummary{Period: period}
+
+	volume, err := as.GetDailyVolume(period)
+	if err != nil {
+		return nil, err
+	}
+	for _, day := range volume {
+		summary.Created += day.Created
+		summary.Assigned += day.Assigned
+		summary.Delivered += day.Delivered
+	}
+
+	assignment, err := as.GetAssignmentTime(period)
+	if err != nil {
+		return nil, err
+	}
+	summary.Assignment = *assignment
+
+	prices, err := as.GetPriceStats(period)
+	if err != nil {
+		return nil, err
+	}
+	summary.Prices = *prices
+
+	if summary.TopRoutes, err = as.GetTopRoutes(period, summaryTopLimit); err != nil {
+		return nil, err
+	}
+	if summary.TopDrivers, summary.ActiveDrivers, err = as.GetDriverActivity(period, summaryTopLimit); err != nil {
+		return nil, err
+	}
+	if summary.DriverSources, err = as.GetDriverSources(period); err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+// SendWeeklySummary отправляет администраторам сводку за прошедшую неделю и возвращает число отправленных сообщений
+func (as *AnalyticsService) SendWeeklySummary(now time.Time) (int, error) {
+	if as.notifier == nil || len(as.adminChatIDs) == 0 {
+		return 0, nil
+	}
+
+	summary, err := as.GetSummary(WeeklyPeriod(now))
+	if err != nil {
+		return 0, fmt.Errorf("ошибка подготовки еженедельной сводки: %v", err)
+	}
+
+	sent := 0
+	for _, chatID := range as.adminChatIDs {
+		if err := as.notifier.SendAnalyticsSummary(chatID, summary); err != nil {
+			log.Printf("Ошибка отправки еженедельной сводки в чат %d: %v", chatID, err)
+			continue
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+// roundAnalytics округляет показатель до сотых
+func roundAnalytics(value *float64) *float64 {
+	if value == nil {
+		return nil
+	}
+	rounded := math.Round(*value*100) / 100
+	return &rounded
+}
@@ -0,0 +1,236 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"path"
+	"strings"
+	"time"
+
+	"dalnoboy/internal/database"
+	"dalnoboy/internal/domain"
+	"dalnoboy/internal/storage"
+
+	"github.com/google/uuid"
+)
+
+// MaxMirroredFileSize — файлы крупнее не зеркалируются: Bot API не отдает файлы больше 20 МБ
+const MaxMirroredFileSize = 20 << 20
+
+// mirrorTimeout ограничивает время копирования одного файла в хранилище
+const mirrorTimeout = 2 * time.Minute
+
+// TelegramFileDownloader скачивает файл из Telegram по file_id бота, который его получил
+type TelegramFileDownloader interface {
+	DownloadFile(fileID string) (io.ReadCloser, error)
+}
+
+// AttachmentService представляет сервис для работы с фото и документами заказов
+type AttachmentService struct {
+	database    *database.Database
+	store       storage.BlobStore                 // nil, если зеркалирование отключено
+	downloaders map[string]TelegramFileDownloader // Загрузчики файлов по ботам-источникам
+}
+
+// NewAttachmentService создает новый экземпляр сервиса вложений
+func NewAttachmentService(db *database.Database, store storage.BlobStore) *AttachmentService {
+	return &AttachmentService{
+		database:    db,
+		store:       store,
+		downloaders: make(map[string]TelegramFileDownloader),
+	}
+}
+
+// SetDownloader задает загрузчик файлов, полученных указанным ботом
+func (as *AttachmentService) SetDownloader(bot string, downloader TelegramFileDownloader) {
+	as.downloaders[bot] = downloader
+}
+
+// AddAttachment прикрепляет файл к заказу. Если включено хранилище, файл копируется туда;
+// ошибка копирования не мешает добавлению — файл остается доступен через Telegram.
+func (as *AttachmentService) AddAttachment(request *domain.AddAttachmentRequest) (*domain.Attachment, error) {
+	if _, err := uuid.Parse(request.OrderUUID); err != nil {
+		return nil, newValidationError("некорректный UUID заказа: %s", request.OrderUUID)
+	}
+
+	order, err := as.database.GetOrderByUUID(request.OrderUUID)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil {
+		return nil, ErrOrderNotFound
+	}
+
+	uploadedBy := domain.AttachmentUploaderAdmin
+	if request.CustomerUUID != nil {
+		if order.CustomerUUID != *request.CustomerUUID {
+			return nil, newValidationError("заказ %s принадлежит другому заказчику", request.OrderUUID[:8])
+		}
+		uploadedBy = domain.AttachmentUploaderCustomer
+	}
+
+	return as.saveAttachment(order, request, uploadedBy, domain.AttachmentPurposeCargo, domain.AttachmentBotAdmin)
+}
+
+// saveAttachment проверяет и сохраняет вложение заказа, полученное ботом sourceBot
+func (as *AttachmentService) saveAttachment(order *domain.Order, request *domain.AddAttachmentRequest, uploadedBy, purpose, sourceBot string) (*domain.Attachment, error) {
+	if request.Kind != domain.AttachmentKindPhoto && request.Kind != domain.AttachmentKindDocument {
+		return nil, newValidationError("неизвестный вид вложения: %s", request.Kind)
+	}
+	if request.TelegramFileID == "" || request.TelegramUniqueID == "" {
+		return nil, newValidationError("не указан файл Telegram")
+	}
+
+	existing, err := as.database.GetAttachmentsByOrder(order.UUID)
+	if err != nil {
+		return nil, err
+	}
+	for _, attachment := range existing {
+		if attachment.TelegramUniqueID == request.TelegramUniqueID {
+			return nil, newValidationError("этот файл уже прикреплен к заказу")
+		}
+	}
+
+	attachment := &domain.Attachment{
+		UUID:               uuid.New().String(),
+		OrderUUID:          order.UUID,
+		Kind:               request.Kind,
+		Purpose:            purpose,
+		SourceBot:          sourceBot,
+		TelegramFileID:     request.TelegramFileID,
+		TelegramUniqueID:   request.TelegramUniqueID,
+		FileName:           optionalString(request.FileName),
+		MimeType:           optionalString(request.MimeType),
+		Caption:            optionalString(request.Caption),
+		UploadedBy:         uploadedBy,
+		UploaderTelegramID: request.UploaderTelegramID,
+		CreatedAt:          time.Now(),
+	}
+	if request.SizeBytes > 0 {
+		size := request.SizeBytes
+		attachment.SizeBytes = &size
+	}
+
+	if err := as.database.CreateAttachment(attachment); err != nil {
+		return nil, err
+	}
+
+	if err := as.mirror(attachment); err != nil {
+		log.Printf("Ошибка копирования вложения %s в хранилище: %v", attachment.UUID, err)
+	}
+
+	return attachment, nil
+}
+
+// mirror копирует файл вложения из Telegram в хранилище
+func (as *AttachmentService) mirror(attachment *domain.Attachment) error {
+	downloader := as.downloaders[attachment.SourceBot]
+	if as.store == nil || downloader == nil {
+		return nil
+	}
+	if attachment.SizeBytes != nil && *attachment.SizeBytes > MaxMirroredFileSize {
+		return fmt.Errorf("файл больше %d МБ", MaxMirroredFileSize>>20)
+	}
+
+	content, err := downloader.DownloadFile(attachment.TelegramFileID)
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), mirrorTimeout)
+	defer cancel()
+
+	key := attachmentStorageKey(attachment)
+	size := int64(-1)
+	if attachment.SizeBytes != nil {
+		size = *attachment.SizeBytes
+	}
+	if err := as.store.Put(ctx, key, content, size, stringValue(attachment.MimeType)); err != nil {
+		return err
+	}
+
+	attachment.StorageKey = &key
+	return as.database.UpdateAttachmentStorageKey(attachment.UUID, key)
+}
+
+// attachmentStorageKey возвращает ключ файла в хранилище: orders/<заказ>/<вложение>.<расширение>
+func attachmentStorageKey(attachment *domain.Attachment) string {
+	ext := path.Ext(stringValue(attachment.FileName))
+	if ext == "" && attachment.Kind == domain.AttachmentKindPhoto {
+		ext = ".jpg"
+	}
+	return "orders/" + attachment.OrderUUID + "/" + attachment.UUID + strings.ToLower(ext)
+}
+
+// GetAttachments возвращает вложения заказа
+func (as *AttachmentService) GetAttachments(orderUUID string) ([]domain.Attachment, error) {
+	if _, err := uuid.Parse(orderUUID); err != nil {
+		return nil, newValidationError("некорректный UUID заказа: %s", orderUUID)
+	}
+	return as.database.GetAttachmentsByOrder(orderUUID)
+}
+
+// GetAttachment возвращает вложение по UUID
+func (as *AttachmentService) GetAttachment(attachmentUUID string) (*domain.Attachment, error) {
+	if _, err := uuid.Parse(attachmentUUID); err != nil {
+		return nil, newValidationError("некорректный UUID вложения: %s", attachmentUUID)
+	}
+
+	attachment, err := as.database.GetAttachmentByUUID(attachmentUUID)
+	if err != nil {
+		return nil, err
+	}
+	if attachment == nil {
+		return nil, ErrAttachmentNotFound
+	}
+	return attachment, nil
+}
+
+// OpenAttachment открывает содержимое вложения: из хранилища, а если копии нет — из Telegram
+func (as *AttachmentService) OpenAttachment(attachment *domain.Attachment) (io.ReadCloser, error) {
+	if as.store != nil && attachment.StorageKey != nil {
+		content, err := as.store.Get(context.Background(), *attachment.StorageKey)
+		if err == nil {
+			return content, nil
+		}
+		log.Printf("Ошибка чтения вложения %s из хранилища: %v", attachment.UUID, err)
+	}
+
+	downloader := as.downloaders[attachment.SourceBot]
+	if downloader == nil {
+		return nil, fmt.Errorf("файл вложения недоступен")
+	}
+	return downloader.DownloadFile(attachment.TelegramFileID)
+}
+
+// SetBotFileID запоминает file_id вложения в боте, чтобы не загружать файл повторно
+func (as *AttachmentService) SetBotFileID(attachment *domain.Attachment, bot, fileID string) error {
+	if bot == domain.AttachmentBotAdmin {
+		attachment.AdminFileID = &fileID
+	} else {
+		attachment.DriverFileID = &fileID
+	}
+	return as.database.UpdateAttachmentBotFileID(attachment.UUID, bot, fileID)
+}
+
+// DeleteAttachment удаляет вложение и его копию в хранилище
+func (as *AttachmentService) DeleteAttachment(attachmentUUID string) (*domain.Attachment, error) {
+	attachment, err := as.GetAttachment(attachmentUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := as.database.DeleteAttachment(attachment.UUID); err != nil {
+		return nil, err
+	}
+
+	if as.store != nil && attachment.StorageKey != nil {
+		if err := as.store.Delete(context.Background(), *attachment.StorageKey); err != nil {
+			log.Printf("Ошибка удаления вложения %s из хранилища: %v", attachment.UUID, err)
+		}
+	}
+	return attachment, nil
+}
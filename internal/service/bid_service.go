@@ -0,0 +1,267 @@
+package service
+
+import (
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"dalnoboy/internal/database"
+	"dalnoboy/internal/domain"
+)
+
+// maxBidCommentLength ограничивает длину комментария к предложению
+const maxBidCommentLength = 500
+
+// BidNotifier сообщает заказчику и администраторам о новых предложениях водителей (админский бот)
+type BidNotifier interface {
+	NotifyNewBid(chatID int64, order *domain.Order, bid *domain.Bid) error
+}
+
+// BidderNotifier сообщает водителям о решении по их предложениям (бот для водителей)
+type BidderNotifier interface {
+	NotifyBidAccepted(telegramID int64, order *domain.Order, bid *domain.Bid) error
+	NotifyBidRejected(telegramID int64, order *domain.Order, bid *domain.Bid) error
+}
+
+// BidService представляет сервис предложений водителей по цене и дате погрузки.
+// Принятое предложение назначает заказ водителю, остальные предложения отклоняются.
+type BidService struct {
+	database       *database.Database
+	adminChatIDs   []int64
+	notifier       BidNotifier
+	bidderNotifier BidderNotifier
+}
+
+// NewBidService создает новый экземпляр сервиса предложений
+func NewBidService(db *database.Database, adminChatIDs []int64) *BidService {
+	return &BidService{
+		database:     db,
+		adminChatIDs: adminChatIDs,
+	}
+}
+
+// SetNotifier задает канал уведомлений заказчиков и администраторов (админский бот)
+func (bs *BidService) SetNotifier(notifier BidNotifier) {
+	bs.notifier = notifier
+}
+
+// SetBidderNotifier задает канал уведомлений водителей (бот для водителей)
+func (bs *BidService) SetBidderNotifier(notifier BidderNotifier) {
+	bs.bidderNotifier = notifier
+}
+
+// PlaceBid сохраняет предложение водителя по активному заказу и сообщает о нем заказчику
+// и администраторам
+func (bs *BidService) PlaceBid(request *domain.PlaceBidRequest) (*domain.Order, *domain.Bid, error) {
+	driver, err := bs.database.GetDriverByTelegramID(request.DriverTelegramID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if driver == nil {
+		return nil, nil, newValidationError("вы не зарегистрированы как водитель, отправьте /start")
+	}
+
+	order, err := bs.findActiveOrder(request.OrderRef)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if request.Price <= 0 {
+		return nil, nil, newValidationError("цена должна быть больше нуля")
+	}
+	if request.PickupDate != nil {
+		now := time.Now()
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, request.PickupDate.Location())
+		if request.PickupDate.Before(today) {
+			return nil, nil, newValidationError("дата погрузки %s уже прошла", request.PickupDate.Format("02.01.2006"))
+		}
+	}
+	comment := strings.TrimSpace(request.Comment)
+	if utf8.RuneCountInString(comment) > maxBidCommentLength {
+		return nil, nil, newValidationError("комментарий длиннее %d символов", maxBidCommentLength)
+	}
+
+	bid, err := bs.database.SaveBid(&domain.Bid{
+		OrderUUID:  order.UUID,
+		DriverUUID: driver.UUID.String(),
+		Price:      request.Price,
+		PickupDate: request.PickupDate,
+		Comment:    optionalString(comment),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	bid.Driver = driver
+
+	bs.notifyNewBid(order, bid)
+	return order, bid, nil
+}
+
+// notifyNewBid сообщает о предложении заказчику (если он пишет админскому боту) и администраторам
+func (bs *BidService) notifyNewBid(order *domain.Order, bid *domain.Bid) {
+	if bs.notifier == nil {
+		return
+	}
+
+	chatIDs := append([]int64(nil), bs.adminChatIDs...)
+	if order.CustomerTelegramID != nil && !containsChatID(chatIDs, *order.CustomerTelegramID) {
+		chatIDs = append(chatIDs, *order.CustomerTelegramID)
+	}
+	for _, chatID := range chatIDs {
+		if err := bs.notifier.NotifyNewBid(chatID, order, bid); err != nil {
+			log.Printf("Ошибка уведомления %d о предложении %s: %v", chatID, bid.UUID, err)
+		}
+	}
+}
+
+// GetOrderBids возвращает заказ и предложения по нему с данными водителей (рейтинг, транспорт).
+// Заказчик видит предложения только по своим заказам.
+func (bs *BidService) GetOrderBids(orderUUID string, viewerTelegramID int64, isAdmin bool) (*domain.Order, []domain.Bid, error) {
+	order, err := bs.database.GetOrderByUUID(strings.TrimSpace(orderUUID))
+	if err != nil {
+		return nil, nil, err
+	}
+	if order == nil {
+		return nil, nil, ErrOrderNotFound
+	}
+	if !isAdmin && !isOrderCustomer(order, viewerTelegramID) {
+		return nil, nil, newValidationError("это чужой заказ")
+	}
+
+	bids, err := bs.database.GetBidsByOrder(order.UUID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := bs.loadDrivers(bids); err != nil {
+		return nil, nil, err
+	}
+	return order, bids, nil
+}
+
+// AcceptBid принимает предложение: заказ назначается водителю по его цене и дате погрузки,
+// остальные ожидающие предложения отклоняются, все участники получают уведомления.
+// Заказчик может принимать предложения только по своим заказам.
+func (bs *BidService) AcceptBid(bidRef string, actorTelegramID int64, isAdmin bool) (*domain.Order, *domain.Bid, error) {
+	bid, err := bs.getBid(bidRef)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	order, bids, err := bs.GetOrderBids(bid.OrderUUID, actorTelegramID, isAdmin)
+	if err != nil {
+		return nil, nil, err
+	}
+	if bid.Status != domain.BidStatusPending {
+		return nil, nil, newValidationError("предложение %s уже %s", bid.ShortID(), domain.BidStatusNames[bid.Status])
+	}
+
+	accepted, err := bs.database.AcceptBid(bid)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !accepted {
+		return nil, nil, newValidationError("заказ %s уже не активен (статус: %s)", order.UUID[:8], order.Status)
+	}
+
+	order, err = bs.database.GetOrderByUUID(order.UUID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if order == nil {
+		return nil, nil, ErrOrderNotFound
+	}
+	bid.Status = domain.BidStatusAccepted
+
+	if bs.bidderNotifier != nil {
+		for i := range bids {
+			other := &bids[i]
+			if other.Driver == nil {
+				continue
+			}
+			if other.UUID == bid.UUID {
+				if err := bs.bidderNotifier.NotifyBidAccepted(other.Driver.TelegramID, order, bid); err != nil {
+					log.Printf("Ошибка уведомления водителя %d о принятии предложения %s: %v", other.Driver.TelegramID, bid.UUID, err)
+				}
+				continue
+			}
+			if other.Status != domain.BidStatusPending {
+				continue
+			}
+			other.Status = domain.BidStatusRejected
+			if err := bs.bidderNotifier.NotifyBidRejected(other.Driver.TelegramID, order, other); err != nil {
+				log.Printf("Ошибка уведомления водителя %d об отклонении предложения %s: %v", other.Driver.TelegramID, other.UUID, err)
+			}
+		}
+	}
+	return order, bid, nil
+}
+
+// findActiveOrder ищет активный заказ по UUID или его первым символам
+func (bs *BidService) findActiveOrder(orderRef string) (*domain.Order, error) {
+	prefix := strings.ToLower(strings.TrimSpace(orderRef))
+	if len(prefix) < 6 || strings.Trim(prefix, "0123456789abcdef-") != "" {
+		return nil, newValidationError("некорректный номер заказа: %s", orderRef)
+	}
+
+	orders, err := bs.database.GetActiveOrdersByUUIDPrefix(prefix)
+	if err != nil {
+		return nil, err
+	}
+	switch len(orders) {
+	case 0:
+		return nil, ErrOrderNotFound
+	case 1:
+		return &orders[0], nil
+	default:
+		return nil, newValidationError("номер %s подходит нескольким заказам, укажите больше символов", orderRef)
+	}
+}
+
+// getBid ищет предложение по UUID или его первым символам
+func (bs *BidService) getBid(ref string) (*domain.Bid, error) {
+	prefix := strings.ToLower(strings.TrimSpace(ref))
+	if len(prefix) < 6 || strings.Trim(prefix, "0123456789abcdef-") != "" {
+		return nil, newValidationError("некорректный номер предложения: %s", ref)
+	}
+
+	bids, err := bs.database.GetBidsByUUIDPrefix(prefix)
+	if err != nil {
+		return nil, err
+	}
+	switch len(bids) {
+	case 0:
+		return nil, newValidationError("предложение %s не найдено", ref)
+	case 1:
+		return &bids[0], nil
+	default:
+		return nil, newValidationError("номер %s подходит нескольким предложениям, укажите больше символов", ref)
+	}
+}
+
+// loadDrivers заполняет данные водителей предложений
+func (bs *BidService) loadDrivers(bids []domain.Bid) error {
+	for i := range bids {
+		driver, err := bs.database.GetDriverByUUID(bids[i].DriverUUID)
+		if err != nil {
+			return err
+		}
+		bids[i].Driver = driver
+	}
+	return nil
+}
+
+// isOrderCustomer проверяет, что Telegram ID принадлежит заказчику заказа
+func isOrderCustomer(order *domain.Order, telegramID int64) bool {
+	return order.CustomerTelegramID != nil && *order.CustomerTelegramID == telegramID
+}
+
+// containsChatID проверяет наличие чата в списке
+func containsChatID(chatIDs []int64, chatID int64) bool {
+	for _, id := range chatIDs {
+		if id == chatID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,347 @@
+package service
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"dalnoboy/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// Допустимый диапазон температурного режима, °C
+const (
+	minCargoTemperatureC = -40
+	maxCargoTemperatureC = 40
+)
+
+// adrClasses содержит классы и подклассы опасных грузов по ДОПОГ
+var adrClasses = map[string]bool{
+	"1": true, "1.1": true, "1.2": true, "1.3": true, "1.4": true, "1.5": true, "1.6": true,
+	"2": true, "2.1": true, "2.2": true, "2.3": true,
+	"3":   true,
+	"4.1": true, "4.2": true, "4.3": true,
+	"5.1": true, "5.2": true,
+	"6.1": true, "6.2": true,
+	"7": true, "8": true, "9": true,
+}
+
+// requirementAliases сопоставляет написания требований с их кодами
+var requirementAliases = map[string]string{
+	"refrigerated": domain.RequirementRefrigerated,
+	"реф":          domain.RequirementRefrigerated,
+	"рефрижератор": domain.RequirementRefrigerated,
+	"температура":  domain.RequirementRefrigerated,
+	"adr":          domain.RequirementDangerous,
+	"опасный":      domain.RequirementDangerous,
+	"допог":        domain.RequirementDangerous,
+	"oversize":     domain.RequirementOversize,
+	"негабарит":    domain.RequirementOversize,
+	"fragile":      domain.RequirementFragile,
+	"хрупкий":      domain.RequirementFragile,
+}
+
+// cargoTypeCodePattern описывает допустимый код типа груза
+var cargoTypeCodePattern = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+// temperaturePattern разбирает "+2..+6", "-18", "от -20 до -15"
+var temperaturePattern = regexp.MustCompile(`^(?:от\s*)?([+-]?\d+(?:[.,]\d+)?)\s*(?:(?:\.\.|…|—|–|\s-\s|до)\s*([+-]?\d+(?:[.,]\d+)?))?\s*(?:°?\s*c|°?\s*с)?$`)
+
+// CargoService представляет сервис для работы со справочником типов грузов
+type CargoService struct {
+	cargoTypeRepo domain.CargoTypeRepository
+}
+
+// NewCargoService создает новый экземпляр сервиса типов грузов
+func NewCargoService(cargoTypeRepo domain.CargoTypeRepository) *CargoService {
+	return &CargoService{
+		cargoTypeRepo: cargoTypeRepo,
+	}
+}
+
+// GetCargoTypes возвращает справочник типов грузов. При activeOnly неактивные типы пропускаются.
+func (cs *CargoService) GetCargoTypes(activeOnly bool) ([]domain.CargoType, error) {
+	cargoTypes, err := cs.cargoTypeRepo.GetAllCargoTypes()
+	if err != nil {
+		return nil, err
+	}
+	if !activeOnly {
+		return cargoTypes, nil
+	}
+
+	var active []domain.CargoType
+	for _, cargoType := range cargoTypes {
+		if cargoType.IsActive {
+			active = append(active, cargoType)
+		}
+	}
+	return active, nil
+}
+
+// GetCargoType находит тип груза по UUID, коду или названию без учета регистра и "ё"
+func (cs *CargoService) GetCargoType(ref string) (*domain.CargoType, error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return nil, newValidationError("тип груза не может быть пустым")
+	}
+
+	cargoTypes, err := cs.cargoTypeRepo.GetAllCargoTypes()
+	if err != nil {
+		return nil, err
+	}
+
+	parsedUUID, uuidErr := uuid.Parse(ref)
+	normalized := normalizeCityName(ref)
+	for i := range cargoTypes {
+		cargoType := &cargoTypes[i]
+		if uuidErr == nil && cargoType.UUID == parsedUUID {
+			return cargoType, nil
+		}
+		if strings.EqualFold(cargoType.Code, ref) || normalizeCityName(cargoType.Name) == normalized {
+			return cargoType, nil
+		}
+	}
+	return nil, ErrCargoTypeNotFound
+}
+
+// resolveActiveCargoType находит тип груза для нового или изменяемого заказа
+func (cs *CargoService) resolveActiveCargoType(ref string) (*domain.CargoType, error) {
+	cargoType, err := cs.GetCargoType(ref)
+	if err == ErrCargoTypeNotFound {
+		return nil, newValidationError("тип груза '%s' не найден в справочнике", strings.TrimSpace(ref))
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !cargoType.IsActive {
+		return nil, newValidationError("тип груза '%s' отключен", cargoType.Name)
+	}
+	return cargoType, nil
+}
+
+// CreateCargoType добавляет тип груза в справочник
+func (cs *CargoService) CreateCargoType(code, name string) (*domain.CargoType, error) {
+	code = strings.ToLower(strings.TrimSpace(code))
+	name = strings.TrimSpace(name)
+	if !cargoTypeCodePattern.MatchString(code) {
+		return nil, newValidationError("код типа груза должен состоять из латинских букв, цифр и '_'")
+	}
+	if name == "" {
+		return nil, newValidationError("название типа груза не может быть пустым")
+	}
+	if err := cs.checkCargoTypeUnique(uuid.Nil, code, name); err != nil {
+		return nil, err
+	}
+
+	cargoType := &domain.CargoType{UUID: uuid.New(), Code: code, Name: name, IsActive: true}
+	if err := cs.cargoTypeRepo.CreateCargoType(cargoType); err != nil {
+		return nil, err
+	}
+	return cargoType, nil
+}
+
+// RenameCargoType меняет название типа груза. Код остается прежним, чтобы не ломать фильтры API.
+func (cs *CargoService) RenameCargoType(ref, name string) (*domain.CargoType, error) {
+	cargoType, err := cs.GetCargoType(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, newValidationError("название типа груза не может быть пустым")
+	}
+	if err := cs.checkCargoTypeUnique(cargoType.UUID, cargoType.Code, name); err != nil {
+		return nil, err
+	}
+
+	cargoType.Name = name
+	if err := cs.cargoTypeRepo.UpdateCargoType(cargoType); err != nil {
+		return nil, err
+	}
+	return cargoType, nil
+}
+
+// SetCargoTypeActive включает или отключает тип груза. Отключенный тип остается у
+// существующих заказов, но его нельзя выбрать для новых.
+func (cs *CargoService) SetCargoTypeActive(ref string, active bool) (*domain.CargoType, error) {
+	cargoType, err := cs.GetCargoType(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	cargoType.IsActive = active
+	if err := cs.cargoTypeRepo.UpdateCargoType(cargoType); err != nil {
+		return nil, err
+	}
+	return cargoType, nil
+}
+
+// checkCargoTypeUnique проверяет, что код и название не заняты другим типом груза
+func (cs *CargoService) checkCargoTypeUnique(selfUUID uuid.UUID, code, name string) error {
+	cargoTypes, err := cs.cargoTypeRepo.GetAllCargoTypes()
+	if err != nil {
+		return err
+	}
+
+	normalized := normalizeCityName(name)
+	for _, other := range cargoTypes {
+		if other.UUID == selfUUID {
+			continue
+		}
+		if other.Code == code {
+			return newValidationError("код '%s' уже используется типом груза '%s'", code, other.Name)
+		}
+		if normalizeCityName(other.Name) == normalized {
+			return newValidationError("тип груза '%s' уже существует", other.Name)
+		}
+	}
+	return nil
+}
+
+// ParseTemperatureRange разбирает температурный режим: "+2..+6", "-18", "от -20 до -15".
+// Одиночное значение задает фиксированную температуру.
+func ParseTemperatureRange(value string) (*float64, *float64, error) {
+	normalized := strings.ToLower(strings.TrimSpace(value))
+	matches := temperaturePattern.FindStringSubmatch(normalized)
+	if matches == nil {
+		return nil, nil, newValidationError("не удалось разобрать температурный режим '%s', пример: +2..+6", value)
+	}
+
+	minC, err := strconv.ParseFloat(strings.Replace(matches[1], ",", ".", 1), 64)
+	if err != nil {
+		return nil, nil, newValidationError("некорректная температура '%s'", matches[1])
+	}
+	maxC := minC
+	if matches[2] != "" {
+		maxC, err = strconv.ParseFloat(strings.Replace(matches[2], ",", ".", 1), 64)
+		if err != nil {
+			return nil, nil, newValidationError("некорректная температура '%s'", matches[2])
+		}
+	}
+
+	if minC > maxC {
+		minC, maxC = maxC, minC
+	}
+	if minC < minCargoTemperatureC || maxC > maxCargoTemperatureC {
+		return nil, nil, newValidationError("температурный режим должен быть в пределах от %d до +%d °C",
+			minCargoTemperatureC, maxCargoTemperatureC)
+	}
+	return &minC, &maxC, nil
+}
+
+// ParseADRClass проверяет класс опасности по ДОПОГ: "3", "2.1", "класс 8"
+func ParseADRClass(value string) (string, error) {
+	class := strings.ToLower(strings.TrimSpace(value))
+	class = strings.TrimSpace(strings.TrimPrefix(class, "класс"))
+	class = strings.Replace(class, ",", ".", 1)
+	if !adrClasses[class] {
+		return "", newValidationError("неизвестный класс опасности ADR '%s'", strings.TrimSpace(value))
+	}
+	return class, nil
+}
+
+// ParseRequirement возвращает код особого требования по его названию
+func ParseRequirement(value string) (string, error) {
+	code, ok := requirementAliases[strings.ToLower(strings.TrimSpace(value))]
+	if !ok {
+		return "", newValidationError("неизвестное требование '%s', доступны: refrigerated, adr, oversize, fragile",
+			strings.TrimSpace(value))
+	}
+	return code, nil
+}
+
+// ParseRequirements разбирает список требований через запятую
+func ParseRequirements(value string) ([]string, error) {
+	var codes []string
+	for _, part := range strings.Split(value, ",") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		code, err := ParseRequirement(part)
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+	}
+	return codes, nil
+}
+
+// buildCargoRequirements собирает особые требования из текстовых полей запроса
+func buildCargoRequirements(temperatureRange, adrClass string, oversize, fragile bool) (domain.CargoRequirements, error) {
+	requirements := domain.CargoRequirements{Oversize: oversize, Fragile: fragile}
+
+	if strings.TrimSpace(temperatureRange) != "" {
+		minC, maxC, err := ParseTemperatureRange(temperatureRange)
+		if err != nil {
+			return requirements, err
+		}
+		requirements.TemperatureMinC, requirements.TemperatureMaxC = minC, maxC
+	}
+
+	if strings.TrimSpace(adrClass) != "" {
+		class, err := ParseADRClass(adrClass)
+		if err != nil {
+			return requirements, err
+		}
+		requirements.ADRClass = &class
+	}
+
+	return requirements, nil
+}
+
+// validateCargoRequirements проверяет требования, заданные напрямую структурой
+func validateCargoRequirements(requirements *domain.CargoRequirements) error {
+	minC, maxC := requirements.TemperatureMinC, requirements.TemperatureMaxC
+	if (minC == nil) != (maxC == nil) {
+		return newValidationError("температурный режим задается минимальной и максимальной температурой")
+	}
+	if minC != nil {
+		if math.IsNaN(*minC) || math.IsNaN(*maxC) || *minC > *maxC {
+			return newValidationError("минимальная температура не может быть выше максимальной")
+		}
+		if *minC < minCargoTemperatureC || *maxC > maxCargoTemperatureC {
+			return newValidationError("температурный режим должен быть в пределах от %d до +%d °C",
+				minCargoTemperatureC, maxCargoTemperatureC)
+		}
+	}
+	if requirements.ADRClass != nil {
+		class, err := ParseADRClass(*requirements.ADRClass)
+		if err != nil {
+			return err
+		}
+		requirements.ADRClass = &class
+	}
+	return nil
+}
+
+// FilterOrdersByCargo оставляет заказы с указанным типом груза (пустой UUID не фильтрует)
+// и со всеми перечисленными требованиями
+func FilterOrdersByCargo(orders []domain.Order, cargoTypeUUID string, requirements []string) []domain.Order {
+	if cargoTypeUUID == "" && len(requirements) == 0 {
+		return orders
+	}
+
+	var filtered []domain.Order
+	for i := range orders {
+		if orderMatchesCargo(&orders[i], cargoTypeUUID, requirements) {
+			filtered = append(filtered, orders[i])
+		}
+	}
+	return filtered
+}
+
+// orderMatchesCargo проверяет тип груза и наличие всех требований у заказа
+func orderMatchesCargo(order *domain.Order, cargoTypeUUID string, requirements []string) bool {
+	if cargoTypeUUID != "" && (order.CargoTypeUUID == nil || *order.CargoTypeUUID != cargoTypeUUID) {
+		return false
+	}
+	for _, code := range requirements {
+		if !order.Requirements.Has(code) {
+			return false
+		}
+	}
+	return true
+}
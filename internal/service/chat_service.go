@@ -0,0 +1,275 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"dalnoboy/internal/database"
+	"dalnoboy/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// maxChatMessageLength ограничивает длину пересылаемого сообщения (лимит Telegram — 4096 с подписью)
+const maxChatMessageLength = 3500
+
+// CustomerRelay пересылает сообщения водителя заказчику или диспетчеру (админский бот)
+type CustomerRelay interface {
+	RelayToCustomer(chatID int64, conversation *domain.Conversation, toAdmin bool, text string) error
+}
+
+// DriverRelay пересылает водителю ответы заказчика или диспетчера (бот для водителей)
+type DriverRelay interface {
+	RelayToDriver(telegramID int64, conversation *domain.Conversation, sender, text string) error
+}
+
+// ChatService представляет сервис анонимной переписки водителей с заказчиками по заказам.
+// Водитель пишет в своем боте, заказчик отвечает в админском боте; если у заказчика
+// нет Telegram, сообщения получают администраторы и отвечают от его имени.
+type ChatService struct {
+	database      *database.Database
+	adminChatIDs  []int64
+	customerRelay CustomerRelay
+	driverRelay   DriverRelay
+}
+
+// NewChatService создает новый экземпляр сервиса переписки
+func NewChatService(db *database.Database, adminChatIDs []int64) *ChatService {
+	return &ChatService{
+		database:     db,
+		adminChatIDs: adminChatIDs,
+	}
+}
+
+// SetCustomerRelay задает канал пересылки сообщений заказчикам (админский бот создается позже сервисов)
+func (cs *ChatService) SetCustomerRelay(relay CustomerRelay) {
+	cs.customerRelay = relay
+}
+
+// SetDriverRelay задает канал пересылки сообщений водителям (бот для водителей создается позже сервисов)
+func (cs *ChatService) SetDriverRelay(relay DriverRelay) {
+	cs.driverRelay = relay
+}
+
+// StartConversation открывает переписку водителя по активному заказу или заказу, назначенному ему.
+// orderRef — UUID заказа или его первые символы.
+func (cs *ChatService) StartConversation(driverTelegramID int64, orderRef string) (*domain.Conversation, error) {
+	driver, err := cs.database.GetDriverByTelegramID(driverTelegramID)
+	if err != nil {
+		return nil, err
+	}
+	if driver == nil {
+		return nil, newValidationError("вы не зарегистрированы как водитель, отправьте /start")
+	}
+
+	order, err := cs.findDriverOrder(driver, orderRef)
+	if err != nil {
+		return nil, err
+	}
+	return cs.database.GetOrCreateConversation(order.UUID, driver.UUID.String())
+}
+
+// findDriverOrder ищет заказ, о котором водитель может писать заказчику
+func (cs *ChatService) findDriverOrder(driver *domain.Driver, orderRef string) (*domain.Order, error) {
+	prefix := strings.ToLower(strings.TrimSpace(orderRef))
+	if len(prefix) < 6 || strings.Trim(prefix, "0123456789abcdef-") != "" {
+		return nil, newValidationError("некорректный номер заказа: %s", orderRef)
+	}
+
+	orders, err := cs.database.GetActiveOrdersByUUIDPrefix(prefix)
+	if err != nil {
+		return nil, err
+	}
+	assigned, err := cs.database.GetOrdersByAssignedDriver(driver.UUID.String())
+	if err != nil {
+		return nil, err
+	}
+	for _, order := range assigned {
+		if strings.HasPrefix(order.UUID, prefix) {
+			orders = append(orders, order)
+		}
+	}
+
+	switch len(orders) {
+	case 0:
+		return nil, ErrOrderNotFound
+	case 1:
+		return &orders[0], nil
+	default:
+		return nil, newValidationError("номер %s подходит нескольким заказам, укажите больше символов", orderRef)
+	}
+}
+
+// SendFromDriver пересылает сообщение водителя заказчику (или администраторам) и сохраняет его в журнал
+func (cs *ChatService) SendFromDriver(driverTelegramID int64, conversationUUID, text string) (*domain.Conversation, error) {
+	conversation, err := cs.GetConversation(conversationUUID)
+	if err != nil {
+		return nil, err
+	}
+	if conversation.DriverTelegramID != driverTelegramID {
+		return nil, newValidationError("это не ваша переписка")
+	}
+	if text, err = validateChatText(text); err != nil {
+		return nil, err
+	}
+
+	order, err := cs.database.GetOrderByUUID(conversation.OrderUUID)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil {
+		return nil, ErrOrderNotFound
+	}
+
+	delivered := false
+	if cs.customerRelay != nil {
+		if order.CustomerTelegramID != nil {
+			if err := cs.customerRelay.RelayToCustomer(*order.CustomerTelegramID, conversation, false, text); err != nil {
+				log.Printf("Ошибка пересылки сообщения заказчику по переписке %s: %v", conversation.UUID, err)
+			} else {
+				delivered = true
+			}
+		}
+		// Заказчик без Telegram или недоступен: пишем диспетчерам
+		if !delivered {
+			for _, chatID := range cs.adminChatIDs {
+				if err := cs.customerRelay.RelayToCustomer(chatID, conversation, true, text); err != nil {
+					log.Printf("Ошибка пересылки сообщения администратору %d по переписке %s: %v", chatID, conversation.UUID, err)
+					continue
+				}
+				delivered = true
+			}
+		}
+	}
+
+	if err := cs.saveMessage(conversation, domain.ChatSenderDriver, text, delivered); err != nil {
+		return nil, err
+	}
+	if !delivered {
+		return nil, newValidationError("не удалось доставить сообщение, попробуйте позже")
+	}
+	return conversation, nil
+}
+
+// SendToDriver пересылает водителю ответ заказчика или диспетчера. Заказчик может отвечать
+// только по своим заказам; isAdmin разрешает ответ по любой переписке.
+func (cs *ChatService) SendToDriver(senderTelegramID int64, isAdmin bool, conversationUUID, text string) (*domain.Conversation, error) {
+	conversation, err := cs.GetConversation(conversationUUID)
+	if err != nil {
+		return nil, err
+	}
+	if text, err = validateChatText(text); err != nil {
+		return nil, err
+	}
+
+	sender := domain.ChatSenderAdmin
+	if !isAdmin {
+		order, err := cs.database.GetOrderByUUID(conversation.OrderUUID)
+		if err != nil {
+			return nil, err
+		}
+		if order == nil || order.CustomerTelegramID == nil || *order.CustomerTelegramID != senderTelegramID {
+			return nil, newValidationError("эта переписка относится к чужому заказу")
+		}
+		sender = domain.ChatSenderCustomer
+	}
+
+	delivered := false
+	if cs.driverRelay != nil {
+		if err := cs.driverRelay.RelayToDriver(conversation.DriverTelegramID, conversation, sender, text); err != nil {
+			log.Printf("Ошибка пересылки сообщения водителю по переписке %s: %v", conversation.UUID, err)
+		} else {
+			delivered = true
+		}
+	}
+
+	if err := cs.saveMessage(conversation, sender, text, delivered); err != nil {
+		return nil, err
+	}
+	if !delivered {
+		return nil, newValidationError("не удалось доставить сообщение водителю, попробуйте позже")
+	}
+	return conversation, nil
+}
+
+// GetConversation возвращает переписку по UUID или короткому номеру из пересланного сообщения
+func (cs *ChatService) GetConversation(ref string) (*domain.Conversation, error) {
+	ref = strings.ToLower(strings.TrimSpace(ref))
+	if _, err := uuid.Parse(ref); err == nil {
+		conversation, err := cs.database.GetConversationByUUID(ref)
+		if err != nil {
+			return nil, err
+		}
+		if conversation == nil {
+			return nil, newValidationError("переписка %s не найдена", ref)
+		}
+		return conversation, nil
+	}
+
+	if len(ref) < 6 || strings.Trim(ref, "0123456789abcdef-") != "" {
+		return nil, newValidationError("некорректный номер переписки: %s", ref)
+	}
+	conversations, err := cs.database.GetConversationsByUUIDPrefix(ref)
+	if err != nil {
+		return nil, err
+	}
+	if len(conversations) != 1 {
+		return nil, newValidationError("переписка %s не найдена", ref)
+	}
+	return &conversations[0], nil
+}
+
+// GetOrderConversations возвращает переписки по заказу вместе с сообщениями
+func (cs *ChatService) GetOrderConversations(orderUUID string) ([]domain.Conversation, map[string][]domain.ChatMessage, error) {
+	if _, err := uuid.Parse(orderUUID); err != nil {
+		return nil, nil, newValidationError("некорректный UUID заказа: %s", orderUUID)
+	}
+
+	conversations, err := cs.database.GetConversationsByOrder(orderUUID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	messages := make(map[string][]domain.ChatMessage, len(conversations))
+	for _, conversation := range conversations {
+		if messages[conversation.UUID], err = cs.database.GetChatMessages(conversation.UUID); err != nil {
+			return nil, nil, err
+		}
+	}
+	return conversations, messages, nil
+}
+
+// GetRecentConversations возвращает переписки с последними сообщениями
+func (cs *ChatService) GetRecentConversations(limit int) ([]domain.Conversation, error) {
+	return cs.database.GetRecentConversations(limit)
+}
+
+func (cs *ChatService) saveMessage(conversation *domain.Conversation, sender, text string, delivered bool) error {
+	message := &domain.ChatMessage{
+		UUID:             uuid.New().String(),
+		ConversationUUID: conversation.UUID,
+		Sender:           sender,
+		Text:             text,
+		Delivered:        delivered,
+		CreatedAt:        time.Now(),
+	}
+	if err := cs.database.CreateChatMessage(message); err != nil {
+		return fmt.Errorf("ошибка сохранения сообщения переписки: %v", err)
+	}
+	return nil
+}
+
+// validateChatText проверяет текст пересылаемого сообщения
+func validateChatText(text string) (string, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return "", newValidationError("пересылаются только текстовые сообщения")
+	}
+	if utf8.RuneCountInString(text) > maxChatMessageLength {
+		return "", newValidationError("сообщение длиннее %d символов", maxChatMessageLength)
+	}
+	return text, nil
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"dalnoboy/internal/domain"
+)
+
+// maxCitySuggestions ограничивает количество предлагаемых вариантов
+const maxCitySuggestions = 5
+
+// normalizeCityName приводит название к виду для сравнения: нижний регистр, "ё" → "е",
+// без префикса "г." и с одиночными пробелами вместо дефисов и знаков препинания
+func normalizeCityName(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	name = strings.ReplaceAll(name, "ё", "е")
+	name = strings.TrimPrefix(name, "город ")
+	name = strings.TrimPrefix(name, "г.")
+
+	var result strings.Builder
+	space := false
+	for _, r := range name {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if space && result.Len() > 0 {
+				result.WriteRune(' ')
+			}
+			result.WriteRune(r)
+			space = false
+		} else {
+			space = true
+		}
+	}
+	return result.String()
+}
+
+// levenshtein вычисляет редакционное расстояние между строками в символах
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
+
+// allowedTypos возвращает допустимое число опечаток для запроса заданной длины
+func allowedTypos(query string) int {
+	switch length := len([]rune(query)); {
+	case length <= 3:
+		return 0
+	case length <= 6:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// cityCandidate описывает город и расстояние от запроса до ближайшего его названия
+type cityCandidate struct {
+	city     domain.City
+	distance int
+	prefix   bool
+}
+
+// matchCity ищет город по названию или псевдониму. Возвращает найденный город, если
+// совпадение однозначно, иначе nil и список похожих городов.
+func matchCity(cities []domain.City, query string) (*domain.City, []domain.City) {
+	normalized := normalizeCityName(query)
+	if normalized == "" {
+		return nil, nil
+	}
+
+	var candidates []cityCandidate
+	for _, city := range cities {
+		best := cityCandidate{city: city, distance: -1}
+		for _, name := range append([]string{city.Name}, city.Aliases...) {
+			key := normalizeCityName(name)
+			if key == normalized {
+				matched := city
+				return &matched, nil
+			}
+
+			distance := levenshtein(normalized, key)
+			if best.distance < 0 || distance < best.distance {
+				best.distance = distance
+			}
+			if len([]rune(normalized)) >= 3 && strings.HasPrefix(key, normalized) {
+				best.prefix = true
+			}
+		}
+		if best.distance <= allowedTypos(normalized) || best.prefix {
+			candidates = append(candidates, best)
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].distance < candidates[j].distance
+	})
+
+	// Однозначное совпадение с опечаткой: лучший кандидат в пределах допуска и без соперников с тем же расстоянием
+	if len(candidates) > 0 && candidates[0].distance <= allowedTypos(normalized) &&
+		(len(candidates) == 1 || candidates[1].distance > candidates[0].distance) {
+		matched := candidates[0].city
+		return &matched, nil
+	}
+
+	var suggestions []domain.City
+	for i := 0; i < len(candidates) && i < maxCitySuggestions; i++ {
+		suggestions = append(suggestions, candidates[i].city)
+	}
+	return nil, suggestions
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"testing"
+
+	"dalnoboy/internal/domain"
+)
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"абв", "", 3},
+		{"", "абв", 3},
+		{"казань", "казань", 0},
+		{"казань", "казнь", 1},
+		{"самара", "самра", 1},
+		{"kitten", "sitting", 3},
+		{"ёж", "еж", 1}, // Считаются символы, а не байты
+	}
+
+	for _, tt := range tests {
+		if got := levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, ожидалось %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestAllowedTypos(t *testing.T) {
+	tests := []struct {
+		query string
+		want  int
+	}{
+		{"", 0},
+		{"уфа", 0},
+		{"тула", 1},
+		{"самара", 1},
+		{"саратов", 2},
+		{"екатеринбург", 2},
+	}
+
+	for _, tt := range tests {
+		if got := allowedTypos(tt.query); got != tt.want {
+			t.Errorf("allowedTypos(%q) = %d, ожидалось %d", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestMatchCity(t *testing.T) {
+	cities := []domain.City{
+		{Name: "Москва", Aliases: []string{"Мск"}},
+		{Name: "Санкт-Петербург", Aliases: []string{"Питер", "СПб"}},
+		{Name: "Казань"},
+		{Name: "Самара"},
+		{Name: "Орёл"},
+		{Name: "Тула"},
+		{Name: "Тура"},
+	}
+
+	tests := []struct {
+		name        string
+		query       string
+		want        string   // Однозначно найденный город
+		suggestions []string // Варианты, если город не найден однозначно
+	}{
+		{"точное название", "Москва", "Москва", nil},
+		{"регистр", "москва", "Москва", nil},
+		{"псевдоним", "спб", "Санкт-Петербург", nil},
+		{"префикс г. и дефис", "г. Санкт Петербург", "Санкт-Петербург", nil},
+		{"ё как е", "Орел", "Орёл", nil},
+		{"одна опечатка", "Казнь", "Казань", nil},
+		{"пропущенная буква", "Самра", "Самара", nil},
+		{"равные кандидаты", "Тупа", "", []string{"Тула", "Тура"}},
+		{"начало названия", "сам", "", []string{"Самара"}},
+		{"короткий запрос без опечаток", "Тул", "", []string{"Тула"}},
+		{"неизвестный город", "Лондон", "", nil},
+		{"пустой запрос", "  ", "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			city, suggestions := matchCity(cities, tt.query)
+			if tt.want != "" {
+				if city == nil || city.Name != tt.want {
+					t.Fatalf("matchCity(%q) = %v, ожидался %s", tt.query, city, tt.want)
+				}
+				return
+			}
+			if city != nil {
+				t.Fatalf("matchCity(%q) = %s, ожидалось неоднозначное совпадение", tt.query, city.Name)
+			}
+			var names []string
+			for _, suggestion := range suggestions {
+				names = append(names, suggestion.Name)
+			}
+			if len(names) != len(tt.suggestions) {
+				t.Fatalf("matchCity(%q): варианты %v, ожидались %v", tt.query, names, tt.suggestions)
+			}
+			for i := range names {
+				if names[i] != tt.suggestions[i] {
+					t.Fatalf("matchCity(%q): варианты %v, ожидались %v", tt.query, names, tt.suggestions)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,316 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"dalnoboy/internal/domain"
+	"dalnoboy/internal/geo"
+
+	"github.com/google/uuid"
+)
+
+// CityNotFoundError возвращается, когда название не удалось однозначно сопоставить городу
+type CityNotFoundError struct {
+	Query       string
+	Suggestions []domain.City
+}
+
+func (e *CityNotFoundError) Error() string {
+	if len(e.Suggestions) == 0 {
+		return fmt.Sprintf("город '%s' не найден", e.Query)
+	}
+
+	names := make([]string, len(e.Suggestions))
+	for i, city := range e.Suggestions {
+		names[i] = city.Name
+	}
+	return fmt.Sprintf("город '%s' не найден. Возможно, вы имели в виду: %s", e.Query, strings.Join(names, ", "))
+}
+
+// CityService представляет сервис для работы со справочником городов
+type CityService struct {
+	cityRepo domain.CityRepository
+}
+
+// NewCityService создает новый экземпляр сервиса городов
+func NewCityService(cityRepo domain.CityRepository) *CityService {
+	return &CityService{
+		cityRepo: cityRepo,
+	}
+}
+
+// GetAllCities возвращает весь справочник городов
+func (cs *CityService) GetAllCities() ([]domain.City, error) {
+	return cs.cityRepo.GetAllCities()
+}
+
+// loadCityIndex загружает справочник городов для расчета расстояний
+func (cs *CityService) loadCityIndex() (cityIndex, error) {
+	cities, err := cs.cityRepo.GetAllCities()
+	if err != nil {
+		return nil, err
+	}
+	return newCityIndex(cities), nil
+}
+
+// ResolveCity находит город по названию или псевдониму без учета регистра, "ё" и
+// небольших опечаток. Если совпадение неоднозначно, возвращает *CityNotFoundError с подсказками.
+func (cs *CityService) ResolveCity(query string) (*domain.City, error) {
+	if strings.TrimSpace(query) == "" {
+		return nil, newValidationError("название города не может быть пустым")
+	}
+
+	cities, err := cs.cityRepo.GetAllCities()
+	if err != nil {
+		return nil, err
+	}
+
+	city, suggestions := matchCity(cities, query)
+	if city == nil {
+		return nil, &CityNotFoundError{Query: strings.TrimSpace(query), Suggestions: suggestions}
+	}
+	return city, nil
+}
+
+// GetCity возвращает город по UUID или по названию
+func (cs *CityService) GetCity(ref string) (*domain.City, error) {
+	if cityUUID, err := uuid.Parse(strings.TrimSpace(ref)); err == nil {
+		city, err := cs.cityRepo.GetCityByUUID(cityUUID)
+		if err != nil {
+			return nil, err
+		}
+		if city == nil {
+			return nil, ErrCityNotFound
+		}
+		return city, nil
+	}
+	return cs.ResolveCity(ref)
+}
+
+// CreateCity добавляет город в справочник. Название обязательно, остальные поля — нет.
+func (cs *CityService) CreateCity(request *domain.CityRequest) (*domain.City, error) {
+	if request.Name == nil {
+		return nil, newValidationError("название города не может быть пустым")
+	}
+
+	city := &domain.City{UUID: uuid.New()}
+	if err := cs.applyCityRequest(city, request); err != nil {
+		return nil, err
+	}
+
+	if err := cs.cityRepo.CreateCity(city); err != nil {
+		return nil, err
+	}
+	return city, nil
+}
+
+// UpdateCity переименовывает город и/или заменяет список его псевдонимов
+func (cs *CityService) UpdateCity(ref string, request *domain.CityRequest) (*domain.City, error) {
+	city, err := cs.GetCity(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cs.applyCityRequest(city, request); err != nil {
+		return nil, err
+	}
+
+	if err := cs.cityRepo.UpdateCity(city); err != nil {
+		return nil, err
+	}
+	return city, nil
+}
+
+// AddAliases добавляет псевдонимы к городу
+func (cs *CityService) AddAliases(ref string, aliases []string) (*domain.City, error) {
+	city, err := cs.GetCity(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := append(append([]string{}, city.Aliases...), aliases...)
+	return cs.UpdateCity(city.UUID.String(), &domain.CityRequest{Aliases: &merged})
+}
+
+// RemoveAliases удаляет псевдонимы города
+func (cs *CityService) RemoveAliases(ref string, aliases []string) (*domain.City, error) {
+	city, err := cs.GetCity(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	removed := make(map[string]bool, len(aliases))
+	for _, alias := range aliases {
+		removed[normalizeCityName(alias)] = true
+	}
+
+	var kept []string
+	for _, alias := range city.Aliases {
+		if !removed[normalizeCityName(alias)] {
+			kept = append(kept, alias)
+		}
+	}
+	return cs.UpdateCity(city.UUID.String(), &domain.CityRequest{Aliases: &kept})
+}
+
+// CityImportResult описывает результат импорта встроенного справочника
+type CityImportResult struct {
+	Created int `json:"created"`
+	Updated int `json:"updated"`
+	Skipped int `json:"skipped"`
+}
+
+// ImportBundledCities загружает встроенный справочник городов России: существующим городам
+// (по названию или псевдониму) проставляются регион и координаты, отсутствующие создаются.
+// Уже заданные координаты не перезаписываются, если overwrite = false.
+func (cs *CityService) ImportBundledCities(overwrite bool) (*CityImportResult, error) {
+	places, err := geo.BundledRussianCities()
+	if err != nil {
+		return nil, err
+	}
+
+	cities, err := cs.cityRepo.GetAllCities()
+	if err != nil {
+		return nil, err
+	}
+
+	byKey := make(map[string]*domain.City)
+	for i := range cities {
+		city := &cities[i]
+		for _, key := range append([]string{city.Name}, city.Aliases...) {
+			byKey[normalizeCityName(key)] = city
+		}
+	}
+
+	result := &CityImportResult{}
+	for _, place := range places {
+		region := place.Region
+		lat, lon := place.Point.Latitude, place.Point.Longitude
+
+		city, exists := byKey[normalizeCityName(place.Name)]
+		if !exists {
+			city = &domain.City{
+				UUID:      uuid.New(),
+				Name:      place.Name,
+				Region:    &region,
+				Latitude:  &lat,
+				Longitude: &lon,
+			}
+			if err := cs.cityRepo.CreateCity(city); err != nil {
+				return result, err
+			}
+			byKey[normalizeCityName(place.Name)] = city
+			result.Created++
+			continue
+		}
+
+		if city.HasCoordinates() && !overwrite {
+			result.Skipped++
+			continue
+		}
+
+		city.Latitude = &lat
+		city.Longitude = &lon
+		if city.Region == nil || overwrite {
+			city.Region = &region
+		}
+		if err := cs.cityRepo.UpdateCity(city); err != nil {
+			return result, err
+		}
+		result.Updated++
+	}
+
+	return result, nil
+}
+
+// DeleteCity удаляет город, если на него не ссылаются заказы, водители и сохраненные поиски водителей
+func (cs *CityService) DeleteCity(ref string) (*domain.City, error) {
+	city, err := cs.GetCity(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	references, err := cs.cityRepo.CountCityReferences(city.UUID)
+	if err != nil {
+		return nil, err
+	}
+	if references > 0 {
+		return nil, newValidationError("город %s используется в заказах, у водителей или в их сохраненных поисках (%d), удаление невозможно", city.Name, references)
+	}
+
+	if err := cs.cityRepo.DeleteCity(city.UUID); err != nil {
+		return nil, err
+	}
+	return city, nil
+}
+
+// applyCityRequest проверяет запрос и переносит изменения в город.
+// Название и псевдонимы должны быть уникальны в пределах справочника.
+func (cs *CityService) applyCityRequest(city *domain.City, request *domain.CityRequest) error {
+	if request.Name == nil && request.Aliases == nil && request.Region == nil &&
+		request.Latitude == nil && request.Longitude == nil {
+		return newValidationError("не указано ни одного поля для изменения")
+	}
+	if (request.Latitude == nil) != (request.Longitude == nil) {
+		return newValidationError("широта и долгота задаются вместе")
+	}
+	if request.Latitude != nil && !geo.ValidPoint(geo.Point{Latitude: *request.Latitude, Longitude: *request.Longitude}) {
+		return newValidationError("некорректные координаты: широта от -90 до 90, долгота от -180 до 180")
+	}
+
+	name := city.Name
+	if request.Name != nil {
+		name = strings.TrimSpace(*request.Name)
+		if name == "" {
+			return newValidationError("название города не может быть пустым")
+		}
+	}
+
+	aliases := city.Aliases
+	if request.Aliases != nil {
+		aliases = nil
+		seen := map[string]bool{normalizeCityName(name): true}
+		for _, alias := range *request.Aliases {
+			alias = strings.TrimSpace(alias)
+			key := normalizeCityName(alias)
+			if key == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			aliases = append(aliases, alias)
+		}
+	}
+
+	cities, err := cs.cityRepo.GetAllCities()
+	if err != nil {
+		return err
+	}
+
+	// Собираем занятые другими городами названия
+	taken := make(map[string]string)
+	for _, other := range cities {
+		if other.UUID == city.UUID {
+			continue
+		}
+		for _, key := range append([]string{other.Name}, other.Aliases...) {
+			taken[normalizeCityName(key)] = other.Name
+		}
+	}
+	for _, key := range append([]string{name}, aliases...) {
+		if owner, ok := taken[normalizeCityName(key)]; ok {
+			return newValidationError("название '%s' уже используется городом %s", key, owner)
+		}
+	}
+
+	city.Name = name
+	city.Aliases = aliases
+	if request.Region != nil {
+		city.Region = optionalString(strings.TrimSpace(*request.Region))
+	}
+	if request.Latitude != nil {
+		city.Latitude = request.Latitude
+		city.Longitude = request.Longitude
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"dalnoboy/internal/database"
@@ -24,17 +25,22 @@ func NewCustomerService(db *database.Database) *CustomerService {
 
 // CreateCustomer создает нового заказчика
 func (cs *CustomerService) CreateCustomer(name, phone string, telegramID *int64, telegramTag *string) (*domain.Customer, error) {
+	phone, err := NormalizePhone(phone)
+	if err != nil {
+		return nil, err
+	}
+
 	// Проверяем, не существует ли уже заказчик с таким телефоном
 	existingCustomer, err := cs.database.GetCustomerByPhone(phone)
 	if err == nil && existingCustomer != nil {
-		return nil, fmt.Errorf("заказчик с телефоном %s уже существует", phone)
+		return nil, newValidationError("заказчик с телефоном %s уже существует", phone)
 	}
 
 	// Если указан Telegram ID, проверяем, не существует ли уже заказчик с таким ID
 	if telegramID != nil {
 		existingCustomer, err := cs.database.GetCustomerByTelegramID(*telegramID)
 		if err == nil && existingCustomer != nil {
-			return nil, fmt.Errorf("заказчик с Telegram ID %d уже существует", *telegramID)
+			return nil, newValidationError("заказчик с Telegram ID %d уже существует", *telegramID)
 		}
 	}
 
@@ -45,6 +51,7 @@ func (cs *CustomerService) CreateCustomer(name, phone string, telegramID *int64,
 		Phone:       phone,
 		TelegramID:  telegramID,
 		TelegramTag: telegramTag,
+		IsActive:    true,
 		CreatedAt:   time.Now(),
 	}
 
@@ -66,3 +73,144 @@ func (cs *CustomerService) GetAllCustomers() ([]domain.Customer, error) {
 func (cs *CustomerService) GetCustomersCount() (int, error) {
 	return cs.database.GetCustomersCount()
 }
+
+// GetCustomerByPhone ищет заказчика по телефону в любом распространенном формате
+func (cs *CustomerService) GetCustomerByPhone(phone string) (*domain.Customer, error) {
+	normalized, err := NormalizePhone(phone)
+	if err != nil {
+		return nil, err
+	}
+	return cs.database.GetCustomerByPhone(normalized)
+}
+
+// GetCustomerByUUID возвращает заказчика по UUID или ErrCustomerNotFound
+func (cs *CustomerService) GetCustomerByUUID(customerUUID uuid.UUID) (*domain.Customer, error) {
+	customer, err := cs.database.GetCustomerByUUID(customerUUID)
+	if err != nil {
+		return nil, err
+	}
+	if customer == nil {
+		return nil, ErrCustomerNotFound
+	}
+	return customer, nil
+}
+
+// GetCustomerByTelegramID возвращает заказчика по Telegram ID или nil, если такого нет
+func (cs *CustomerService) GetCustomerByTelegramID(telegramID int64) (*domain.Customer, error) {
+	return cs.database.GetCustomerByTelegramID(telegramID)
+}
+
+// UpdateCustomer изменяет имя, телефон и Telegram данные заказчика
+func (cs *CustomerService) UpdateCustomer(customerUUID uuid.UUID, request *domain.UpdateCustomerRequest) (*domain.Customer, error) {
+	customer, err := cs.GetCustomerByUUID(customerUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.Name != nil {
+		name := strings.TrimSpace(*request.Name)
+		if name == "" {
+			return nil, newValidationError("имя заказчика не может быть пустым")
+		}
+		customer.Name = name
+	}
+
+	if request.Phone != nil {
+		phone, err := NormalizePhone(*request.Phone)
+		if err != nil {
+			return nil, err
+		}
+		existing, err := cs.database.GetCustomerByPhone(phone)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil && existing.UUID != customer.UUID {
+			return nil, newValidationError("телефон %s уже принадлежит заказчику %s (%s), используйте объединение", phone, existing.Name, existing.UUID)
+		}
+		customer.Phone = phone
+	}
+
+	if request.TelegramID != nil {
+		if *request.TelegramID == 0 {
+			customer.TelegramID = nil
+		} else {
+			existing, err := cs.database.GetCustomerByTelegramID(*request.TelegramID)
+			if err != nil {
+				return nil, err
+			}
+			if existing != nil && existing.UUID != customer.UUID {
+				return nil, newValidationError("Telegram ID %d уже принадлежит заказчику %s (%s)", *request.TelegramID, existing.Name, existing.UUID)
+			}
+			telegramID := *request.TelegramID
+			customer.TelegramID = &telegramID
+		}
+	}
+
+	if request.TelegramTag != nil {
+		customer.TelegramTag = optionalString(*request.TelegramTag)
+	}
+
+	if err := cs.database.UpdateCustomer(customer); err != nil {
+		return nil, err
+	}
+
+	return customer, nil
+}
+
+// SetCustomerActive активирует или деактивирует заказчика.
+// Деактивированный заказчик остается в истории, но для него нельзя создавать заказы.
+func (cs *CustomerService) SetCustomerActive(customerUUID uuid.UUID, active bool) (*domain.Customer, error) {
+	customer, err := cs.GetCustomerByUUID(customerUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cs.database.SetCustomerActive(customerUUID, active); err != nil {
+		return nil, err
+	}
+	customer.IsActive = active
+
+	return customer, nil
+}
+
+// MergeCustomers объединяет дубликат с основным заказчиком и возвращает основного
+// заказчика и количество перенесенных заказов
+func (cs *CustomerService) MergeCustomers(duplicateUUID, targetUUID uuid.UUID) (*domain.Customer, int64, error) {
+	if duplicateUUID == targetUUID {
+		return nil, 0, newValidationError("нельзя объединить заказчика с самим собой")
+	}
+
+	if _, err := cs.GetCustomerByUUID(duplicateUUID); err != nil {
+		return nil, 0, err
+	}
+	if _, err := cs.GetCustomerByUUID(targetUUID); err != nil {
+		return nil, 0, err
+	}
+
+	moved, err := cs.database.MergeCustomers(duplicateUUID, targetUUID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	target, err := cs.GetCustomerByUUID(targetUUID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return target, moved, nil
+}
+
+// GetCustomerOrders возвращает историю заказов заказчика
+func (cs *CustomerService) GetCustomerOrders(customerUUID uuid.UUID) (*domain.Customer, []domain.Order, error) {
+	customer, err := cs.GetCustomerByUUID(customerUUID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	orders, err := cs.database.GetOrdersByCustomer(customerUUID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return customer, orders, nil
+}
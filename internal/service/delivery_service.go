@@ -0,0 +1,331 @@
+package service
+
+import (
+	"encoding/csv"
+	"io"
+	"log"
+	"strconv"
+	"time"
+
+	"dalnoboy/internal/database"
+	"dalnoboy/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// DeliveryNotifier доставляет подтверждение доставки с фото документов заказчику и администраторам
+type DeliveryNotifier interface {
+	NotifyOrderDelivered(chatID int64, order *domain.Order, podAttachments []domain.Attachment) error
+}
+
+// AssignmentNotifier сообщает водителю о назначении заказа и его отмене
+type AssignmentNotifier interface {
+	NotifyOrderAssigned(telegramID int64, order *domain.Order) error
+	NotifyOrderUnassigned(telegramID int64, order *domain.Order) error
+}
+
+// DeliveryService представляет сервис назначения заказов водителям и подтверждения доставки
+type DeliveryService struct {
+	database           *database.Database
+	attachmentService  *AttachmentService
+	ratingService      *RatingService
+	adminChatIDs       []int64
+	notifier           DeliveryNotifier
+	assignmentNotifier AssignmentNotifier
+}
+
+// NewDeliveryService создает новый экземпляр сервиса доставки
+func NewDeliveryService(db *database.Database, attachmentService *AttachmentService, ratingService *RatingService, adminChatIDs []int64) *DeliveryService {
+	return &DeliveryService{
+		database:          db,
+		attachmentService: attachmentService,
+		ratingService:     ratingService,
+		adminChatIDs:      adminChatIDs,
+	}
+}
+
+// SetNotifier задает канал доставки подтверждений заказчикам и администраторам (админский бот)
+func (ds *DeliveryService) SetNotifier(notifier DeliveryNotifier) {
+	ds.notifier = notifier
+}
+
+// SetAssignmentNotifier задает канал уведомлений водителей о назначениях (бот для водителей)
+func (ds *DeliveryService) SetAssignmentNotifier(notifier AssignmentNotifier) {
+	ds.assignmentNotifier = notifier
+}
+
+// AssignOrder назначает активный заказ водителю и уведомляет водителя
+func (ds *DeliveryService) AssignOrder(orderUUID, driverUUID string) (*domain.Order, error) {
+	order, err := ds.getOrder(orderUUID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := uuid.Parse(driverUUID); err != nil {
+		return nil, newValidationError("некорректный UUID водителя: %s", driverUUID)
+	}
+
+	driver, err := ds.database.GetDriverByUUID(driverUUID)
+	if err != nil {
+		return nil, err
+	}
+	if driver == nil {
+		return nil, newValidationError("водитель %s не найден", driverUUID)
+	}
+
+	assigned, err := ds.database.AssignOrder(order.UUID, driver.UUID.String())
+	if err != nil {
+		return nil, err
+	}
+	if !assigned {
+		return nil, newValidationError("заказ %s не активен (статус: %s)", order.UUID[:8], order.Status)
+	}
+
+	order, err = ds.getOrder(order.UUID)
+	if err != nil {
+		return nil, err
+	}
+
+	if ds.assignmentNotifier != nil {
+		if err := ds.assignmentNotifier.NotifyOrderAssigned(driver.TelegramID, order); err != nil {
+			log.Printf("Ошибка уведомления водителя %d о назначении заказа %s: %v", driver.TelegramID, order.UUID, err)
+		}
+	}
+	return order, nil
+}
+
+// UnassignOrder снимает заказ с водителя и возвращает его в активные
+func (ds *DeliveryService) UnassignOrder(orderUUID string) (*domain.Order, error) {
+	order, err := ds.getOrder(orderUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	unassigned, err := ds.database.UnassignOrder(order.UUID)
+	if err != nil {
+		return nil, err
+	}
+	if !unassigned {
+		return nil, newValidationError("заказ %s не назначен водителю", order.UUID[:8])
+	}
+
+	if ds.assignmentNotifier != nil && order.AssignedDriverTgID != nil {
+		if err := ds.assignmentNotifier.NotifyOrderUnassigned(*order.AssignedDriverTgID, order); err != nil {
+			log.Printf("Ошибка уведомления водителя %d о снятии заказа %s: %v", *order.AssignedDriverTgID, order.UUID, err)
+		}
+	}
+
+	order.Status = domain.OrderStatusActive
+	order.AssignedDriverUUID, order.AssignedDriverName, order.AssignedDriverTgID, order.AssignedAt = nil, nil, nil, nil
+	return order, nil
+}
+
+// GetAssignedOrders возвращает заказы, которые водитель везет сейчас
+func (ds *DeliveryService) GetAssignedOrders(driverTelegramID int64) ([]domain.Order, error) {
+	driver, err := ds.getDriver(driverTelegramID)
+	if err != nil {
+		return nil, err
+	}
+	return ds.database.GetOrdersByAssignedDriver(driver.UUID.String())
+}
+
+// ConfirmDelivery переводит заказ в статус delivered, сохраняет фото документов как вложения
+// подтверждения доставки и отправляет их заказчику и администраторам, после чего
+// просит заказчика оценить водителя.
+// Требуется хотя бы одно фото.
+func (ds *DeliveryService) ConfirmDelivery(request *domain.ConfirmDeliveryRequest) (*domain.Order, error) {
+	driver, err := ds.getDriver(request.DriverTelegramID)
+	if err != nil {
+		return nil, err
+	}
+	order, err := ds.getOrder(request.OrderUUID)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != domain.OrderStatusAssigned || order.AssignedDriverUUID == nil || *order.AssignedDriverUUID != driver.UUID.String() {
+		return nil, newValidationError("заказ %s не назначен вам", order.UUID[:8])
+	}
+
+	hasPhoto := false
+	for _, file := range request.Files {
+		if file.Kind == domain.AttachmentKindPhoto {
+			hasPhoto = true
+		}
+	}
+	if !hasPhoto {
+		return nil, newValidationError("пришлите хотя бы одно фото подписанных документов")
+	}
+	if (request.Latitude == nil) != (request.Longitude == nil) {
+		return nil, newValidationError("некорректные координаты доставки")
+	}
+
+	delivered, err := ds.database.MarkOrderDelivered(order.UUID, driver.UUID.String(), request.Latitude, request.Longitude)
+	if err != nil {
+		return nil, err
+	}
+	if !delivered {
+		return nil, newValidationError("заказ %s уже подтвержден или снят с вас", order.UUID[:8])
+	}
+
+	// Статус уже изменен: ошибки сохранения отдельных файлов только логируются
+	for i := range request.Files {
+		file := request.Files[i]
+		file.OrderUUID = order.UUID
+		file.UploaderTelegramID = request.DriverTelegramID
+		if _, err := ds.attachmentService.saveAttachment(order, &file, domain.AttachmentUploaderDriver, domain.AttachmentPurposePOD, domain.AttachmentBotDriver); err != nil {
+			log.Printf("Ошибка сохранения подтверждения доставки заказа %s: %v", order.UUID, err)
+		}
+	}
+
+	order, err = ds.getOrder(order.UUID)
+	if err != nil {
+		return nil, err
+	}
+	ds.notifyDelivered(order)
+	ds.ratingService.RequestRating(order)
+	return order, nil
+}
+
+// notifyDelivered отправляет подтверждение доставки заказчику и администраторам
+func (ds *DeliveryService) notifyDelivered(order *domain.Order) {
+	if ds.notifier == nil {
+		return
+	}
+
+	podAttachments, err := ds.GetPODAttachments(order.UUID)
+	if err != nil {
+		log.Printf("Ошибка получения подтверждения доставки заказа %s: %v", order.UUID, err)
+	}
+
+	recipients := append([]int64{}, ds.adminChatIDs...)
+	if order.CustomerTelegramID != nil {
+		recipients = append(recipients, *order.CustomerTelegramID)
+	}
+	for _, chatID := range recipients {
+		if err := ds.notifier.NotifyOrderDelivered(chatID, order, podAttachments); err != nil {
+			log.Printf("Ошибка отправки подтверждения доставки заказа %s в чат %d: %v", order.UUID, chatID, err)
+		}
+	}
+}
+
+// GetPODAttachments возвращает фото и документы подтверждения доставки заказа
+func (ds *DeliveryService) GetPODAttachments(orderUUID string) ([]domain.Attachment, error) {
+	attachments, err := ds.attachmentService.GetAttachments(orderUUID)
+	if err != nil {
+		return nil, err
+	}
+	return FilterAttachmentsByPurpose(attachments, domain.AttachmentPurposePOD), nil
+}
+
+// GetDeliveries возвращает заказы, доставленные в интервале [from, to), с количеством файлов подтверждения
+func (ds *DeliveryService) GetDeliveries(from, to time.Time) ([]domain.Delivery, error) {
+	if !to.After(from) {
+		return nil, newValidationError("конец периода должен быть позже начала")
+	}
+
+	orders, err := ds.database.GetDeliveredOrders(from, to)
+	if err != nil {
+		return nil, err
+	}
+	if len(orders) == 0 {
+		return nil, nil
+	}
+
+	counts, err := ds.database.GetPODAttachmentCounts(orderUUIDs(orders))
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries := make([]domain.Delivery, len(orders))
+	for i, order := range orders {
+		deliveries[i] = domain.Delivery{Order: order, PODFiles: counts[order.UUID]}
+	}
+	return deliveries, nil
+}
+
+// WriteDeliveriesCSV выгружает сводку доставок в CSV (разделитель ";" для Excel).
+// Телефоны заказчиков выгружаются по правилам доступа для получателя viewer.
+func WriteDeliveriesCSV(w io.Writer, deliveries []domain.Delivery, viewer domain.ContactViewer) error {
+	writer := csv.NewWriter(w)
+	writer.Comma = ';'
+
+	header := []string{"Заказ", "Название", "Откуда", "Куда", "Заказчик", "Телефон", "Водитель",
+		"Назначен", "Доставлен", "Широта", "Долгота", "Файлов POD", "Цена"}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for _, delivery := range deliveries {
+		order := delivery.Order
+		record := []string{
+			order.UUID,
+			order.Title,
+			stringValue(order.FromCityName),
+			stringValue(order.ToCityName),
+			order.CustomerName,
+			CustomerPhoneFor(&order, viewer),
+			stringValue(order.AssignedDriverName),
+			formatOptionalTime(order.AssignedAt),
+			formatOptionalTime(order.DeliveredAt),
+			formatOptionalCoordinate(order.DeliveryLatitude),
+			formatOptionalCoordinate(order.DeliveryLongitude),
+			strconv.Itoa(delivery.PODFiles),
+			strconv.FormatFloat(order.Price, 'f', -1, 64),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// FilterAttachmentsByPurpose оставляет вложения с указанным назначением
+func FilterAttachmentsByPurpose(attachments []domain.Attachment, purpose string) []domain.Attachment {
+	var result []domain.Attachment
+	for _, attachment := range attachments {
+		if attachment.Purpose == purpose {
+			result = append(result, attachment)
+		}
+	}
+	return result
+}
+
+func (ds *DeliveryService) getOrder(orderUUID string) (*domain.Order, error) {
+	if _, err := uuid.Parse(orderUUID); err != nil {
+		return nil, newValidationError("некорректный UUID заказа: %s", orderUUID)
+	}
+	order, err := ds.database.GetOrderByUUID(orderUUID)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil {
+		return nil, ErrOrderNotFound
+	}
+	return order, nil
+}
+
+func (ds *DeliveryService) getDriver(telegramID int64) (*domain.Driver, error) {
+	driver, err := ds.database.GetDriverByTelegramID(telegramID)
+	if err != nil {
+		return nil, err
+	}
+	if driver == nil {
+		return nil, newValidationError("вы не зарегистрированы как водитель, отправьте /start")
+	}
+	return driver, nil
+}
+
+func formatOptionalTime(value *time.Time) string {
+	if value == nil {
+		return ""
+	}
+	return value.Format("2006-01-02 15:04")
+}
+
+func formatOptionalCoordinate(value *float64) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*value, 'f', 6, 64)
+}
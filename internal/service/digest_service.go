@@ -0,0 +1,297 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+	_ "time/tzdata" // База часовых поясов на случай ее отсутствия в системе
+
+	"dalnoboy/internal/database"
+	"dalnoboy/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// maxSavedSearches ограничивает число сохраненных поисков водителя
+const maxSavedSearches = 5
+
+// maxDigestOrders — сколько лучших заказов попадает в сводку
+const maxDigestOrders = 10
+
+// digestMinInterval защищает от повторной сводки в тот же день при нескольких проверках за час
+const digestMinInterval = 20 * time.Hour
+
+// DigestNotifier доставляет водителю ежедневную сводку заказов (бот для водителей)
+type DigestNotifier interface {
+	SendDigest(telegramID int64, orders []domain.Order, total int) error
+}
+
+// DigestService представляет сервис сохраненных поисков и ежедневных сводок для водителей.
+// Сводка приходит в выбранный водителем час по его часовому поясу и содержит новые активные
+// заказы по его поискам, о которых он еще не получал уведомлений.
+type DigestService struct {
+	database    *database.Database
+	cityService *CityService
+	notifier    DigestNotifier
+}
+
+// NewDigestService создает новый экземпляр сервиса сводок
+func NewDigestService(db *database.Database, cityService *CityService) *DigestService {
+	return &DigestService{
+		database:    db,
+		cityService: cityService,
+	}
+}
+
+// SetNotifier задает канал доставки сводок (бот для водителей создается позже сервисов)
+func (ds *DigestService) SetNotifier(notifier DigestNotifier) {
+	ds.notifier = notifier
+}
+
+// AddSearch сохраняет поиск водителя
+func (ds *DigestService) AddSearch(request *domain.SavedSearchRequest) (*domain.SavedSearch, error) {
+	driver, err := ds.getDriver(request.DriverTelegramID)
+	if err != nil {
+		return nil, err
+	}
+
+	searches, err := ds.database.GetSavedSearches(driver.UUID.String())
+	if err != nil {
+		return nil, err
+	}
+	if len(searches) >= maxSavedSearches {
+		return nil, newValidationError("можно сохранить не больше %d поисков, удалите ненужный", maxSavedSearches)
+	}
+
+	radius := request.RadiusKm
+	if err := validateRadius(&radius); err != nil {
+		return nil, err
+	}
+	direction := request.Direction
+	switch direction {
+	case "":
+		direction = SearchDirectionAny
+	case SearchDirectionPickup, SearchDirectionDelivery, SearchDirectionAny:
+	default:
+		return nil, newValidationError("направление поиска должно быть %s, %s или %s",
+			SearchDirectionPickup, SearchDirectionDelivery, SearchDirectionAny)
+	}
+	if request.MinPrice != nil && *request.MinPrice < 0 {
+		return nil, newValidationError("минимальная цена не может быть отрицательной")
+	}
+
+	city, err := ds.cityService.ResolveCity(request.CityName)
+	if err != nil {
+		return nil, err
+	}
+	if !city.HasCoordinates() {
+		return nil, newValidationError("для города %s не заданы координаты", city.Name)
+	}
+
+	search := &domain.SavedSearch{
+		UUID:       uuid.New().String(),
+		DriverUUID: driver.UUID.String(),
+		CityUUID:   city.UUID.String(),
+		CityName:   city.Name,
+		RadiusKm:   radius,
+		Direction:  direction,
+		MinPrice:   request.MinPrice,
+		CreatedAt:  time.Now(),
+	}
+	if err := ds.database.CreateSavedSearch(search); err != nil {
+		return nil, err
+	}
+	return search, nil
+}
+
+// GetSearches возвращает водителя и его сохраненные поиски
+func (ds *DigestService) GetSearches(driverTelegramID int64) (*domain.Driver, []domain.SavedSearch, error) {
+	driver, err := ds.getDriver(driverTelegramID)
+	if err != nil {
+		return nil, nil, err
+	}
+	searches, err := ds.database.GetSavedSearches(driver.UUID.String())
+	if err != nil {
+		return nil, nil, err
+	}
+	return driver, searches, nil
+}
+
+// DeleteSearch удаляет поиск водителя по номеру
+func (ds *DigestService) DeleteSearch(driverTelegramID int64, ref string) error {
+	driver, err := ds.getDriver(driverTelegramID)
+	if err != nil {
+		return err
+	}
+
+	prefix := strings.ToLower(strings.TrimSpace(ref))
+	if len(prefix) < 6 || strings.Trim(prefix, "0123456789abcdef-") != "" {
+		return newValidationError("некорректный номер поиска: %s", ref)
+	}
+	deleted, err := ds.database.DeleteSavedSearch(driver.UUID.String(), prefix)
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return newValidationError("поиск %s не найден", ref)
+	}
+	return nil
+}
+
+// SetDigest включает ежедневную сводку в час hour по местному времени (nil — выключает).
+// Пустой timezone оставляет текущий часовой пояс водителя.
+func (ds *DigestService) SetDigest(driverTelegramID int64, hour *int, timezone string) (*domain.Driver, error) {
+	driver, err := ds.getDriver(driverTelegramID)
+	if err != nil {
+		return nil, err
+	}
+	if hour != nil && (*hour < 0 || *hour > 23) {
+		return nil, newValidationError("час сводки должен быть от 0 до 23")
+	}
+
+	var zone *string
+	if timezone = strings.TrimSpace(timezone); timezone != "" {
+		if _, err := time.LoadLocation(timezone); err != nil {
+			return nil, newValidationError("неизвестный часовой пояс '%s', например: Europe/Moscow, Asia/Vladivostok", timezone)
+		}
+		zone = &timezone
+	}
+
+	if err := ds.database.UpdateDriverDigest(driver.UUID, hour, zone); err != nil {
+		return nil, err
+	}
+	driver.DigestHour = hour
+	if zone != nil {
+		driver.Timezone = zone
+	}
+	return driver, nil
+}
+
+// Run отправляет сводки водителям, у которых сейчас наступил выбранный час, и возвращает
+// число отправленных сводок
+func (ds *DigestService) Run(now time.Time) (int, error) {
+	if ds.notifier == nil {
+		return 0, nil
+	}
+
+	drivers, err := ds.database.GetDriversWithDigest()
+	if err != nil {
+		return 0, err
+	}
+	cities, err := ds.cityService.loadCityIndex()
+	if err != nil {
+		return 0, fmt.Errorf("ошибка загрузки справочника городов: %v", err)
+	}
+
+	sent := 0
+	for i := range drivers {
+		driver := &drivers[i]
+		if !digestDue(driver, now) {
+			continue
+		}
+		delivered, err := ds.sendDigest(driver, cities, now)
+		if err != nil {
+			log.Printf("Ошибка отправки сводки водителю %s: %v", driver.UUID, err)
+			continue
+		}
+		if delivered {
+			sent++
+		}
+	}
+	return sent, nil
+}
+
+// sendDigest собирает и отправляет сводку одному водителю. Отправленные заказы отмечаются
+// в журнале уведомлений, чтобы не повторяться ни в сводках, ни в мгновенных уведомлениях.
+func (ds *DigestService) sendDigest(driver *domain.Driver, cities cityIndex, now time.Time) (bool, error) {
+	since := now.Add(-24 * time.Hour)
+	if driver.LastDigestAt != nil {
+		since = *driver.LastDigestAt
+	}
+
+	orders, err := ds.database.GetDigestOrders(driver.UUID, since)
+	if err != nil {
+		return false, err
+	}
+	searches, err := ds.database.GetSavedSearches(driver.UUID.String())
+	if err != nil {
+		return false, err
+	}
+
+	var matched []domain.Order
+	for i := range orders {
+		if vehicleFits(driver, &orders[i]) && digestMatches(driver, searches, cities, &orders[i]) {
+			matched = append(matched, orders[i])
+		}
+	}
+	sort.SliceStable(matched, func(i, j int) bool {
+		return matched[i].Price > matched[j].Price
+	})
+	total := len(matched)
+	if len(matched) > maxDigestOrders {
+		matched = matched[:maxDigestOrders]
+	}
+
+	if len(matched) > 0 {
+		if err := ds.notifier.SendDigest(driver.TelegramID, matched, total); err != nil {
+			return false, err
+		}
+		for _, order := range matched {
+			if err := ds.database.RecordOrderNotification(order.UUID, driver.UUID); err != nil {
+				log.Printf("Ошибка записи уведомления о заказе %s: %v", order.UUID, err)
+			}
+		}
+	}
+
+	if err := ds.database.MarkDigestSent(driver.UUID, now); err != nil {
+		return false, err
+	}
+	return len(matched) > 0, nil
+}
+
+// digestDue проверяет, что у водителя наступил час сводки и сегодня она еще не отправлялась
+func digestDue(driver *domain.Driver, now time.Time) bool {
+	if driver.DigestHour == nil {
+		return false
+	}
+	location, err := time.LoadLocation(driver.TimezoneName())
+	if err != nil {
+		log.Printf("Неизвестный часовой пояс водителя %s: %s", driver.UUID, driver.TimezoneName())
+		return false
+	}
+	if now.In(location).Hour() != *driver.DigestHour {
+		return false
+	}
+	return driver.LastDigestAt == nil || now.Sub(*driver.LastDigestAt) >= digestMinInterval
+}
+
+// digestMatches проверяет заказ по сохраненным поискам водителя. Без сохраненных поисков
+// используются город и радиусы водителя, как в мгновенных уведомлениях.
+func digestMatches(driver *domain.Driver, searches []domain.SavedSearch, cities cityIndex, order *domain.Order) bool {
+	if len(searches) == 0 {
+		return cities.driverMatchesOrder(driver, order)
+	}
+	for _, search := range searches {
+		if search.MinPrice != nil && order.Price < *search.MinPrice {
+			continue
+		}
+		if cities.orderNearCity(order, search.CityUUID, search.RadiusKm, search.Direction) {
+			return true
+		}
+	}
+	return false
+}
+
+// getDriver возвращает водителя по Telegram ID
+func (ds *DigestService) getDriver(telegramID int64) (*domain.Driver, error) {
+	driver, err := ds.database.GetDriverByTelegramID(telegramID)
+	if err != nil {
+		return nil, err
+	}
+	if driver == nil {
+		return nil, newValidationError("вы не зарегистрированы как водитель, отправьте /start")
+	}
+	return driver, nil
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"time"
+
+	"dalnoboy/internal/domain"
+)
+
+// onboardingCityChoices — сколько городов предлагать кнопками на шаге выбора города
+const onboardingCityChoices = 12
+
+// nextOnboardingStep возвращает шаг регистрации после step; nil — регистрация завершена
+func nextOnboardingStep(step string) *string {
+	var next string
+	switch step {
+	case domain.OnboardingStepPhone:
+		next = domain.OnboardingStepCity
+	case domain.OnboardingStepCity:
+		next = domain.OnboardingStepVehicle
+	case domain.OnboardingStepVehicle:
+		next = domain.OnboardingStepTerms
+	default:
+		return nil
+	}
+	return &next
+}
+
+// checkOnboardingStep проверяет, что водитель находится на шаге регистрации step
+func checkOnboardingStep(driver *domain.Driver, step string) error {
+	if driver == nil {
+		return newValidationError("водитель не найден")
+	}
+	if driver.OnboardingStep == nil || *driver.OnboardingStep != step {
+		return newValidationError("этот шаг регистрации уже пройден")
+	}
+	return nil
+}
+
+// SubmitOnboardingPhone сохраняет телефон водителя и переводит его к выбору города
+func (ds *DriverService) SubmitOnboardingPhone(driver *domain.Driver, phone string) error {
+	if err := checkOnboardingStep(driver, domain.OnboardingStepPhone); err != nil {
+		return err
+	}
+	normalized, err := NormalizePhone(phone)
+	if err != nil {
+		return err
+	}
+
+	next := nextOnboardingStep(domain.OnboardingStepPhone)
+	if err := ds.database.UpdateDriverPhone(driver.UUID, normalized, next); err != nil {
+		return err
+	}
+	driver.Phone = &normalized
+	driver.OnboardingStep = next
+	return nil
+}
+
+// SubmitOnboardingCity сохраняет домашний город водителя и переводит его к описанию транспорта.
+// Неизвестный город возвращает *CityNotFoundError с подсказками.
+func (ds *DriverService) SubmitOnboardingCity(driver *domain.Driver, cityName string) (*domain.City, error) {
+	if err := checkOnboardingStep(driver, domain.OnboardingStepCity); err != nil {
+		return nil, err
+	}
+	city, err := ds.cityService.ResolveCity(cityName)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ds.database.UpdateDriverCity(driver.UUID, &city.UUID); err != nil {
+		return nil, err
+	}
+	next := nextOnboardingStep(domain.OnboardingStepCity)
+	if err := ds.database.UpdateDriverOnboardingStep(driver.UUID, next); err != nil {
+		return nil, err
+	}
+	driver.CityUUID = &city.UUID
+	driver.CityName = &city.Name
+	driver.OnboardingStep = next
+	return city, nil
+}
+
+// SubmitOnboardingVehicle сохраняет транспорт водителя и переводит его к условиям сервиса.
+// request == nil — водитель пропустил шаг и будет видеть все заказы без учета вместимости.
+func (ds *DriverService) SubmitOnboardingVehicle(driver *domain.Driver, request *domain.VehicleRequest) error {
+	if err := checkOnboardingStep(driver, domain.OnboardingStepVehicle); err != nil {
+		return err
+	}
+	if request != nil {
+		if _, err := ds.SaveVehicle(driver, request); err != nil {
+			return err
+		}
+	}
+
+	next := nextOnboardingStep(domain.OnboardingStepVehicle)
+	if err := ds.database.UpdateDriverOnboardingStep(driver.UUID, next); err != nil {
+		return err
+	}
+	driver.OnboardingStep = next
+	return nil
+}
+
+// AcceptTerms отмечает принятие условий сервиса, завершает регистрацию и включает уведомления о заказах
+func (ds *DriverService) AcceptTerms(driver *domain.Driver) error {
+	if err := checkOnboardingStep(driver, domain.OnboardingStepTerms); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if err := ds.database.CompleteDriverOnboarding(driver.UUID, now); err != nil {
+		return err
+	}
+	driver.TermsAcceptedAt = &now
+	driver.OnboardingStep = nil
+	driver.NotificationEnabled = true
+	return nil
+}
+
+// GetOnboardingCityChoices возвращает города для кнопок выбора домашнего города
+func (ds *DriverService) GetOnboardingCityChoices() ([]string, error) {
+	return ds.database.GetPopularCityNames(onboardingCityChoices)
+}
@@ -3,7 +3,6 @@ package service
 import (
 	"dalnoboy/internal/database"
 	"dalnoboy/internal/domain"
-	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -11,13 +10,15 @@ import (
 
 // DriverService представляет сервис для работы с водителями
 type DriverService struct {
-	database *database.Database
+	database    *database.Database
+	cityService *CityService
 }
 
 // NewDriverService создает новый экземпляр сервиса водителей
-func NewDriverService(db *database.Database) *DriverService {
+func NewDriverService(db *database.Database, cityService *CityService) *DriverService {
 	return &DriverService{
-		database: db,
+		database:    db,
+		cityService: cityService,
 	}
 }
 
@@ -36,13 +37,10 @@ func (ds *DriverService) UpdateDriverCity(driverUUID uuid.UUID, cityName string)
 	var cityUUID *uuid.UUID
 
 	if cityName != "" && cityName != "-" {
-		// Получаем город по названию
-		city, err := ds.database.GetCityByName(cityName)
+		// Получаем город по названию или псевдониму
+		city, err := ds.cityService.ResolveCity(cityName)
 		if err != nil {
-			return fmt.Errorf("ошибка получения города '%s': %v", cityName, err)
-		}
-		if city == nil {
-			return fmt.Errorf("город '%s' не найден", cityName)
+			return err
 		}
 		cityUUID = &city.UUID
 	} else if cityName == "-" {
@@ -64,13 +62,10 @@ func (ds *DriverService) UpdateDriverCityAndNotifications(driverUUID uuid.UUID,
 	var cityUUID *uuid.UUID
 
 	if cityName != "" && cityName != "-" {
-		// Получаем город по названию
-		city, err := ds.database.GetCityByName(cityName)
+		// Получаем город по названию или псевдониму
+		city, err := ds.cityService.ResolveCity(cityName)
 		if err != nil {
-			return fmt.Errorf("ошибка получения города '%s': %v", cityName, err)
-		}
-		if city == nil {
-			return fmt.Errorf("город '%s' не найден", cityName)
+			return err
 		}
 		cityUUID = &city.UUID
 	} else if cityName == "-" {
@@ -82,9 +77,21 @@ func (ds *DriverService) UpdateDriverCityAndNotifications(driverUUID uuid.UUID,
 	return ds.database.UpdateDriverCityAndNotifications(driverUUID, cityUUID, notificationEnabled)
 }
 
-// GetCityByName возвращает город по названию
+// SetDriverRadius задает радиусы поиска погрузки и выгрузки вокруг города водителя
+func (ds *DriverService) SetDriverRadius(request *domain.SetDriverRadiusRequest) error {
+	if err := validateRadius(request.PickupRadiusKm); err != nil {
+		return err
+	}
+	if err := validateRadius(request.DeliveryRadiusKm); err != nil {
+		return err
+	}
+
+	return ds.database.UpdateDriverRadius(request.DriverUUID, request.PickupRadiusKm, request.DeliveryRadiusKm)
+}
+
+// GetCityByName возвращает город по названию или псевдониму
 func (ds *DriverService) GetCityByName(cityName string) (*domain.City, error) {
-	return ds.database.GetCityByName(cityName)
+	return ds.cityService.ResolveCity(cityName)
 }
 
 // GetDriverByTelegramID возвращает водителя по Telegram ID
@@ -92,15 +99,20 @@ func (ds *DriverService) GetDriverByTelegramID(telegramID int64) (*domain.Driver
 	return ds.database.GetDriverByTelegramID(telegramID)
 }
 
-// CreateDriver создает водителя
-func (ds *DriverService) CreateDriver(name string, telegramID int64, telegramTag *string) (*domain.Driver, error) {
+// CreateDriver создает водителя. source — источник привлечения (ссылка, с которой водитель открыл бота), может быть nil.
+// Новый водитель начинает регистрацию в боте с телефона; уведомления включаются после ее завершения.
+func (ds *DriverService) CreateDriver(name string, telegramID int64, telegramTag *string, source *string) (*domain.Driver, error) {
+	step := domain.OnboardingStepPhone
 	driver := &domain.Driver{
 		UUID:                uuid.New(),
 		Name:                name,
 		TelegramID:          telegramID,
 		TelegramTag:         telegramTag,
 		NotificationEnabled: false,
+		NotificationMode:    domain.NotificationModeInstant,
 		CityUUID:            nil,
+		Source:              source,
+		OnboardingStep:      &step,
 		CreatedAt:           time.Now(),
 	}
 	if err := ds.database.CreateDriver(driver); err != nil {
@@ -109,8 +121,9 @@ func (ds *DriverService) CreateDriver(name string, telegramID int64, telegramTag
 	return driver, nil
 }
 
-// EnsureDriverExistsByTelegram auto-registers a driver if missing
-func (ds *DriverService) EnsureDriverExistsByTelegram(name string, telegramID int64, telegramTag *string) (*domain.Driver, error) {
+// EnsureDriverExistsByTelegram auto-registers a driver if missing.
+// Источник привлечения сохраняется только при регистрации: учитывается первая ссылка, по которой пришел водитель.
+func (ds *DriverService) EnsureDriverExistsByTelegram(name string, telegramID int64, telegramTag *string, source *string) (*domain.Driver, error) {
 	existing, err := ds.GetDriverByTelegramID(telegramID)
 	if err != nil {
 		return nil, err
@@ -136,5 +149,5 @@ func (ds *DriverService) EnsureDriverExistsByTelegram(name string, telegramID in
 		}
 		return existing, nil
 	}
-	return ds.CreateDriver(name, telegramID, telegramTag)
+	return ds.CreateDriver(name, telegramID, telegramTag, source)
 }
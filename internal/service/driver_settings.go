@@ -0,0 +1,111 @@
+package service
+
+import (
+	"strings"
+	"time"
+
+	"dalnoboy/internal/domain"
+	"dalnoboy/internal/geo"
+)
+
+// defaultDigestHour — час сводки по умолчанию, если водитель выбрал уведомления раз в день
+const defaultDigestHour = 8
+
+// ChangeDriverCity меняет домашний город водителя. Название ищется с учетом псевдонимов и опечаток;
+// неизвестный город возвращает *CityNotFoundError с подсказками.
+func (ds *DriverService) ChangeDriverCity(driver *domain.Driver, cityName string) (*domain.City, error) {
+	city, err := ds.cityService.ResolveCity(cityName)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ds.database.UpdateDriverCity(driver.UUID, &city.UUID); err != nil {
+		return nil, err
+	}
+	driver.CityUUID = &city.UUID
+	driver.CityName = &city.Name
+	driver.CityTimezone = nil
+	if zone := geo.RegionTimezone(stringValue(city.Region)); zone != "" {
+		driver.CityTimezone = &zone
+	}
+	return city, nil
+}
+
+// SetDriverTimezone задает часовой пояс водителя в формате IANA (Asia/Novosibirsk).
+// Пустая строка возвращает пояс, определенный по домашнему городу.
+func (ds *DriverService) SetDriverTimezone(driver *domain.Driver, timezone string) error {
+	var zone *string
+	if timezone = strings.TrimSpace(timezone); timezone != "" {
+		if _, err := time.LoadLocation(timezone); err != nil || !strings.Contains(timezone, "/") {
+			return newValidationError("неизвестный часовой пояс '%s', например: Europe/Moscow, Asia/Vladivostok", timezone)
+		}
+		zone = &timezone
+	}
+
+	if err := ds.database.UpdateDriverTimezone(driver.UUID, zone); err != nil {
+		return err
+	}
+	driver.Timezone = zone
+	return nil
+}
+
+// SetNotificationMode задает частоту уведомлений о новых заказах. В режиме сводки мгновенные
+// уведомления не приходят, а сводка включается в 8:00, если водитель не выбрал другой час.
+// Возврат к мгновенным уведомлениям выключает сводку.
+func (ds *DriverService) SetNotificationMode(driver *domain.Driver, mode string) error {
+	digestHour := driver.DigestHour
+	switch mode {
+	case domain.NotificationModeInstant:
+		digestHour = nil
+	case domain.NotificationModeDigest:
+		if digestHour == nil {
+			hour := defaultDigestHour
+			digestHour = &hour
+		}
+	default:
+		return newValidationError("неизвестная частота уведомлений: %s", mode)
+	}
+
+	if err := ds.database.UpdateDriverNotificationMode(driver.UUID, mode, digestHour); err != nil {
+		return err
+	}
+	driver.NotificationMode = mode
+	driver.DigestHour = digestHour
+	return nil
+}
+
+// SetQuietHours задает тихие часы водителя по его местному времени: с start:00 до end:00.
+// В тихие часы несрочные уведомления откладываются до их окончания. nil убирает тихие часы.
+func (ds *DriverService) SetQuietHours(driver *domain.Driver, start, end *int) error {
+	if (start == nil) != (end == nil) {
+		return newValidationError("укажите начало и конец тихих часов")
+	}
+	if start != nil {
+		if *start < 0 || *start > 23 || *end < 0 || *end > 23 {
+			return newValidationError("часы должны быть от 0 до 23")
+		}
+		if *start == *end {
+			return newValidationError("начало и конец тихих часов должны различаться")
+		}
+	}
+
+	if err := ds.database.UpdateDriverQuietHours(driver.UUID, start, end); err != nil {
+		return err
+	}
+	driver.QuietHoursStart = start
+	driver.QuietHoursEnd = end
+	return nil
+}
+
+// DeleteDriverAccount удаляет аккаунт водителя. Пока у водителя есть заказы в пути, удаление
+// запрещено: заказчик и администратор должны знать, кто везет груз.
+func (ds *DriverService) DeleteDriverAccount(driver *domain.Driver) error {
+	orders, err := ds.database.GetOrdersByAssignedDriver(driver.UUID.String())
+	if err != nil {
+		return err
+	}
+	if len(orders) > 0 {
+		return newValidationError("у вас есть заказы в пути (%d). Подтвердите доставку, затем удалите аккаунт", len(orders))
+	}
+	return ds.database.DeleteDriver(driver.UUID)
+}
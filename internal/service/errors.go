@@ -0,0 +1,42 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrOrderNotFound возвращается, когда заказ с указанным UUID отсутствует
+var ErrOrderNotFound = errors.New("заказ не найден")
+
+// ErrCustomerNotFound возвращается, когда заказчик с указанным UUID отсутствует
+var ErrCustomerNotFound = errors.New("заказчик не найден")
+
+// ErrCityNotFound возвращается, когда город с указанным UUID отсутствует
+var ErrCityNotFound = errors.New("город не найден")
+
+// ErrCargoTypeNotFound возвращается, когда тип груза отсутствует в справочнике
+var ErrCargoTypeNotFound = errors.New("тип груза не найден")
+
+// ErrAttachmentNotFound возвращается, когда вложение с указанным UUID отсутствует
+var ErrAttachmentNotFound = errors.New("вложение не найдено")
+
+// ValidationError описывает ошибку проверки входных данных
+type ValidationError struct {
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+// newValidationError создает ошибку валидации с форматированным сообщением
+func newValidationError(format string, args ...interface{}) error {
+	return &ValidationError{Message: fmt.Sprintf(format, args...)}
+}
+
+// IsValidationError сообщает, что ошибка вызвана некорректными входными данными
+func IsValidationError(err error) bool {
+	var validationErr *ValidationError
+	var cityErr *CityNotFoundError
+	return errors.As(err, &validationErr) || errors.As(err, &cityErr)
+}
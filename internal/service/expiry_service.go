@@ -0,0 +1,203 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"dalnoboy/internal/database"
+	"dalnoboy/internal/domain"
+)
+
+// ExpiryNotifier доставляет предупреждения об истечении заказов заказчикам и администраторам
+type ExpiryNotifier interface {
+	NotifyOrderExpiring(chatID int64, order *domain.Order, expiresAt time.Time) error
+	NotifyOrdersExpired(chatID int64, orders []domain.Order) error
+}
+
+// ExpirySettings задает правила истечения заказов
+type ExpirySettings struct {
+	GracePeriod time.Duration // Сколько заказ живет после даты погрузки
+	MaxAge      time.Duration // Максимальный возраст заказа с момента создания
+	WarnBefore  time.Duration // За сколько до истечения предупреждать (0 — не предупреждать)
+}
+
+// ExpiryResult описывает результат одного прохода проверки
+type ExpiryResult struct {
+	Warned  int
+	Expired int
+}
+
+// ExpiryService снимает устаревшие заказы и заранее предупреждает об этом
+type ExpiryService struct {
+	database     *database.Database
+	settings     ExpirySettings
+	adminChatIDs []int64
+	notifier     ExpiryNotifier
+}
+
+// NewExpiryService создает новый экземпляр сервиса истечения заказов
+func NewExpiryService(db *database.Database, settings ExpirySettings, adminChatIDs []int64) *ExpiryService {
+	return &ExpiryService{
+		database:     db,
+		settings:     settings,
+		adminChatIDs: adminChatIDs,
+	}
+}
+
+// SetNotifier задает канал доставки предупреждений (админский бот создается позже сервисов)
+func (es *ExpiryService) SetNotifier(notifier ExpiryNotifier) {
+	es.notifier = notifier
+}
+
+// ExpiresAt возвращает момент истечения заказа: раньшее из "дата погрузки + отсрочка"
+// и "начало активности + максимальный возраст". Началом активности считается создание
+// или повторная активация; дата погрузки раньше активации отсчитывается от активации.
+func (es *ExpiryService) ExpiresAt(order *domain.Order) time.Time {
+	activeSince := order.CreatedAt
+	if order.ActivatedAt != nil {
+		activeSince = *order.ActivatedAt
+	}
+
+	expiresAt := activeSince.Add(es.settings.MaxAge)
+	if order.AvailableFrom != nil {
+		byDate := order.AvailableFrom.Add(es.settings.GracePeriod)
+		if order.AvailableFrom.Before(activeSince) {
+			byDate = activeSince.Add(es.settings.GracePeriod)
+		}
+		if byDate.Before(expiresAt) {
+			expiresAt = byDate
+		}
+	}
+	return expiresAt
+}
+
+// Run выполняет один проход: снимает истекшие заказы и предупреждает о скором истечении.
+// Безопасен при параллельном запуске: статусы меняются условным UPDATE, и каждый заказ
+// обрабатывает только тот экземпляр, который его изменил.
+func (es *ExpiryService) Run(now time.Time) (*ExpiryResult, error) {
+	result := &ExpiryResult{}
+
+	expired, err := es.expireOrders(now)
+	if err != nil {
+		return result, err
+	}
+	result.Expired = len(expired)
+
+	if es.settings.WarnBefore > 0 {
+		warned, err := es.warnExpiringOrders(now)
+		if err != nil {
+			return result, err
+		}
+		result.Warned = warned
+	}
+
+	return result, nil
+}
+
+// expireOrders переводит истекшие заказы в статус expired и уведомляет об этом
+func (es *ExpiryService) expireOrders(now time.Time) ([]domain.Order, error) {
+	availableBefore, createdBefore := es.expiryCutoffs(now)
+	candidates, err := es.database.GetActiveOrdersForExpiry(availableBefore, createdBefore, false)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка поиска истекших заказов: %v", err)
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	updated, err := es.database.ExpireOrders(orderUUIDs(candidates))
+	if err != nil {
+		return nil, err
+	}
+
+	expired := selectOrders(candidates, updated)
+	for i := range expired {
+		expired[i].Status = domain.OrderStatusExpired
+		if expired[i].CustomerTelegramID != nil {
+			es.send(func() error {
+				return es.notifier.NotifyOrdersExpired(*expired[i].CustomerTelegramID, expired[i:i+1])
+			})
+		}
+	}
+	if len(expired) > 0 {
+		for _, chatID := range es.adminChatIDs {
+			es.send(func() error { return es.notifier.NotifyOrdersExpired(chatID, expired) })
+		}
+	}
+
+	return expired, nil
+}
+
+// warnExpiringOrders предупреждает о заказах, которые истекут в ближайшие WarnBefore
+func (es *ExpiryService) warnExpiringOrders(now time.Time) (int, error) {
+	availableBefore, createdBefore := es.expiryCutoffs(now.Add(es.settings.WarnBefore))
+	candidates, err := es.database.GetActiveOrdersForExpiry(availableBefore, createdBefore, true)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка поиска заказов для предупреждения: %v", err)
+	}
+	if len(candidates) == 0 {
+		return 0, nil
+	}
+
+	updated, err := es.database.MarkOrdersExpiryWarned(orderUUIDs(candidates))
+	if err != nil {
+		return 0, err
+	}
+
+	warned := selectOrders(candidates, updated)
+	for i := range warned {
+		order := &warned[i]
+		expiresAt := es.ExpiresAt(order)
+
+		recipients := append([]int64{}, es.adminChatIDs...)
+		if order.CustomerTelegramID != nil {
+			recipients = append(recipients, *order.CustomerTelegramID)
+		}
+		for _, chatID := range recipients {
+			es.send(func() error { return es.notifier.NotifyOrderExpiring(chatID, order, expiresAt) })
+		}
+	}
+
+	return len(warned), nil
+}
+
+// expiryCutoffs возвращает границы выборки заказов, истекших к моменту at: дата погрузки
+// и начало активности раньше availableBefore или начало активности раньше createdBefore
+func (es *ExpiryService) expiryCutoffs(at time.Time) (availableBefore, createdBefore time.Time) {
+	return at.Add(-es.settings.GracePeriod), at.Add(-es.settings.MaxAge)
+}
+
+// send отправляет уведомление, если задан канал доставки; ошибки только логируются
+func (es *ExpiryService) send(deliver func() error) {
+	if es.notifier == nil {
+		return
+	}
+	if err := deliver(); err != nil {
+		log.Printf("Ошибка отправки уведомления об истечении заказа: %v", err)
+	}
+}
+
+func orderUUIDs(orders []domain.Order) []string {
+	uuids := make([]string, len(orders))
+	for i, order := range orders {
+		uuids[i] = order.UUID
+	}
+	return uuids
+}
+
+// selectOrders оставляет заказы, UUID которых входят в список
+func selectOrders(orders []domain.Order, uuids []string) []domain.Order {
+	selected := make(map[string]bool, len(uuids))
+	for _, orderUUID := range uuids {
+		selected[orderUUID] = true
+	}
+
+	var result []domain.Order
+	for _, order := range orders {
+		if selected[order.UUID] {
+			result = append(result, order)
+		}
+	}
+	return result
+}
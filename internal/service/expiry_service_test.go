@@ -0,0 +1,148 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"dalnoboy/internal/domain"
+)
+
+var testExpirySettings = ExpirySettings{
+	GracePeriod: 24 * time.Hour,
+	MaxAge:      30 * 24 * time.Hour,
+	WarnBefore:  12 * time.Hour,
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
+
+func TestExpiresAt(t *testing.T) {
+	es := &ExpiryService{settings: testExpirySettings}
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	tests := []struct {
+		name  string
+		order domain.Order
+		want  time.Time
+	}{
+		{
+			name:  "без даты погрузки живет MaxAge с создания",
+			order: domain.Order{CreatedAt: now.Add(-10 * day)},
+			want:  now.Add(20 * day),
+		},
+		{
+			name:  "дата погрузки в будущем",
+			order: domain.Order{CreatedAt: now.Add(-day), AvailableFrom: timePtr(now.Add(3 * day))},
+			want:  now.Add(4 * day),
+		},
+		{
+			name:  "дата погрузки позже максимального возраста",
+			order: domain.Order{CreatedAt: now.Add(-day), AvailableFrom: timePtr(now.Add(40 * day))},
+			want:  now.Add(29 * day),
+		},
+		{
+			name:  "дата погрузки в прошлом",
+			order: domain.Order{CreatedAt: now.Add(-10 * day), AvailableFrom: timePtr(now.Add(-5 * day))},
+			want:  now.Add(-4 * day),
+		},
+		{
+			name:  "дата погрузки раньше создания",
+			order: domain.Order{CreatedAt: now.Add(-2 * time.Hour), AvailableFrom: timePtr(now.Add(-3 * day))},
+			want:  now.Add(22 * time.Hour),
+		},
+		{
+			name:  "повторная активация продлевает максимальный возраст",
+			order: domain.Order{CreatedAt: now.Add(-60 * day), ActivatedAt: timePtr(now.Add(-time.Hour))},
+			want:  now.Add(30*day - time.Hour),
+		},
+		{
+			name: "после активации прошедшая дата погрузки отсчитывается от активации",
+			order: domain.Order{
+				CreatedAt:     now.Add(-60 * day),
+				AvailableFrom: timePtr(now.Add(-50 * day)),
+				ActivatedAt:   timePtr(now.Add(-time.Hour)),
+			},
+			want: now.Add(23 * time.Hour),
+		},
+		{
+			name: "после активации будущая дата погрузки действует как обычно",
+			order: domain.Order{
+				CreatedAt:     now.Add(-60 * day),
+				AvailableFrom: timePtr(now.Add(2 * day)),
+				ActivatedAt:   timePtr(now.Add(-time.Hour)),
+			},
+			want: now.Add(3 * day),
+		},
+	}
+
+	for _, tt := range tests {
+		if got := es.ExpiresAt(&tt.order); !got.Equal(tt.want) {
+			t.Errorf("%s: ExpiresAt = %v, ожидалось %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// matchesExpiryQuery повторяет условие выборки GetActiveOrdersForExpiry для одного заказа
+func matchesExpiryQuery(order *domain.Order, availableBefore, createdBefore time.Time) bool {
+	activeSince := order.CreatedAt
+	if order.ActivatedAt != nil {
+		activeSince = *order.ActivatedAt
+	}
+	return (order.AvailableFrom != nil && order.AvailableFrom.Before(availableBefore) && activeSince.Before(availableBefore)) ||
+		activeSince.Before(createdBefore)
+}
+
+// Снятие и предупреждение выбирают заказы по границам expiryCutoffs; выборка должна
+// совпадать с ExpiresAt, иначе срок в предупреждении разойдется с фактическим снятием
+func TestExpiryCutoffsMatchExpiresAt(t *testing.T) {
+	es := &ExpiryService{settings: testExpirySettings}
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	horizon := now.Add(testExpirySettings.WarnBefore)
+	day := 24 * time.Hour
+
+	// Заказы, истекающие вокруг момента at: по дате погрузки, по возрасту, после повторной активации
+	ordersExpiringAt := func(at time.Time) map[string]domain.Order {
+		return map[string]domain.Order{
+			"по дате погрузки": {
+				CreatedAt:     at.Add(-5 * day),
+				AvailableFrom: timePtr(at.Add(-testExpirySettings.GracePeriod)),
+			},
+			"по максимальному возрасту": {
+				CreatedAt: at.Add(-testExpirySettings.MaxAge),
+			},
+			"после повторной активации": {
+				CreatedAt:     at.Add(-90 * day),
+				AvailableFrom: timePtr(at.Add(-60 * day)),
+				ActivatedAt:   timePtr(at.Add(-testExpirySettings.GracePeriod)),
+			},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		at        time.Time // Момент, относительно которого выбираются заказы
+		expiresAt time.Time
+		want      bool
+	}{
+		{"снятие: истек секунду назад", now, now.Add(-time.Second), true},
+		{"снятие: истекает ровно сейчас", now, now, false},
+		{"снятие: истекает через секунду", now, now.Add(time.Second), false},
+		{"предупреждение: истекает внутри окна", horizon, horizon.Add(-time.Second), true},
+		{"предупреждение: истекает на границе окна", horizon, horizon, false},
+		{"предупреждение: истекает за окном", horizon, horizon.Add(time.Second), false},
+	}
+
+	for _, tt := range tests {
+		availableBefore, createdBefore := es.expiryCutoffs(tt.at)
+		for kind, order := range ordersExpiringAt(tt.expiresAt) {
+			if got := es.ExpiresAt(&order); !got.Equal(tt.expiresAt) {
+				t.Fatalf("%s, %s: ExpiresAt = %v, ожидалось %v", tt.name, kind, got, tt.expiresAt)
+			}
+			if got := matchesExpiryQuery(&order, availableBefore, createdBefore); got != tt.want {
+				t.Errorf("%s, %s: попадает в выборку = %v, ожидалось %v", tt.name, kind, got, tt.want)
+			}
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package service
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	"dalnoboy/internal/database"
+	"dalnoboy/internal/domain"
+)
+
+// Виды выгрузок
+const (
+	ExportOrders    = "orders"
+	ExportCustomers = "customers"
+	ExportDrivers   = "drivers"
+)
+
+var (
+	orderExportHeader = []interface{}{"Заказ", "Создан", "Статус", "Название", "Вес, кг", "Откуда", "Адрес погрузки",
+		"Куда", "Адрес выгрузки", "Расстояние, км", "Цена", "Дата погрузки", "Тип груза", "Заказчик", "Телефон заказчика",
+		"Водитель", "Назначен", "Доставлен"}
+	customerExportHeader = []interface{}{"Заказчик", "Имя", "Телефон", "Telegram ID", "Telegram", "Активен", "Создан"}
+	driverExportHeader   = []interface{}{"Водитель", "Имя", "Телефон", "Telegram ID", "Telegram", "Город", "Уведомления",
+		"Кузов", "Грузоподъемность, кг", "ADR", "Рейтинг", "Оценок", "Источник", "Регистрация", "Создан"}
+)
+
+// ExportService представляет сервис выгрузки заказов, заказчиков и водителей в CSV и XLSX.
+// Строки читаются из базы и пишутся в файл по одной, поэтому размер выгрузки не ограничен памятью.
+type ExportService struct {
+	database     *database.Database
+	orderService *OrderService
+}
+
+// NewExportService создает новый экземпляр сервиса выгрузок
+func NewExportService(db *database.Database, orderService *OrderService) *ExportService {
+	return &ExportService{
+		database:     db,
+		orderService: orderService,
+	}
+}
+
+// ExportFileName возвращает имя файла выгрузки, например orders_20250131.xlsx
+func ExportFileName(kind, format string, now time.Time) string {
+	return fmt.Sprintf("%s_%s.%s", kind, now.Format("20060102"), format)
+}
+
+// exportTable начинает файл при первой строке: пока в w ничего не записано,
+// ошибку фильтров или базы можно вернуть обычным ответом
+type exportTable struct {
+	w      io.Writer
+	format string
+	header []interface{}
+	table  tableWriter
+	rows   int
+}
+
+func (t *exportTable) write(values []interface{}) error {
+	if t.table == nil {
+		table, err := newTableWriter(t.w, t.format)
+		if err != nil {
+			return err
+		}
+		if err := table.WriteRow(t.header); err != nil {
+			return err
+		}
+		t.table = table
+	}
+	if values == nil {
+		return nil
+	}
+	t.rows++
+	return t.table.WriteRow(values)
+}
+
+// close дописывает файл; пустая выгрузка содержит только заголовок
+func (t *exportTable) close() (int, error) {
+	if err := t.write(nil); err != nil {
+		return 0, err
+	}
+	return t.rows, t.table.Close()
+}
+
+// ExportOrders выгружает заказы по фильтрам списка заказов и возвращает число строк
+func (es *ExportService) ExportOrders(w io.Writer, format string, filter *domain.OrderFilter) (int, error) {
+	table := &exportTable{w: w, format: format, header: orderExportHeader}
+	err := es.orderService.StreamOrders(filter, func(order *domain.Order) error {
+		availableFrom := ""
+		if order.AvailableFrom != nil {
+			availableFrom = order.AvailableFrom.Format("2006-01-02")
+		}
+		return table.write([]interface{}{
+			order.UUID,
+			order.CreatedAt,
+			domain.OrderStatusNames[order.Status],
+			order.Title,
+			order.WeightKg,
+			order.FromCityName,
+			order.FromAddress,
+			order.ToCityName,
+			order.ToAddress,
+			order.DistanceKm,
+			order.Price,
+			availableFrom,
+			order.CargoTypeName,
+			order.CustomerName,
+			CustomerPhoneFor(order, domain.AdminViewer()),
+			order.AssignedDriverName,
+			order.AssignedAt,
+			order.DeliveredAt,
+		})
+	})
+	if err != nil {
+		return table.rows, err
+	}
+	return table.close()
+}
+
+// ExportCustomers выгружает всех заказчиков и возвращает число строк
+func (es *ExportService) ExportCustomers(w io.Writer, format string) (int, error) {
+	table := &exportTable{w: w, format: format, header: customerExportHeader}
+	err := es.database.StreamCustomers(func(customer *domain.Customer) error {
+		return table.write([]interface{}{
+			customer.UUID.String(),
+			customer.Name,
+			customer.Phone,
+			customer.TelegramID,
+			customer.TelegramTag,
+			customer.IsActive,
+			customer.CreatedAt,
+		})
+	})
+	if err != nil {
+		return table.rows, err
+	}
+	return table.close()
+}
+
+// ExportDrivers выгружает всех водителей и возвращает число строк
+func (es *ExportService) ExportDrivers(w io.Writer, format string) (int, error) {
+	table := &exportTable{w: w, format: format, header: driverExportHeader}
+	err := es.database.StreamDrivers(func(driver *domain.Driver) error {
+		var bodyType string
+		var payloadKg *float64
+		adr := false
+		if driver.Vehicle != nil {
+			bodyType = domain.BodyTypeNames[driver.Vehicle.BodyType]
+			payloadKg = &driver.Vehicle.PayloadKg
+			adr = driver.Vehicle.ADR
+		}
+		var rating *float64
+		if driver.RatingAvg != nil {
+			rounded := math.Round(*driver.RatingAvg*100) / 100
+			rating = &rounded
+		}
+		// Шаг незавершенной регистрации; пусто — регистрация пройдена
+		var onboardingStep *string
+		if driver.IsOnboarding() {
+			onboardingStep = driver.OnboardingStep
+		}
+		return table.write([]interface{}{
+			driver.UUID.String(),
+			driver.Name,
+			driver.Phone,
+			driver.TelegramID,
+			driver.TelegramTag,
+			driver.CityName,
+			driver.NotificationEnabled,
+			bodyType,
+			payloadKg,
+			adr,
+			rating,
+			driver.RatingCount,
+			driver.Source,
+			onboardingStep,
+			driver.CreatedAt,
+		})
+	})
+	if err != nil {
+		return table.rows, err
+	}
+	return table.close()
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"math"
+
+	"dalnoboy/internal/domain"
+	"dalnoboy/internal/geo"
+)
+
+// MaxSearchRadiusKm ограничивает радиус поиска заказов вокруг города водителя
+const MaxSearchRadiusKm = 1000
+
+// Направления поиска заказов вокруг города
+const (
+	SearchDirectionPickup   = "pickup"   // Погрузка рядом с городом
+	SearchDirectionDelivery = "delivery" // Выгрузка рядом с городом
+	SearchDirectionAny      = "any"      // Погрузка или выгрузка рядом с городом
+)
+
+// cityIndex позволяет быстро находить город по UUID при расчете расстояний
+type cityIndex map[string]domain.City
+
+func newCityIndex(cities []domain.City) cityIndex {
+	index := make(cityIndex, len(cities))
+	for _, city := range cities {
+		index[city.UUID.String()] = city
+	}
+	return index
+}
+
+// distanceKm возвращает расстояние между городами. Если у одного из городов нет координат, ok = false.
+func (idx cityIndex) distanceKm(fromUUID, toUUID string) (float64, bool) {
+	if fromUUID == toUUID {
+		return 0, true
+	}
+
+	from, ok := idx[fromUUID]
+	if !ok || !from.HasCoordinates() {
+		return 0, false
+	}
+	to, ok := idx[toUUID]
+	if !ok || !to.HasCoordinates() {
+		return 0, false
+	}
+
+	return geo.DistanceKm(cityPoint(&from), cityPoint(&to)), true
+}
+
+// routeDistanceKm оценивает расстояние маршрута по дорогам с точностью до километра.
+// Возвращает nil для внутригородских заказов и городов без координат.
+func (idx cityIndex) routeDistanceKm(fromUUID, toUUID *string) *float64 {
+	if fromUUID == nil || toUUID == nil || *fromUUID == *toUUID {
+		return nil
+	}
+	distance, ok := idx.distanceKm(*fromUUID, *toUUID)
+	if !ok {
+		return nil
+	}
+
+	road := math.Round(distance * geo.RoadCoefficient)
+	return &road
+}
+
+// withinRadius проверяет, что город cityUUID находится не дальше radiusKm от центра
+func (idx cityIndex) withinRadius(centerUUID string, cityUUID *string, radiusKm int) bool {
+	if cityUUID == nil {
+		return false
+	}
+	distance, ok := idx.distanceKm(centerUUID, *cityUUID)
+	return ok && distance <= float64(radiusKm)
+}
+
+// orderNearCity проверяет, что погрузка и/или выгрузка заказа находятся в пределах радиуса от города
+func (idx cityIndex) orderNearCity(order *domain.Order, centerUUID string, radiusKm int, direction string) bool {
+	switch direction {
+	case SearchDirectionPickup:
+		return idx.withinRadius(centerUUID, order.FromCityUUID, radiusKm)
+	case SearchDirectionDelivery:
+		return idx.withinRadius(centerUUID, order.ToCityUUID, radiusKm)
+	default:
+		return idx.withinRadius(centerUUID, order.FromCityUUID, radiusKm) ||
+			idx.withinRadius(centerUUID, order.ToCityUUID, radiusKm)
+	}
+}
+
+// driverMatchesOrder проверяет, подходит ли заказ водителю по его городу и радиусам.
+// Заказ подходит, если погрузка в городе водителя, погрузка в пределах радиуса погрузки
+// или выгрузка в пределах радиуса выгрузки (например, попутный груз домой).
+func (idx cityIndex) driverMatchesOrder(driver *domain.Driver, order *domain.Order) bool {
+	if driver.CityUUID == nil {
+		return false
+	}
+	home := driver.CityUUID.String()
+
+	if order.FromCityUUID != nil && *order.FromCityUUID == home {
+		return true
+	}
+	if driver.PickupRadiusKm != nil && idx.withinRadius(home, order.FromCityUUID, *driver.PickupRadiusKm) {
+		return true
+	}
+	if driver.DeliveryRadiusKm != nil && idx.withinRadius(home, order.ToCityUUID, *driver.DeliveryRadiusKm) {
+		return true
+	}
+	return false
+}
+
+func cityPoint(city *domain.City) geo.Point {
+	return geo.Point{Latitude: *city.Latitude, Longitude: *city.Longitude}
+}
+
+// validateRadius проверяет радиус поиска в километрах
+func validateRadius(radiusKm *int) error {
+	if radiusKm == nil {
+		return nil
+	}
+	if *radiusKm < 0 || *radiusKm > MaxSearchRadiusKm {
+		return newValidationError("радиус должен быть от 0 до %d км", MaxSearchRadiusKm)
+	}
+	return nil
+}
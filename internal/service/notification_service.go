@@ -0,0 +1,361 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"time"
+
+	"dalnoboy/internal/database"
+	"dalnoboy/internal/domain"
+)
+
+// OrderNotifier доставляет водителям уведомления о заказах
+type OrderNotifier interface {
+	NotifyNewOrder(telegramID int64, order *domain.Order) error
+	NotifyOrderUpdated(telegramID int64, order *domain.Order, changes []string) error
+}
+
+// NotificationService выбирает получателей уведомлений о заказах и ведет журнал отправок
+type NotificationService struct {
+	database    *database.Database
+	cityService *CityService
+	notifier    OrderNotifier
+}
+
+// NewNotificationService создает новый экземпляр сервиса уведомлений
+func NewNotificationService(db *database.Database, cityService *CityService) *NotificationService {
+	return &NotificationService{
+		database:    db,
+		cityService: cityService,
+	}
+}
+
+// SetNotifier задает канал доставки уведомлений (бот для водителей создается позже сервисов)
+func (ns *NotificationService) SetNotifier(notifier OrderNotifier) {
+	ns.notifier = notifier
+}
+
+// Ключи настроек приоритетной рассылки в app_settings
+const (
+	settingPriorityMinRating = "notification_priority_min_rating"
+	settingPriorityDelay     = "notification_priority_delay"
+)
+
+// NotifyNewOrder рассылает новый заказ водителям, подходящим по городу, радиусам поиска и транспорту,
+// и возвращает число получателей. Водители с более высоким рейтингом получают заказ первыми;
+// если включен приоритет, остальным уведомление откладывается на задержку и отправляется
+// планировщиком отложенных уведомлений, если заказ к тому времени еще активен.
+// Срочный заказ приходит всем сразу.
+func (ns *NotificationService) NotifyNewOrder(order *domain.Order) (int, error) {
+	if ns.notifier == nil {
+		return 0, nil
+	}
+
+	priority, err := ns.GetPriority()
+	if err != nil {
+		log.Printf("Ошибка получения настроек приоритета уведомлений: %v", err)
+	}
+	if order.Urgent {
+		priority = domain.NotificationPriority{}
+	}
+
+	return ns.sendNewOrder(order, priority)
+}
+
+// sendNewOrder отправляет заказ подходящим водителям, которые еще не получали его,
+// в порядке убывания рейтинга. Если приоритет включен, водителям без приоритета уведомление
+// откладывается на priority.Delay. Водителям в тихие часы несрочный заказ откладывается
+// до конца тихих часов.
+func (ns *NotificationService) sendNewOrder(order *domain.Order, priority domain.NotificationPriority) (int, error) {
+	drivers, err := ns.database.GetDriversToNotifyAboutOrder(order)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка получения водителей для уведомления: %v", err)
+	}
+
+	cities, err := ns.cityService.loadCityIndex()
+	if err != nil {
+		return 0, fmt.Errorf("ошибка загрузки справочника городов: %v", err)
+	}
+
+	sortDriversByRating(drivers)
+
+	now := time.Now()
+	sent := 0
+	for _, driver := range drivers {
+		if !cities.driverMatchesOrder(&driver, order) || !vehicleFits(&driver, order) {
+			continue
+		}
+		if priority.Enabled() && !priority.IsPriority(&driver) {
+			deliverAt := now.Add(priority.Delay)
+			if until, quiet := driver.QuietUntil(deliverAt); quiet {
+				deliverAt = until
+			}
+			if ns.deferNotification(&driver, order, domain.DeferredNotificationNew, nil, deliverAt) {
+				continue
+			}
+		}
+		if ns.deferIfQuiet(&driver, order, domain.DeferredNotificationNew, nil, now) {
+			continue
+		}
+		if err := ns.notifier.NotifyNewOrder(driver.TelegramID, order); err != nil {
+			log.Printf("Ошибка отправки уведомления о заказе %s водителю %s: %v", order.UUID, driver.UUID, err)
+			continue
+		}
+		if err := ns.database.RecordOrderNotification(order.UUID, driver.UUID); err != nil {
+			log.Printf("Ошибка записи уведомления о заказе %s: %v", order.UUID, err)
+		}
+		sent++
+	}
+
+	return sent, nil
+}
+
+// GetPriority возвращает настройки приоритетной рассылки водителям с высоким рейтингом
+func (ns *NotificationService) GetPriority() (domain.NotificationPriority, error) {
+	var priority domain.NotificationPriority
+
+	minRating, err := ns.database.GetSetting(settingPriorityMinRating)
+	if err != nil || minRating == "" {
+		return priority, err
+	}
+	delay, err := ns.database.GetSetting(settingPriorityDelay)
+	if err != nil {
+		return priority, err
+	}
+
+	if priority.MinRating, err = strconv.ParseFloat(minRating, 64); err != nil {
+		return domain.NotificationPriority{}, fmt.Errorf("некорректная настройка %s: %s", settingPriorityMinRating, minRating)
+	}
+	if priority.Delay, err = time.ParseDuration(delay); err != nil {
+		return domain.NotificationPriority{}, fmt.Errorf("некорректная настройка %s: %s", settingPriorityDelay, delay)
+	}
+	return priority, nil
+}
+
+// SetPriority сохраняет настройки приоритетной рассылки. Нулевой MinRating отключает приоритет.
+func (ns *NotificationService) SetPriority(priority domain.NotificationPriority) error {
+	if priority.MinRating < 0 || priority.MinRating > domain.MaxRatingScore {
+		return newValidationError("минимальный рейтинг должен быть от 0 до %d", domain.MaxRatingScore)
+	}
+	if priority.MinRating > 0 && (priority.Delay < time.Minute || priority.Delay > 24*time.Hour) {
+		return newValidationError("задержка должна быть от 1 минуты до 24 часов")
+	}
+	if priority.MinRating == 0 {
+		priority.Delay = 0
+	}
+
+	if err := ns.database.SetSetting(settingPriorityMinRating, strconv.FormatFloat(priority.MinRating, 'f', -1, 64)); err != nil {
+		return err
+	}
+	return ns.database.SetSetting(settingPriorityDelay, priority.Delay.String())
+}
+
+// sortDriversByRating упорядочивает водителей по убыванию средней оценки; без оценок — в конце
+func sortDriversByRating(drivers []domain.Driver) {
+	sort.SliceStable(drivers, func(i, j int) bool {
+		a, b := drivers[i].RatingAvg, drivers[j].RatingAvg
+		if a == nil || b == nil {
+			return a != nil && b == nil
+		}
+		return *a > *b
+	})
+}
+
+// NotifyOrderUpdated сообщает об изменении заказа водителям, которые уже получали его, и возвращает число получателей.
+// Об изменении заказа в пути узнает только назначенный водитель.
+func (ns *NotificationService) NotifyOrderUpdated(order *domain.Order, changes []string) (int, error) {
+	if ns.notifier == nil || len(changes) == 0 {
+		return 0, nil
+	}
+
+	drivers, err := ns.orderUpdateRecipients(order)
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	sent := 0
+	for _, driver := range drivers {
+		if ns.deferIfQuiet(&driver, order, domain.DeferredNotificationUpdated, changes, now) {
+			continue
+		}
+		if err := ns.notifier.NotifyOrderUpdated(driver.TelegramID, order, changes); err != nil {
+			log.Printf("Ошибка отправки изменений заказа %s водителю %s: %v", order.UUID, driver.UUID, err)
+			continue
+		}
+		sent++
+	}
+
+	return sent, nil
+}
+
+// orderUpdateRecipients возвращает водителей, которым нужно сообщить об изменении заказа
+func (ns *NotificationService) orderUpdateRecipients(order *domain.Order) ([]domain.Driver, error) {
+	switch order.Status {
+	case domain.OrderStatusActive:
+		drivers, err := ns.database.GetNotifiedDriversForOrder(order.UUID)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка получения уведомленных водителей: %v", err)
+		}
+		return drivers, nil
+	case domain.OrderStatusAssigned:
+		if order.AssignedDriverUUID == nil {
+			return nil, nil
+		}
+		driver, err := ns.database.GetDriverByUUID(*order.AssignedDriverUUID)
+		if err != nil || driver == nil {
+			return nil, err
+		}
+		return []domain.Driver{*driver}, nil
+	default:
+		return nil, nil
+	}
+}
+
+// deferIfQuiet откладывает несрочное уведомление, если у водителя сейчас тихие часы, и сообщает,
+// что отправлять его сейчас не нужно. Если отложить не удалось, уведомление отправляется сразу.
+func (ns *NotificationService) deferIfQuiet(driver *domain.Driver, order *domain.Order, kind string, changes []string, now time.Time) bool {
+	if order.Urgent {
+		return false
+	}
+	until, quiet := driver.QuietUntil(now)
+	if !quiet {
+		return false
+	}
+	return ns.deferNotification(driver, order, kind, changes, until)
+}
+
+// deferNotification сохраняет уведомление водителю в очередь до deliverAt и сообщает, удалось ли это
+func (ns *NotificationService) deferNotification(driver *domain.Driver, order *domain.Order, kind string, changes []string, deliverAt time.Time) bool {
+	notification := &domain.DeferredNotification{
+		OrderUUID:  order.UUID,
+		DriverUUID: driver.UUID,
+		Kind:       kind,
+		Changes:    changes,
+		// В базе время хранится без часового пояса, в местном времени сервера
+		DeliverAt: deliverAt.In(time.Local),
+	}
+	if err := ns.database.DeferNotification(notification); err != nil {
+		log.Printf("Ошибка откладывания уведомления о заказе %s водителю %s: %v", order.UUID, driver.UUID, err)
+		return false
+	}
+	return true
+}
+
+// SendDeferred отправляет уведомления, отложенные до конца тихих часов, и возвращает число отправленных.
+// О новом заказе водитель узнает, только если заказ еще активен и по-прежнему ему подходит.
+func (ns *NotificationService) SendDeferred(now time.Time) (int, error) {
+	if ns.notifier == nil {
+		return 0, nil
+	}
+
+	notifications, err := ns.database.GetDueDeferredNotifications(now)
+	if err != nil {
+		return 0, err
+	}
+	return ns.sendDeferred(notifications, now, false), nil
+}
+
+// SendDeferredForOrder сразу отправляет все отложенные уведомления о заказе, например после того,
+// как администратор отметил его срочным
+func (ns *NotificationService) SendDeferredForOrder(orderUUID string) (int, error) {
+	if ns.notifier == nil {
+		return 0, nil
+	}
+
+	notifications, err := ns.database.GetDeferredNotificationsForOrder(orderUUID)
+	if err != nil {
+		return 0, err
+	}
+	return ns.sendDeferred(notifications, time.Now(), true), nil
+}
+
+// sendDeferred отправляет отложенные уведомления. Если водитель тем временем сменил тихие часы
+// и они еще идут, уведомление откладывается снова, кроме случая force.
+func (ns *NotificationService) sendDeferred(notifications []domain.DeferredNotification, now time.Time, force bool) int {
+	cities, err := ns.cityService.loadCityIndex()
+	if err != nil {
+		log.Printf("Ошибка загрузки справочника городов: %v", err)
+		return 0
+	}
+
+	sent := 0
+	for _, notification := range notifications {
+		delivered, err := ns.sendDeferredNotification(&notification, cities, now, force)
+		if err != nil {
+			log.Printf("Ошибка отправки отложенного уведомления о заказе %s водителю %s: %v",
+				notification.OrderUUID, notification.DriverUUID, err)
+			continue
+		}
+		if delivered {
+			sent++
+		}
+	}
+	return sent
+}
+
+// sendDeferredNotification отправляет одно отложенное уведомление и удаляет его из очереди
+func (ns *NotificationService) sendDeferredNotification(notification *domain.DeferredNotification, cities cityIndex, now time.Time, force bool) (bool, error) {
+	order, err := ns.database.GetOrderByUUID(notification.OrderUUID)
+	if err != nil {
+		return false, err
+	}
+	driver, err := ns.database.GetDriverByUUID(notification.DriverUUID.String())
+	if err != nil {
+		return false, err
+	}
+
+	if order == nil || driver == nil || !deferredStillRelevant(notification, order, driver) {
+		return false, ns.database.DeleteDeferredNotification(notification.OrderUUID, notification.DriverUUID)
+	}
+
+	if until, quiet := driver.QuietUntil(now); quiet && !force && !order.Urgent {
+		notification.DeliverAt = until.In(time.Local)
+		notification.Changes = nil
+		return false, ns.database.DeferNotification(notification)
+	}
+
+	if err := ns.database.DeleteDeferredNotification(notification.OrderUUID, notification.DriverUUID); err != nil {
+		return false, err
+	}
+
+	switch notification.Kind {
+	case domain.DeferredNotificationNew:
+		if !driver.NotificationEnabled || driver.NotificationMode != domain.NotificationModeInstant ||
+			!cities.driverMatchesOrder(driver, order) || !vehicleFits(driver, order) {
+			return false, nil
+		}
+		if err := ns.notifier.NotifyNewOrder(driver.TelegramID, order); err != nil {
+			return false, err
+		}
+		if err := ns.database.RecordOrderNotification(order.UUID, driver.UUID); err != nil {
+			log.Printf("Ошибка записи уведомления о заказе %s: %v", order.UUID, err)
+		}
+	case domain.DeferredNotificationUpdated:
+		if len(notification.Changes) == 0 {
+			return false, nil
+		}
+		if err := ns.notifier.NotifyOrderUpdated(driver.TelegramID, order, notification.Changes); err != nil {
+			return false, err
+		}
+	default:
+		return false, fmt.Errorf("неизвестный вид отложенного уведомления: %s", notification.Kind)
+	}
+	return true, nil
+}
+
+// deferredStillRelevant проверяет, что отложенное уведомление еще нужно отправить: о новом заказе —
+// пока он активен, об изменении — пока заказ активен или водитель везет его сам
+func deferredStillRelevant(notification *domain.DeferredNotification, order *domain.Order, driver *domain.Driver) bool {
+	switch order.Status {
+	case domain.OrderStatusActive:
+		return true
+	case domain.OrderStatusAssigned:
+		return notification.Kind == domain.DeferredNotificationUpdated &&
+			order.AssignedDriverUUID != nil && *order.AssignedDriverUUID == driver.UUID.String()
+	default:
+		return false
+	}
+}
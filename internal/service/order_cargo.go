@@ -0,0 +1,109 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"dalnoboy/internal/domain"
+)
+
+// applyCargoType устанавливает заказу тип груза из справочника. Пустая ссылка очищает тип.
+func (os *OrderService) applyCargoType(order *domain.Order, ref string) error {
+	if strings.TrimSpace(ref) == "" {
+		order.CargoTypeUUID = nil
+		order.CargoTypeName = nil
+		return nil
+	}
+
+	cargoType, err := os.cargoService.resolveActiveCargoType(ref)
+	if err != nil {
+		return err
+	}
+	cargoTypeUUID := cargoType.UUID.String()
+	order.CargoTypeUUID = &cargoTypeUUID
+	order.CargoTypeName = &cargoType.Name
+	return nil
+}
+
+// applyCargoUpdate применяет изменения типа груза и особых требований и возвращает список изменений
+func (os *OrderService) applyCargoUpdate(order *domain.Order, request *domain.UpdateOrderRequest) ([]string, error) {
+	var changes []string
+
+	if request.CargoType != nil {
+		before := stringValue(order.CargoTypeName)
+		if err := os.applyCargoType(order, *request.CargoType); err != nil {
+			return nil, err
+		}
+		if after := stringValue(order.CargoTypeName); after != before {
+			changes = append(changes, fmt.Sprintf("📦 Тип груза: %s", valueOrDash(after)))
+		}
+	}
+
+	requirements := &order.Requirements
+	if request.TemperatureRange != nil {
+		var minC, maxC *float64
+		if strings.TrimSpace(*request.TemperatureRange) != "" {
+			var err error
+			if minC, maxC, err = ParseTemperatureRange(*request.TemperatureRange); err != nil {
+				return nil, err
+			}
+		}
+		before := FormatTemperatureRange(requirements.TemperatureMinC, requirements.TemperatureMaxC)
+		after := FormatTemperatureRange(minC, maxC)
+		if after != before {
+			requirements.TemperatureMinC, requirements.TemperatureMaxC = minC, maxC
+			changes = append(changes, fmt.Sprintf("❄️ Температурный режим: %s", valueOrDash(after)))
+		}
+	}
+	if request.ADRClass != nil {
+		var class *string
+		if strings.TrimSpace(*request.ADRClass) != "" {
+			parsed, err := ParseADRClass(*request.ADRClass)
+			if err != nil {
+				return nil, err
+			}
+			class = &parsed
+		}
+		if stringValue(class) != stringValue(requirements.ADRClass) {
+			requirements.ADRClass = class
+			changes = append(changes, fmt.Sprintf("☢️ Класс ADR: %s", valueOrDash(stringValue(class))))
+		}
+	}
+	if request.Oversize != nil && *request.Oversize != requirements.Oversize {
+		requirements.Oversize = *request.Oversize
+		changes = append(changes, fmt.Sprintf("📐 Негабарит: %s", yesNo(requirements.Oversize)))
+	}
+	if request.Fragile != nil && *request.Fragile != requirements.Fragile {
+		requirements.Fragile = *request.Fragile
+		changes = append(changes, fmt.Sprintf("🥚 Хрупкий груз: %s", yesNo(requirements.Fragile)))
+	}
+
+	return changes, nil
+}
+
+// FormatTemperatureRange форматирует температурный режим: "+2..+6 °C" или "-18 °C"
+func FormatTemperatureRange(minC, maxC *float64) string {
+	if minC == nil || maxC == nil {
+		return ""
+	}
+	if *minC == *maxC {
+		return fmt.Sprintf("%+g °C", *minC)
+	}
+	return fmt.Sprintf("%+g..%+g °C", *minC, *maxC)
+}
+
+// valueOrDash возвращает значение или прочерк для пустой строки
+func valueOrDash(value string) string {
+	if value == "" {
+		return "—"
+	}
+	return value
+}
+
+// yesNo возвращает "да" или "нет"
+func yesNo(value bool) string {
+	if value {
+		return "да"
+	}
+	return "нет"
+}
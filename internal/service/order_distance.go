@@ -0,0 +1,115 @@
+package service
+
+import (
+	"log"
+	"sort"
+
+	"dalnoboy/internal/domain"
+)
+
+// Порядок сортировки заказов
+const (
+	OrderSortNewest     = domain.OrderSortNewest
+	OrderSortPricePerKm = domain.OrderSortPricePerKm
+	OrderSortRelevance  = domain.OrderSortRelevance
+)
+
+// fillRouteDistance рассчитывает расстояние маршрута заказа по справочнику городов.
+// Ошибка загрузки справочника не мешает сохранению заказа: расстояние остается пустым.
+func (os *OrderService) fillRouteDistance(order *domain.Order) {
+	cities, err := os.cityService.loadCityIndex()
+	if err != nil {
+		log.Printf("Ошибка расчета расстояния заказа %s: %v", order.UUID, err)
+		order.DistanceKm = nil
+		return
+	}
+	order.DistanceKm = cities.routeDistanceKm(order.FromCityUUID, order.ToCityUUID)
+}
+
+// RecalculateDistances пересчитывает расстояния всех заказов (например, после импорта координат)
+// и возвращает количество заказов, у которых расстояние изменилось
+func (os *OrderService) RecalculateDistances() (int, error) {
+	orders, err := os.database.GetAllOrders()
+	if err != nil {
+		return 0, err
+	}
+
+	cities, err := os.cityService.loadCityIndex()
+	if err != nil {
+		return 0, err
+	}
+
+	updated := 0
+	for _, order := range orders {
+		distance := cities.routeDistanceKm(order.FromCityUUID, order.ToCityUUID)
+		if sameDistance(order.DistanceKm, distance) {
+			continue
+		}
+		if err := os.database.UpdateOrderDistance(order.UUID, distance); err != nil {
+			return updated, err
+		}
+		updated++
+	}
+
+	return updated, nil
+}
+
+// FilterOrdersByPricePerKm оставляет заказы со ставкой за километр в указанных пределах.
+// Заказы без расстояния отбрасываются, если задана хотя бы одна граница.
+func FilterOrdersByPricePerKm(orders []domain.Order, minRate, maxRate *float64) []domain.Order {
+	if minRate == nil && maxRate == nil {
+		return orders
+	}
+
+	var filtered []domain.Order
+	for i := range orders {
+		if pricePerKmInRange(&orders[i], minRate, maxRate) {
+			filtered = append(filtered, orders[i])
+		}
+	}
+	return filtered
+}
+
+// pricePerKmInRange проверяет, что ставка за километр известна и попадает в границы
+func pricePerKmInRange(order *domain.Order, minRate, maxRate *float64) bool {
+	rate := order.PricePerKm()
+	if rate == nil {
+		return false
+	}
+	if minRate != nil && *rate < *minRate {
+		return false
+	}
+	if maxRate != nil && *rate > *maxRate {
+		return false
+	}
+	return true
+}
+
+// SortOrders упорядочивает заказы: по умолчанию сначала новые,
+// при OrderSortPricePerKm — по убыванию ставки за километр (заказы без расстояния в конце)
+func SortOrders(orders []domain.Order, sortBy string) error {
+	switch sortBy {
+	case "", OrderSortNewest:
+		sort.SliceStable(orders, func(i, j int) bool {
+			return orders[i].CreatedAt.After(orders[j].CreatedAt)
+		})
+	case OrderSortPricePerKm:
+		sort.SliceStable(orders, func(i, j int) bool {
+			rateI, rateJ := orders[i].PricePerKm(), orders[j].PricePerKm()
+			if rateI == nil || rateJ == nil {
+				return rateI != nil
+			}
+			return *rateI > *rateJ
+		})
+	default:
+		return newValidationError("неизвестная сортировка '%s': используйте %s или %s", sortBy, OrderSortNewest, OrderSortPricePerKm)
+	}
+	return nil
+}
+
+func sameDistance(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"dalnoboy/internal/database"
+	"dalnoboy/internal/domain"
+)
+
+const (
+	// maxOrderEventSubscribers ограничивает число открытых лент событий на экземпляр приложения
+	maxOrderEventSubscribers = 1000
+	// orderEventBuffer — сколько событий ждут медленного подписчика, прежде чем он получит resync
+	orderEventBuffer = 32
+	// orderEventsRetryDelay — пауза перед повторной подпиской, если база недоступна
+	orderEventsRetryDelay = 5 * time.Second
+)
+
+var (
+	// ErrTooManySubscribers возвращается, когда открыто слишком много лент событий
+	ErrTooManySubscribers = errors.New("слишком много подключений к ленте заказов, попробуйте позже")
+	// ErrOrderEventsStopped возвращается после остановки приложения
+	ErrOrderEventsStopped = errors.New("лента заказов остановлена")
+)
+
+// OrderEventHub раздает события ленты заказов подписчикам (Server-Sent Events сайта).
+// События приходят из базы данных, поэтому изменения, сделанные любым экземпляром приложения,
+// видны всем подписчикам.
+type OrderEventHub struct {
+	database    *database.Database
+	mu          sync.Mutex
+	subscribers map[chan domain.OrderEvent]struct{}
+	closed      bool
+}
+
+// NewOrderEventHub создает новый экземпляр раздачи событий ленты заказов
+func NewOrderEventHub(db *database.Database) *OrderEventHub {
+	return &OrderEventHub{
+		database:    db,
+		subscribers: make(map[chan domain.OrderEvent]struct{}),
+	}
+}
+
+// Run слушает события базы данных до отмены контекста, затем закрывает каналы подписчиков
+func (h *OrderEventHub) Run(ctx context.Context) {
+	defer h.closeAll()
+	for {
+		err := h.database.ListenOrderEvents(ctx, h.broadcast)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("Ошибка ленты событий заказов: %v", err)
+		// Подписчики могли пропустить события, пока подписка не работала
+		h.broadcast(domain.OrderEvent{Type: domain.OrderEventResync})
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(orderEventsRetryDelay):
+		}
+	}
+}
+
+// Subscribe открывает ленту событий. Канал закрывается при остановке приложения;
+// вызовите возвращенную функцию, когда события больше не нужны.
+func (h *OrderEventHub) Subscribe() (<-chan domain.OrderEvent, func(), error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return nil, nil, ErrOrderEventsStopped
+	}
+	if len(h.subscribers) >= maxOrderEventSubscribers {
+		return nil, nil, ErrTooManySubscribers
+	}
+
+	events := make(chan domain.OrderEvent, orderEventBuffer)
+	h.subscribers[events] = struct{}{}
+	unsubscribe := func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		if _, ok := h.subscribers[events]; ok {
+			delete(h.subscribers, events)
+			close(events)
+		}
+	}
+	return events, unsubscribe, nil
+}
+
+// broadcast отправляет событие всем подписчикам. Переполненная очередь медленного подписчика
+// заменяется одним событием resync: клиент загрузит список заново.
+func (h *OrderEventHub) broadcast(event domain.OrderEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for events := range h.subscribers {
+		select {
+		case events <- event:
+			continue
+		default:
+		}
+	drain:
+		for {
+			select {
+			case <-events:
+			default:
+				break drain
+			}
+		}
+		// Отправляет только broadcast под блокировкой, поэтому в пустой очереди место есть
+		events <- domain.OrderEvent{Type: domain.OrderEventResync}
+	}
+}
+
+// closeAll закрывает каналы подписчиков: открытые ленты событий завершаются
+func (h *OrderEventHub) closeAll() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closed = true
+	for events := range h.subscribers {
+		delete(h.subscribers, events)
+		close(events)
+	}
+}
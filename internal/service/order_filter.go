@@ -0,0 +1,257 @@
+package service
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"dalnoboy/internal/domain"
+)
+
+// ParseOrderFilter разбирает параметры фильтрации заказов — общие для GET /v1/orders, выгрузок
+// и команды EXPORT_ORDERS: min_weight, max_weight, near_city, radius_km, direction, cargo_type,
+// requirements, min_price_per_km, max_price_per_km, from_city, to_city, min_price, max_price,
+// pickup_from, pickup_to (ГГГГ-ММ-ДД, по дате погрузки), status, from, to (ГГГГ-ММ-ДД, по дате создания),
+// q (полнотекстовый поиск), sort.
+func ParseOrderFilter(values url.Values) (*domain.OrderFilter, error) {
+	filter := &domain.OrderFilter{
+		FromCity:  strings.TrimSpace(values.Get("from_city")),
+		ToCity:    strings.TrimSpace(values.Get("to_city")),
+		NearCity:  strings.TrimSpace(values.Get("near_city")),
+		Direction: values.Get("direction"),
+		CargoType: strings.TrimSpace(values.Get("cargo_type")),
+		Query:     strings.TrimSpace(values.Get("q")),
+		Status:    values.Get("status"),
+		Sort:      values.Get("sort"),
+	}
+	if err := validateSearchQuery(filter.Query); err != nil {
+		return nil, err
+	}
+
+	numbers := []struct {
+		param  string
+		target **float64
+	}{
+		{"min_weight", &filter.MinWeight},
+		{"max_weight", &filter.MaxWeight},
+		{"min_price_per_km", &filter.MinPricePerKm},
+		{"max_price_per_km", &filter.MaxPricePerKm},
+		{"min_price", &filter.MinPrice},
+		{"max_price", &filter.MaxPrice},
+	}
+	for _, number := range numbers {
+		value := values.Get(number.param)
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, newValidationError("некорректный параметр %s", number.param)
+		}
+		*number.target = &parsed
+	}
+	if (filter.MinWeight != nil && *filter.MinWeight < 0) || (filter.MaxWeight != nil && *filter.MaxWeight < 0) {
+		return nil, newValidationError("вес не может быть отрицательным")
+	}
+	if filter.MinWeight != nil && filter.MaxWeight != nil && *filter.MinWeight > *filter.MaxWeight {
+		return nil, newValidationError("минимальный вес не может быть больше максимального")
+	}
+	if filter.MinPrice != nil && filter.MaxPrice != nil && *filter.MinPrice > *filter.MaxPrice {
+		return nil, newValidationError("минимальная цена не может быть больше максимальной")
+	}
+
+	if filter.NearCity != "" {
+		radiusKm, err := strconv.Atoi(values.Get("radius_km"))
+		if err != nil {
+			return nil, newValidationError("некорректный параметр radius_km")
+		}
+		filter.RadiusKm = radiusKm
+	}
+
+	requirements, err := ParseRequirements(values.Get("requirements"))
+	if err != nil {
+		return nil, err
+	}
+	filter.Requirements = requirements
+
+	if filter.Status != "" {
+		if _, ok := domain.OrderStatusNames[filter.Status]; !ok {
+			return nil, newValidationError("неизвестный статус заказа '%s'", filter.Status)
+		}
+	}
+	switch filter.Sort {
+	case "", OrderSortNewest, OrderSortPricePerKm:
+	case OrderSortRelevance:
+		if filter.Query == "" {
+			return nil, newValidationError("сортировка %s работает только с поисковым запросом q", OrderSortRelevance)
+		}
+	default:
+		return nil, newValidationError("неизвестная сортировка '%s': используйте %s, %s или %s", filter.Sort,
+			OrderSortNewest, OrderSortPricePerKm, OrderSortRelevance)
+	}
+
+	for _, date := range []struct {
+		param  string
+		target **time.Time
+		days   int
+	}{
+		{"from", &filter.CreatedFrom, 0},
+		{"to", &filter.CreatedTo, 1}, // Дата "по" включительная
+		{"pickup_from", &filter.PickupFrom, 0},
+		{"pickup_to", &filter.PickupTo, 1},
+	} {
+		value := values.Get(date.param)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.ParseInLocation("2006-01-02", value, time.Local)
+		if err != nil {
+			return nil, newValidationError("некорректный параметр %s, ожидается ГГГГ-ММ-ДД", date.param)
+		}
+		parsed = parsed.AddDate(0, 0, date.days)
+		*date.target = &parsed
+	}
+
+	return filter, nil
+}
+
+// maxSearchQueryLength ограничивает длину поискового запроса в символах
+const maxSearchQueryLength = 200
+
+// validateSearchQuery проверяет, что в непустом поисковом запросе есть слова и он не слишком длинный
+func validateSearchQuery(query string) error {
+	if query == "" {
+		return nil
+	}
+	if len([]rune(query)) > maxSearchQueryLength {
+		return newValidationError("поисковый запрос длиннее %d символов", maxSearchQueryLength)
+	}
+	if len(domain.SearchWords(query)) == 0 {
+		return newValidationError("в поисковом запросе нет слов: используйте буквы или цифры")
+	}
+	return nil
+}
+
+// SearchOrders ищет заказы по словам из названия, описания, адресов, городов и имени заказчика.
+// Пустой status — заказы в любом статусе. Лучше подходящие заказы — первыми.
+func (os *OrderService) SearchOrders(query, status string) ([]domain.Order, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, newValidationError("укажите, что искать")
+	}
+	if err := validateSearchQuery(query); err != nil {
+		return nil, err
+	}
+	return os.ListOrders(&domain.OrderFilter{Query: query, Status: status})
+}
+
+// orderMatcher проверяет фильтры, которые не выражаются в SQL: города, расстояние до города, груз и ставку за километр
+type orderMatcher struct {
+	filter        *domain.OrderFilter
+	cities        cityIndex
+	centerUUID    string
+	fromCityUUID  string
+	toCityUUID    string
+	cargoTypeUUID string
+}
+
+// newOrderMatcher находит города и тип груза из фильтра. Ошибки возвращаются до чтения заказов.
+func (os *OrderService) newOrderMatcher(filter *domain.OrderFilter) (*orderMatcher, error) {
+	matcher := &orderMatcher{filter: filter}
+
+	if filter.NearCity != "" {
+		if err := validateRadius(&filter.RadiusKm); err != nil {
+			return nil, err
+		}
+		switch filter.Direction {
+		case "":
+			filter.Direction = SearchDirectionAny
+		case SearchDirectionPickup, SearchDirectionDelivery, SearchDirectionAny:
+		default:
+			return nil, newValidationError("направление поиска должно быть %s, %s или %s",
+				SearchDirectionPickup, SearchDirectionDelivery, SearchDirectionAny)
+		}
+
+		center, err := os.cityService.GetCity(filter.NearCity)
+		if err != nil {
+			return nil, err
+		}
+		if !center.HasCoordinates() {
+			return nil, newValidationError("для города %s не заданы координаты", center.Name)
+		}
+		if matcher.cities, err = os.cityService.loadCityIndex(); err != nil {
+			return nil, err
+		}
+		matcher.centerUUID = center.UUID.String()
+	}
+
+	for _, city := range []struct {
+		name   string
+		target *string
+	}{
+		{filter.FromCity, &matcher.fromCityUUID},
+		{filter.ToCity, &matcher.toCityUUID},
+	} {
+		if city.name == "" {
+			continue
+		}
+		found, err := os.cityService.GetCity(city.name)
+		if err != nil {
+			return nil, err
+		}
+		*city.target = found.UUID.String()
+	}
+
+	if filter.CargoType != "" {
+		cargoType, err := os.cargoService.GetCargoType(filter.CargoType)
+		if err != nil {
+			return nil, err
+		}
+		matcher.cargoTypeUUID = cargoType.UUID.String()
+	}
+	return matcher, nil
+}
+
+func (m *orderMatcher) matches(order *domain.Order) bool {
+	if m.fromCityUUID != "" && (order.FromCityUUID == nil || *order.FromCityUUID != m.fromCityUUID) {
+		return false
+	}
+	if m.toCityUUID != "" && (order.ToCityUUID == nil || *order.ToCityUUID != m.toCityUUID) {
+		return false
+	}
+	if m.centerUUID != "" && !m.cities.orderNearCity(order, m.centerUUID, m.filter.RadiusKm, m.filter.Direction) {
+		return false
+	}
+	if !orderMatchesCargo(order, m.cargoTypeUUID, m.filter.Requirements) {
+		return false
+	}
+	if m.filter.MinPricePerKm != nil || m.filter.MaxPricePerKm != nil {
+		return pricePerKmInRange(order, m.filter.MinPricePerKm, m.filter.MaxPricePerKm)
+	}
+	return true
+}
+
+// StreamOrders построчно передает в fn заказы, подходящие под фильтр, не загружая их все в память
+func (os *OrderService) StreamOrders(filter *domain.OrderFilter, fn func(order *domain.Order) error) error {
+	matcher, err := os.newOrderMatcher(filter)
+	if err != nil {
+		return err
+	}
+	return os.database.StreamOrders(filter, func(order *domain.Order) error {
+		if !matcher.matches(order) {
+			return nil
+		}
+		return fn(order)
+	})
+}
+
+// ListOrders возвращает заказы, подходящие под фильтр
+func (os *OrderService) ListOrders(filter *domain.OrderFilter) ([]domain.Order, error) {
+	var orders []domain.Order
+	err := os.StreamOrders(filter, func(order *domain.Order) error {
+		orders = append(orders, *order)
+		return nil
+	})
+	return orders, err
+}
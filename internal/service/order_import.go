@@ -0,0 +1,621 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"dalnoboy/internal/domain"
+)
+
+const (
+	// maxImportFileSize ограничивает размер импортируемого файла
+	maxImportFileSize = 10 << 20
+	// maxImportRows ограничивает число строк данных в одном импорте
+	maxImportRows = 500
+	// maxXLSXPartSize ограничивает распакованный размер XML-части книги, чтобы zip-бомба не исчерпала память
+	maxXLSXPartSize = 50 << 20
+)
+
+// importFieldAliases сопоставляет нормализованные заголовки колонок полям заказа
+var importFieldAliases = map[string]string{
+	"название":         domain.ImportFieldTitle,
+	"груз":             domain.ImportFieldTitle,
+	"описание":         domain.ImportFieldDescription,
+	"вес":              domain.ImportFieldWeight,
+	"вес кг":           domain.ImportFieldWeight,
+	"откуда":           domain.ImportFieldFromCity,
+	"город погрузки":   domain.ImportFieldFromCity,
+	"адрес погрузки":   domain.ImportFieldFromAddress,
+	"откуда адрес":     domain.ImportFieldFromAddress,
+	"куда":             domain.ImportFieldToCity,
+	"город выгрузки":   domain.ImportFieldToCity,
+	"адрес выгрузки":   domain.ImportFieldToAddress,
+	"куда адрес":       domain.ImportFieldToAddress,
+	"цена":             domain.ImportFieldPrice,
+	"ставка":           domain.ImportFieldPrice,
+	"дата":             domain.ImportFieldDate,
+	"дата погрузки":    domain.ImportFieldDate,
+	"тип груза":        domain.ImportFieldCargoType,
+	"температура":      domain.ImportFieldTemperature,
+	"adr":              domain.ImportFieldADR,
+	"теги":             domain.ImportFieldTags,
+	"длина":            domain.ImportFieldLength,
+	"длина см":         domain.ImportFieldLength,
+	"ширина":           domain.ImportFieldWidth,
+	"ширина см":        domain.ImportFieldWidth,
+	"высота":           domain.ImportFieldHeight,
+	"высота см":        domain.ImportFieldHeight,
+	"негабарит":        domain.ImportFieldOversize,
+	"хрупкий":          domain.ImportFieldFragile,
+	"хрупкий груз":     domain.ImportFieldFragile,
+	"описание груза":   domain.ImportFieldDescription,
+	"наименование":     domain.ImportFieldTitle,
+	"город отправки":   domain.ImportFieldFromCity,
+	"город назначения": domain.ImportFieldToCity,
+}
+
+// requiredImportFields — колонки, без которых заказ не пройдет проверки CreateOrder
+var requiredImportFields = []string{
+	domain.ImportFieldTitle,
+	domain.ImportFieldDescription,
+	domain.ImportFieldWeight,
+	domain.ImportFieldFromCity,
+	domain.ImportFieldToCity,
+	domain.ImportFieldPrice,
+}
+
+// importFieldNames — названия полей для сообщений об ошибках
+var importFieldNames = map[string]string{
+	domain.ImportFieldTitle:       "Название",
+	domain.ImportFieldDescription: "Описание",
+	domain.ImportFieldWeight:      "Вес",
+	domain.ImportFieldFromCity:    "Откуда",
+	domain.ImportFieldFromAddress: "Адрес погрузки",
+	domain.ImportFieldToCity:      "Куда",
+	domain.ImportFieldToAddress:   "Адрес выгрузки",
+	domain.ImportFieldPrice:       "Цена",
+	domain.ImportFieldDate:        "Дата",
+	domain.ImportFieldCargoType:   "Тип груза",
+	domain.ImportFieldTemperature: "Температура",
+	domain.ImportFieldADR:         "ADR",
+	domain.ImportFieldTags:        "Теги",
+	domain.ImportFieldLength:      "Длина",
+	domain.ImportFieldWidth:       "Ширина",
+	domain.ImportFieldHeight:      "Высота",
+	domain.ImportFieldOversize:    "Негабарит",
+	domain.ImportFieldFragile:     "Хрупкий",
+}
+
+// ImportFieldName возвращает русское название поля заказа для отчета об импорте
+func ImportFieldName(field string) string {
+	if name, ok := importFieldNames[field]; ok {
+		return name
+	}
+	return field
+}
+
+// ReadOrderSheet читает строки таблицы из CSV или XLSX файла. Формат определяется по расширению.
+func ReadOrderSheet(fileName string, r io.Reader) ([][]string, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxImportFileSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения файла: %v", err)
+	}
+	if len(data) > maxImportFileSize {
+		return nil, newValidationError("файл больше %d МБ", maxImportFileSize>>20)
+	}
+
+	switch strings.ToLower(path.Ext(fileName)) {
+	case ".csv", ".txt":
+		return readCSVSheet(data)
+	case ".xlsx":
+		return readXLSXSheet(data)
+	default:
+		return nil, newValidationError("поддерживаются файлы .csv и .xlsx")
+	}
+}
+
+// readCSVSheet разбирает CSV, определяя разделитель (Excel в русской локали сохраняет через ";")
+func readCSVSheet(data []byte) ([][]string, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	header, _, _ := bytes.Cut(data, []byte("\n"))
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	if bytes.Count(header, []byte(";")) > bytes.Count(header, []byte(",")) {
+		reader.Comma = ';'
+	}
+
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, newValidationError("не удалось разобрать CSV: %v", err)
+	}
+	return rows, nil
+}
+
+// Структуры XLSX (Office Open XML), нужные для чтения значений первого листа
+type xlsxWorkbook struct {
+	Sheets []struct {
+		RelID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxRelationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+type xlsxSharedStrings struct {
+	Items []xlsxText `xml:"si"`
+}
+
+// xlsxText — строка с форматированием: текст целиком в <t> или по частям в <r><t>
+type xlsxText struct {
+	T    string `xml:"t"`
+	Runs []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxText) String() string {
+	if len(t.Runs) == 0 {
+		return t.T
+	}
+	var sb strings.Builder
+	for _, run := range t.Runs {
+		sb.WriteString(run.T)
+	}
+	return sb.String()
+}
+
+type xlsxWorksheet struct {
+	Rows []struct {
+		Index int `xml:"r,attr"`
+		Cells []struct {
+			Ref    string   `xml:"r,attr"`
+			Type   string   `xml:"t,attr"`
+			Value  string   `xml:"v"`
+			Inline xlsxText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// readXLSXSheet читает первый лист книги XLSX
+func readXLSXSheet(data []byte) ([][]string, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, newValidationError("файл не является книгой XLSX: %v", err)
+	}
+	files := make(map[string]*zip.File, len(archive.File))
+	for _, file := range archive.File {
+		files[file.Name] = file
+	}
+
+	var sharedStrings xlsxSharedStrings
+	if file, ok := files["xl/sharedStrings.xml"]; ok {
+		if err := decodeXLSXPart(file, &sharedStrings); err != nil {
+			return nil, err
+		}
+	}
+
+	sheetFile, ok := files[firstSheetPath(files)]
+	if !ok {
+		return nil, newValidationError("в книге XLSX нет листов")
+	}
+	var sheet xlsxWorksheet
+	if err := decodeXLSXPart(sheetFile, &sheet); err != nil {
+		return nil, err
+	}
+
+	var rows [][]string
+	for _, row := range sheet.Rows {
+		// Пустые строки в XLSX не сохраняются: номер берем из атрибута, чтобы ошибки указывали на строку в Excel
+		index := len(rows)
+		if row.Index > 0 {
+			index = row.Index - 1
+		}
+		if index > maxImportRows*2 {
+			return nil, newValidationError("в таблице больше %d строк, разделите ее на несколько файлов", maxImportRows)
+		}
+		for len(rows) <= index {
+			rows = append(rows, nil)
+		}
+
+		var values []string
+		for i, cell := range row.Cells {
+			column := i
+			if cell.Ref != "" {
+				column = xlsxColumnIndex(cell.Ref)
+			}
+			if column < 0 || column > 100 {
+				continue
+			}
+			for len(values) <= column {
+				values = append(values, "")
+			}
+
+			switch cell.Type {
+			case "s":
+				if n, err := strconv.Atoi(cell.Value); err == nil && n >= 0 && n < len(sharedStrings.Items) {
+					values[column] = sharedStrings.Items[n].String()
+				}
+			case "inlineStr":
+				values[column] = cell.Inline.String()
+			default:
+				values[column] = cell.Value
+			}
+		}
+		rows[index] = values
+	}
+	return rows, nil
+}
+
+// firstSheetPath находит файл первого листа по описанию книги
+func firstSheetPath(files map[string]*zip.File) string {
+	const fallback = "xl/worksheets/sheet1.xml"
+
+	var workbook xlsxWorkbook
+	var relationships xlsxRelationships
+	workbookFile, ok := files["xl/workbook.xml"]
+	relsFile, relsOK := files["xl/_rels/workbook.xml.rels"]
+	if !ok || !relsOK || decodeXLSXPart(workbookFile, &workbook) != nil ||
+		decodeXLSXPart(relsFile, &relationships) != nil || len(workbook.Sheets) == 0 {
+		return fallback
+	}
+
+	for _, rel := range relationships.Relationships {
+		if rel.ID != workbook.Sheets[0].RelID {
+			continue
+		}
+		if strings.HasPrefix(rel.Target, "/") {
+			return strings.TrimPrefix(rel.Target, "/")
+		}
+		return path.Join("xl", rel.Target)
+	}
+	return fallback
+}
+
+// decodeXLSXPart разбирает XML-часть книги
+func decodeXLSXPart(file *zip.File, target interface{}) error {
+	if file.UncompressedSize64 > maxXLSXPartSize {
+		return newValidationError("часть книги %s больше %d МБ после распаковки", file.Name, maxXLSXPartSize>>20)
+	}
+	reader, err := file.Open()
+	if err != nil {
+		return newValidationError("не удалось прочитать %s: %v", file.Name, err)
+	}
+	defer reader.Close()
+	// Размер в заголовке zip задает отправитель, поэтому чтение ограничено и по факту
+	if err := xml.NewDecoder(io.LimitReader(reader, maxXLSXPartSize)).Decode(target); err != nil {
+		return newValidationError("не удалось разобрать %s: %v", file.Name, err)
+	}
+	return nil
+}
+
+// xlsxColumnIndex переводит ссылку на ячейку ("C12") в номер колонки с нуля
+func xlsxColumnIndex(ref string) int {
+	column := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		column = column*26 + int(r-'A'+1)
+	}
+	return column - 1
+}
+
+// normalizeImportHeader приводит заголовок колонки к виду для поиска: "Вес, кг" → "вес кг"
+func normalizeImportHeader(header string) string {
+	header = strings.ReplaceAll(strings.ToLower(header), "ё", "е")
+	words := strings.FieldsFunc(header, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+	})
+	return strings.Join(words, " ")
+}
+
+// lookupImportField возвращает поле заказа по коду поля или названию колонки
+func lookupImportField(name string) (string, bool) {
+	name = normalizeImportHeader(name)
+	if _, ok := importFieldNames[name]; ok {
+		return name, true
+	}
+	field, ok := importFieldAliases[name]
+	return field, ok
+}
+
+// ParseImportMapping разбирает строки "Заголовок колонки = поле", задающие нестандартные колонки.
+// Поле указывается кодом (title, price) или одним из стандартных названий колонок ("Цена").
+func ParseImportMapping(lines []string) (map[string]string, error) {
+	mapping := make(map[string]string)
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		header, fieldName, found := strings.Cut(line, "=")
+		if !found {
+			return nil, newValidationError("строка сопоставления '%s' должна иметь вид 'Заголовок = поле'", line)
+		}
+		field, ok := lookupImportField(fieldName)
+		if !ok {
+			return nil, newValidationError("неизвестное поле заказа '%s'", strings.TrimSpace(fieldName))
+		}
+		mapping[normalizeImportHeader(header)] = field
+	}
+	return mapping, nil
+}
+
+// PrepareOrderImport проверяет строки таблицы по тем же правилам, что и создание заказа,
+// и собирает заказы без сохранения. Первая непустая строка считается заголовком;
+// mapping дополняет стандартные названия колонок.
+func (os *OrderService) PrepareOrderImport(customerUUID string, rows [][]string, mapping map[string]string) (*domain.OrderImport, error) {
+	if customerUUID == "" {
+		return nil, newValidationError("customerUUID не может быть пустым")
+	}
+	if err := os.ensureCustomerActive(customerUUID); err != nil {
+		return nil, err
+	}
+
+	headerRow := -1
+	for i, row := range rows {
+		if !isEmptyImportRow(row) {
+			headerRow = i
+			break
+		}
+	}
+	if headerRow < 0 {
+		return nil, newValidationError("таблица пуста")
+	}
+
+	columns := make(map[string]int)
+	imp := &domain.OrderImport{CustomerUUID: customerUUID, Columns: make(map[string]string)}
+	for i, header := range rows[headerRow] {
+		field, ok := mapping[normalizeImportHeader(header)]
+		if !ok {
+			field, ok = lookupImportField(header)
+		}
+		if !ok {
+			continue
+		}
+		if _, duplicate := columns[field]; duplicate {
+			return nil, newValidationError("поле «%s» указано в нескольких колонках", ImportFieldName(field))
+		}
+		columns[field] = i
+		imp.Columns[field] = strings.TrimSpace(header)
+	}
+
+	var missing []string
+	for _, field := range requiredImportFields {
+		if _, ok := columns[field]; !ok {
+			missing = append(missing, ImportFieldName(field))
+		}
+	}
+	if len(missing) > 0 {
+		return nil, newValidationError("в таблице не найдены колонки: %s", strings.Join(missing, ", "))
+	}
+
+	unknownCities := make(map[string]bool)
+	resolvedCities := make(map[string]error)
+	for i := headerRow + 1; i < len(rows); i++ {
+		if isEmptyImportRow(rows[i]) {
+			continue
+		}
+		imp.TotalRows++
+		if imp.TotalRows > maxImportRows {
+			return nil, newValidationError("в таблице больше %d строк, разделите ее на несколько файлов", maxImportRows)
+		}
+
+		value := func(field string) string {
+			column, ok := columns[field]
+			if !ok || column >= len(rows[i]) {
+				return ""
+			}
+			return strings.TrimSpace(rows[i][column])
+		}
+
+		// Города проверяем заранее, чтобы собрать в отчете все неизвестные названия
+		for _, field := range []string{domain.ImportFieldFromCity, domain.ImportFieldToCity} {
+			name := value(field)
+			if name == "" {
+				continue
+			}
+			key := strings.ToLower(name)
+			err, resolved := resolvedCities[key]
+			if !resolved {
+				_, err = os.cityService.ResolveCity(name)
+				resolvedCities[key] = err
+			}
+			var cityErr *CityNotFoundError
+			if errors.As(err, &cityErr) {
+				unknownCities[name] = true
+			}
+		}
+
+		order, err := os.buildImportedOrder(customerUUID, value)
+		if err != nil {
+			imp.Errors = append(imp.Errors, domain.OrderImportRowError{Row: i + 1, Message: err.Error()})
+			continue
+		}
+		imp.Orders = append(imp.Orders, order)
+	}
+
+	for name := range unknownCities {
+		imp.UnknownCities = append(imp.UnknownCities, name)
+	}
+	sort.Strings(imp.UnknownCities)
+	return imp, nil
+}
+
+// buildImportedOrder собирает заказ из значений строки таблицы
+func (os *OrderService) buildImportedOrder(customerUUID string, value func(field string) string) (*domain.Order, error) {
+	request := &domain.CreateOrderTgRequest{
+		Title:            value(domain.ImportFieldTitle),
+		Description:      value(domain.ImportFieldDescription),
+		FromCityName:     value(domain.ImportFieldFromCity),
+		FromAddress:      value(domain.ImportFieldFromAddress),
+		ToCityName:       value(domain.ImportFieldToCity),
+		ToAddress:        value(domain.ImportFieldToAddress),
+		CustomerUUID:     customerUUID,
+		CargoType:        value(domain.ImportFieldCargoType),
+		TemperatureRange: value(domain.ImportFieldTemperature),
+		ADRClass:         value(domain.ImportFieldADR),
+	}
+	if request.FromCityName == "" {
+		return nil, newValidationError("не указан город погрузки")
+	}
+	if request.ToCityName == "" {
+		return nil, newValidationError("не указан город выгрузки")
+	}
+
+	var err error
+	if request.WeightKg, err = parseImportNumber(value(domain.ImportFieldWeight), domain.ImportFieldWeight); err != nil {
+		return nil, err
+	}
+	if request.Price, err = parseImportNumber(value(domain.ImportFieldPrice), domain.ImportFieldPrice); err != nil {
+		return nil, err
+	}
+	if request.Oversize, err = parseImportFlag(value(domain.ImportFieldOversize), domain.ImportFieldOversize); err != nil {
+		return nil, err
+	}
+	if request.Fragile, err = parseImportFlag(value(domain.ImportFieldFragile), domain.ImportFieldFragile); err != nil {
+		return nil, err
+	}
+
+	order, err := os.buildOrderFromTgRequest(request)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, dim := range []struct {
+		field  string
+		target **float64
+	}{
+		{domain.ImportFieldLength, &order.LengthCm},
+		{domain.ImportFieldWidth, &order.WidthCm},
+		{domain.ImportFieldHeight, &order.HeightCm},
+	} {
+		text := value(dim.field)
+		if text == "" {
+			continue
+		}
+		size, err := parseImportNumber(text, dim.field)
+		if err != nil {
+			return nil, err
+		}
+		*dim.target = &size
+	}
+
+	if text := value(domain.ImportFieldDate); text != "" {
+		date, err := parseImportDate(text)
+		if err != nil {
+			return nil, err
+		}
+		order.AvailableFrom = &date
+	}
+
+	if text := value(domain.ImportFieldTags); text != "" {
+		for _, tag := range strings.FieldsFunc(text, func(r rune) bool { return r == ',' || r == ';' }) {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				order.Tags = append(order.Tags, tag)
+			}
+		}
+	}
+
+	return order, nil
+}
+
+// CommitOrderImport сохраняет все заказы импорта одной транзакцией и рассылает их водителям
+func (os *OrderService) CommitOrderImport(imp *domain.OrderImport) error {
+	if len(imp.Orders) == 0 {
+		return newValidationError("в импорте нет заказов без ошибок")
+	}
+	if err := os.ensureCustomerActive(imp.CustomerUUID); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, order := range imp.Orders {
+		order.CreatedAt = now
+	}
+	if err := os.database.CreateOrders(imp.Orders); err != nil {
+		return fmt.Errorf("ошибка сохранения заказов: %v", err)
+	}
+
+	for _, order := range imp.Orders {
+		os.notifyNewOrder(order.UUID)
+	}
+	return nil
+}
+
+// isEmptyImportRow проверяет, что в строке таблицы нет значений
+func isEmptyImportRow(row []string) bool {
+	for _, value := range row {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// parseImportNumber разбирает число из ячейки: "12 000", "1500,5", "25000 ₽"
+func parseImportNumber(text, field string) (float64, error) {
+	cleaned := strings.Map(func(r rune) rune {
+		switch {
+		case unicode.IsSpace(r), r == '₽':
+			return -1
+		case r == ',':
+			return '.'
+		}
+		return r
+	}, text)
+	if cleaned == "" {
+		return 0, newValidationError("не заполнено поле «%s»", ImportFieldName(field))
+	}
+	value, err := strconv.ParseFloat(cleaned, 64)
+	if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, newValidationError("поле «%s»: '%s' не является числом", ImportFieldName(field), text)
+	}
+	if value < 0 {
+		return 0, newValidationError("поле «%s» не может быть отрицательным", ImportFieldName(field))
+	}
+	return value, nil
+}
+
+// parseImportFlag разбирает отметку "да"/"нет" из ячейки; пустая ячейка означает "нет"
+func parseImportFlag(text, field string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(text)) {
+	case "", "нет", "no", "false", "0", "-":
+		return false, nil
+	case "да", "yes", "true", "1", "+":
+		return true, nil
+	}
+	return false, newValidationError("поле «%s»: ожидается да или нет, получено '%s'", ImportFieldName(field), text)
+}
+
+// parseImportDate разбирает дату погрузки: ГГГГ-ММ-ДД, ДД.ММ.ГГГГ или число-дату Excel
+func parseImportDate(text string) (time.Time, error) {
+	for _, layout := range []string{"2006-01-02", "02.01.2006", "2.1.2006", "02.01.06"} {
+		if date, err := time.Parse(layout, text); err == nil {
+			return date, nil
+		}
+	}
+	// XLSX хранит даты как число дней от 30.12.1899
+	if serial, err := strconv.ParseFloat(text, 64); err == nil && serial > 1 && serial < 100000 {
+		return time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC).AddDate(0, 0, int(serial)), nil
+	}
+	return time.Time{}, newValidationError("некорректная дата '%s', ожидается ДД.ММ.ГГГГ или ГГГГ-ММ-ДД", text)
+}
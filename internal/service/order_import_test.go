@@ -0,0 +1,217 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"dalnoboy/internal/domain"
+)
+
+// buildXLSX собирает книгу XLSX из XML-частей
+func buildXLSX(t *testing.T, parts map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for name, content := range parts {
+		part, err := archive.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := part.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+const (
+	testXLSXWorkbook = `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"
+	xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+	<sheets><sheet name="Заказы" sheetId="1" r:id="rId7"/></sheets></workbook>`
+	testXLSXRels = `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+	<Relationship Id="rId1" Target="styles.xml"/>
+	<Relationship Id="rId7" Target="worksheets/orders.xml"/></Relationships>`
+	testXLSXSharedStrings = `<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+	<si><t>Название</t></si>
+	<si><r><t>Цена</t></r><r><rPr><b/></rPr><t>, ₽</t></r></si></sst>`
+	testXLSXSheet = `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>
+	<row r="1"><c r="A1" t="s"><v>0</v></c><c r="C1" t="s"><v>1</v></c></row>
+	<row r="3"><c r="A3" t="inlineStr"><is><t>Диван</t></is></c><c r="C3"><v>5000</v></c></row>
+	<row r="4"><c t="inlineStr"><is><r><t>Кресло </t></r><r><t>мягкое</t></r></is></c><c><v>1500.5</v></c></row>
+	</sheetData></worksheet>`
+)
+
+func TestReadOrderSheet(t *testing.T) {
+	tests := []struct {
+		name     string
+		fileName string
+		data     []byte
+		want     [][]string
+	}{
+		{
+			name:     "CSV с точкой с запятой и BOM",
+			fileName: "orders.csv",
+			data:     []byte("\xef\xbb\xbfНазвание;Вес;Цена\nДиван, угловой;1 200,5;12 000 ₽\n"),
+			want:     [][]string{{"Название", "Вес", "Цена"}, {"Диван, угловой", "1 200,5", "12 000 ₽"}},
+		},
+		{
+			name:     "CSV с запятой и BOM",
+			fileName: "ORDERS.CSV",
+			data:     []byte("\xef\xbb\xbfНазвание,Вес\r\n\"Шкаф; купе\",80\r\n"),
+			want:     [][]string{{"Название", "Вес"}, {"Шкаф; купе", "80"}},
+		},
+		{
+			name:     "CSV пропускает пустые строки",
+			fileName: "orders.txt",
+			data:     []byte("Название;Вес\n\nДиван;50\n"),
+			want:     [][]string{{"Название", "Вес"}, {"Диван", "50"}},
+		},
+		{
+			name:     "XLSX: общие и встроенные строки, пропуски строк и колонок",
+			fileName: "orders.xlsx",
+			data: buildXLSX(t, map[string]string{
+				"xl/workbook.xml":            testXLSXWorkbook,
+				"xl/_rels/workbook.xml.rels": testXLSXRels,
+				"xl/sharedStrings.xml":       testXLSXSharedStrings,
+				"xl/worksheets/orders.xml":   testXLSXSheet,
+			}),
+			want: [][]string{
+				{"Название", "", "Цена, ₽"},
+				nil,
+				{"Диван", "", "5000"},
+				{"Кресло мягкое", "1500.5"},
+			},
+		},
+		{
+			name:     "XLSX без описания книги читает sheet1",
+			fileName: "orders.xlsx",
+			data: buildXLSX(t, map[string]string{
+				"xl/worksheets/sheet1.xml": `<worksheet><sheetData><row r="2"><c r="B2"><v>7</v></c></row></sheetData></worksheet>`,
+			}),
+			want: [][]string{nil, {"", "7"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := ReadOrderSheet(tt.fileName, bytes.NewReader(tt.data))
+			if err != nil {
+				t.Fatalf("неожиданная ошибка: %v", err)
+			}
+			if !reflect.DeepEqual(rows, tt.want) {
+				t.Fatalf("строки %q, ожидались %q", rows, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadOrderSheetErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		fileName string
+		data     []byte
+	}{
+		{"неподдерживаемый формат", "orders.xls", []byte("Название;Вес\n")},
+		{"не zip-архив", "orders.xlsx", []byte("Название;Вес\n")},
+		{"книга без листов", "orders.xlsx", buildXLSX(t, map[string]string{"xl/styles.xml": "<styleSheet/>"})},
+		{"битый XML листа", "orders.xlsx", buildXLSX(t, map[string]string{"xl/worksheets/sheet1.xml": "<worksheet><sheetData><row>"})},
+		{"распакованная часть больше лимита", "orders.xlsx", buildXLSX(t, map[string]string{
+			"xl/worksheets/sheet1.xml": "<worksheet>" + strings.Repeat(" ", maxXLSXPartSize) + "</worksheet>",
+		})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadOrderSheet(tt.fileName, bytes.NewReader(tt.data))
+			if !IsValidationError(err) {
+				t.Fatalf("ожидалась ошибка валидации, получено %v", err)
+			}
+		})
+	}
+}
+
+func TestParseImportMapping(t *testing.T) {
+	mapping, err := ParseImportMapping([]string{
+		"Стоимость перевозки = Цена",
+		"",
+		"  Вес, кг (брутто) = weight  ",
+		"Пункт Б = to_city",
+	})
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	want := map[string]string{
+		"стоимость перевозки": domain.ImportFieldPrice,
+		"вес кг брутто":       domain.ImportFieldWeight,
+		"пункт б":             domain.ImportFieldToCity,
+	}
+	if !reflect.DeepEqual(mapping, want) {
+		t.Fatalf("сопоставление %v, ожидалось %v", mapping, want)
+	}
+
+	for _, lines := range [][]string{
+		{"Стоимость"},
+		{"Стоимость = скидка"},
+		{"Стоимость = "},
+	} {
+		if _, err := ParseImportMapping(lines); !IsValidationError(err) {
+			t.Errorf("ParseImportMapping(%q): ожидалась ошибка валидации, получено %v", lines, err)
+		}
+	}
+}
+
+func TestParseImportNumber(t *testing.T) {
+	tests := []struct {
+		text    string
+		want    float64
+		wantErr bool
+	}{
+		{"12 000 ₽", 12000, false},
+		{"1500,5", 1500.5, false},
+		{"25000", 25000, false},
+		{"12 000", 12000, false}, // Неразрывный пробел из Excel
+		{" 0 ", 0, false},
+		{"", 0, true},
+		{"₽", 0, true},
+		{"много", 0, true},
+		{"1,500,5", 0, true},
+		{"-5", 0, true},
+		{"NaN", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseImportNumber(tt.text, domain.ImportFieldPrice)
+		if tt.wantErr {
+			if !IsValidationError(err) {
+				t.Errorf("parseImportNumber(%q): ожидалась ошибка валидации, получено %v, %v", tt.text, got, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("parseImportNumber(%q) = %v, %v, ожидалось %v", tt.text, got, err, tt.want)
+		}
+	}
+}
+
+func TestParseImportDate(t *testing.T) {
+	want := time.Date(2024, 11, 4, 0, 0, 0, 0, time.UTC)
+	for _, text := range []string{"2024-11-04", "04.11.2024", "4.11.2024", "04.11.24", "45600", "45600.75"} {
+		got, err := parseImportDate(text)
+		if err != nil || !got.Equal(want) {
+			t.Errorf("parseImportDate(%q) = %v, %v, ожидалось %v", text, got, err, want)
+		}
+	}
+
+	for _, text := range []string{"", "завтра", "31.02.2024", "2024/11/04", "1", "100000"} {
+		if got, err := parseImportDate(text); !IsValidationError(err) {
+			t.Errorf("parseImportDate(%q) = %v, ожидалась ошибка валидации", text, got)
+		}
+	}
+}
@@ -2,6 +2,8 @@ package service
 
 import (
 	"fmt"
+	"log"
+	"strings"
 	"time"
 
 	"dalnoboy/internal/database"
@@ -12,18 +14,39 @@ import (
 
 // OrderService представляет сервис для работы с заказами
 type OrderService struct {
-	database *database.Database
-	cityRepo domain.CityRepository
+	database            *database.Database
+	cityService         *CityService
+	cargoService        *CargoService
+	notificationService *NotificationService
 }
 
 // NewOrderService создает новый экземпляр сервиса заказов
-func NewOrderService(db *database.Database, cityRepo domain.CityRepository) *OrderService {
+func NewOrderService(db *database.Database, cityService *CityService, cargoService *CargoService, notificationService *NotificationService) *OrderService {
 	return &OrderService{
-		database: db,
-		cityRepo: cityRepo,
+		database:            db,
+		cityService:         cityService,
+		cargoService:        cargoService,
+		notificationService: notificationService,
 	}
 }
 
+// validateOrderFields проверяет обязательные поля заказа
+func validateOrderFields(title, description string, weightKg, price float64) error {
+	if title == "" {
+		return newValidationError("название заказа не может быть пустым")
+	}
+	if description == "" {
+		return newValidationError("описание заказа не может быть пустым")
+	}
+	if weightKg <= 0 {
+		return newValidationError("вес должен быть больше нуля")
+	}
+	if price <= 0 {
+		return newValidationError("цена должна быть больше нуля")
+	}
+	return nil
+}
+
 // CreateOrder создает новый заказ
 func (os *OrderService) CreateOrder(
 	customerUUID, title, description string,
@@ -33,24 +56,23 @@ func (os *OrderService) CreateOrder(
 	tags []string,
 	price float64,
 	availableFrom *time.Time,
+	cargoType string,
+	requirements domain.CargoRequirements,
 ) (*domain.Order, error) {
 	// Проверяем, что customerUUID не пустой
 	if customerUUID == "" {
-		return nil, fmt.Errorf("customerUUID не может быть пустым")
+		return nil, newValidationError("customerUUID не может быть пустым")
 	}
 
 	// Проверяем обязательные поля
-	if title == "" {
-		return nil, fmt.Errorf("название заказа не может быть пустым")
+	if err := validateOrderFields(title, description, weightKg, price); err != nil {
+		return nil, err
 	}
-	if description == "" {
-		return nil, fmt.Errorf("описание заказа не может быть пустым")
+	if err := os.ensureCustomerActive(customerUUID); err != nil {
+		return nil, err
 	}
-	if weightKg <= 0 {
-		return nil, fmt.Errorf("вес должен быть больше нуля")
-	}
-	if price <= 0 {
-		return nil, fmt.Errorf("цена должна быть больше нуля")
+	if err := validateCargoRequirements(&requirements); err != nil {
+		return nil, err
 	}
 
 	// Создаем новый заказ
@@ -70,65 +92,91 @@ func (os *OrderService) CreateOrder(
 		Tags:          tags,
 		Price:         price,
 		AvailableFrom: availableFrom,
+		Requirements:  requirements,
 		Status:        domain.OrderStatusActive,
 		CreatedAt:     time.Now(),
 	}
 
+	if err := os.applyCargoType(order, cargoType); err != nil {
+		return nil, err
+	}
+
+	os.fillRouteDistance(order)
+
 	// Сохраняем в базу данных
 	err := os.database.CreateOrder(order)
 	if err != nil {
 		return nil, fmt.Errorf("ошибка сохранения заказа: %v", err)
 	}
 
+	os.notifyNewOrder(order.UUID)
+
 	return order, nil
 }
 
 // CreateOrderFromTgRequest создает новый заказ из упрощенного Telegram-запроса
 func (os *OrderService) CreateOrderFromTgRequest(request *domain.CreateOrderTgRequest) (*domain.Order, error) {
+	order, err := os.buildOrderFromTgRequest(request)
+	if err != nil {
+		return nil, err
+	}
+
+	// Логируем создание заказа для отладки
+	fmt.Printf("Создаем заказ: UUID=%s, FromCityUUID=%v, ToCityUUID=%v, Tags=%v\n",
+		order.UUID, order.FromCityUUID, order.ToCityUUID, order.Tags)
+
+	// Сохраняем в базу данных
+	err = os.database.CreateOrder(order)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка сохранения заказа: %v", err)
+	}
+
+	os.notifyNewOrder(order.UUID)
+
+	return order, nil
+}
+
+// buildOrderFromTgRequest проверяет упрощенный запрос и собирает по нему заказ без сохранения:
+// те же правила, что и при создании заказа, используются при импорте из таблиц
+func (os *OrderService) buildOrderFromTgRequest(request *domain.CreateOrderTgRequest) (*domain.Order, error) {
 	// Проверяем, что customerUUID не пустой
 	if request.CustomerUUID == "" {
-		return nil, fmt.Errorf("customerUUID не может быть пустым")
+		return nil, newValidationError("customerUUID не может быть пустым")
 	}
 
 	// Проверяем обязательные поля
-	if request.Title == "" {
-		return nil, fmt.Errorf("название заказа не может быть пустым")
+	if err := validateOrderFields(request.Title, request.Description, request.WeightKg, request.Price); err != nil {
+		return nil, err
 	}
-	if request.Description == "" {
-		return nil, fmt.Errorf("описание заказа не может быть пустым")
+	if err := os.ensureCustomerActive(request.CustomerUUID); err != nil {
+		return nil, err
 	}
-	if request.WeightKg <= 0 {
-		return nil, fmt.Errorf("вес должен быть больше нуля")
-	}
-	if request.Price <= 0 {
-		return nil, fmt.Errorf("цена должна быть больше нуля")
+	requirements, err := buildCargoRequirements(request.TemperatureRange, request.ADRClass, request.Oversize, request.Fragile)
+	if err != nil {
+		return nil, err
 	}
 
 	// Ищем UUID городов по названиям
 	var fromCityUUID, toCityUUID *string
 
 	if request.FromCityName != "" {
-		fromCity, err := os.cityRepo.GetCityByName(request.FromCityName)
+		fromCity, err := os.resolveCity(request.FromCityName, "отправления")
 		if err != nil {
-			return nil, fmt.Errorf("ошибка поиска города отправления '%s': %v", request.FromCityName, err)
-		}
-		if fromCity == nil {
-			return nil, fmt.Errorf("город отправления '%s' не найден в базе данных", request.FromCityName)
+			return nil, err
 		}
 		fromCityUUIDStr := fromCity.UUID.String()
 		fromCityUUID = &fromCityUUIDStr
+		request.FromCityName = fromCity.Name
 	}
 
 	if request.ToCityName != "" {
-		toCity, err := os.cityRepo.GetCityByName(request.ToCityName)
+		toCity, err := os.resolveCity(request.ToCityName, "назначения")
 		if err != nil {
-			return nil, fmt.Errorf("ошибка поиска города назначения '%s': %v", request.ToCityName, err)
-		}
-		if toCity == nil {
-			return nil, fmt.Errorf("город назначения '%s' не найден в базе данных", request.ToCityName)
+			return nil, err
 		}
 		toCityUUIDStr := toCity.UUID.String()
 		toCityUUID = &toCityUUIDStr
+		request.ToCityName = toCity.Name
 	}
 
 	// Создаем новый заказ
@@ -150,20 +198,18 @@ func (os *OrderService) CreateOrderFromTgRequest(request *domain.CreateOrderTgRe
 		Tags:          []string{}, // Упрощенный формат не включает теги, передаем пустой массив
 		Price:         request.Price,
 		AvailableFrom: nil, // Упрощенный формат не включает дату
+		Requirements:  requirements,
+		Urgent:        request.Urgent,
 		Status:        domain.OrderStatusActive,
 		CreatedAt:     time.Now(),
 	}
 
-	// Логируем создание заказа для отладки
-	fmt.Printf("Создаем заказ: UUID=%s, FromCityUUID=%v, ToCityUUID=%v, Tags=%v\n",
-		order.UUID, order.FromCityUUID, order.ToCityUUID, order.Tags)
-
-	// Сохраняем в базу данных
-	err := os.database.CreateOrder(order)
-	if err != nil {
-		return nil, fmt.Errorf("ошибка сохранения заказа: %v", err)
+	if err := os.applyCargoType(order, request.CargoType); err != nil {
+		return nil, err
 	}
 
+	os.fillRouteDistance(order)
+
 	return order, nil
 }
 
@@ -187,23 +233,331 @@ func (os *OrderService) GetOrdersByStatus(status string) ([]domain.Order, error)
 	return os.database.GetOrdersByStatus(status)
 }
 
-// GetOrdersByWeightRange возвращает заказы в указанном диапазоне веса
-func (os *OrderService) GetOrdersByWeightRange(minWeight, maxWeight *float64) ([]domain.Order, error) {
-	// Валидация параметров
-	if minWeight != nil && *minWeight < 0 {
-		return nil, fmt.Errorf("минимальный вес не может быть отрицательным")
+// GetActiveOrdersForDriver возвращает активные заказы, подходящие водителю по городу, радиусам поиска и транспорту
+func (os *OrderService) GetActiveOrdersForDriver(driver *domain.Driver) ([]domain.Order, error) {
+	if driver.CityUUID == nil {
+		return nil, newValidationError("у водителя не указан город")
 	}
-	if maxWeight != nil && *maxWeight < 0 {
-		return nil, fmt.Errorf("максимальный вес не может быть отрицательным")
+
+	orders, err := os.database.GetActiveOrders()
+	if err != nil {
+		return nil, err
 	}
-	if minWeight != nil && maxWeight != nil && *minWeight > *maxWeight {
-		return nil, fmt.Errorf("минимальный вес не может быть больше максимального")
+
+	cities, err := os.cityService.loadCityIndex()
+	if err != nil {
+		return nil, err
 	}
 
-	return os.database.GetOrdersByWeightRange(minWeight, maxWeight)
+	var matched []domain.Order
+	for i := range orders {
+		if cities.driverMatchesOrder(driver, &orders[i]) && vehicleFits(driver, &orders[i]) {
+			matched = append(matched, orders[i])
+		}
+	}
+	return matched, nil
 }
 
 // UpdateOrderStatus обновляет статус заказа
 func (os *OrderService) UpdateOrderStatus(orderUUID string, status string) error {
 	return os.database.UpdateOrderStatus(orderUUID, status)
 }
+
+// ensureCustomerActive проверяет, что заказчик существует и не деактивирован
+func (os *OrderService) ensureCustomerActive(customerUUID string) error {
+	parsedUUID, err := uuid.Parse(customerUUID)
+	if err != nil {
+		return newValidationError("некорректный UUID заказчика: %s", customerUUID)
+	}
+
+	customer, err := os.database.GetCustomerByUUID(parsedUUID)
+	if err != nil {
+		return err
+	}
+	if customer == nil {
+		return newValidationError("заказчик %s не найден", customerUUID)
+	}
+	if !customer.IsActive {
+		return newValidationError("заказчик %s деактивирован", customer.Name)
+	}
+	return nil
+}
+
+// resolveCity ищет город по названию или псевдониму; kind уточняет роль города в сообщении об ошибке
+func (os *OrderService) resolveCity(cityName, kind string) (*domain.City, error) {
+	city, err := os.cityService.ResolveCity(cityName)
+	if err != nil {
+		return nil, fmt.Errorf("город %s: %w", kind, err)
+	}
+	return city, nil
+}
+
+// notifyNewOrder в фоне рассылает только что созданный заказ подходящим водителям
+func (os *OrderService) notifyNewOrder(orderUUID string) {
+	if os.notificationService == nil {
+		return
+	}
+
+	go func() {
+		// Перечитываем заказ, чтобы получить данные заказчика и названия городов
+		order, err := os.database.GetOrderByUUID(orderUUID)
+		if err != nil || order == nil {
+			log.Printf("Не удалось загрузить заказ %s для рассылки: %v", orderUUID, err)
+			return
+		}
+		sent, err := os.notificationService.NotifyNewOrder(order)
+		if err != nil {
+			log.Printf("Ошибка рассылки заказа %s: %v", orderUUID, err)
+			return
+		}
+		log.Printf("Заказ %s разослан %d водителям", orderUUID, sent)
+	}()
+}
+
+// GetOrderByUUID возвращает заказ по UUID
+func (os *OrderService) GetOrderByUUID(orderUUID string) (*domain.Order, error) {
+	if _, err := uuid.Parse(orderUUID); err != nil {
+		return nil, newValidationError("некорректный UUID заказа: %s", orderUUID)
+	}
+
+	order, err := os.database.GetOrderByUUID(orderUUID)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil {
+		return nil, ErrOrderNotFound
+	}
+	return order, nil
+}
+
+// FindActiveOrderByShortID находит активный заказ по первым символам UUID (как в "Заказ #1a2b3c4d")
+func (os *OrderService) FindActiveOrderByShortID(shortID string) (*domain.Order, error) {
+	shortID = strings.ToLower(strings.TrimSpace(shortID))
+	if len(shortID) < 6 || strings.Trim(shortID, "0123456789abcdef-") != "" {
+		return nil, newValidationError("некорректный номер заказа: %s", shortID)
+	}
+
+	orders, err := os.database.GetActiveOrdersByUUIDPrefix(shortID)
+	if err != nil {
+		return nil, err
+	}
+	switch len(orders) {
+	case 0:
+		return nil, ErrOrderNotFound
+	case 1:
+		return &orders[0], nil
+	default:
+		return nil, newValidationError("номер %s подходит нескольким заказам, укажите больше символов", shortID)
+	}
+}
+
+// UpdateOrder частично изменяет заказ и возвращает обновленный заказ и список изменений.
+// Изменять можно только активные заказы и заказы в пути. Об изменении ключевых полей в фоне
+// узнают водители, получавшие уведомление о заказе, а для заказа в пути — только назначенный водитель.
+func (os *OrderService) UpdateOrder(orderUUID string, request *domain.UpdateOrderRequest) (*domain.Order, []string, error) {
+	if request == nil || request.IsEmpty() {
+		return nil, nil, newValidationError("не указано ни одного поля для изменения")
+	}
+
+	order, err := os.GetOrderByUUID(orderUUID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if order.Status != domain.OrderStatusActive && order.Status != domain.OrderStatusAssigned {
+		return nil, nil, newValidationError("заказ в статусе «%s» нельзя изменить", domain.OrderStatusNames[order.Status])
+	}
+
+	var changes []string
+	keyChanged := false
+	routeChanged := false
+
+	if request.Title != nil && strings.TrimSpace(*request.Title) != order.Title {
+		title := strings.TrimSpace(*request.Title)
+		changes = append(changes, fmt.Sprintf("📝 Название: %s → %s", order.Title, title))
+		order.Title = title
+	}
+	if request.Description != nil && strings.TrimSpace(*request.Description) != order.Description {
+		order.Description = strings.TrimSpace(*request.Description)
+		changes = append(changes, "📄 Описание обновлено")
+	}
+	if request.WeightKg != nil && *request.WeightKg != order.WeightKg {
+		changes = append(changes, fmt.Sprintf("⚖️ Вес: %.1f → %.1f кг", order.WeightKg, *request.WeightKg))
+		order.WeightKg = *request.WeightKg
+		keyChanged = true
+	}
+	if request.Price != nil && *request.Price != order.Price {
+		changes = append(changes, fmt.Sprintf("💰 Цена: %.0f → %.0f ₽", order.Price, *request.Price))
+		order.Price = *request.Price
+		keyChanged = true
+	}
+
+	if err := validateOrderFields(order.Title, order.Description, order.WeightKg, order.Price); err != nil {
+		return nil, nil, err
+	}
+
+	dimensions := []struct {
+		value   *float64
+		target  **float64
+		caption string
+	}{
+		{request.LengthCm, &order.LengthCm, "Длина"},
+		{request.WidthCm, &order.WidthCm, "Ширина"},
+		{request.HeightCm, &order.HeightCm, "Высота"},
+	}
+	for _, dim := range dimensions {
+		if dim.value == nil {
+			continue
+		}
+		if *dim.value < 0 {
+			return nil, nil, newValidationError("%s не может быть отрицательной", strings.ToLower(dim.caption))
+		}
+		value := *dim.value
+		if *dim.target != nil && **dim.target == value {
+			continue
+		}
+		*dim.target = &value
+		changes = append(changes, fmt.Sprintf("📏 %s: %.0f см", dim.caption, value))
+	}
+
+	if request.FromCityName != nil {
+		city, err := os.resolveCity(strings.TrimSpace(*request.FromCityName), "отправления")
+		if err != nil {
+			return nil, nil, err
+		}
+		// Псевдоним или другое написание того же города маршрут не меняет
+		if cityUUID := city.UUID.String(); cityUUID != stringValue(order.FromCityUUID) {
+			changes = append(changes, fmt.Sprintf("🏙️ Откуда: %s → %s", stringValue(order.FromCityName), city.Name))
+			order.FromCityUUID = &cityUUID
+			order.FromCityName = &city.Name
+			keyChanged = true
+			routeChanged = true
+		}
+	}
+	if request.ToCityName != nil {
+		city, err := os.resolveCity(strings.TrimSpace(*request.ToCityName), "назначения")
+		if err != nil {
+			return nil, nil, err
+		}
+		// Псевдоним или другое написание того же города маршрут не меняет
+		if cityUUID := city.UUID.String(); cityUUID != stringValue(order.ToCityUUID) {
+			changes = append(changes, fmt.Sprintf("🏙️ Куда: %s → %s", stringValue(order.ToCityName), city.Name))
+			order.ToCityUUID = &cityUUID
+			order.ToCityName = &city.Name
+			keyChanged = true
+			routeChanged = true
+		}
+	}
+	if request.FromAddress != nil && strings.TrimSpace(*request.FromAddress) != stringValue(order.FromAddress) {
+		order.FromAddress = optionalString(*request.FromAddress)
+		changes = append(changes, fmt.Sprintf("🏠 Адрес погрузки: %s", stringValue(order.FromAddress)))
+		keyChanged = true
+	}
+	if request.ToAddress != nil && strings.TrimSpace(*request.ToAddress) != stringValue(order.ToAddress) {
+		order.ToAddress = optionalString(*request.ToAddress)
+		changes = append(changes, fmt.Sprintf("🏠 Адрес выгрузки: %s", stringValue(order.ToAddress)))
+		keyChanged = true
+	}
+	if request.AvailableFrom != nil {
+		dateStr := strings.TrimSpace(*request.AvailableFrom)
+		if dateStr == "" && order.AvailableFrom != nil {
+			order.AvailableFrom = nil
+			changes = append(changes, "📅 Дата погрузки: не указана")
+			keyChanged = true
+		} else if dateStr != "" {
+			date, err := time.Parse("2006-01-02", dateStr)
+			if err != nil {
+				return nil, nil, newValidationError("некорректная дата '%s', ожидается формат ГГГГ-ММ-ДД", dateStr)
+			}
+			if order.AvailableFrom == nil || !order.AvailableFrom.Equal(date) {
+				order.AvailableFrom = &date
+				changes = append(changes, fmt.Sprintf("📅 Дата погрузки: %s", date.Format("02.01.2006")))
+				keyChanged = true
+			}
+		}
+	}
+
+	cargoChanges, err := os.applyCargoUpdate(order, request)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(cargoChanges) > 0 {
+		changes = append(changes, cargoChanges...)
+		keyChanged = true
+	}
+
+	if len(changes) == 0 {
+		return order, nil, nil
+	}
+
+	if routeChanged {
+		os.fillRouteDistance(order)
+		if distance := order.DistanceKm; distance != nil {
+			changes = append(changes, fmt.Sprintf("🛣 Расстояние: ~%.0f км", *distance))
+		}
+	}
+
+	if err := os.database.UpdateOrder(order); err != nil {
+		return nil, nil, fmt.Errorf("ошибка сохранения заказа: %v", err)
+	}
+
+	if keyChanged && os.notificationService != nil {
+		notified := *order
+		go func() {
+			sent, err := os.notificationService.NotifyOrderUpdated(&notified, changes)
+			if err != nil {
+				log.Printf("Ошибка уведомления водителей об изменении заказа %s: %v", notified.UUID, err)
+			} else if sent > 0 {
+				log.Printf("Об изменении заказа %s уведомлено водителей: %d", notified.UUID, sent)
+			}
+		}()
+	}
+
+	return order, changes, nil
+}
+
+// SetOrderUrgent отмечает заказ как срочный или снимает отметку. О срочном заказе водители узнают
+// и в тихие часы: уже отложенные уведомления о нем отправляются сразу.
+func (os *OrderService) SetOrderUrgent(orderUUID string, urgent bool) (*domain.Order, error) {
+	order, err := os.GetOrderByUUID(orderUUID)
+	if err != nil {
+		return nil, err
+	}
+	if order.Urgent == urgent {
+		return order, nil
+	}
+
+	if err := os.database.SetOrderUrgent(order.UUID, urgent); err != nil {
+		return nil, err
+	}
+	order.Urgent = urgent
+
+	if urgent && order.Status == domain.OrderStatusActive && os.notificationService != nil {
+		go func() {
+			sent, err := os.notificationService.SendDeferredForOrder(order.UUID)
+			if err != nil {
+				log.Printf("Ошибка отправки отложенных уведомлений о срочном заказе %s: %v", order.UUID, err)
+			} else if sent > 0 {
+				log.Printf("Отложенные уведомления о срочном заказе %s отправлены водителям: %d", order.UUID, sent)
+			}
+		}()
+	}
+
+	return order, nil
+}
+
+// stringValue возвращает значение строки по указателю или пустую строку
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+// optionalString возвращает nil для пустой строки и указатель на обрезанное значение иначе
+func optionalString(value string) *string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	return &value
+}
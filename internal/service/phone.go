@@ -0,0 +1,43 @@
+package service
+
+import (
+	"strings"
+	"unicode"
+)
+
+// NormalizePhone приводит номер телефона к формату E.164 (+79001234567).
+// Российские номера вида "8 900 …", "7 900 …" и "900 …" дополняются кодом страны +7;
+// номера других стран должны начинаться с "+".
+func NormalizePhone(phone string) (string, error) {
+	phone = strings.TrimSpace(phone)
+	if phone == "" {
+		return "", newValidationError("телефон не может быть пустым")
+	}
+
+	hasPlus := strings.HasPrefix(phone, "+")
+	var digits strings.Builder
+	for _, r := range phone {
+		switch {
+		case unicode.IsDigit(r):
+			digits.WriteRune(r)
+		case r == '+' || r == ' ' || r == '-' || r == '(' || r == ')' || r == '.':
+			// Допустимые разделители
+		default:
+			return "", newValidationError("телефон '%s' содержит недопустимый символ '%c'", phone, r)
+		}
+	}
+	number := digits.String()
+
+	switch {
+	case len(number) == 11 && number[0] == '8' && !hasPlus:
+		number = "7" + number[1:]
+	case len(number) == 11 && number[0] == '7':
+	case len(number) == 10 && number[0] == '9' && !hasPlus:
+		number = "7" + number
+	case hasPlus && len(number) >= 8 && len(number) <= 15:
+	default:
+		return "", newValidationError("не удалось распознать телефон '%s', используйте формат +79001234567", phone)
+	}
+
+	return "+" + number, nil
+}
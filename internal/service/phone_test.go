@@ -0,0 +1,47 @@
+package service
+
+import "testing"
+
+func TestNormalizePhone(t *testing.T) {
+	tests := []struct {
+		name    string
+		phone   string
+		want    string
+		wantErr bool
+	}{
+		{"уже E.164", "+79001234567", "+79001234567", false},
+		{"восьмерка со скобками", "8 (900) 123-45-67", "+79001234567", false},
+		{"семерка без плюса", "7 900 123 45 67", "+79001234567", false},
+		{"десять цифр", "9001234567", "+79001234567", false},
+		{"пробелы по краям", "  +7 900 123-45-67 ", "+79001234567", false},
+		{"точки", "8.900.123.45.67", "+79001234567", false},
+		{"другая страна", "+375 29 123-45-67", "+375291234567", false},
+		{"пусто", "   ", "", true},
+		{"буквы", "8 900 ABC 45 67", "", true},
+		{"слишком короткий", "12345", "", true},
+		{"десять цифр не на 9", "4951234567", "", true},
+		{"иностранный без плюса", "375291234567", "", true},
+		{"слишком длинный", "+1234567890123456", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizePhone(tt.phone)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("NormalizePhone(%q) = %q, ожидалась ошибка", tt.phone, got)
+				}
+				if !IsValidationError(err) {
+					t.Fatalf("NormalizePhone(%q): ошибка %v должна быть ошибкой валидации", tt.phone, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NormalizePhone(%q): неожиданная ошибка %v", tt.phone, err)
+			}
+			if got != tt.want {
+				t.Fatalf("NormalizePhone(%q) = %q, ожидалось %q", tt.phone, got, tt.want)
+			}
+		})
+	}
+}
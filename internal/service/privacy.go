@@ -0,0 +1,41 @@
+package service
+
+import (
+	"strings"
+	"unicode"
+
+	"dalnoboy/internal/domain"
+)
+
+// CanViewCustomerContacts проверяет, видны ли получателю контакты заказчика: только
+// администраторам и водителю, которому назначен заказ
+func CanViewCustomerContacts(order *domain.Order, viewer domain.ContactViewer) bool {
+	if viewer.Admin {
+		return true
+	}
+	return viewer.DriverTelegramID != 0 && order.AssignedDriverTgID != nil &&
+		*order.AssignedDriverTgID == viewer.DriverTelegramID
+}
+
+// CustomerPhoneFor возвращает телефон заказчика таким, каким его можно показать получателю
+func CustomerPhoneFor(order *domain.Order, viewer domain.ContactViewer) string {
+	if CanViewCustomerContacts(order, viewer) {
+		return order.CustomerPhone
+	}
+	return MaskPhone(order.CustomerPhone)
+}
+
+// MaskPhone оставляет в номере код страны с началом кода оператора и две последние цифры:
+// +79001234567 → +7900*****67
+func MaskPhone(phone string) string {
+	var digits []rune
+	for _, r := range phone {
+		if unicode.IsDigit(r) {
+			digits = append(digits, r)
+		}
+	}
+	if len(digits) < 7 {
+		return "скрыт"
+	}
+	return "+" + string(digits[:4]) + strings.Repeat("*", len(digits)-6) + string(digits[len(digits)-2:])
+}
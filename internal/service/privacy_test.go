@@ -0,0 +1,65 @@
+package service
+
+import (
+	"testing"
+
+	"dalnoboy/internal/domain"
+)
+
+func TestMaskPhone(t *testing.T) {
+	tests := []struct {
+		phone string
+		want  string
+	}{
+		{"+79001234567", "+7900*****67"},
+		{"+375291234567", "+3752******67"},
+		{"+1234567", "+1234*67"},
+		{"123456", "скрыт"},
+		{"", "скрыт"},
+	}
+
+	for _, tt := range tests {
+		if got := MaskPhone(tt.phone); got != tt.want {
+			t.Errorf("MaskPhone(%q) = %q, ожидалось %q", tt.phone, got, tt.want)
+		}
+	}
+}
+
+func TestCanViewCustomerContacts(t *testing.T) {
+	const assignedDriver, otherDriver = int64(1001), int64(2002)
+	driverID := assignedDriver
+
+	active := &domain.Order{Status: domain.OrderStatusActive, CustomerPhone: "+79001234567"}
+	assigned := &domain.Order{Status: domain.OrderStatusAssigned, CustomerPhone: "+79001234567", AssignedDriverTgID: &driverID}
+	delivered := &domain.Order{Status: domain.OrderStatusDelivered, CustomerPhone: "+79001234567", AssignedDriverTgID: &driverID}
+
+	tests := []struct {
+		name   string
+		order  *domain.Order
+		viewer domain.ContactViewer
+		want   bool
+	}{
+		{"администратор, свободный заказ", active, domain.AdminViewer(), true},
+		{"администратор, назначенный заказ", assigned, domain.AdminViewer(), true},
+		{"назначенный водитель", assigned, domain.DriverViewer(assignedDriver), true},
+		{"назначенный водитель после доставки", delivered, domain.DriverViewer(assignedDriver), true},
+		{"другой водитель", assigned, domain.DriverViewer(otherDriver), false},
+		{"другой водитель после доставки", delivered, domain.DriverViewer(otherDriver), false},
+		{"водитель, заказ никому не назначен", active, domain.DriverViewer(assignedDriver), false},
+		{"анонимный посетитель", assigned, domain.ContactViewer{}, false},
+		{"анонимный посетитель, свободный заказ", active, domain.ContactViewer{}, false},
+	}
+
+	for _, tt := range tests {
+		if got := CanViewCustomerContacts(tt.order, tt.viewer); got != tt.want {
+			t.Errorf("%s: CanViewCustomerContacts = %v, ожидалось %v", tt.name, got, tt.want)
+		}
+		wantPhone := MaskPhone(tt.order.CustomerPhone)
+		if tt.want {
+			wantPhone = tt.order.CustomerPhone
+		}
+		if got := CustomerPhoneFor(tt.order, tt.viewer); got != wantPhone {
+			t.Errorf("%s: CustomerPhoneFor = %q, ожидалось %q", tt.name, got, wantPhone)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"dalnoboy/internal/database"
+	"dalnoboy/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// maxRatingCommentLength ограничивает длину комментария к оценке
+const maxRatingCommentLength = 1000
+
+// RatingNotifier просит заказчика оценить водителя после доставки
+type RatingNotifier interface {
+	RequestDriverRating(chatID int64, order *domain.Order, link string) error
+}
+
+// RatingService представляет сервис оценок водителей заказчиками
+type RatingService struct {
+	database   *database.Database
+	linkSecret []byte // Ключ подписи ссылок на оценку; пустой — ссылки отключены
+	baseURL    string // Публичный адрес сайта для ссылок
+	notifier   RatingNotifier
+}
+
+// NewRatingService создает новый экземпляр сервиса оценок
+func NewRatingService(db *database.Database, linkSecret, baseURL string) *RatingService {
+	return &RatingService{
+		database:   db,
+		linkSecret: []byte(linkSecret),
+		baseURL:    strings.TrimRight(baseURL, "/"),
+	}
+}
+
+// SetNotifier задает канал запросов оценки (админский бот создается позже сервисов)
+func (rs *RatingService) SetNotifier(notifier RatingNotifier) {
+	rs.notifier = notifier
+}
+
+// RequestRating просит заказчика доставленного заказа оценить водителя
+func (rs *RatingService) RequestRating(order *domain.Order) {
+	if rs.notifier == nil || order.CustomerTelegramID == nil || order.AssignedDriverUUID == nil {
+		return
+	}
+	if err := rs.notifier.RequestDriverRating(*order.CustomerTelegramID, order, rs.RatingLink(order.UUID)); err != nil {
+		log.Printf("Ошибка запроса оценки водителя по заказу %s: %v", order.UUID, err)
+	}
+}
+
+// RatingLink возвращает подписанную ссылку на страницу оценки или пустую строку,
+// если ссылки не настроены
+func (rs *RatingService) RatingLink(orderUUID string) string {
+	if len(rs.linkSecret) == 0 || rs.baseURL == "" {
+		return ""
+	}
+	query := url.Values{"order": {orderUUID}, "sig": {rs.sign(orderUUID)}}
+	return rs.baseURL + "/rate?" + query.Encode()
+}
+
+// VerifyLink проверяет подпись ссылки на оценку заказа
+func (rs *RatingService) VerifyLink(orderUUID, signature string) bool {
+	if len(rs.linkSecret) == 0 {
+		return false
+	}
+	return hmac.Equal([]byte(signature), []byte(rs.sign(orderUUID)))
+}
+
+func (rs *RatingService) sign(orderUUID string) string {
+	mac := hmac.New(sha256.New, rs.linkSecret)
+	mac.Write([]byte("rate:" + orderUUID))
+	return hex.EncodeToString(mac.Sum(nil))[:32]
+}
+
+// GetRatableOrder возвращает доставленный заказ, по которому можно оценить водителя
+func (rs *RatingService) GetRatableOrder(orderUUID string) (*domain.Order, error) {
+	if _, err := uuid.Parse(orderUUID); err != nil {
+		return nil, newValidationError("некорректный UUID заказа: %s", orderUUID)
+	}
+
+	order, err := rs.database.GetOrderByUUID(orderUUID)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil {
+		return nil, ErrOrderNotFound
+	}
+	if order.Status != domain.OrderStatusDelivered || order.AssignedDriverUUID == nil {
+		return nil, newValidationError("оценить водителя можно только после доставки заказа")
+	}
+	return order, nil
+}
+
+// RateDriver сохраняет оценку водителя по доставленному заказу. Повторная оценка заменяет предыдущую.
+func (rs *RatingService) RateDriver(request *domain.RateDriverRequest) (*domain.DriverRating, error) {
+	if request.Score < domain.MinRatingScore || request.Score > domain.MaxRatingScore {
+		return nil, newValidationError("оценка должна быть от %d до %d", domain.MinRatingScore, domain.MaxRatingScore)
+	}
+	comment := strings.TrimSpace(request.Comment)
+	if utf8.RuneCountInString(comment) > maxRatingCommentLength {
+		return nil, newValidationError("комментарий длиннее %d символов", maxRatingCommentLength)
+	}
+
+	order, err := rs.GetRatableOrder(request.OrderUUID)
+	if err != nil {
+		return nil, err
+	}
+	if request.TelegramID != nil && (order.CustomerTelegramID == nil || *order.CustomerTelegramID != *request.TelegramID) {
+		return nil, newValidationError("заказ %s принадлежит другому заказчику", order.UUID[:8])
+	}
+
+	rating := &domain.DriverRating{
+		UUID:         uuid.New().String(),
+		OrderUUID:    order.UUID,
+		DriverUUID:   *order.AssignedDriverUUID,
+		CustomerUUID: order.CustomerUUID,
+		Score:        request.Score,
+		Comment:      optionalString(comment),
+		Source:       request.Source,
+		CreatedAt:    time.Now(),
+		OrderTitle:   order.Title,
+		CustomerName: order.CustomerName,
+	}
+	if err := rs.database.SaveDriverRating(rating); err != nil {
+		return nil, err
+	}
+	return rating, nil
+}
+
+// GetRatingByOrder возвращает оценку водителя по заказу или nil, если ее нет
+func (rs *RatingService) GetRatingByOrder(orderUUID string) (*domain.DriverRating, error) {
+	return rs.database.GetRatingByOrder(orderUUID)
+}
+
+// GetDriverRatings возвращает последние оценки водителя
+func (rs *RatingService) GetDriverRatings(driverUUID string, limit int) ([]domain.DriverRating, error) {
+	if _, err := uuid.Parse(driverUUID); err != nil {
+		return nil, newValidationError("некорректный UUID водителя: %s", driverUUID)
+	}
+	return rs.database.GetDriverRatings(driverUUID, limit)
+}
@@ -0,0 +1,235 @@
+package service
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Форматы выгрузок
+const (
+	ExportFormatCSV  = "csv"
+	ExportFormatXLSX = "xlsx"
+)
+
+// ParseExportFormat проверяет формат выгрузки; по умолчанию CSV
+func ParseExportFormat(value string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", ExportFormatCSV:
+		return ExportFormatCSV, nil
+	case ExportFormatXLSX:
+		return ExportFormatXLSX, nil
+	}
+	return "", newValidationError("неизвестный формат выгрузки '%s': используйте %s или %s", value, ExportFormatCSV, ExportFormatXLSX)
+}
+
+// ExportContentType возвращает MIME-тип файла выгрузки
+func ExportContentType(format string) string {
+	if format == ExportFormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// tableWriter построчно записывает таблицу выгрузки. Значения ячеек: string, числа,
+// time.Time, указатели на них (nil — пустая ячейка) и bool.
+type tableWriter interface {
+	WriteRow(values []interface{}) error
+	Close() error
+}
+
+// newTableWriter создает запись таблицы в выбранном формате
+func newTableWriter(w io.Writer, format string) (tableWriter, error) {
+	switch format {
+	case ExportFormatCSV:
+		return newCSVTableWriter(w)
+	case ExportFormatXLSX:
+		return newXLSXTableWriter(w)
+	}
+	return nil, newValidationError("неизвестный формат выгрузки '%s'", format)
+}
+
+// formatCell приводит значение ячейки к тексту; number сообщает, что это число
+func formatCell(value interface{}) (text string, number bool) {
+	switch v := value.(type) {
+	case nil:
+		return "", false
+	case string:
+		return v, false
+	case *string:
+		if v == nil {
+			return "", false
+		}
+		return *v, false
+	case int:
+		return strconv.Itoa(v), true
+	case int64:
+		return strconv.FormatInt(v, 10), true
+	case *int:
+		if v == nil {
+			return "", false
+		}
+		return strconv.Itoa(*v), true
+	case *int64:
+		if v == nil {
+			return "", false
+		}
+		return strconv.FormatInt(*v, 10), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case *float64:
+		if v == nil {
+			return "", false
+		}
+		return strconv.FormatFloat(*v, 'f', -1, 64), true
+	case bool:
+		return yesNo(v), false
+	case time.Time:
+		return v.Format("2006-01-02 15:04"), false
+	case *time.Time:
+		if v == nil {
+			return "", false
+		}
+		return v.Format("2006-01-02 15:04"), false
+	}
+	return fmt.Sprint(value), false
+}
+
+// csvTableWriter пишет CSV с разделителем ";" (как WriteDeliveriesCSV) для Excel
+type csvTableWriter struct {
+	writer *csv.Writer
+}
+
+func newCSVTableWriter(w io.Writer) (*csvTableWriter, error) {
+	// BOM нужен Excel, чтобы открыть UTF-8 без выбора кодировки
+	if _, err := io.WriteString(w, "\xef\xbb\xbf"); err != nil {
+		return nil, err
+	}
+	writer := csv.NewWriter(w)
+	writer.Comma = ';'
+	return &csvTableWriter{writer: writer}, nil
+}
+
+func (t *csvTableWriter) WriteRow(values []interface{}) error {
+	record := make([]string, len(values))
+	for i, value := range values {
+		text, number := formatCell(value)
+		if !number {
+			text = escapeCSVFormula(text)
+		}
+		record[i] = text
+	}
+	return t.writer.Write(record)
+}
+
+// escapeCSVFormula экранирует текст, который Excel выполнил бы как формулу: названия, адреса
+// и имена вводят заказчики и водители, поэтому ячейка "=..." не должна стать формулой
+func escapeCSVFormula(text string) string {
+	if text == "" {
+		return text
+	}
+	switch text[0] {
+	case '=', '+', '-', '@', '\t', '\r':
+		return "'" + text
+	}
+	return text
+}
+
+func (t *csvTableWriter) Close() error {
+	t.writer.Flush()
+	return t.writer.Error()
+}
+
+// Неизменяемые части книги XLSX с одним листом
+var xlsxStaticParts = []struct {
+	name    string
+	content string
+}{
+	{"[Content_Types].xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/></Types>`},
+	{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`},
+	{"xl/workbook.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="Выгрузка" sheetId="1" r:id="rId1"/></sheets></workbook>`},
+	{"xl/_rels/workbook.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/></Relationships>`},
+}
+
+// xlsxTableWriter пишет книгу XLSX потоком: строки листа сразу уходят в архив
+type xlsxTableWriter struct {
+	archive *zip.Writer
+	sheet   *bufio.Writer
+	row     int
+}
+
+func newXLSXTableWriter(w io.Writer) (*xlsxTableWriter, error) {
+	archive := zip.NewWriter(w)
+	for _, part := range xlsxStaticParts {
+		file, err := archive.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(file, part.content); err != nil {
+			return nil, err
+		}
+	}
+
+	file, err := archive.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	sheet := bufio.NewWriter(file)
+	if _, err := sheet.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n" +
+		`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`); err != nil {
+		return nil, err
+	}
+	return &xlsxTableWriter{archive: archive, sheet: sheet}, nil
+}
+
+func (t *xlsxTableWriter) WriteRow(values []interface{}) error {
+	t.row++
+	fmt.Fprintf(t.sheet, `<row r="%d">`, t.row)
+	for i, value := range values {
+		text, number := formatCell(value)
+		if text == "" {
+			continue
+		}
+		ref := xlsxColumnName(i) + strconv.Itoa(t.row)
+		if number {
+			fmt.Fprintf(t.sheet, `<c r="%s"><v>%s</v></c>`, ref, text)
+			continue
+		}
+		fmt.Fprintf(t.sheet, `<c r="%s" t="inlineStr"><is><t xml:space="preserve">`, ref)
+		if err := xml.EscapeText(t.sheet, []byte(text)); err != nil {
+			return err
+		}
+		t.sheet.WriteString(`</t></is></c>`)
+	}
+	_, err := t.sheet.WriteString(`</row>`)
+	return err
+}
+
+func (t *xlsxTableWriter) Close() error {
+	if _, err := t.sheet.WriteString(`</sheetData></worksheet>`); err != nil {
+		return err
+	}
+	if err := t.sheet.Flush(); err != nil {
+		return err
+	}
+	return t.archive.Close()
+}
+
+// xlsxColumnName переводит номер колонки с нуля в буквенное обозначение: 0 → A, 26 → AA
+func xlsxColumnName(index int) string {
+	name := ""
+	for index++; index > 0; index = (index - 1) / 26 {
+		name = string(rune('A'+(index-1)%26)) + name
+	}
+	return name
+}
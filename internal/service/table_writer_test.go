@@ -0,0 +1,79 @@
+package service
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+)
+
+func TestCSVTableWriterEscapesFormulas(t *testing.T) {
+	price := -1500.5
+	formula := "=1+1"
+	tests := []struct {
+		name  string
+		value interface{}
+		want  string
+	}{
+		{"обычный текст", "Доставка мебели", "Доставка мебели"},
+		{"формула", "=HYPERLINK(\"http://evil\")", "'=HYPERLINK(\"http://evil\")"},
+		{"плюс", "+79001234567", "'+79001234567"},
+		{"минус", "-2+3", "'-2+3"},
+		{"собака", "@SUM(A1)", "'@SUM(A1)"},
+		{"табуляция", "\t=1", "'\t=1"},
+		{"перевод каретки", "\r=1", "'\r=1"},
+		{"формула в середине", "Цена =100", "Цена =100"},
+		{"указатель на строку", &formula, "'=1+1"},
+		{"отрицательное число", -3, "-3"},
+		{"отрицательная дробь", &price, "-1500.5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			writer, err := newTableWriter(&buf, ExportFormatCSV)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := writer.WriteRow([]interface{}{tt.value}); err != nil {
+				t.Fatal(err)
+			}
+			if err := writer.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			reader := csv.NewReader(strings.NewReader(strings.TrimPrefix(buf.String(), "\xef\xbb\xbf")))
+			reader.Comma = ';'
+			record, err := reader.Read()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if record[0] != tt.want {
+				t.Fatalf("ячейка %q, ожидалась %q", record[0], tt.want)
+			}
+		})
+	}
+}
+
+func TestXLSXColumnName(t *testing.T) {
+	tests := []struct {
+		index int
+		want  string
+	}{
+		{0, "A"},
+		{1, "B"},
+		{25, "Z"},
+		{26, "AA"},
+		{27, "AB"},
+		{51, "AZ"},
+		{52, "BA"},
+		{701, "ZZ"},
+		{702, "AAA"},
+	}
+
+	for _, tt := range tests {
+		if got := xlsxColumnName(tt.index); got != tt.want {
+			t.Errorf("xlsxColumnName(%d) = %q, ожидалось %q", tt.index, got, tt.want)
+		}
+	}
+}
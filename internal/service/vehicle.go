@@ -0,0 +1,164 @@
+package service
+
+import (
+	"strings"
+	"time"
+
+	"dalnoboy/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// bodyTypeAliases содержит разговорные названия типов кузова
+var bodyTypeAliases = map[string]string{
+	"реф":       domain.BodyTypeRefrigerator,
+	"фургон":    domain.BodyTypeVan,
+	"цмф":       domain.BodyTypeVan,
+	"борт":      domain.BodyTypeFlatbed,
+	"площадка":  domain.BodyTypeFlatbed,
+	"трал":      domain.BodyTypeLowboy,
+	"контейнер": domain.BodyTypeContainer,
+}
+
+// ParseBodyType распознает тип кузова по коду, русскому названию или его началу.
+// Если начало подходит к нескольким типам, возвращает ошибку со списком вариантов.
+func ParseBodyType(text string) (string, error) {
+	key := strings.ToLower(strings.TrimSpace(text))
+	if key == "" {
+		return "", newValidationError("тип кузова не указан")
+	}
+	if bodyType, ok := bodyTypeAliases[key]; ok {
+		return bodyType, nil
+	}
+
+	// Точное совпадение кода или названия важнее начала названия
+	var candidates []string
+	for _, bodyType := range domain.BodyTypes {
+		name := strings.ToLower(domain.BodyTypeNames[bodyType])
+		if key == bodyType || key == name {
+			return bodyType, nil
+		}
+		if strings.HasPrefix(name, key) {
+			candidates = append(candidates, bodyType)
+		}
+	}
+
+	switch len(candidates) {
+	case 0:
+	case 1:
+		return candidates[0], nil
+	default:
+		names := make([]string, len(candidates))
+		for i, bodyType := range candidates {
+			names[i] = domain.BodyTypeNames[bodyType]
+		}
+		return "", newValidationError("тип кузова '%s' подходит к нескольким: %s. Уточните название", text, strings.Join(names, ", "))
+	}
+
+	return "", newValidationError("неизвестный тип кузова '%s'. Доступные: %s", text, BodyTypeList())
+}
+
+// BodyTypeList возвращает перечень названий типов кузова через запятую
+func BodyTypeList() string {
+	names := make([]string, len(domain.BodyTypes))
+	for i, bodyType := range domain.BodyTypes {
+		names[i] = domain.BodyTypeNames[bodyType]
+	}
+	return strings.Join(names, ", ")
+}
+
+// SaveVehicle создает или изменяет транспорт водителя
+func (ds *DriverService) SaveVehicle(driver *domain.Driver, request *domain.VehicleRequest) (*domain.Vehicle, error) {
+	vehicle := &domain.Vehicle{UUID: uuid.New(), DriverUUID: driver.UUID}
+	if driver.Vehicle != nil {
+		existing := *driver.Vehicle
+		vehicle = &existing
+	}
+
+	if request.BodyType != nil {
+		bodyType, err := ParseBodyType(*request.BodyType)
+		if err != nil {
+			return nil, err
+		}
+		vehicle.BodyType = bodyType
+	}
+	if request.ADR != nil {
+		vehicle.ADR = *request.ADR
+	}
+	if request.PayloadKg != nil {
+		vehicle.PayloadKg = *request.PayloadKg
+	}
+
+	optional := []struct {
+		value   *float64
+		target  **float64
+		caption string
+	}{
+		{request.VolumeM3, &vehicle.VolumeM3, "объем"},
+		{request.LengthCm, &vehicle.LengthCm, "длина кузова"},
+		{request.WidthCm, &vehicle.WidthCm, "ширина кузова"},
+		{request.HeightCm, &vehicle.HeightCm, "высота кузова"},
+	}
+	for _, field := range optional {
+		if field.value == nil {
+			continue
+		}
+		if *field.value < 0 {
+			return nil, newValidationError("%s не может быть отрицательной величиной", field.caption)
+		}
+		// Ноль очищает необязательную характеристику
+		if *field.value == 0 {
+			*field.target = nil
+			continue
+		}
+		value := *field.value
+		*field.target = &value
+	}
+
+	if vehicle.BodyType == "" {
+		return nil, newValidationError("укажите тип кузова. Доступные: %s", BodyTypeList())
+	}
+	if vehicle.PayloadKg <= 0 {
+		return nil, newValidationError("грузоподъемность должна быть больше нуля")
+	}
+
+	vehicle.UpdatedAt = time.Now()
+	if err := ds.database.SaveVehicle(vehicle); err != nil {
+		return nil, err
+	}
+	driver.Vehicle = vehicle
+	return vehicle, nil
+}
+
+// DeleteVehicle удаляет транспорт водителя; после этого заказы не фильтруются по вместимости
+func (ds *DriverService) DeleteVehicle(driver *domain.Driver) error {
+	if err := ds.database.DeleteVehicle(driver.UUID); err != nil {
+		return err
+	}
+	driver.Vehicle = nil
+	return nil
+}
+
+// vehicleFits проверяет, что заказ подходит транспорту водителя.
+// Водитель без заполненного транспорта получает все заказы.
+func vehicleFits(driver *domain.Driver, order *domain.Order) bool {
+	if driver.Vehicle == nil {
+		return true
+	}
+	return driver.Vehicle.CanCarry(order) && driver.Vehicle.MeetsRequirements(&order.Requirements)
+}
+
+// FilterOrdersForDriverVehicle оставляет заказы, которые помещаются в транспорт водителя
+func FilterOrdersForDriverVehicle(orders []domain.Order, driver *domain.Driver) []domain.Order {
+	if driver == nil || driver.Vehicle == nil {
+		return orders
+	}
+
+	var filtered []domain.Order
+	for i := range orders {
+		if vehicleFits(driver, &orders[i]) {
+			filtered = append(filtered, orders[i])
+		}
+	}
+	return filtered
+}
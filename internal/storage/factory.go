@@ -0,0 +1,42 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+)
+
+// StoreType тип файлового хранилища
+type StoreType string
+
+const (
+	LocalStoreType StoreType = "local"
+	S3StoreType    StoreType = "s3"
+)
+
+// Factory создает файловое хранилище указанного типа
+type Factory struct{}
+
+// NewFactory создает новую фабрику хранилищ
+func NewFactory() *Factory {
+	return &Factory{}
+}
+
+// Create создает хранилище указанного типа
+func (f *Factory) Create(storeType StoreType, config interface{}) (BlobStore, error) {
+	switch strings.ToLower(string(storeType)) {
+	case string(LocalStoreType):
+		localConfig, ok := config.(LocalConfig)
+		if !ok {
+			return nil, fmt.Errorf("invalid config type for local storage")
+		}
+		return NewLocalStore(localConfig)
+	case string(S3StoreType):
+		s3Config, ok := config.(S3Config)
+		if !ok {
+			return nil, fmt.Errorf("invalid config type for S3 storage")
+		}
+		return NewS3Store(s3Config)
+	default:
+		return nil, fmt.Errorf("unsupported storage type: %s", storeType)
+	}
+}
@@ -0,0 +1,22 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrNotFound возвращается, когда объекта с указанным ключом нет в хранилище
+var ErrNotFound = errors.New("объект не найден в хранилище")
+
+// BlobStore интерфейс файлового хранилища для копий вложений
+type BlobStore interface {
+	// Put сохраняет объект под ключом, перезаписывая существующий
+	Put(ctx context.Context, key string, content io.Reader, size int64, contentType string) error
+
+	// Get открывает объект для чтения. Вызывающий обязан закрыть ридер.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+
+	// Delete удаляет объект. Отсутствие объекта не считается ошибкой.
+	Delete(ctx context.Context, key string) error
+}
@@ -0,0 +1,97 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LocalConfig конфигурация хранилища в локальной директории
+type LocalConfig struct {
+	Dir string
+}
+
+// LocalStore хранит объекты файлами в локальной директории
+type LocalStore struct {
+	dir string
+}
+
+// NewLocalStore создает хранилище в директории, создавая ее при необходимости
+func NewLocalStore(config LocalConfig) (*LocalStore, error) {
+	if config.Dir == "" {
+		return nil, fmt.Errorf("не указана директория локального хранилища")
+	}
+	if err := os.MkdirAll(config.Dir, 0o755); err != nil {
+		return nil, fmt.Errorf("ошибка создания директории хранилища: %v", err)
+	}
+	return &LocalStore{dir: config.Dir}, nil
+}
+
+// path возвращает путь к файлу объекта, не позволяя выйти за пределы директории
+func (s *LocalStore) path(key string) (string, error) {
+	cleaned := filepath.Clean("/" + key)
+	if cleaned == "/" || strings.Contains(key, "..") {
+		return "", fmt.Errorf("некорректный ключ объекта: %s", key)
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(cleaned)), nil
+}
+
+// Put сохраняет объект во временный файл и атомарно переименовывает его
+func (s *LocalStore) Put(ctx context.Context, key string, content io.Reader, size int64, contentType string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("ошибка создания директории: %v", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("ошибка создания файла: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, content); err != nil {
+		tmp.Close()
+		return fmt.Errorf("ошибка записи файла: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("ошибка записи файла: %v", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("ошибка сохранения файла: %v", err)
+	}
+	return nil
+}
+
+// Get открывает файл объекта
+func (s *LocalStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка открытия файла: %v", err)
+	}
+	return file, nil
+}
+
+// Delete удаляет файл объекта
+func (s *LocalStore) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("ошибка удаления файла: %v", err)
+	}
+	return nil
+}
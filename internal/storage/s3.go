@@ -0,0 +1,198 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// unsignedPayload отключает подпись тела запроса: файлы передаются потоком без подсчета хеша
+const unsignedPayload = "UNSIGNED-PAYLOAD"
+
+// S3Config конфигурация S3-совместимого хранилища (AWS S3, MinIO, Yandex Object Storage)
+type S3Config struct {
+	Endpoint  string // https://storage.yandexcloud.net
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+}
+
+// S3Store хранит объекты в S3-совместимом хранилище. Запросы подписываются AWS Signature V4,
+// бакет адресуется в пути (path-style), что поддерживают все совместимые реализации.
+type S3Store struct {
+	config S3Config
+	client *http.Client
+}
+
+// NewS3Store создает клиент S3-совместимого хранилища
+func NewS3Store(config S3Config) (*S3Store, error) {
+	if config.Endpoint == "" || config.Bucket == "" {
+		return nil, fmt.Errorf("для S3 хранилища нужно указать endpoint и bucket")
+	}
+	if config.AccessKey == "" || config.SecretKey == "" {
+		return nil, fmt.Errorf("для S3 хранилища нужно указать ключи доступа")
+	}
+	if config.Region == "" {
+		config.Region = "us-east-1"
+	}
+	config.Endpoint = strings.TrimRight(config.Endpoint, "/")
+
+	return &S3Store{
+		config: config,
+		client: &http.Client{Timeout: 5 * time.Minute},
+	}, nil
+}
+
+// Put загружает объект. Если размер неизвестен, содержимое предварительно читается в память.
+func (s *S3Store) Put(ctx context.Context, key string, content io.Reader, size int64, contentType string) error {
+	if size < 0 {
+		data, err := io.ReadAll(content)
+		if err != nil {
+			return fmt.Errorf("ошибка чтения файла: %v", err)
+		}
+		content, size = bytes.NewReader(data), int64(len(data))
+	}
+
+	request, err := s.newRequest(ctx, http.MethodPut, key, content)
+	if err != nil {
+		return err
+	}
+	request.ContentLength = size
+	if contentType != "" {
+		request.Header.Set("Content-Type", contentType)
+	}
+
+	response, err := s.do(request)
+	if err != nil {
+		return err
+	}
+	response.Body.Close()
+	return nil
+}
+
+// Get скачивает объект
+func (s *S3Store) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	request, err := s.newRequest(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := s.do(request)
+	if err != nil {
+		return nil, err
+	}
+	return response.Body, nil
+}
+
+// Delete удаляет объект
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	request, err := s.newRequest(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+
+	response, err := s.do(request)
+	if err == ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	response.Body.Close()
+	return nil
+}
+
+// newRequest создает подписанный запрос к объекту бакета
+func (s *S3Store) newRequest(ctx context.Context, method, key string, body io.Reader) (*http.Request, error) {
+	path := "/" + s.config.Bucket + "/" + strings.TrimLeft(key, "/")
+	request, err := http.NewRequestWithContext(ctx, method, s.config.Endpoint+uriEncode(path), body)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка создания запроса к хранилищу: %v", err)
+	}
+	s.sign(request, uriEncode(path), time.Now().UTC())
+	return request, nil
+}
+
+// do выполняет запрос и превращает неуспешные ответы в ошибки
+func (s *S3Store) do(request *http.Request) (*http.Response, error) {
+	response, err := s.client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка запроса к хранилищу: %v", err)
+	}
+	if response.StatusCode == http.StatusNotFound {
+		response.Body.Close()
+		return nil, ErrNotFound
+	}
+	if response.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(response.Body, 1024))
+		response.Body.Close()
+		return nil, fmt.Errorf("хранилище ответило %s: %s", response.Status, strings.TrimSpace(string(message)))
+	}
+	return response, nil
+}
+
+// sign добавляет к запросу заголовки подписи AWS Signature V4
+func (s *S3Store) sign(request *http.Request, canonicalURI string, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	scope := date + "/" + s.config.Region + "/s3/aws4_request"
+
+	request.Header.Set("X-Amz-Date", amzDate)
+	request.Header.Set("X-Amz-Content-Sha256", unsignedPayload)
+
+	const signedHeaders = "host;x-amz-content-sha256;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		request.Method,
+		canonicalURI,
+		"", // Параметров запроса нет
+		"host:" + request.URL.Host,
+		"x-amz-content-sha256:" + unsignedPayload,
+		"x-amz-date:" + amzDate,
+		"",
+		signedHeaders,
+		unsignedPayload,
+	}, "\n")
+
+	hash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(hash[:])
+
+	key := hmacSHA256([]byte("AWS4"+s.config.SecretKey), date)
+	key = hmacSHA256(key, s.config.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	request.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.config.AccessKey, scope, signedHeaders, signature))
+}
+
+// hmacSHA256 вычисляет HMAC-SHA256
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// uriEncode кодирует путь по правилам SigV4: все, кроме незарезервированных символов и "/"
+func uriEncode(path string) string {
+	var result strings.Builder
+	for _, b := range []byte(path) {
+		switch {
+		case b >= 'A' && b <= 'Z', b >= 'a' && b <= 'z', b >= '0' && b <= '9',
+			b == '-', b == '_', b == '.', b == '~', b == '/':
+			result.WriteByte(b)
+		default:
+			fmt.Fprintf(&result, "%%%02X", b)
+		}
+	}
+	return result.String()
+}
@@ -101,39 +101,84 @@ INSERT INTO drivers (name, telegram_id, telegram_tag, city_uuid) VALUES
     ('Василий Безгородов', 111111111, '@vasily_no_city', NULL),
     ('Петр Неопределенный', 222222222, '@petr_undefined', NULL);
 
+-- Псевдонимы популярных городов для распознавания "Питер", "спб", "мск" и т.п.
+UPDATE cities SET aliases = ARRAY['Мск', 'Msk'] WHERE name = 'Москва';
+UPDATE cities SET aliases = ARRAY['Питер', 'СПб', 'Петербург', 'Ленинград'] WHERE name = 'Санкт-Петербург';
+UPDATE cities SET aliases = ARRAY['Новосиб', 'Нск'] WHERE name = 'Новосибирск';
+UPDATE cities SET aliases = ARRAY['Екб', 'Ебург', 'Свердловск'] WHERE name = 'Екатеринбург';
+UPDATE cities SET aliases = ARRAY['Нижний', 'НН', 'Горький'] WHERE name = 'Нижний Новгород';
+UPDATE cities SET aliases = ARRAY['Ростов'] WHERE name = 'Ростов-на-Дону';
+UPDATE cities SET aliases = ARRAY['Влад'] WHERE name = 'Владивосток';
+
+-- Регион и координаты городов (полный справочник: IMPORT_CITIES в админском боте)
+UPDATE cities c SET region = v.region, latitude = v.latitude, longitude = v.longitude
+FROM (VALUES
+    ('Москва', 'Москва', 55.7558, 37.6173),
+    ('Санкт-Петербург', 'Санкт-Петербург', 59.9343, 30.3351),
+    ('Новосибирск', 'Новосибирская область', 55.0084, 82.9357),
+    ('Екатеринбург', 'Свердловская область', 56.8389, 60.6057),
+    ('Казань', 'Республика Татарстан', 55.7963, 49.1088),
+    ('Нижний Новгород', 'Нижегородская область', 56.2965, 43.9361),
+    ('Челябинск', 'Челябинская область', 55.1644, 61.4368),
+    ('Самара', 'Самарская область', 53.1959, 50.1002),
+    ('Ростов-на-Дону', 'Ростовская область', 47.2357, 39.7015),
+    ('Уфа', 'Республика Башкортостан', 54.7388, 55.9721),
+    ('Волгоград', 'Волгоградская область', 48.7080, 44.5133),
+    ('Пермь', 'Пермский край', 58.0105, 56.2502),
+    ('Воронеж', 'Воронежская область', 51.6720, 39.1843),
+    ('Краснодар', 'Краснодарский край', 45.0355, 38.9753),
+    ('Саратов', 'Саратовская область', 51.5331, 46.0342),
+    ('Тюмень', 'Тюменская область', 57.1530, 65.5343),
+    ('Тольятти', 'Самарская область', 53.5078, 49.4204),
+    ('Ижевск', 'Удмуртская Республика', 56.8526, 53.2045),
+    ('Барнаул', 'Алтайский край', 53.3548, 83.7698),
+    ('Ульяновск', 'Ульяновская область', 54.3142, 48.4031),
+    ('Иркутск', 'Иркутская область', 52.2870, 104.3050),
+    ('Хабаровск', 'Хабаровский край', 48.4802, 135.0719),
+    ('Ярославль', 'Ярославская область', 57.6261, 39.8845),
+    ('Владивосток', 'Приморский край', 43.1155, 131.8855),
+    ('Махачкала', 'Республика Дагестан', 42.9849, 47.5047),
+    ('Томск', 'Томская область', 56.4846, 84.9476),
+    ('Оренбург', 'Оренбургская область', 51.7682, 55.0970),
+    ('Кемерово', 'Кемеровская область', 55.3547, 86.0873),
+    ('Рязань', 'Рязанская область', 54.6269, 39.6916),
+    ('Астрахань', 'Астраханская область', 46.3497, 48.0408)
+) AS v(name, region, latitude, longitude)
+WHERE c.name = v.name;
+
 -- Вставляем 30 тестовых клиентов
 INSERT INTO customers (name, phone, telegram_id, telegram_tag) VALUES 
-    ('ООО "Грузовик"', '+7(495)123-45-67', 111222333, '@gruzovik_company'),
-    ('ИП Сидоров', '+7(812)987-65-43', 444555666, '@ip_sidorov'),
-    ('АО "Транспорт"', '+7(343)555-44-33', 777888999, '@transport_ao'),
-    ('ООО "Логистика Плюс"', '+7(383)111-22-33', 123456789, '@logistika_plus'),
-    ('ИП Иванов', '+7(351)444-55-66', 987654321, '@ip_ivanov'),
-    ('АО "Доставка Экспресс"', '+7(843)777-88-99', 555666777, '@delivery_express'),
-    ('ООО "Перевозки"', '+7(831)222-33-44', 111333555, '@perevozki_company'),
-    ('ИП Петров', '+7(351)666-77-88', 444777999, '@ip_petrov'),
-    ('АО "Транс Сервис"', '+7(846)999-00-11', 777111333, '@trans_service'),
-    ('ООО "Груз Доставка"', '+7(347)333-44-55', 222666888, '@gruz_delivery'),
-    ('ИП Волков', '+7(844)555-66-77', 888111444, '@ip_volkov'),
-    ('АО "Логистика"', '+7(342)777-88-99', 333555777, '@logistika_company'),
-    ('ООО "Транспортные Решения"', '+7(345)111-22-33', 666999222, '@transport_solutions'),
-    ('ИП Соколов', '+7(846)444-55-66', 111777444, '@ip_sokolov'),
-    ('АО "Доставка"', '+7(347)777-88-99', 444111777, '@delivery_company'),
-    ('ООО "Перевозчик"', '+7(844)222-33-44', 777444111, '@perevozchik'),
-    ('ИП Попов', '+7(342)555-66-77', 222777555, '@ip_popov'),
-    ('АО "Транс Логистика"', '+7(345)888-99-00', 555222888, '@trans_logistika'),
-    ('ООО "Груз Сервис"', '+7(846)111-22-33', 888555222, '@gruz_service'),
-    ('ИП Лебедев', '+7(347)444-55-66', 333888555, '@ip_lebedev'),
-    ('АО "Логистика Сервис"', '+7(844)777-88-99', 666333999, '@logistika_service'),
-    ('ООО "Транспорт Плюс"', '+7(342)000-11-22', 999666333, '@transport_plus'),
-    ('ИП Козлов', '+7(345)333-44-55', 222999666, '@ip_kozlov'),
-    ('АО "Доставка Сервис"', '+7(846)666-77-88', 555333999, '@delivery_service'),
-    ('ООО "Перевозки Плюс"', '+7(347)999-00-11', 888666333, '@perevozki_plus'),
-    ('ИП Новиков', '+7(844)222-33-44', 333999666, '@ip_novikov'),
-    ('АО "Транс Доставка"', '+7(342)555-66-77', 666333999, '@trans_delivery'),
-    ('ООО "Груз Логистика"', '+7(345)888-99-00', 999666333, '@gruz_logistika'),
-    ('ИП Морозов', '+7(846)111-22-33', 222999666, '@ip_morozov'),
-    ('АО "Логистика Доставка"', '+7(347)444-55-66', 555333999, '@logistika_delivery'),
-    ('ООО "Транспорт Сервис"', '+7(844)777-88-99', 888666333, '@transport_service_company');
+    ('ООО "Грузовик"', '+74951234567', 111222333, '@gruzovik_company'),
+    ('ИП Сидоров', '+78129876543', 444555666, '@ip_sidorov'),
+    ('АО "Транспорт"', '+73435554433', 777888999, '@transport_ao'),
+    ('ООО "Логистика Плюс"', '+73831112233', 123456789, '@logistika_plus'),
+    ('ИП Иванов', '+73514445566', 987654321, '@ip_ivanov'),
+    ('АО "Доставка Экспресс"', '+78437778899', 555666777, '@delivery_express'),
+    ('ООО "Перевозки"', '+78312223344', 111333555, '@perevozki_company'),
+    ('ИП Петров', '+73516667788', 444777999, '@ip_petrov'),
+    ('АО "Транс Сервис"', '+78469990011', 777111333, '@trans_service'),
+    ('ООО "Груз Доставка"', '+73473334455', 222666888, '@gruz_delivery'),
+    ('ИП Волков', '+78445556677', 888111444, '@ip_volkov'),
+    ('АО "Логистика"', '+73427778899', 333555777, '@logistika_company'),
+    ('ООО "Транспортные Решения"', '+73451112233', 666999222, '@transport_solutions'),
+    ('ИП Соколов', '+78464445566', 111777444, '@ip_sokolov'),
+    ('АО "Доставка"', '+73477778899', 444111777, '@delivery_company'),
+    ('ООО "Перевозчик"', '+78442223344', 777444111, '@perevozchik'),
+    ('ИП Попов', '+73425556677', 222777555, '@ip_popov'),
+    ('АО "Транс Логистика"', '+73458889900', 555222888, '@trans_logistika'),
+    ('ООО "Груз Сервис"', '+78461112233', 888555222, '@gruz_service'),
+    ('ИП Лебедев', '+73474445566', 333888555, '@ip_lebedev'),
+    ('АО "Логистика Сервис"', '+78447778899', 666333999, '@logistika_service'),
+    ('ООО "Транспорт Плюс"', '+73420001122', 999666333, '@transport_plus'),
+    ('ИП Козлов', '+73453334455', 222999666, '@ip_kozlov'),
+    ('АО "Доставка Сервис"', '+78466667788', 555333999, '@delivery_service'),
+    ('ООО "Перевозки Плюс"', '+73479990011', 888666333, '@perevozki_plus'),
+    ('ИП Новиков', '+78442223344', 333999666, '@ip_novikov'),
+    ('АО "Транс Доставка"', '+73425556677', 666333999, '@trans_delivery'),
+    ('ООО "Груз Логистика"', '+73458889900', 999666333, '@gruz_logistika'),
+    ('ИП Морозов', '+78461112233', 222999666, '@ip_morozov'),
+    ('АО "Логистика Доставка"', '+73474445566', 555333999, '@logistika_delivery'),
+    ('ООО "Транспорт Сервис"', '+78447778899', 888666333, '@transport_service_company');
 
 -- Вставляем 30 тестовых заказов
 INSERT INTO orders (customer_uuid, title, description, weight_kg, length_cm, width_cm, height_cm, from_city_uuid, from_address, to_city_uuid, to_address, tags, price) VALUES 
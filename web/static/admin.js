@@ -0,0 +1,263 @@
+// Страница аналитики для администраторов: данные /v1/analytics/* с ключом администратора API
+
+const API_KEY_STORAGE = 'dalnoboy_admin_api_key';
+
+// Цвета серий графика заказов по дням
+const VOLUME_SERIES = [
+    { key: 'created', name: 'Созданы', color: '#3498db' },
+    { key: 'assigned', name: 'Назначены', color: '#f39c12' },
+    { key: 'delivered', name: 'Доставлены', color: '#27ae60' },
+];
+
+const SVG_NS = 'http://www.w3.org/2000/svg';
+
+// DOM элементы
+const filtersEl = document.getElementById('filters');
+const apiKeyEl = document.getElementById('api-key');
+const fromEl = document.getElementById('from');
+const toEl = document.getElementById('to');
+const loadingEl = document.getElementById('loading');
+const errorEl = document.getElementById('error');
+const analyticsEl = document.getElementById('analytics');
+
+// Запрос отчета аналитики за выбранный период
+async function fetchReport(report, params = {}) {
+    const query = new URLSearchParams(params);
+    if (fromEl.value) query.set('from', fromEl.value);
+    if (toEl.value) query.set('to', toEl.value);
+
+    const response = await fetch(`/v1/analytics/${report}?${query}`, {
+        headers: { 'X-API-Key': apiKeyEl.value },
+    });
+    const body = await response.json().catch(() => ({}));
+    if (!response.ok) {
+        throw new Error(body.error || `HTTP ${response.status}`);
+    }
+    return body.data;
+}
+
+// Загрузка всех отчетов страницы
+async function loadAnalytics() {
+    loadingEl.classList.remove('hidden');
+    errorEl.classList.add('hidden');
+    try {
+        const [summary, volume, routes, drivers] = await Promise.all([
+            fetchReport('summary'),
+            fetchReport('volume'),
+            fetchReport('routes', { limit: 10 }),
+            fetchReport('drivers', { limit: 20 }),
+        ]);
+        localStorage.setItem(API_KEY_STORAGE, apiKeyEl.value);
+
+        renderStats(summary);
+        renderVolumeChart(volume);
+        renderRoutesChart(routes);
+        renderDrivers(drivers);
+        renderSources(summary.driver_sources);
+        analyticsEl.classList.remove('hidden');
+    } catch (error) {
+        console.error('Error loading analytics:', error);
+        errorEl.textContent = `⚠️ Не удалось загрузить аналитику: ${error.message}`;
+        errorEl.classList.remove('hidden');
+        analyticsEl.classList.add('hidden');
+    } finally {
+        loadingEl.classList.add('hidden');
+    }
+}
+
+// Карточки с итогами периода
+function renderStats(summary) {
+    const prices = summary.prices;
+    const assignment = summary.assignment;
+    const cards = [
+        ['Создано заказов', summary.created],
+        ['Назначено водителям', summary.assigned],
+        ['Доставлено', summary.delivered],
+        ['Медиана до назначения', assignment.median_hours != null ? formatHours(assignment.median_hours) : '—'],
+        ['Нашли водителя', assignment.conversion_rate != null ? `${Math.round(assignment.conversion_rate * 100)}%` : '—'],
+        ['Средняя цена', formatMoney(prices.avg_price)],
+        ['Цена за кг', formatMoney(prices.avg_price_per_kg, 2)],
+        ['Цена за км', formatMoney(prices.avg_price_per_km, 2)],
+        ['Активных водителей', summary.active_drivers],
+    ];
+    document.getElementById('stats').innerHTML = cards.map(([title, value]) => `
+        <div class="stat">
+            <div class="stat-value">${escapeHTML(value)}</div>
+            <div class="stat-title">${escapeHTML(title)}</div>
+        </div>
+    `).join('');
+}
+
+// Линейный график созданных, назначенных и доставленных заказов по дням
+function renderVolumeChart(volume) {
+    const chartEl = document.getElementById('volume-chart');
+    document.getElementById('volume-legend').innerHTML = VOLUME_SERIES.map(series =>
+        `<span><i style="background:${series.color}"></i>${series.name}</span>`).join('');
+
+    const width = 760, height = 260;
+    const pad = { top: 10, right: 10, bottom: 30, left: 40 };
+    const plotWidth = width - pad.left - pad.right;
+    const plotHeight = height - pad.top - pad.bottom;
+    const max = Math.max(1, ...volume.flatMap(day => VOLUME_SERIES.map(series => day[series.key])));
+    const x = i => pad.left + (volume.length > 1 ? i * plotWidth / (volume.length - 1) : plotWidth / 2);
+    const y = value => pad.top + plotHeight - value * plotHeight / max;
+
+    const svg = createSVG(width, height);
+    for (const tick of axisTicks(max)) {
+        svg.appendChild(svgElement('line', { x1: pad.left, x2: width - pad.right, y1: y(tick), y2: y(tick), class: 'grid' }));
+        svg.appendChild(svgElement('text', { x: pad.left - 6, y: y(tick) + 4, 'text-anchor': 'end' }, tick));
+    }
+    const labelEvery = Math.ceil(volume.length / 8);
+    volume.forEach((day, i) => {
+        if (i % labelEvery === 0) {
+            svg.appendChild(svgElement('text', { x: x(i), y: height - 8, 'text-anchor': 'middle' }, formatDay(day.date)));
+        }
+    });
+    for (const series of VOLUME_SERIES) {
+        const points = volume.map((day, i) => `${x(i)},${y(day[series.key])}`).join(' ');
+        svg.appendChild(svgElement('polyline', { points, fill: 'none', stroke: series.color, 'stroke-width': 2 }));
+        volume.forEach((day, i) => {
+            const dot = svgElement('circle', { cx: x(i), cy: y(day[series.key]), r: 3, fill: series.color });
+            dot.appendChild(svgElement('title', {}, `${formatDay(day.date)}: ${series.name.toLowerCase()} ${day[series.key]}`));
+            svg.appendChild(dot);
+        });
+    }
+    chartEl.replaceChildren(svg);
+}
+
+// Горизонтальные столбцы популярных направлений
+function renderRoutesChart(routes) {
+    const chartEl = document.getElementById('routes-chart');
+    if (routes.length === 0) {
+        chartEl.innerHTML = '<div class="loading">Нет заказов с указанными городами</div>';
+        return;
+    }
+
+    const width = 760, rowHeight = 28, labelWidth = 260, valueWidth = 190;
+    const max = Math.max(...routes.map(route => route.orders));
+    const svg = createSVG(width, routes.length * rowHeight);
+    routes.forEach((route, i) => {
+        const top = i * rowHeight;
+        const barWidth = Math.max(2, route.orders * (width - labelWidth - valueWidth) / max);
+        svg.appendChild(svgElement('text', { x: labelWidth - 8, y: top + 18, 'text-anchor': 'end' },
+            `${route.from_city} → ${route.to_city}`));
+        svg.appendChild(svgElement('rect', { x: labelWidth, y: top + 5, width: barWidth, height: rowHeight - 10, rx: 3, fill: '#3498db' }));
+        const perKm = route.avg_price_per_km != null ? `, ${formatMoney(route.avg_price_per_km, 2)}/км` : '';
+        svg.appendChild(svgElement('text', { x: labelWidth + barWidth + 6, y: top + 18 },
+            `${route.orders} · ${formatMoney(route.avg_price)}${perKm}`));
+    });
+    chartEl.replaceChildren(svg);
+}
+
+// Таблица активности водителей
+function renderDrivers(data) {
+    const driversEl = document.getElementById('drivers');
+    if (data.drivers.length === 0) {
+        driversEl.innerHTML = '<div class="loading">За период водители не были активны</div>';
+        return;
+    }
+
+    const rows = data.drivers.map(driver => `
+        <tr>
+            <td>${escapeHTML(driver.name)}</td>
+            <td>${driver.bids}</td>
+            <td>${driver.assigned}</td>
+            <td>${driver.delivered}</td>
+            <td>${formatMoney(driver.revenue)}</td>
+            <td>${driver.rating_avg != null ? driver.rating_avg.toFixed(1) : '—'}</td>
+        </tr>
+    `).join('');
+    driversEl.innerHTML = `
+        <table class="admin-table">
+            <thead><tr><th>Водитель</th><th>Предложений</th><th>Назначено</th><th>Доставлено</th><th>Выручка</th><th>Рейтинг</th></tr></thead>
+            <tbody>${rows}</tbody>
+        </table>
+        <p class="muted">Показано ${data.drivers.length} из ${data.active_drivers} активных водителей</p>
+    `;
+}
+
+// Таблица новых водителей по источникам привлечения
+function renderSources(sources) {
+    const sourcesEl = document.getElementById('sources');
+    if (sources.length === 0) {
+        sourcesEl.innerHTML = '<div class="loading">За период новых водителей не было</div>';
+        return;
+    }
+
+    const rows = sources.map(source => `
+        <tr>
+            <td>${escapeHTML(formatSource(source.source))}</td>
+            <td>${source.drivers}</td>
+            <td>${source.active}</td>
+            <td>${Math.round(source.active * 100 / source.drivers)}%</td>
+        </tr>
+    `).join('');
+    sourcesEl.innerHTML = `
+        <table class="admin-table">
+            <thead><tr><th>Источник</th><th>Новых водителей</th><th>Активны</th><th>Доля активных</th></tr></thead>
+            <tbody>${rows}</tbody>
+        </table>
+    `;
+}
+
+// Название источника: ref_<код> — рекламная кампания, order_link — ссылка на заказ с сайта
+function formatSource(source) {
+    if (!source) return 'Без метки';
+    if (source === 'order_link') return 'Ссылка на заказ с сайта';
+    if (source.startsWith('ref_')) return `Кампания ${source.slice(4)}`;
+    return source;
+}
+
+function createSVG(width, height) {
+    return svgElement('svg', { viewBox: `0 0 ${width} ${height}`, width: '100%', role: 'img' });
+}
+
+function svgElement(name, attributes, text) {
+    const element = document.createElementNS(SVG_NS, name);
+    for (const [key, value] of Object.entries(attributes)) {
+        element.setAttribute(key, value);
+    }
+    if (text !== undefined) element.textContent = text;
+    return element;
+}
+
+// Деления оси: 0, примерно половина и максимум
+function axisTicks(max) {
+    const ticks = [0, Math.round(max / 2), max];
+    return [...new Set(ticks)];
+}
+
+function formatDay(date) {
+    const [, month, day] = date.split('-');
+    return `${day}.${month}`;
+}
+
+function formatHours(hours) {
+    if (hours < 1) return `${Math.round(hours * 60)} мин`;
+    if (hours < 24) return `${Math.floor(hours)} ч ${Math.round((hours % 1) * 60)} мин`;
+    return `${Math.floor(hours / 24)} д ${Math.round(hours % 24)} ч`;
+}
+
+function formatMoney(value, digits = 0) {
+    if (value == null) return '—';
+    return `${value.toLocaleString('ru-RU', { minimumFractionDigits: digits, maximumFractionDigits: digits })} ₽`;
+}
+
+function escapeHTML(value) {
+    return String(value).replace(/[&<>"']/g, char => ({
+        '&': '&amp;', '<': '&lt;', '>': '&gt;', '"': '&quot;', "'": '&#39;',
+    })[char]);
+}
+
+// Инициализация: ключ из прошлого входа и отчет за последние 30 дней
+document.addEventListener('DOMContentLoaded', function() {
+    filtersEl.addEventListener('submit', event => {
+        event.preventDefault();
+        loadAnalytics();
+    });
+
+    apiKeyEl.value = localStorage.getItem(API_KEY_STORAGE) || '';
+    if (apiKeyEl.value) {
+        loadAnalytics();
+    }
+});
@@ -0,0 +1,117 @@
+// Страница заказа по постоянной ссылке /orders/<uuid>: данные из /v1/orders/<uuid>,
+// обновление по событиям ленты заказов
+
+// DOM элементы
+const loadingEl = document.getElementById('loading');
+const errorEl = document.getElementById('error');
+const orderEl = document.getElementById('order');
+const shareEl = document.getElementById('share');
+const shareStatusEl = document.getElementById('share-status');
+
+const orderUUID = orderEl.dataset.uuid;
+
+// Загрузка заказа
+async function loadOrder() {
+    try {
+        const response = await fetch(`/v1/orders/${encodeURIComponent(orderUUID)}`);
+        const body = await response.json().catch(() => ({}));
+        if (!response.ok) {
+            throw new Error(body.error || `HTTP ${response.status}`);
+        }
+        renderOrder(body);
+        loadingEl.classList.add('hidden');
+        errorEl.classList.add('hidden');
+        orderEl.classList.remove('hidden');
+    } catch (error) {
+        console.error('Error loading order:', error);
+        loadingEl.classList.add('hidden');
+        errorEl.classList.remove('hidden');
+    }
+}
+
+// Карточка заказа
+function renderOrder(order) {
+    const rows = [
+        ['Маршрут', order.from],
+        ['Адреса', order.to],
+        ['Вес', order.weight ? `${order.weight} кг` : 'Не указан'],
+        ['Размеры', order.dimensions],
+        ['Цена', order.price ? `${order.price} ₽` : 'Не указана'],
+        ['Расстояние', order.distance_km ? `~${order.distance_km} км${order.price_per_km ? ` (${order.price_per_km.toFixed(1)} ₽/км)` : ''}` : 'Не рассчитано'],
+        ['Груз', formatCargo(order)],
+        ['Погрузка', order.date],
+        ['Заказчик', `${order.customer || 'Не указан'} (${order.phone || 'нет телефона'}${order.phone_hidden ? ', полный номер — после назначения водителя' : ''})`],
+        ['Теги', order.tags],
+    ];
+    orderEl.innerHTML = `
+        <div class="order-card">
+            <div class="order-header">
+                <div class="order-id">#${escapeHTML(order.id)}</div>
+                <span class="order-status ${escapeHTML(order.status)}">${escapeHTML(order.status_name || order.status)}</span>
+            </div>
+            <h2>${escapeHTML(order.title || 'Без названия')}</h2>
+            ${order.description ? `<p>${escapeHTML(order.description)}</p>` : ''}
+            ${order.status !== 'active' ? '<p class="muted">Заказ уже не ищет водителя.</p>' : ''}
+            <div class="order-details">
+                ${rows.map(([title, value]) => `
+                    <div class="order-detail"><strong>${title}:</strong> ${escapeHTML(value)}</div>
+                `).join('')}
+            </div>
+            ${order.attachments > 0 ? `<div class="order-detail"><a href="/v1/orders/${order.uuid}/attachments" target="_blank">📎 Фото и документы: ${order.attachments}</a></div>` : ''}
+        </div>
+    `;
+}
+
+// Форматирование типа груза и особых требований
+function formatCargo(order) {
+    const parts = [];
+    if (order.cargo_type) parts.push(order.cargo_type);
+    const req = order.requirements || {};
+    if (order.temperature) parts.push(`❄️ ${order.temperature}`);
+    if (req.adr_class) parts.push(`☢️ ADR ${req.adr_class}`);
+    if (req.oversize) parts.push('📐 Негабарит');
+    if (req.fragile) parts.push('🥚 Хрупкий');
+    return parts.length > 0 ? parts.join(' · ') : 'Не указан';
+}
+
+function escapeHTML(value) {
+    return String(value ?? '').replace(/[&<>"']/g, char => ({
+        '&': '&amp;', '<': '&lt;', '>': '&gt;', '"': '&quot;', "'": '&#39;',
+    })[char]);
+}
+
+// Поделиться ссылкой: системное меню на телефоне, копирование в буфер на компьютере
+async function shareOrder() {
+    const url = location.origin + location.pathname;
+    try {
+        if (navigator.share) {
+            await navigator.share({ title: document.title, url });
+            return;
+        }
+        await navigator.clipboard.writeText(url);
+        shareStatusEl.textContent = 'Ссылка скопирована';
+    } catch (error) {
+        if (error.name !== 'AbortError') {
+            shareStatusEl.textContent = url;
+        }
+    }
+}
+
+// Заказ перезагружается, когда меняется он сам или после пропуска событий
+function subscribeOrderEvents() {
+    if (!window.EventSource) return;
+    const events = new EventSource('/v1/orders/events');
+    const reload = event => {
+        const data = JSON.parse(event.data || '{}');
+        if (event.type === 'resync' || data.uuid === orderUUID) loadOrder();
+    };
+    for (const type of ['updated', 'removed', 'resync']) {
+        events.addEventListener(type, reload);
+    }
+}
+
+document.addEventListener('DOMContentLoaded', function() {
+    shareEl.addEventListener('click', shareOrder);
+    loadOrder();
+    subscribeOrderEvents();
+});
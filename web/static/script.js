@@ -0,0 +1,282 @@
+console.log('=== SCRIPT.JS ЗАГРУЖЕН ===');
+
+// DOM элементы
+const loadingEl = document.getElementById('loading');
+const errorEl = document.getElementById('error');
+const ordersEl = document.getElementById('orders');
+const searchFormEl = document.getElementById('search-form');
+const searchEl = document.getElementById('search');
+const filtersEl = document.getElementById('filters');
+const citiesEl = document.getElementById('cities');
+const liveEl = document.getElementById('live');
+const errorTextEl = document.getElementById('error-text');
+
+// Поля панели фильтров: id поля совпадает с параметром /v1/orders
+const FILTER_FIELDS = ['from_city', 'to_city', 'min_weight', 'max_weight', 'min_price', 'max_price', 'pickup_from', 'pickup_to'];
+// Задержка перед обновлением списка после событий ленты: пачка изменений — один запрос
+const REFRESH_DELAY_MS = 1000;
+let refreshTimer = null;
+// UUID показанных заказов: новые карточки подсвечиваются после обновления по событию
+let shownOrders = null;
+
+// Задержка перед поиском, пока пользователь печатает
+const SEARCH_DELAY_MS = 300;
+let searchTimer = null;
+// Номер последней загрузки: ответы на устаревшие запросы поиска не отображаются
+let loadSeq = 0;
+
+// Параметры поиска и фильтров из полей страницы
+function currentParams() {
+    const params = new URLSearchParams();
+    const query = searchEl.value.trim();
+    if (query) params.set('q', query);
+    for (const name of FILTER_FIELDS) {
+        const value = document.getElementById(name).value.trim();
+        if (value) params.set(name, value);
+    }
+    return params;
+}
+
+// Загрузка заказов. При обновлении по событию ленты (silent) список не скрывается.
+async function loadOrders(silent = false) {
+    const seq = ++loadSeq;
+    try {
+        if (!silent) showLoading();
+        
+        const params = currentParams();
+        const filtered = params.toString() !== '';
+        // На доске только заказы, которые еще ищут водителя
+        params.set('status', 'active');
+        const response = await fetch(`/v1/orders?${params}`);
+        
+        if (!response.ok) {
+            // Ошибки фильтров (неизвестный город, неверная дата) показываем как есть
+            const message = response.status === 400 ? (await response.text()).trim() : '';
+            throw new Error(message || `HTTP ${response.status}`);
+        }
+        
+        const orders = await response.json();
+        if (seq !== loadSeq) return;
+        console.log('Получены заказы:', orders); // Логируем данные
+        
+        if (!orders || orders.length === 0) {
+            console.log('Заказов нет');
+            ordersEl.innerHTML = `<div class="loading">${filtered ? 'По запросу ничего не найдено' : 'Заказов не найдено'}</div>`;
+            shownOrders = new Set();
+            showOrders();
+            return;
+        }
+        
+        displayOrders(orders, silent);
+        
+    } catch (error) {
+        if (seq !== loadSeq) return;
+        console.error('Error loading orders:', error);
+        showError(error.message.startsWith('HTTP') ? '' : error.message);
+    }
+}
+
+// Отображение заказов. После обновления по событию новые заказы подсвечиваются.
+function displayOrders(orders, highlightNew = false) {
+    console.log('displayOrders вызван с:', orders); // Логируем вызов
+    
+    if (!orders || orders.length === 0) {
+        console.log('Заказов нет в displayOrders');
+        ordersEl.innerHTML = '<div class="loading">Заказов не найдено</div>';
+        showOrders();
+        return;
+    }
+    
+    console.log('Создаем HTML для', orders.length, 'заказов');
+    const ordersHTML = orders.map(order => createOrderHTML(order)).join('');
+    console.log('HTML создан:', ordersHTML.substring(0, 200) + '...'); // Показываем начало HTML
+    
+    ordersEl.innerHTML = ordersHTML;
+    if (highlightNew && shownOrders) {
+        for (const card of ordersEl.querySelectorAll('.order-card')) {
+            if (!shownOrders.has(card.dataset.uuid)) card.classList.add('order-new');
+        }
+    }
+    shownOrders = new Set(orders.map(order => order.uuid));
+    showOrders();
+}
+
+// Создание HTML для заказа
+function createOrderHTML(order) {
+    return `
+        <div class="order-card" data-uuid="${order.uuid}">
+            <div class="order-header">
+                <div class="order-id">#${order.uuid || order.id || 'N/A'}</div>
+                <div class="order-title"><a href="${order.url}">${order.title || 'Без названия'}</a></div>
+            </div>
+            <div class="order-details">
+                <div class="order-detail">
+                    <strong>Описание:</strong> ${order.description || 'Не указано'}
+                </div>
+                <div class="order-detail">
+                    <strong>Клиент:</strong> ${order.customer_name || order.customer || 'Не указано'} (${order.customer_phone || order.phone || 'нет телефона'}${order.phone_hidden ? ', полный номер — после назначения водителя' : ''})
+                </div>
+                <div class="order-detail">
+                    <strong>Маршрут:</strong> ${order.from_city_name && order.to_city_name ? 
+                        `${order.from_city_name} → ${order.to_city_name}` : 
+                        (order.from || 'Не указано')}
+                </div>
+                <div class="order-detail">
+                    <strong>Адреса:</strong> ${order.from_city_name && order.to_city_name && order.from_address && order.to_address ? 
+                        `${order.from_city_name}: ${order.from_address} | ${order.to_city_name}: ${order.to_address}` : 
+                        (order.from_address && order.to_address ? `${order.from_address} → ${order.to_address}` : 'Не указаны')}
+                </div>
+                <div class="order-detail">
+                    <strong>Вес:</strong> ${order.weight ? order.weight + ' кг' : 'Не указан'}
+                </div>
+                <div class="order-detail">
+                    <strong>Размеры:</strong> ${order.length_cm && order.width_cm && order.height_cm ? 
+                        `${order.length_cm}×${order.width_cm}×${order.width_cm} см` : 
+                        (order.dimensions || 'Не указаны')}
+                </div>
+                <div class="order-detail">
+                    <strong>Цена:</strong> ${order.price ? order.price + ' ₽' : 'Не указана'}
+                </div>
+                <div class="order-detail">
+                    <strong>Расстояние:</strong> ${order.distance_km ? `~${order.distance_km} км` : 'Не рассчитано'}${order.price_per_km ? ` (${order.price_per_km.toFixed(1)} ₽/км)` : ''}
+                </div>
+                <div class="order-detail">
+                    <strong>Груз:</strong> ${formatCargo(order)}
+                </div>
+                <div class="order-detail">
+                    <strong>Дата:</strong> ${order.created_at ? new Date(order.created_at).toLocaleDateString('ru-RU') : (order.date || 'Не указана')}
+                </div>
+            </div>
+            ${order.attachments > 0 ? `<div class="order-detail"><a href="/v1/orders/${order.uuid}/attachments" target="_blank">📎 Фото и документы: ${order.attachments}</a></div>` : ''}
+            ${order.tags && order.tags.length > 0 ? `<div class="order-tags">${order.tags.map(tag => `<span class="tag">${tag}</span>`).join('')}</div>` : ''}
+            <div class="order-actions">
+                <a href="${order.url}">Подробнее</a>
+                ${order.bot_link ? `<a class="bot-link" href="${order.bot_link}" target="_blank" rel="noopener">🤖 Открыть в боте</a>` : ''}
+            </div>
+        </div>
+    `;
+}
+
+// Форматирование типа груза и особых требований
+function formatCargo(order) {
+    const parts = [];
+    if (order.cargo_type) parts.push(order.cargo_type);
+    const req = order.requirements || {};
+    if (order.temperature) parts.push(`❄️ ${order.temperature}`);
+    if (req.adr_class) parts.push(`☢️ ADR ${req.adr_class}`);
+    if (req.oversize) parts.push('📐 Негабарит');
+    if (req.fragile) parts.push('🥚 Хрупкий');
+    return parts.length > 0 ? parts.join(' · ') : 'Не указан';
+}
+
+// Показать загрузку
+function showLoading() {
+    loadingEl.classList.remove('hidden');
+    errorEl.classList.add('hidden');
+    ordersEl.classList.add('hidden');
+}
+
+// Показать ошибку
+function showError(message) {
+    errorTextEl.textContent = message ? `${message}.` : 'Не удалось загрузить.';
+    loadingEl.classList.add('hidden');
+    errorEl.classList.remove('hidden');
+    ordersEl.classList.add('hidden');
+}
+
+// Показать заказы
+function showOrders() {
+    loadingEl.classList.add('hidden');
+    errorEl.classList.add('hidden');
+    ordersEl.classList.remove('hidden');
+}
+
+// Инициализация: показываем загрузку при старте
+document.addEventListener('DOMContentLoaded', function() {
+    // Поиск по названию, описанию, адресам, городам и заказчику: при вводе и по Enter
+    const params = new URLSearchParams(location.search);
+    searchEl.value = params.get('q') || '';
+    searchEl.addEventListener('input', () => {
+        clearTimeout(searchTimer);
+        searchTimer = setTimeout(searchOrders, SEARCH_DELAY_MS);
+    });
+    searchFormEl.addEventListener('submit', event => {
+        event.preventDefault();
+        clearTimeout(searchTimer);
+        searchOrders();
+    });
+
+    // Фильтры: значения из адреса страницы, применяются при изменении полей
+    for (const name of FILTER_FIELDS) {
+        document.getElementById(name).value = params.get(name) || '';
+    }
+    filtersEl.addEventListener('input', () => {
+        clearTimeout(searchTimer);
+        searchTimer = setTimeout(searchOrders, SEARCH_DELAY_MS);
+    });
+    filtersEl.addEventListener('submit', event => {
+        event.preventDefault();
+        clearTimeout(searchTimer);
+        searchOrders();
+    });
+    filtersEl.addEventListener('reset', () => {
+        // Поля очищаются после события reset
+        setTimeout(searchOrders);
+    });
+    loadCities();
+
+    showLoading();
+    loadOrders();
+    subscribeOrderEvents();
+});
+
+// Поиск заказов: запрос и фильтры сохраняются в адресе страницы, чтобы ими можно было поделиться
+function searchOrders() {
+    const params = currentParams();
+    history.replaceState(null, '', params.toString() ? `?${params}` : location.pathname);
+    shownOrders = null;
+    loadOrders();
+}
+
+// Подсказки городов для фильтров «Откуда» и «Куда»
+async function loadCities() {
+    try {
+        const response = await fetch('/v1/cities');
+        if (!response.ok) return;
+        const cities = await response.json();
+        citiesEl.replaceChildren(...cities.map(city => {
+            const option = document.createElement('option');
+            option.value = city.name;
+            return option;
+        }));
+    } catch (error) {
+        console.error('Error loading cities:', error);
+    }
+}
+
+// Живое обновление: сервер присылает события о новых, измененных и снятых заказах,
+// список перезагружается с текущими фильтрами. EventSource сам переподключается после обрыва.
+function subscribeOrderEvents() {
+    if (!window.EventSource) return;
+    const events = new EventSource('/v1/orders/events');
+    const refresh = () => {
+        clearTimeout(refreshTimer);
+        refreshTimer = setTimeout(() => loadOrders(true), REFRESH_DELAY_MS);
+    };
+    for (const type of ['created', 'updated', 'removed', 'resync']) {
+        events.addEventListener(type, refresh);
+    }
+    // После переподключения события за время обрыва потеряны — загружаем список заново
+    let reconnecting = false;
+    events.addEventListener('open', () => {
+        liveEl.classList.remove('hidden');
+        if (reconnecting) refresh();
+        reconnecting = false;
+    });
+    events.addEventListener('error', () => {
+        liveEl.classList.add('hidden');
+        reconnecting = true;
+    });
+}
+
+ 
\ No newline at end of file
@@ -0,0 +1,21 @@
+// Package web содержит файлы сайта, встроенные в бинарный файл приложения
+package web
+
+import (
+	"embed"
+	"io/fs"
+)
+
+// files — содержимое директории static на момент сборки, включая сжатые brotli копии (*.br), если они созданы
+//
+//go:embed static
+var files embed.FS
+
+// Static возвращает файлы сайта
+func Static() fs.FS {
+	static, err := fs.Sub(files, "static")
+	if err != nil {
+		panic(err) // Директория static встроена при сборке
+	}
+	return static
+}